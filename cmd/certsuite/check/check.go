@@ -2,6 +2,7 @@ package check
 
 import (
 	imagecert "github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/check/image_cert_status"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/check/permissions"
 	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/check/results"
 	"github.com/spf13/cobra"
 )
@@ -16,6 +17,7 @@ var (
 func NewCommand() *cobra.Command {
 	checkCmd.AddCommand(imagecert.NewCommand())
 	checkCmd.AddCommand(results.NewCommand())
+	checkCmd.AddCommand(permissions.NewCommand())
 
 	return checkCmd
 }
@@ -0,0 +1,110 @@
+package permissions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/certsuite"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
+	"github.com/spf13/cobra"
+)
+
+const rbacFilePermissions = 0o644
+
+var checkPermissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Verifies that the user running the test suite has the permissions needed by the selected checks.",
+	Long: `Reviews, without running anything, the API permissions needed by the probe daemonset, the discovery of the
+workload and the checks matching the labels filter. The missing permissions are shown with the checks they
+block, which the run command skips. Optionally, the minimal ClusterRole and Roles granting all the needed
+permissions are saved in a YAML file.`,
+	Example: `certsuite check permissions --config-file config/tnf_config.yml --label-filter lifecycle --output-rbac rbac.yaml`,
+	RunE:    checkPermissions,
+}
+
+func NewCommand() *cobra.Command {
+	checkPermissionsCmd.Flags().StringP("label-filter", "l", "all", "Label expression to filter the checks whose permissions are verified")
+	checkPermissionsCmd.Flags().StringP("config-file", "c", "config/tnf_config.yml", "The workload configuration file")
+	checkPermissionsCmd.Flags().StringP("kubeconfig", "k", "", "The target cluster's Kubeconfig file")
+	checkPermissionsCmd.Flags().String("plugins-dir", "", "Folder with the executables of the plugins providing custom checks")
+	checkPermissionsCmd.Flags().String("policies-file", "", "YAML file with custom checks defined as CEL expressions")
+	checkPermissionsCmd.Flags().String("output-rbac", "", "File where the YAML of the ClusterRole and Roles granting the needed permissions is saved")
+	checkPermissionsCmd.Flags().String("rbac-name", "certsuite", "Name of the generated ClusterRole and Roles")
+
+	return checkPermissionsCmd
+}
+
+func checkPermissions(cmd *cobra.Command, _ []string) error {
+	testParams := configuration.GetTestParameters()
+	testParams.LabelsFilter, _ = cmd.Flags().GetString("label-filter")
+	testParams.ConfigFile, _ = cmd.Flags().GetString("config-file")
+	testParams.Kubeconfig, _ = cmd.Flags().GetString("kubeconfig")
+	testParams.PluginsDir, _ = cmd.Flags().GetString("plugins-dir")
+	testParams.PoliciesFile, _ = cmd.Flags().GetString("policies-file")
+	rbacFile, _ := cmd.Flags().GetString("output-rbac")
+	rbacName, _ := cmd.Flags().GetString("rbac-name")
+
+	required, err := certsuite.CheckPermissions()
+	if err != nil {
+		return fmt.Errorf("could not check the permissions, err: %v", err)
+	}
+
+	if rbacFile != "" {
+		perms := []permissions.Permission{}
+		for i := range required {
+			perms = append(perms, required[i].Permission)
+		}
+
+		rbac, err := permissions.GenerateRBAC(rbacName, perms)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(rbacFile, rbac, rbacFilePermissions); err != nil {
+			return fmt.Errorf("could not write the RBAC file %s, err: %v", rbacFile, err)
+		}
+		fmt.Printf("RBAC granting the needed permissions saved in %s\n\n", rbacFile)
+	}
+
+	missing := printMissingPermissions(os.Stdout, required)
+	if missing > 0 {
+		return fmt.Errorf("%d of the %d needed permissions are missing", missing, len(required))
+	}
+
+	return nil
+}
+
+// Prints a table with the missing permissions and what needs them. Returns the number of missing
+// permissions.
+func printMissingPermissions(w io.Writer, required []certsuite.RequiredPermission) int {
+	const (
+		permissionHeader = "PERMISSION"
+		neededByHeader   = "NEEDED BY"
+		columnPadding    = 2
+	)
+
+	width := len(permissionHeader)
+	missing := []*certsuite.RequiredPermission{}
+	for i := range required {
+		if required[i].Missing {
+			missing = append(missing, &required[i])
+			width = max(width, len(required[i].String()))
+		}
+	}
+
+	if len(missing) == 0 {
+		fmt.Fprintf(w, "All the %d permissions needed are granted.\n", len(required))
+		return 0
+	}
+
+	width += columnPadding
+	fmt.Fprintf(w, "%-*s%s\n", width, permissionHeader, neededByHeader)
+	for _, p := range missing {
+		fmt.Fprintf(w, "%-*s%s\n", width, p.String(), strings.Join(p.NeededBy, ", "))
+	}
+
+	return len(missing)
+}
@@ -0,0 +1,29 @@
+package permissions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/certsuite"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintMissingPermissions(t *testing.T) {
+	required := []certsuite.RequiredPermission{
+		{Permission: permissions.ListNodes, NeededBy: []string{"discovery of nodes", "lifecycle-pod-recreation"}},
+		{Permission: permissions.Permission{Verb: "delete", Resource: "pods", Namespace: "tnf"}, NeededBy: []string{"lifecycle-pod-recreation"}, Missing: true},
+		{Permission: permissions.ListStorageClasses, NeededBy: []string{"discovery of storageClasses", "lifecycle-storage-provisioner"}, Missing: true},
+	}
+
+	var out bytes.Buffer
+	assert.Equal(t, 2, printMissingPermissions(&out, required))
+	assert.Equal(t, `PERMISSION                          NEEDED BY
+delete pods in namespace tnf        lifecycle-pod-recreation
+list storageclasses.storage.k8s.io  discovery of storageClasses, lifecycle-storage-provisioner
+`, out.String())
+
+	out.Reset()
+	assert.Equal(t, 0, printMissingPermissions(&out, required[:1]))
+	assert.Equal(t, "All the 1 permissions needed are granted.\n", out.String())
+}
@@ -2,7 +2,12 @@ package claim
 
 import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/compare"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/merge"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/report"
 	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/show"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/trend"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/validate"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/verify"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +21,11 @@ var (
 func NewCommand() *cobra.Command {
 	claimCommand.AddCommand(compare.NewCommand())
 	claimCommand.AddCommand(show.NewCommand())
+	claimCommand.AddCommand(report.NewCommand())
+	claimCommand.AddCommand(merge.NewCommand())
+	claimCommand.AddCommand(trend.NewCommand())
+	claimCommand.AddCommand(validate.NewCommand())
+	claimCommand.AddCommand(verify.NewCommand())
 
 	return claimCommand
 }
@@ -0,0 +1,329 @@
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	claimschema "github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/claimhelper"
+	"github.com/spf13/cobra"
+)
+
+const (
+	minClaimFiles = 2
+
+	configField      = "Config"
+	nodeSummaryField = "nodeSummary"
+)
+
+var (
+	outputFileFlag     string
+	allowConflictsFlag bool
+
+	claimMergeCommand = &cobra.Command{
+		Use:   "merge <claim1.json> <claim2.json> [<claimN.json>...]",
+		Short: "Merges several claim files of the same cluster into one.",
+		Long: `Merges the results of several claim files into a single claim file, e.g. the claims of runs that were split
+by labels filter. The claims must describe the same cluster: same k8s and OCP versions, same nodes and same
+certsuite configuration.
+
+The results are merged by test case ID. A test case that ran in several claims must have the same result in
+all of them, otherwise the merge fails unless --allow-conflicts is set, in which case the result of the most
+recent claim is kept. Results of test cases that ran are preferred over the skipped ones.
+
+The "mergedFrom" field of the merged claim configurations records the source claims, the claim each result
+was taken from and the conflicts found.
+`,
+		Example: `certsuite claim merge claim-access-control.json claim-networking.json -o merged.json`,
+		Args:    cobra.MinimumNArgs(minClaimFiles),
+		RunE:    claimMerge,
+	}
+)
+
+func NewCommand() *cobra.Command {
+	claimMergeCommand.Flags().StringVarP(&outputFileFlag, "output", "o", "",
+		"Merged claim file path. The merged claim is printed to stdout if not set.",
+	)
+	claimMergeCommand.Flags().BoolVar(&allowConflictsFlag, "allow-conflicts", false,
+		"Keep the result of the most recent claim when a test case has different results in several claims.",
+	)
+
+	return claimMergeCommand
+}
+
+// SourceClaim describes one of the claims used to create a merged claim.
+type SourceClaim struct {
+	File             string `json:"file"`
+	StartTime        string `json:"startTime"`
+	EndTime          string `json:"endTime"`
+	CertsuiteVersion string `json:"certsuiteVersion"`
+}
+
+// Conflict is a test case that has different results in several claims.
+type Conflict struct {
+	TestID string `json:"testId"`
+	// Result of the test case in each claim file.
+	States map[string]string `json:"states"`
+	// File of the claim whose result was kept.
+	Selected string `json:"selected"`
+}
+
+// Provenance is stored in the MergedFromField of the merged claim configurations.
+type Provenance struct {
+	Claims []SourceClaim `json:"claims"`
+	// Maps every test case ID to the file of the claim its result was taken from.
+	Results   map[string]string `json:"results"`
+	Conflicts []Conflict        `json:"conflicts,omitempty"`
+}
+
+type sourceClaim struct {
+	file      string
+	claim     *claimschema.Claim
+	startTime time.Time
+	endTime   time.Time
+}
+
+func readClaim(file string) (*sourceClaim, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read claim file %s: %v", file, err)
+	}
+
+	claimRoot := claimschema.Root{}
+	if err := json.Unmarshal(data, &claimRoot); err != nil {
+		return nil, fmt.Errorf("failed to parse claim file %s: %v", file, err)
+	}
+
+	c := claimRoot.Claim
+	if c == nil || c.Versions == nil || c.Metadata == nil {
+		return nil, fmt.Errorf("claim file %s has no claim versions or metadata", file)
+	}
+
+	if err := claim.CheckVersion(c.Versions.ClaimFormat); err != nil {
+		return nil, fmt.Errorf("claim file %s: %v", file, err)
+	}
+
+	source := &sourceClaim{file: file, claim: c}
+	if source.startTime, err = claim.ParseMetadataTime(c.Metadata.StartTime); err != nil {
+		return nil, fmt.Errorf("claim file %s has an invalid start time: %v", file, err)
+	}
+	if source.endTime, err = claim.ParseMetadataTime(c.Metadata.EndTime); err != nil {
+		return nil, fmt.Errorf("claim file %s has an invalid end time: %v", file, err)
+	}
+
+	return source, nil
+}
+
+func getNodeNames(nodes map[string]interface{}) []string {
+	summary, ok := nodes[nodeSummaryField].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	names := []string{}
+	for name := range summary {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Returns an error listing the differences between the clusters of the two claims.
+func checkSameCluster(claim1, claim2 *sourceClaim) error {
+	diffs := []string{}
+
+	versions1, versions2 := claim1.claim.Versions, claim2.claim.Versions
+	if versions1.K8s != versions2.K8s {
+		diffs = append(diffs, fmt.Sprintf("k8s version %q != %q", versions1.K8s, versions2.K8s))
+	}
+	if versions1.Ocp != versions2.Ocp {
+		diffs = append(diffs, fmt.Sprintf("ocp version %q != %q", versions1.Ocp, versions2.Ocp))
+	}
+
+	nodes1, nodes2 := getNodeNames(claim1.claim.Nodes), getNodeNames(claim2.claim.Nodes)
+	if !reflect.DeepEqual(nodes1, nodes2) {
+		diffs = append(diffs, fmt.Sprintf("nodes %v != %v", nodes1, nodes2))
+	}
+
+	if !reflect.DeepEqual(claim1.claim.Configurations[configField], claim2.claim.Configurations[configField]) {
+		diffs = append(diffs, "certsuite configurations are different")
+	}
+
+	if len(diffs) > 0 {
+		return fmt.Errorf("claim files %s and %s are not from the same cluster: %s",
+			claim1.file, claim2.file, strings.Join(diffs, ", "))
+	}
+
+	return nil
+}
+
+// Appends the items of the list that are not in the merged list yet.
+func mergeLists(merged, list interface{}) interface{} {
+	mergedItems, _ := merged.([]interface{})
+	items, ok := list.([]interface{})
+	if !ok {
+		return merged
+	}
+
+	for _, item := range items {
+		found := false
+		for _, mergedItem := range mergedItems {
+			if reflect.DeepEqual(item, mergedItem) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			mergedItems = append(mergedItems, item)
+		}
+	}
+
+	return mergedItems
+}
+
+// Merges the claims, sorted by start time. The returned claim reuses the first claim.
+func mergeClaims(claims []*sourceClaim, allowConflicts bool) (*claimschema.Root, *Provenance, error) {
+	merged := claims[0].claim
+	if merged.Configurations == nil {
+		merged.Configurations = map[string]interface{}{}
+	}
+	// The readiness score of the first claim doesn't apply to the merged results.
+	delete(merged.Configurations, claimhelper.ScoreField)
+
+	provenance := Provenance{Results: map[string]string{}}
+	results := map[string]claimschema.Result{}
+	// Results of every test case that ran, by claim file, to report the conflicts.
+	statesByTestID := map[string]map[string]string{}
+	startTime, endTime := claims[0].startTime, claims[0].endTime
+
+	for i, source := range claims {
+		if i > 0 {
+			if err := checkSameCluster(claims[0], source); err != nil {
+				return nil, nil, err
+			}
+
+			for _, field := range []string{claimhelper.ChecksOrderField, claimhelper.WaiversField, claimhelper.ExpiredWaiversField} {
+				if list, exists := source.claim.Configurations[field]; exists {
+					merged.Configurations[field] = mergeLists(merged.Configurations[field], list)
+				}
+			}
+		}
+
+		provenance.Claims = append(provenance.Claims, SourceClaim{
+			File:             source.file,
+			StartTime:        source.claim.Metadata.StartTime,
+			EndTime:          source.claim.Metadata.EndTime,
+			CertsuiteVersion: source.claim.Versions.Tnf,
+		})
+
+		if source.startTime.Before(startTime) {
+			startTime = source.startTime
+		}
+		if source.endTime.After(endTime) {
+			endTime = source.endTime
+		}
+
+		for testID, result := range source.claim.Results {
+			if result.State != claimhelper.TestStateSkipped {
+				if statesByTestID[testID] == nil {
+					statesByTestID[testID] = map[string]string{}
+				}
+				statesByTestID[testID][source.file] = result.State
+			}
+
+			// The most recent result is kept, unless it was skipped and the previous one was not.
+			previous, exists := results[testID]
+			if exists && result.State == claimhelper.TestStateSkipped && previous.State != claimhelper.TestStateSkipped {
+				continue
+			}
+
+			results[testID] = result
+			provenance.Results[testID] = source.file
+		}
+	}
+
+	conflictIDs := []string{}
+	for testID, states := range statesByTestID {
+		for _, state := range states {
+			if state != results[testID].State {
+				conflictIDs = append(conflictIDs, testID)
+				break
+			}
+		}
+	}
+	sort.Strings(conflictIDs)
+
+	if len(conflictIDs) > 0 && !allowConflicts {
+		return nil, nil, fmt.Errorf("test cases with different results in several claim files: %s",
+			strings.Join(conflictIDs, ", "))
+	}
+
+	for _, testID := range conflictIDs {
+		provenance.Conflicts = append(provenance.Conflicts, Conflict{
+			TestID:   testID,
+			States:   statesByTestID[testID],
+			Selected: provenance.Results[testID],
+		})
+	}
+
+	merged.Results = results
+	merged.Configurations[claimhelper.MergedFromField] = provenance
+	delete(merged.Configurations, claimhelper.WaivedObjectsField)
+	if waivedObjects := claimhelper.GetWaivedObjectsCounts(results); len(waivedObjects) > 0 {
+		merged.Configurations[claimhelper.WaivedObjectsField] = waivedObjects
+	}
+	merged.Metadata = &claimschema.Metadata{
+		StartTime: startTime.Format(claimhelper.DateTimeFormatDirective),
+		EndTime:   endTime.Format(claimhelper.DateTimeFormatDirective),
+	}
+
+	return &claimschema.Root{Claim: merged}, &provenance, nil
+}
+
+// Reads and merges the claim files, writing the merged claim in the writer.
+func writeMergedClaim(files []string, allowConflicts bool, w io.Writer) error {
+	claims := []*sourceClaim{}
+	for _, file := range files {
+		source, err := readClaim(file)
+		if err != nil {
+			return err
+		}
+		claims = append(claims, source)
+	}
+
+	sort.SliceStable(claims, func(i, j int) bool {
+		return claims[i].startTime.Before(claims[j].startTime)
+	})
+
+	claimRoot, provenance, err := mergeClaims(claims, allowConflicts)
+	if err != nil {
+		return err
+	}
+
+	for _, conflict := range provenance.Conflicts {
+		fmt.Fprintf(os.Stderr, "WARNING: test case %s has different results %v, keeping the one of %s\n",
+			conflict.TestID, conflict.States, conflict.Selected)
+	}
+
+	bytes, err := json.MarshalIndent(claimRoot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the merged claim: %v", err)
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", string(bytes))
+	return err
+}
+
+func claimMerge(_ *cobra.Command, args []string) error {
+	return claim.WriteOutput(outputFileFlag, func(w io.Writer) error {
+		return writeMergedClaim(args, allowConflictsFlag, w)
+	})
+}
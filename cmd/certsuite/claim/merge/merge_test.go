@@ -0,0 +1,126 @@
+package merge
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	claimschema "github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	accessControlClaim = "testdata/claim_access_control.json"
+	networkingClaim    = "testdata/claim_networking.json"
+	lifecycleClaim     = "testdata/claim_lifecycle.json"
+	otherClusterClaim  = "testdata/claim_other_cluster.json"
+
+	accessControlTestID = "access-control-sys-admin-capability-check"
+	networkingTestID    = "networking-icmpv4-connectivity"
+	lifecycleTestID     = "lifecycle-liveness-probe"
+	observabilityTestID = "observability-container-logging"
+)
+
+func mergeFiles(t *testing.T, files []string, allowConflicts bool) (*claimschema.Claim, *Provenance) {
+	var out bytes.Buffer
+	err := writeMergedClaim(files, allowConflicts, &out)
+	assert.Nil(t, err)
+
+	// The merged claim is a valid claim, with the provenance in its configurations.
+	mergedRoot := claimschema.Root{}
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &mergedRoot))
+
+	provenanceJSON, err := json.Marshal(mergedRoot.Claim.Configurations["mergedFrom"])
+	assert.Nil(t, err)
+	provenance := Provenance{}
+	assert.Nil(t, json.Unmarshal(provenanceJSON, &provenance))
+
+	return mergedRoot.Claim, &provenance
+}
+
+func getStates(c *claimschema.Claim) map[string]string {
+	states := map[string]string{}
+	for testID := range c.Results {
+		states[testID] = c.Results[testID].State
+	}
+	return states
+}
+
+func TestMergeClaims(t *testing.T) {
+	// The claims are sorted by start time, whatever the order of the files.
+	merged, provenance := mergeFiles(t, []string{networkingClaim, accessControlClaim}, false)
+
+	// The results that ran are preferred over the skipped ones.
+	assert.Equal(t, map[string]string{
+		accessControlTestID: "passed",
+		networkingTestID:    "passed",
+		lifecycleTestID:     "failed",
+		observabilityTestID: "passed",
+	}, getStates(merged))
+
+	assert.Equal(t, "2024-01-10 10:00:00 +0000 UTC", merged.Metadata.StartTime)
+	assert.Equal(t, "2024-01-10 11:45:00 +0000 UTC", merged.Metadata.EndTime)
+	assert.Equal(t, []interface{}{accessControlTestID, networkingTestID, lifecycleTestID, observabilityTestID},
+		merged.Configurations["checksOrder"])
+
+	assert.Len(t, provenance.Claims, 2)
+	assert.Equal(t, SourceClaim{
+		File:             accessControlClaim,
+		StartTime:        "2024-01-10 10:00:00 +0000 UTC",
+		EndTime:          "2024-01-10 10:30:00 +0000 UTC",
+		CertsuiteVersion: "v5.0.0",
+	}, provenance.Claims[0])
+	assert.Equal(t, networkingClaim, provenance.Claims[1].File)
+	assert.Equal(t, map[string]string{
+		accessControlTestID: accessControlClaim,
+		networkingTestID:    networkingClaim,
+		lifecycleTestID:     accessControlClaim,
+		observabilityTestID: networkingClaim,
+	}, provenance.Results)
+	assert.Empty(t, provenance.Conflicts)
+}
+
+func TestMergeClaimsConflicts(t *testing.T) {
+	var out bytes.Buffer
+	err := writeMergedClaim([]string{accessControlClaim, lifecycleClaim}, false, &out)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), lifecycleTestID)
+	assert.Empty(t, out.String())
+
+	// The result of the most recent claim is kept and the conflict is recorded.
+	merged, provenance := mergeFiles(t, []string{lifecycleClaim, accessControlClaim}, true)
+	assert.Equal(t, "passed", merged.Results[lifecycleTestID].State)
+	assert.Equal(t, lifecycleClaim, provenance.Results[lifecycleTestID])
+	assert.Equal(t, []Conflict{{
+		TestID:   lifecycleTestID,
+		States:   map[string]string{accessControlClaim: "failed", lifecycleClaim: "passed"},
+		Selected: lifecycleClaim,
+	}}, provenance.Conflicts)
+}
+
+func TestMergeClaimsErrors(t *testing.T) {
+	testCases := []struct {
+		files         []string
+		expectedError string
+	}{
+		{
+			files:         []string{accessControlClaim, otherClusterClaim},
+			expectedError: `not from the same cluster: k8s version "v1.27.3" != "v1.28.1", nodes [master-0 worker-0] != [master-0]`,
+		},
+		{
+			files:         []string{accessControlClaim, "testdata/non-existent.json"},
+			expectedError: "failed to read claim file testdata/non-existent.json",
+		},
+		{
+			files:         []string{accessControlClaim, "merge.go"},
+			expectedError: "failed to parse claim file merge.go",
+		},
+	}
+
+	for _, tc := range testCases {
+		var out bytes.Buffer
+		err := writeMergedClaim(tc.files, true, &out)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), tc.expectedError)
+	}
+}
@@ -0,0 +1,55 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/report.html.tmpl
+var htmlTemplate string
+
+//go:embed templates/report.md.tmpl
+var markdownTemplate string
+
+// Escapes the text so it can be used inside a markdown table cell.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(strings.TrimSpace(text), "\n", "<br>")
+}
+
+var templateFuncs = map[string]any{
+	"join": strings.Join,
+	"md":   markdownCell,
+	"inc":  func(i int) int { return i + 1 },
+}
+
+func renderHTML(report *Report, w io.Writer) error {
+	tmpl, err := htmltemplate.New("report").Funcs(templateFuncs).Parse(htmlTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse html report template: %v", err)
+	}
+
+	if err := tmpl.Execute(w, report); err != nil {
+		return fmt.Errorf("failed to render html report: %v", err)
+	}
+
+	return nil
+}
+
+func renderMarkdown(report *Report, w io.Writer) error {
+	tmpl, err := template.New("report").Funcs(templateFuncs).Parse(markdownTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse markdown report template: %v", err)
+	}
+
+	if err := tmpl.Execute(w, report); err != nil {
+		return fmt.Errorf("failed to render markdown report: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/claimhelper"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+	"github.com/spf13/cobra"
+)
+
+const (
+	formatHTML     = "html"
+	formatMarkdown = "md"
+
+	// Results that are not passed, skipped or failed.
+	resultError   = "error"
+	resultAborted = "aborted"
+)
+
+var availableFormats = []string{formatHTML, formatMarkdown}
+
+var (
+	formatFlag     string
+	outputFileFlag string
+
+	claimReportCommand = &cobra.Command{
+		Use:   "report <claim.json>",
+		Short: "Creates a compliance report from a claim file.",
+		Long: `Creates a self-contained compliance report from a claim file, in HTML or Markdown format, that can be
+printed or attached to certification tickets. The report contains:
+ - The versions of the environment where the test suite ran.
+ - A summary of the results per test suite and per category classification (Telco, NonTelco, FarEdge, Extended).
+ - The failed test cases with their remediation, exception process and non compliant objects.
+ - The test cases that ended with an error or were aborted.
+`,
+		Example: `certsuite claim report --format html claim.json > report.html
+certsuite claim report --format md --output report.md claim.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: claimReport,
+	}
+)
+
+func NewCommand() *cobra.Command {
+	claimReportCommand.Flags().StringVarP(&formatFlag, "format", "f", formatHTML,
+		fmt.Sprintf("Report format. Available formats: %v", availableFormats),
+	)
+	claimReportCommand.Flags().StringVarP(&outputFileFlag, "output", "o", "",
+		"Report file path. The report is printed to stdout if not set.",
+	)
+
+	return claimReportCommand
+}
+
+// ResultsSummary holds the number of test cases in each state.
+type ResultsSummary struct {
+	Name    string
+	Total   int
+	Passed  int
+	Failed  int
+	Skipped int
+	Errors  int
+}
+
+func (s *ResultsSummary) add(state string) {
+	s.Total++
+	switch state {
+	case claim.TestCaseResultPassed:
+		s.Passed++
+	case claim.TestCaseResultFailed:
+		s.Failed++
+	case claim.TestCaseResultSkipped:
+		s.Skipped++
+	default:
+		s.Errors++
+	}
+}
+
+// CategorySummary holds the results of the mandatory and optional test cases of a category.
+type CategorySummary struct {
+	Name      string
+	Mandatory ResultsSummary
+	Optional  ResultsSummary
+}
+
+type NonCompliantObject struct {
+	Type   string
+	Reason string
+	Fields string
+}
+
+type FailedTestCase struct {
+	ID                    string
+	Suite                 string
+	Description           string
+	Remediation           string
+	ExceptionProcess      string
+	BestPracticeReference string
+	// Mandatory categories of the test case.
+	MandatoryIn         []string
+	NonCompliantObjects []NonCompliantObject
+	// Set when the non compliant objects could not be parsed from the check details.
+	CheckDetails string
+}
+
+type ErroredTestCase struct {
+	ID     string
+	State  string
+	Reason string
+}
+
+type Version struct {
+	Name  string
+	Value string
+}
+
+type Report struct {
+	Versions         []Version
+	Total            ResultsSummary
+	Suites           []ResultsSummary
+	Categories       []CategorySummary
+	FailedTestCases  []FailedTestCase
+	ErroredTestCases []ErroredTestCase
+}
+
+func getVersions(claimScheme *claim.Schema) []Version {
+	versions := claimScheme.Claim.Versions
+	return []Version{
+		{Name: "Certsuite", Value: versions.Tnf},
+		{Name: "Certsuite Git commit", Value: versions.TnfGitCommit},
+		{Name: "Claim format", Value: versions.ClaimFormat},
+		{Name: "OCP", Value: versions.Ocp},
+		{Name: "Kubernetes", Value: versions.K8s},
+		{Name: "oc client", Value: versions.OcClient},
+	}
+}
+
+// Returns the non compliant objects from the test case's check details.
+func getNonCompliantObjects(checkDetails string) ([]NonCompliantObject, error) {
+	resultObjects, err := claimhelper.GetResultObjects(checkDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []NonCompliantObject{}
+	for _, obj := range resultObjects.NonCompliantObjectsOut {
+		object := NonCompliantObject{Type: obj.ObjectType}
+		fields := []string{}
+		for i := range obj.ObjectFieldsKeys {
+			if i >= len(obj.ObjectFieldsValues) {
+				break
+			}
+
+			if obj.ObjectFieldsKeys[i] == testhelper.ReasonForNonCompliance {
+				object.Reason = obj.ObjectFieldsValues[i]
+				continue
+			}
+			fields = append(fields, obj.ObjectFieldsKeys[i]+": "+obj.ObjectFieldsValues[i])
+		}
+		object.Fields = strings.Join(fields, ", ")
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
+
+// Returns the test case's catalog entry, using the one in the claim file for the test cases that
+// don't exist in this version's catalog, like the ones from plugins or policies.
+func getFailedTestCase(tcResult *claim.TestCaseResult) FailedTestCase {
+	failedTc := FailedTestCase{
+		ID:                    tcResult.TestID.ID,
+		Suite:                 tcResult.TestID.Suite,
+		Description:           tcResult.CatalogInfo.Description,
+		Remediation:           tcResult.CatalogInfo.Remediation,
+		ExceptionProcess:      tcResult.CatalogInfo.ExceptionProcess,
+		BestPracticeReference: tcResult.CatalogInfo.BestPracticeReference,
+	}
+
+	for aID := range identifiers.Catalog {
+		if aID.Id == tcResult.TestID.ID {
+			entry := identifiers.Catalog[aID]
+			failedTc.Description = entry.Description
+			failedTc.Remediation = entry.Remediation
+			failedTc.ExceptionProcess = entry.ExceptionProcess
+			failedTc.BestPracticeReference = entry.BestPracticeReference
+			break
+		}
+	}
+
+	for _, category := range []string{identifiers.Telco, identifiers.NonTelco, identifiers.FarEdge, identifiers.Extended} {
+		if tcResult.CategoryClassification[category] == identifiers.Mandatory {
+			failedTc.MandatoryIn = append(failedTc.MandatoryIn, category)
+		}
+	}
+
+	objects, err := getNonCompliantObjects(tcResult.CheckDetails)
+	if err != nil || len(objects) == 0 {
+		// The test case doesn't use report objects, so the raw failure reason is shown instead.
+		failedTc.CheckDetails = tcResult.CheckDetails
+		if failedTc.CheckDetails == "" {
+			failedTc.CheckDetails = tcResult.SkipReason
+		}
+	}
+	failedTc.NonCompliantObjects = objects
+
+	return failedTc
+}
+
+// Creates the report from the parsed claim file. The test cases are sorted by id.
+func buildReport(claimScheme *claim.Schema) *Report {
+	report := Report{
+		Versions: getVersions(claimScheme),
+		Total:    ResultsSummary{Name: "Total"},
+	}
+
+	testIDs := make([]string, 0, len(claimScheme.Claim.Results))
+	for testID := range claimScheme.Claim.Results {
+		testIDs = append(testIDs, testID)
+	}
+	sort.Strings(testIDs)
+
+	suites := map[string]*ResultsSummary{}
+	categories := []CategorySummary{
+		{Name: identifiers.Telco},
+		{Name: identifiers.NonTelco},
+		{Name: identifiers.FarEdge},
+		{Name: identifiers.Extended},
+	}
+
+	for _, testID := range testIDs {
+		tcResult := claimScheme.Claim.Results[testID]
+		report.Total.add(tcResult.State)
+
+		suite, exists := suites[tcResult.TestID.Suite]
+		if !exists {
+			suite = &ResultsSummary{Name: tcResult.TestID.Suite}
+			suites[tcResult.TestID.Suite] = suite
+		}
+		suite.add(tcResult.State)
+
+		for i := range categories {
+			if tcResult.CategoryClassification[categories[i].Name] == identifiers.Mandatory {
+				categories[i].Mandatory.add(tcResult.State)
+			} else {
+				categories[i].Optional.add(tcResult.State)
+			}
+		}
+
+		switch tcResult.State {
+		case claim.TestCaseResultFailed:
+			report.FailedTestCases = append(report.FailedTestCases, getFailedTestCase(&tcResult))
+		case resultError, resultAborted:
+			report.ErroredTestCases = append(report.ErroredTestCases, ErroredTestCase{ID: testID, State: tcResult.State, Reason: tcResult.SkipReason})
+		}
+	}
+
+	for _, suite := range suites {
+		report.Suites = append(report.Suites, *suite)
+	}
+	sort.Slice(report.Suites, func(i, j int) bool { return report.Suites[i].Name < report.Suites[j].Name })
+	report.Categories = categories
+
+	return &report
+}
+
+func writeReport(claimFilePath, format string, w io.Writer) error {
+	if format != formatHTML && format != formatMarkdown {
+		return fmt.Errorf("invalid report format %q - available formats: %v", format, availableFormats)
+	}
+
+	claimScheme, err := claim.Parse(claimFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse claim file %s: %v", claimFilePath, err)
+	}
+
+	err = claim.CheckVersion(claimScheme.Claim.Versions.ClaimFormat)
+	if err != nil {
+		return err
+	}
+
+	report := buildReport(claimScheme)
+	if format == formatMarkdown {
+		return renderMarkdown(report, w)
+	}
+
+	return renderHTML(report, w)
+}
+
+// Main function for the `claim report` subcommand.
+func claimReport(_ *cobra.Command, args []string) error {
+	return claim.WriteOutput(outputFileFlag, func(w io.Writer) error {
+		return writeReport(args[0], formatFlag, w)
+	})
+}
@@ -0,0 +1,114 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildReport(t *testing.T) {
+	claimScheme, err := claim.Parse("testdata/claim.json")
+	assert.Nil(t, err)
+
+	report := buildReport(claimScheme)
+	assert.Equal(t, ResultsSummary{Name: "Total", Total: 4, Passed: 1, Failed: 1, Skipped: 2}, report.Total)
+	assert.Equal(t, []ResultsSummary{
+		{Name: "access-control", Total: 2, Passed: 1, Failed: 1},
+		{Name: "platform-alteration", Total: 2, Skipped: 2},
+	}, report.Suites)
+
+	assert.Len(t, report.Categories, 4)
+	assert.Equal(t, identifiers.Telco, report.Categories[0].Name)
+	assert.Equal(t, ResultsSummary{Total: 4, Passed: 1, Failed: 1, Skipped: 2}, report.Categories[0].Mandatory)
+	assert.Equal(t, identifiers.NonTelco, report.Categories[1].Name)
+	assert.Equal(t, ResultsSummary{Total: 4, Passed: 1, Failed: 1, Skipped: 2}, report.Categories[1].Optional)
+
+	assert.Len(t, report.FailedTestCases, 1)
+	failedTc := report.FailedTestCases[0]
+	assert.Equal(t, "access-control-sys-admin-capability-check", failedTc.ID)
+	assert.Equal(t, []string{identifiers.Telco, identifiers.FarEdge, identifiers.Extended}, failedTc.MandatoryIn)
+	// The remediation comes from the catalog.
+	assert.Equal(t, identifiers.Catalog[identifiers.TestSysAdminIdentifier].Remediation, failedTc.Remediation)
+	assert.Equal(t, []NonCompliantObject{
+		{Type: "Container", Reason: "Non compliant capability detected in container", Fields: "Namespace: tnf, Pod Name: test-887998557-8gwwm, Container Name: test, SCC Capability: SYS_ADMIN"},
+		{Type: "Container", Reason: "Non compliant capability detected in container", Fields: "Namespace: tnf, Pod Name: test-887998557-pr2w5, Container Name: test, SCC Capability: SYS_ADMIN"},
+	}, failedTc.NonCompliantObjects)
+	assert.Empty(t, failedTc.CheckDetails)
+
+	assert.Empty(t, report.ErroredTestCases)
+}
+
+func TestBuildReportWithoutReportObjects(t *testing.T) {
+	claimScheme := &claim.Schema{}
+	claimScheme.Claim.Results = claim.TestSuiteResults{
+		"acme-custom-check": {State: claim.TestCaseResultFailed, CheckDetails: "custom failure | reason"},
+		"acme-other-check":  {State: resultAborted, SkipReason: "check timed out"},
+	}
+	for id, result := range claimScheme.Claim.Results {
+		result.TestID.ID = id
+		result.TestID.Suite = "acme"
+		result.CatalogInfo.Remediation = "Fix " + id
+		claimScheme.Claim.Results[id] = result
+	}
+
+	report := buildReport(claimScheme)
+	assert.Equal(t, ResultsSummary{Name: "acme", Total: 2, Failed: 1, Errors: 1}, report.Suites[0])
+
+	// The catalog info of the test cases that are not in the catalog comes from the claim.
+	assert.Len(t, report.FailedTestCases, 1)
+	assert.Equal(t, "Fix acme-custom-check", report.FailedTestCases[0].Remediation)
+	assert.Equal(t, "custom failure | reason", report.FailedTestCases[0].CheckDetails)
+	assert.Empty(t, report.FailedTestCases[0].MandatoryIn)
+
+	assert.Equal(t, []ErroredTestCase{{ID: "acme-other-check", State: resultAborted, Reason: "check timed out"}}, report.ErroredTestCases)
+
+	output := bytes.Buffer{}
+	assert.Nil(t, renderMarkdown(report, &output))
+	assert.Contains(t, output.String(), "| acme-other-check | aborted | check timed out |")
+	assert.Contains(t, output.String(), "```text\ncustom failure | reason\n```")
+}
+
+func TestWriteReport(t *testing.T) {
+	testCases := []struct {
+		format           string
+		expectedContents []string
+	}{
+		{
+			format: formatHTML,
+			expectedContents: []string{
+				"<!DOCTYPE html>",
+				`<tr><td>Kubernetes</td><td>v1.27.3</td></tr>`,
+				`<h3 id="access-control-sys-admin-capability-check">access-control-sys-admin-capability-check</h3>`,
+				`<td>Namespace: tnf, Pod Name: test-887998557-pr2w5, Container Name: test, SCC Capability: SYS_ADMIN</td>`,
+			},
+		},
+		{
+			format: formatMarkdown,
+			expectedContents: []string{
+				"# Certsuite compliance report",
+				"| Kubernetes | v1.27.3 |",
+				"| access-control | 2 | 1 | 1 | 0 | 0 |",
+				"### access-control-sys-admin-capability-check",
+				"| 2 | Container | Non compliant capability detected in container | Namespace: tnf, Pod Name: test-887998557-pr2w5, Container Name: test, SCC Capability: SYS_ADMIN |",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		output := bytes.Buffer{}
+		assert.Nil(t, writeReport("testdata/claim.json", tc.format, &output))
+		for _, expectedContent := range tc.expectedContents {
+			assert.Contains(t, output.String(), expectedContent)
+		}
+	}
+}
+
+func TestWriteReportErrors(t *testing.T) {
+	output := bytes.Buffer{}
+	assert.NotNil(t, writeReport("testdata/claim.json", "pdf", &output))
+	assert.NotNil(t, writeReport("testdata/non-existent-claim.json", formatHTML, &output))
+	assert.Empty(t, output.String())
+}
@@ -157,9 +157,9 @@ func parseOutputFormatFlag() (string, error) {
 	return "", fmt.Errorf("invalid output format flag %q - available formats: %v", outputFormatFlag, availableOutputFormats)
 }
 
-// Parses the claim's test case's checkDetails field and creates a list
+// GetNonCompliantObjectsFromFailureReason parses the claim's test case's checkDetails field and creates a list
 // of NonCompliantObject's.
-func getNonCompliantObjectsFromFailureReason(checkDetails string) ([]NonCompliantObject, error) {
+func GetNonCompliantObjectsFromFailureReason(checkDetails string) ([]NonCompliantObject, error) {
 	objects := struct {
 		Compliant    []testhelper.ReportObject `json:"CompliantObjectsOut"`
 		NonCompliant []testhelper.ReportObject `json:"NonCompliantObjectsOut"`
@@ -253,7 +253,7 @@ func getFailedTestCasesByTestSuite(claimResultsByTestSuite map[string][]*claim.T
 				TestCaseDescription: tc.CatalogInfo.Description,
 			}
 
-			nonCompliantObjects, err := getNonCompliantObjectsFromFailureReason(tc.CheckDetails)
+			nonCompliantObjects, err := GetNonCompliantObjectsFromFailureReason(tc.CheckDetails)
 			if err != nil {
 				// This means the test case doesn't use the report objects yet. Just use the raw failure reason instead.
 				// Also, send the error into stderr, so it can be filtered out with "2>/errors.txt" or "2>/dev/null".
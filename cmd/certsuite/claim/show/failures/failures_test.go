@@ -260,7 +260,7 @@ func TestGetNonCompliantObjectsFromFailureReason(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		nonCompliantObjects, err := getNonCompliantObjectsFromFailureReason(tc.checkDetails)
+		nonCompliantObjects, err := GetNonCompliantObjectsFromFailureReason(tc.checkDetails)
 		if err != nil {
 			assert.Equal(t, tc.expectedError, err.Error())
 		}
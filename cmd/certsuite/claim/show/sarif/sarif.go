@@ -0,0 +1,81 @@
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	claimschema "github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/claimhelper"
+	"github.com/spf13/cobra"
+)
+
+var (
+	claimFilePathFlag string
+
+	showSarifCommand = &cobra.Command{
+		Use:   "sarif",
+		Short: "Converts a claim file to SARIF format.",
+		Long: `Parses a claim.json file and prints its results in SARIF 2.1.0 format, so they can be uploaded to
+code scanning tools. Every test case of the catalog becomes a rule and every non compliant object of the failed
+test cases becomes a result, whose logical location is the namespace/kind/name of the object and whose physical
+location is the line of the test case in the claim file.
+`,
+		Example: `./certsuite claim show sarif --claim path/to/claim.json > results.sarif`,
+		RunE:    showSarif,
+	}
+)
+
+func NewCommand() *cobra.Command {
+	showSarifCommand.Flags().StringVarP(&claimFilePathFlag, "claim", "c", "",
+		"Required: Existing claim file path.",
+	)
+
+	err := showSarifCommand.MarkFlagRequired("claim")
+	if err != nil {
+		log.Fatalf("Failed to mark claim file path as required parameter: %v", err)
+		return nil
+	}
+
+	return showSarifCommand
+}
+
+// Parses the claim file and writes its SARIF log in the writer.
+func writeSarif(claimFilePath string, w io.Writer) error {
+	data, err := os.ReadFile(claimFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read claim file %s: %v", claimFilePath, err)
+	}
+
+	claimRoot := claimschema.Root{}
+	if err := json.Unmarshal(data, &claimRoot); err != nil {
+		return fmt.Errorf("failed to parse claim file %s: %v", claimFilePath, err)
+	}
+
+	if claimRoot.Claim == nil || claimRoot.Claim.Versions == nil {
+		return fmt.Errorf("claim file %s has no claim versions", claimFilePath)
+	}
+
+	// Check claim format version
+	err = claim.CheckVersion(claimRoot.Claim.Versions.ClaimFormat)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(claimhelper.GenerateSARIF(claimRoot.Claim, filepath.ToSlash(claimFilePath), data), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SARIF log: %v", err)
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", string(bytes))
+	return err
+}
+
+// Main function for the `show sarif` subcommand.
+func showSarif(_ *cobra.Command, _ []string) error {
+	return writeSarif(claimFilePathFlag, os.Stdout)
+}
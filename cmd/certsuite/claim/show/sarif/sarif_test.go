@@ -0,0 +1,40 @@
+package sarif
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/claimhelper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteSarif(t *testing.T) {
+	output := bytes.Buffer{}
+	err := writeSarif("testdata/claim.json", &output)
+	assert.Nil(t, err)
+
+	sarifLog := claimhelper.SarifLog{}
+	assert.Nil(t, json.Unmarshal(output.Bytes(), &sarifLog))
+	assert.Equal(t, claimhelper.SarifVersion, sarifLog.Version)
+	assert.Len(t, sarifLog.Runs, 1)
+
+	// The only failed test case has two non compliant containers.
+	results := sarifLog.Runs[0].Results
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, "access-control-sys-admin-capability-check", result.RuleID)
+		assert.Equal(t, "access-control-sys-admin-capability-check", sarifLog.Runs[0].Tool.Driver.Rules[result.RuleIndex].ID)
+		assert.Len(t, result.Locations, 1)
+		assert.Equal(t, "Container", result.Locations[0].LogicalLocations[0].Kind)
+		assert.Equal(t, "testdata/claim.json", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, 4, result.Locations[0].PhysicalLocation.Region.StartLine)
+	}
+}
+
+func TestWriteSarifErrors(t *testing.T) {
+	output := bytes.Buffer{}
+	err := writeSarif("testdata/non-existent-claim.json", &output)
+	assert.NotNil(t, err)
+	assert.Empty(t, output.String())
+}
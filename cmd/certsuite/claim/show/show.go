@@ -3,6 +3,7 @@ package show
 import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/show/csv"
 	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/show/failures"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/show/sarif"
 	"github.com/spf13/cobra"
 )
 
@@ -16,5 +17,6 @@ var (
 func NewCommand() *cobra.Command {
 	showCommand.AddCommand(failures.NewCommand())
 	showCommand.AddCommand(csv.NewCommand())
+	showCommand.AddCommand(sarif.NewCommand())
 	return showCommand
 }
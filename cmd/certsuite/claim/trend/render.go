@@ -0,0 +1,327 @@
+package trend
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/compare/testcases"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/pkg/claim"
+)
+
+//go:embed templates/trend.html.tmpl
+var htmlTemplate string
+
+const (
+	runTimeFormat    = "2006-01-02 15:04"
+	textSeparatorLen = 100
+
+	// Letters of the results history of the test cases.
+	historyPassed   = 'P'
+	historyFailed   = 'F'
+	historySkipped  = 'S'
+	historyErrored  = 'E'
+	historyNotFound = '-'
+
+	csvRecordTestCase    = "testCase"
+	csvRecordObject      = "nonCompliantObject"
+	csvStateNonCompliant = "non-compliant"
+
+	// Sizes of the html charts, in pixels.
+	chartHeight     = 200
+	chartBarWidth   = 24
+	chartBarGap     = 8
+	sparklineWidth  = 240
+	sparklineHeight = 40
+)
+
+var renderers = map[string]func(*Report, io.Writer) error{
+	formatText: renderText,
+	formatCSV:  renderCSV,
+	formatJSON: renderJSON,
+	formatHTML: renderHTML,
+}
+
+// Returns one letter per run with the result of the test case, e.g. "PPFFP".
+func history(states []string) string {
+	letters := make([]byte, 0, len(states))
+	for _, state := range states {
+		switch state {
+		case claim.TestCaseResultPassed:
+			letters = append(letters, historyPassed)
+		case claim.TestCaseResultFailed:
+			letters = append(letters, historyFailed)
+		case claim.TestCaseResultSkipped:
+			letters = append(letters, historySkipped)
+		case stateNotFound:
+			letters = append(letters, historyNotFound)
+		default:
+			letters = append(letters, historyErrored)
+		}
+	}
+
+	return string(letters)
+}
+
+func formatRunTime(t time.Time) string {
+	return t.UTC().Format(runTimeFormat)
+}
+
+func renderText(report *Report, w io.Writer) error {
+	const (
+		runRowFmt    = "%-4s%-20s%-8s%-8s%-9s%-9s%-s\n"
+		diffRowFmt   = "%-70s%-10s%-s\n"
+		flapRowFmt   = "%-70s%-9s%-s\n"
+		driftRowFmt  = "%-70s%-10s%-9s%-s\n"
+		objectRowFmt = "%-70s%-s\n"
+	)
+
+	section := func(title string) string {
+		return "\n" + title + "\n" + strings.Repeat("-", len(title)) + "\n"
+	}
+
+	str := section("RUNS")
+	str += fmt.Sprintf(runRowFmt, "#", "START TIME (UTC)", "PASSED", "FAILED", "SKIPPED", "CHANGES", "FILE")
+	for i := range report.Runs {
+		run := &report.Runs[i]
+		str += fmt.Sprintf(runRowFmt, strconv.Itoa(i+1), formatRunTime(run.StartTime), strconv.Itoa(run.Summary.Passed),
+			strconv.Itoa(run.Summary.Failed), strconv.Itoa(run.Summary.Skipped), strconv.Itoa(run.ChangedTestCases), run.File)
+	}
+
+	for _, changes := range []struct {
+		title string
+		diffs []testcases.TcResultDifference
+	}{
+		{title: "REGRESSIONS", diffs: report.Regressions},
+		{title: "NEWLY FIXED", diffs: report.NewlyFixed},
+	} {
+		str += section(changes.title)
+		if len(changes.diffs) == 0 {
+			str += "<none>\n"
+			continue
+		}
+		str += fmt.Sprintf(diffRowFmt, "TEST CASE NAME", "PREVIOUS", "LATEST")
+		for _, diff := range changes.diffs {
+			str += fmt.Sprintf(diffRowFmt, diff.Name, diff.Claim1Result, diff.Claim2Result)
+		}
+	}
+
+	str += section("FLAPPING")
+	if len(report.Flapping) == 0 {
+		str += "<none>\n"
+	} else {
+		histories := map[string]string{}
+		for i := range report.TestCases {
+			histories[report.TestCases[i].ID] = history(report.TestCases[i].States)
+		}
+		str += fmt.Sprintf(flapRowFmt, "TEST CASE NAME", "CHANGES", "HISTORY")
+		for _, flapping := range report.Flapping {
+			str += fmt.Sprintf(flapRowFmt, flapping.TestID, strconv.Itoa(flapping.Changes), histories[flapping.TestID])
+		}
+	}
+
+	str += section("DURATION DRIFT")
+	if len(report.DurationDrifts) == 0 {
+		str += "<none>\n"
+	} else {
+		str += fmt.Sprintf(driftRowFmt, "TEST CASE NAME", "BASELINE", "LATEST", "CHANGE")
+		for _, drift := range report.DurationDrifts {
+			str += fmt.Sprintf(driftRowFmt, drift.TestID, fmt.Sprintf("%.1fs", drift.BaselineSeconds),
+				fmt.Sprintf("%ds", drift.LatestSeconds), fmt.Sprintf("%+.0f%%", drift.ChangePercent))
+		}
+	}
+
+	for _, objects := range []struct {
+		title   string
+		changes []ObjectChange
+	}{
+		{title: "NEW NON COMPLIANT OBJECTS", changes: report.NewNonCompliantObjects},
+		{title: "FIXED NON COMPLIANT OBJECTS", changes: report.FixedObjects},
+	} {
+		str += section(objects.title)
+		if len(objects.changes) == 0 {
+			str += "<none>\n"
+			continue
+		}
+		str += fmt.Sprintf(objectRowFmt, "TEST CASE NAME", "OBJECT")
+		for _, change := range objects.changes {
+			str += fmt.Sprintf(objectRowFmt, change.TestID, change.Object)
+		}
+	}
+
+	str += "\n" + strings.Repeat("=", textSeparatorLen) + "\n"
+	str += fmt.Sprintf("Results history, one letter per run: %c passed, %c failed, %c skipped, %c error, %c not found\n",
+		historyPassed, historyFailed, historySkipped, historyErrored, historyNotFound)
+	for i := range report.TestCases {
+		str += fmt.Sprintf(objectRowFmt, report.TestCases[i].ID, history(report.TestCases[i].States))
+	}
+
+	_, err := io.WriteString(w, strings.TrimPrefix(str, "\n"))
+	return err
+}
+
+// Writes one row per test case and run, and one row per non compliant object and run where it
+// was reported.
+func renderCSV(report *Report, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{{"record", "testId", "suite", "object", "runStartTime", "claimFile", "state", "durationSeconds"}}
+
+	suites := map[string]string{}
+	for i := range report.TestCases {
+		series := &report.TestCases[i]
+		suites[series.ID] = series.Suite
+		for run, state := range series.States {
+			rows = append(rows, []string{csvRecordTestCase, series.ID, series.Suite, "", report.Runs[run].StartTime.UTC().Format(time.RFC3339),
+				report.Runs[run].File, state, strconv.Itoa(series.DurationsSeconds[run])})
+		}
+	}
+
+	for _, series := range report.NonCompliantObjects {
+		for run, nonCompliant := range series.NonCompliant {
+			if nonCompliant {
+				rows = append(rows, []string{csvRecordObject, series.TestID, suites[series.TestID], series.Object,
+					report.Runs[run].StartTime.UTC().Format(time.RFC3339), report.Runs[run].File, csvStateNonCompliant, ""})
+			}
+		}
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write csv trend: %v", err)
+	}
+
+	return nil
+}
+
+func renderJSON(report *Report, w io.Writer) error {
+	bytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal trend: %v", err)
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", string(bytes))
+	return err
+}
+
+type chartSegment struct {
+	Y, Height int
+	Class     string
+	Title     string
+}
+
+type chartBar struct {
+	X        int
+	Label    string
+	Segments []chartSegment
+}
+
+type sparkline struct {
+	TestID string
+	Points string
+}
+
+type htmlReport struct {
+	*Report
+	ChartWidth     int
+	ChartHeight    int
+	BarWidth       int
+	Bars           []chartBar
+	SparkWidth     int
+	SparkHeight    int
+	DurationCharts []sparkline
+	Histories      map[string]string
+}
+
+// Stacked bars with the passed, failed and skipped test cases of every run.
+func getResultsChartBars(runs []Run) []chartBar {
+	maxTotal := 1
+	for i := range runs {
+		maxTotal = max(maxTotal, runs[i].Summary.Passed+runs[i].Summary.Failed+runs[i].Summary.Skipped)
+	}
+
+	bars := []chartBar{}
+	for i := range runs {
+		bar := chartBar{X: i * (chartBarWidth + chartBarGap), Label: formatRunTime(runs[i].StartTime)}
+		y := chartHeight
+		for _, segment := range []struct {
+			class string
+			count int
+		}{
+			{claim.TestCaseResultPassed, runs[i].Summary.Passed},
+			{claim.TestCaseResultFailed, runs[i].Summary.Failed},
+			{claim.TestCaseResultSkipped, runs[i].Summary.Skipped},
+		} {
+			height := segment.count * chartHeight / maxTotal
+			y -= height
+			bar.Segments = append(bar.Segments, chartSegment{
+				Y:      y,
+				Height: height,
+				Class:  segment.class,
+				Title:  fmt.Sprintf("%s: %d %s", bar.Label, segment.count, segment.class),
+			})
+		}
+		bars = append(bars, bar)
+	}
+
+	return bars
+}
+
+// Polyline points of the durations of the test case in the runs where it ran.
+func getDurationPoints(series *TestCaseSeries) string {
+	maxDuration := 1
+	for _, duration := range series.DurationsSeconds {
+		maxDuration = max(maxDuration, duration)
+	}
+
+	step := sparklineWidth / max(len(series.States)-1, 1)
+	points := []string{}
+	for i, duration := range series.DurationsSeconds {
+		if ran(series.States[i]) {
+			points = append(points, fmt.Sprintf("%d,%d", i*step, sparklineHeight-duration*sparklineHeight/maxDuration))
+		}
+	}
+
+	return strings.Join(points, " ")
+}
+
+func renderHTML(report *Report, w io.Writer) error {
+	data := htmlReport{
+		Report:      report,
+		ChartWidth:  len(report.Runs) * (chartBarWidth + chartBarGap),
+		ChartHeight: chartHeight,
+		BarWidth:    chartBarWidth,
+		Bars:        getResultsChartBars(report.Runs),
+		SparkWidth:  sparklineWidth,
+		SparkHeight: sparklineHeight,
+		Histories:   map[string]string{},
+	}
+
+	testCases := map[string]*TestCaseSeries{}
+	for i := range report.TestCases {
+		testCases[report.TestCases[i].ID] = &report.TestCases[i]
+		data.Histories[report.TestCases[i].ID] = history(report.TestCases[i].States)
+	}
+	for _, drift := range report.DurationDrifts {
+		data.DurationCharts = append(data.DurationCharts, sparkline{TestID: drift.TestID, Points: getDurationPoints(testCases[drift.TestID])})
+	}
+
+	tmpl, err := htmltemplate.New("trend").Funcs(map[string]any{
+		"runTime":    formatRunTime,
+		"stateClass": func(state string) string { return strings.ReplaceAll(state, " ", "-") },
+		"inc":        func(i int) int { return i + 1 },
+	}).Parse(htmlTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse html trend template: %v", err)
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render html trend: %v", err)
+	}
+
+	return nil
+}
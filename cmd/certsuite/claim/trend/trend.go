@@ -0,0 +1,471 @@
+package trend
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/compare/testcases"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/show/failures"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/pkg/claim"
+	"github.com/spf13/cobra"
+)
+
+const (
+	formatText = "text"
+	formatCSV  = "csv"
+	formatJSON = "json"
+	formatHTML = "html"
+
+	// State of the test cases that are not in a claim file.
+	stateNotFound = "not found"
+
+	minRuns = 2
+
+	defaultFlappingThreshold      = 3
+	defaultDurationDriftThreshold = 50
+	// Durations are whole seconds, so smaller changes are ignored.
+	minDurationDriftSeconds = 5
+)
+
+var availableFormats = []string{formatText, formatCSV, formatJSON, formatHTML}
+
+var (
+	formatFlag                 string
+	outputFileFlag             string
+	flappingThresholdFlag      int
+	durationDriftThresholdFlag int
+
+	claimTrendCommand = &cobra.Command{
+		Use:   "trend <dir-or-glob> [<dir-or-glob>...]",
+		Short: "Shows the results trend of many claim files.",
+		Long: `Builds the time series of the results of every test case and of every non compliant object from many claim
+files, e.g. the claims of nightly runs, sorted by the start time of their runs. A directory argument uses all
+its *.json files. Files that are not valid claim files are skipped with a warning.
+
+The trend report contains:
+ - The results summary of every run and the number of test cases whose result changed from the previous run.
+ - Regressions: test cases that passed in the previous run and fail in the latest one.
+ - Newly fixed: test cases that failed in the previous run and pass in the latest one.
+ - Flapping: test cases whose result changed at least --flapping-threshold times.
+ - Duration drift: test cases whose duration in the latest run differs at least --duration-drift-threshold
+   percent from the median of the previous runs.
+ - The non compliant objects that are new or fixed in the latest run.
+`,
+		Example: `certsuite claim trend /var/lib/certsuite/nightly
+certsuite claim trend --format html --output trend.html 'claims/claim-2024-*.json'`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: claimTrend,
+	}
+)
+
+func NewCommand() *cobra.Command {
+	claimTrendCommand.Flags().StringVarP(&formatFlag, "format", "f", formatText,
+		fmt.Sprintf("Output format. Available formats: %v", availableFormats),
+	)
+	claimTrendCommand.Flags().StringVarP(&outputFileFlag, "output", "o", "",
+		"Output file path. The trend is printed to stdout if not set.",
+	)
+	claimTrendCommand.Flags().IntVar(&flappingThresholdFlag, "flapping-threshold", defaultFlappingThreshold,
+		"Number of result changes from which a test case is reported as flapping.",
+	)
+	claimTrendCommand.Flags().IntVar(&durationDriftThresholdFlag, "duration-drift-threshold", defaultDurationDriftThreshold,
+		"Percentage of change of the duration of a test case from which it is reported as drifting.",
+	)
+
+	return claimTrendCommand
+}
+
+// Run is one of the claim files of the trend.
+type Run struct {
+	File             string                     `json:"file"`
+	StartTime        time.Time                  `json:"startTime"`
+	CertsuiteVersion string                     `json:"certsuiteVersion"`
+	Summary          testcases.TcResultsSummary `json:"summary"`
+	// Number of test cases whose result is different from the previous run.
+	ChangedTestCases int `json:"changedTestCases"`
+}
+
+// TestCaseSeries holds the result and duration of a test case in every run.
+type TestCaseSeries struct {
+	ID               string   `json:"id"`
+	Suite            string   `json:"suite"`
+	States           []string `json:"states"`
+	DurationsSeconds []int    `json:"durationsSeconds"`
+	// Number of times the result changed between runs where the test case ran.
+	Changes int `json:"changes"`
+}
+
+// ObjectSeries tells in which runs an object was reported as non compliant by a test case.
+type ObjectSeries struct {
+	TestID       string `json:"testId"`
+	Object       string `json:"object"`
+	NonCompliant []bool `json:"nonCompliant"`
+}
+
+type ObjectChange struct {
+	TestID string `json:"testId"`
+	Object string `json:"object"`
+}
+
+type FlappingTestCase struct {
+	TestID  string `json:"testId"`
+	Changes int    `json:"changes"`
+}
+
+type DurationDrift struct {
+	TestID          string  `json:"testId"`
+	BaselineSeconds float64 `json:"baselineSeconds"`
+	LatestSeconds   int     `json:"latestSeconds"`
+	ChangePercent   float64 `json:"changePercent"`
+}
+
+type Report struct {
+	Runs                []Run            `json:"runs"`
+	TestCases           []TestCaseSeries `json:"testCases"`
+	NonCompliantObjects []ObjectSeries   `json:"nonCompliantObjects"`
+
+	// Changes between the previous and the latest run.
+	Regressions            []testcases.TcResultDifference `json:"regressions"`
+	NewlyFixed             []testcases.TcResultDifference `json:"newlyFixed"`
+	NewNonCompliantObjects []ObjectChange                 `json:"newNonCompliantObjects"`
+	FixedObjects           []ObjectChange                 `json:"fixedObjects"`
+
+	Flapping       []FlappingTestCase `json:"flapping"`
+	DurationDrifts []DurationDrift    `json:"durationDrifts"`
+}
+
+type runClaim struct {
+	file      string
+	startTime time.Time
+	schema    *claim.Schema
+}
+
+// Returns the claim files of the arguments, that can be directories or glob patterns.
+func getClaimFiles(args []string) ([]string, error) {
+	files := []string{}
+	for _, arg := range args {
+		pattern := arg
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			pattern = filepath.Join(arg, "*.json")
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid claim files pattern %s: %v", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+
+	return files, nil
+}
+
+func readRunClaim(file string) (*runClaim, error) {
+	schema, err := claim.Parse(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := claim.CheckVersion(schema.Claim.Versions.ClaimFormat); err != nil {
+		return nil, err
+	}
+
+	startTime, err := claim.ParseMetadataTime(schema.Claim.Metadata.StartTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &runClaim{file: file, startTime: startTime, schema: schema}, nil
+}
+
+// Reads the claim files, sorted by start time, skipping the invalid ones.
+func readRunClaims(files []string) ([]*runClaim, error) {
+	runs := []*runClaim{}
+	for _, file := range files {
+		run, err := readRunClaim(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: skipping claim file %s: %v\n", file, err)
+			continue
+		}
+		runs = append(runs, run)
+	}
+
+	if len(runs) < minRuns {
+		return nil, fmt.Errorf("at least %d valid claim files are needed, found %d", minRuns, len(runs))
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].startTime.Before(runs[j].startTime) })
+
+	return runs, nil
+}
+
+// Whether the test case ran, so its result can be compared with other runs.
+func ran(state string) bool {
+	return state != claim.TestCaseResultSkipped && state != stateNotFound
+}
+
+// Returns the non compliant objects of a failed test case as strings.
+func getNonCompliantObjects(tcResult *claim.TestCaseResult) []string {
+	if tcResult.State != claim.TestCaseResultFailed {
+		return nil
+	}
+
+	objects, err := failures.GetNonCompliantObjectsFromFailureReason(tcResult.CheckDetails)
+	if err != nil {
+		// The test case doesn't use report objects.
+		return nil
+	}
+
+	objectStrings := []string{}
+	for _, object := range objects {
+		fields := []string{}
+		for _, field := range object.Spec.Fields {
+			fields = append(fields, field.Key+": "+field.Value)
+		}
+		objectStrings = append(objectStrings, fmt.Sprintf("%s (%s): %s", object.Type, strings.Join(fields, ", "), object.Reason))
+	}
+
+	return objectStrings
+}
+
+func getTestCasesSeries(runs []*runClaim) []TestCaseSeries {
+	seriesByID := map[string]*TestCaseSeries{}
+	for i, run := range runs {
+		for _, tcResult := range run.schema.Claim.Results {
+			series, exists := seriesByID[tcResult.TestID.ID]
+			if !exists {
+				series = &TestCaseSeries{
+					ID:               tcResult.TestID.ID,
+					Suite:            tcResult.TestID.Suite,
+					States:           make([]string, len(runs)),
+					DurationsSeconds: make([]int, len(runs)),
+				}
+				for j := range series.States {
+					series.States[j] = stateNotFound
+				}
+				seriesByID[tcResult.TestID.ID] = series
+			}
+
+			series.States[i] = tcResult.State
+			series.DurationsSeconds[i] = tcResult.Duration
+		}
+	}
+
+	allSeries := []TestCaseSeries{}
+	for _, series := range seriesByID {
+		previousState := ""
+		for _, state := range series.States {
+			if !ran(state) {
+				continue
+			}
+			if previousState != "" && state != previousState {
+				series.Changes++
+			}
+			previousState = state
+		}
+		allSeries = append(allSeries, *series)
+	}
+	sort.Slice(allSeries, func(i, j int) bool { return allSeries[i].ID < allSeries[j].ID })
+
+	return allSeries
+}
+
+func getObjectsSeries(runs []*runClaim) []ObjectSeries {
+	seriesByKey := map[ObjectChange]*ObjectSeries{}
+	for i, run := range runs {
+		for _, tcResult := range run.schema.Claim.Results {
+			for _, object := range getNonCompliantObjects(&tcResult) {
+				key := ObjectChange{TestID: tcResult.TestID.ID, Object: object}
+				series, exists := seriesByKey[key]
+				if !exists {
+					series = &ObjectSeries{TestID: key.TestID, Object: key.Object, NonCompliant: make([]bool, len(runs))}
+					seriesByKey[key] = series
+				}
+				series.NonCompliant[i] = true
+			}
+		}
+	}
+
+	allSeries := []ObjectSeries{}
+	for _, series := range seriesByKey {
+		allSeries = append(allSeries, *series)
+	}
+	sort.Slice(allSeries, func(i, j int) bool {
+		if allSeries[i].TestID != allSeries[j].TestID {
+			return allSeries[i].TestID < allSeries[j].TestID
+		}
+		return allSeries[i].Object < allSeries[j].Object
+	})
+
+	return allSeries
+}
+
+func getMedian(values []int) float64 {
+	sorted := append([]int{}, values...)
+	sort.Ints(sorted)
+
+	middle := len(sorted) / 2 //nolint:mnd
+	if len(sorted)%2 == 1 {
+		return float64(sorted[middle])
+	}
+	return float64(sorted[middle-1]+sorted[middle]) / 2 //nolint:mnd
+}
+
+// Returns the drift of the latest duration of the test case from the median of the previous
+// runs where it ran, if it's over the threshold.
+func getDurationDrift(series *TestCaseSeries, thresholdPercent int) (DurationDrift, bool) {
+	latest := len(series.States) - 1
+	if !ran(series.States[latest]) {
+		return DurationDrift{}, false
+	}
+
+	previousDurations := []int{}
+	for i := 0; i < latest; i++ {
+		if ran(series.States[i]) {
+			previousDurations = append(previousDurations, series.DurationsSeconds[i])
+		}
+	}
+	if len(previousDurations) == 0 {
+		return DurationDrift{}, false
+	}
+
+	baseline := getMedian(previousDurations)
+	latestDuration := series.DurationsSeconds[latest]
+	change := float64(latestDuration) - baseline
+	// Sub-second baselines are rounded to zero.
+	changePercent := change / max(baseline, 1) * 100 //nolint:mnd
+
+	if abs(change) < minDurationDriftSeconds || abs(changePercent) < float64(thresholdPercent) {
+		return DurationDrift{}, false
+	}
+
+	return DurationDrift{
+		TestID:          series.ID,
+		BaselineSeconds: baseline,
+		LatestSeconds:   latestDuration,
+		ChangePercent:   changePercent,
+	}, true
+}
+
+func abs(value float64) float64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// Fills the regressions and newly fixed test cases from the diff of the two latest runs.
+func addResultChanges(report *Report, diff *testcases.DiffReport) {
+	for _, tcDiff := range diff.TestCases {
+		previous, latest := tcDiff.Claim1Result, tcDiff.Claim2Result
+		switch {
+		case previous == claim.TestCaseResultPassed && ran(latest):
+			report.Regressions = append(report.Regressions, tcDiff)
+		case latest == claim.TestCaseResultPassed && ran(previous):
+			report.NewlyFixed = append(report.NewlyFixed, tcDiff)
+		}
+	}
+}
+
+// Fills the non compliant objects that appeared or disappeared in the latest run, for the test
+// cases that ran in the two latest runs.
+func addObjectChanges(report *Report, testCases map[string]*TestCaseSeries) {
+	latest := len(report.Runs) - 1
+	for _, series := range report.NonCompliantObjects {
+		tcSeries := testCases[series.TestID]
+		if !ran(tcSeries.States[latest-1]) || !ran(tcSeries.States[latest]) {
+			continue
+		}
+
+		change := ObjectChange{TestID: series.TestID, Object: series.Object}
+		switch {
+		case series.NonCompliant[latest] && !series.NonCompliant[latest-1]:
+			report.NewNonCompliantObjects = append(report.NewNonCompliantObjects, change)
+		case !series.NonCompliant[latest] && series.NonCompliant[latest-1]:
+			report.FixedObjects = append(report.FixedObjects, change)
+		}
+	}
+}
+
+func buildReport(runs []*runClaim, flappingThreshold, durationDriftThreshold int) *Report {
+	report := Report{
+		Regressions:            []testcases.TcResultDifference{},
+		NewlyFixed:             []testcases.TcResultDifference{},
+		NewNonCompliantObjects: []ObjectChange{},
+		FixedObjects:           []ObjectChange{},
+		Flapping:               []FlappingTestCase{},
+		DurationDrifts:         []DurationDrift{},
+	}
+
+	var diff *testcases.DiffReport
+	for i, run := range runs {
+		reportRun := Run{
+			File:             run.file,
+			StartTime:        run.startTime,
+			CertsuiteVersion: run.schema.Claim.Versions.Tnf,
+		}
+
+		if i == 0 {
+			// Compared with itself to get its summary.
+			reportRun.Summary = testcases.GetDiffReport(run.schema.Claim.Results, run.schema.Claim.Results).Claim1ResultsSummary
+		} else {
+			diff = testcases.GetDiffReport(runs[i-1].schema.Claim.Results, run.schema.Claim.Results)
+			reportRun.Summary = diff.Claim2ResultsSummary
+			reportRun.ChangedTestCases = diff.DifferentTestCasesResults
+		}
+
+		report.Runs = append(report.Runs, reportRun)
+	}
+
+	report.TestCases = getTestCasesSeries(runs)
+	report.NonCompliantObjects = getObjectsSeries(runs)
+
+	testCases := map[string]*TestCaseSeries{}
+	for i := range report.TestCases {
+		series := &report.TestCases[i]
+		testCases[series.ID] = series
+
+		if series.Changes >= flappingThreshold {
+			report.Flapping = append(report.Flapping, FlappingTestCase{TestID: series.ID, Changes: series.Changes})
+		}
+
+		if drift, found := getDurationDrift(series, durationDriftThreshold); found {
+			report.DurationDrifts = append(report.DurationDrifts, drift)
+		}
+	}
+
+	addResultChanges(&report, diff)
+	addObjectChanges(&report, testCases)
+
+	return &report
+}
+
+func writeTrend(args []string, format string, w io.Writer) error {
+	render, found := renderers[format]
+	if !found {
+		return fmt.Errorf("invalid trend format %q - available formats: %v", format, availableFormats)
+	}
+
+	files, err := getClaimFiles(args)
+	if err != nil {
+		return err
+	}
+
+	runs, err := readRunClaims(files)
+	if err != nil {
+		return err
+	}
+
+	return render(buildReport(runs, flappingThresholdFlag, durationDriftThresholdFlag), w)
+}
+
+// Main function for the `claim trend` subcommand.
+func claimTrend(_ *cobra.Command, args []string) error {
+	return claim.WriteOutput(outputFileFlag, func(w io.Writer) error {
+		return writeTrend(args, formatFlag, w)
+	})
+}
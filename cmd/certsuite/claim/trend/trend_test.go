@@ -0,0 +1,147 @@
+package trend
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/compare/testcases"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	nightlyDir = "testdata/nightly"
+
+	accessControlTestID = "access-control-sys-admin-capability-check"
+	lifecycleTestID     = "lifecycle-liveness-probe"
+	networkingTestID    = "networking-icmpv4-connectivity"
+	observabilityTestID = "observability-container-logging"
+	platformTestID      = "platform-alteration-base-image"
+)
+
+func getNightlyReport(t *testing.T) *Report {
+	files, err := getClaimFiles([]string{nightlyDir})
+	assert.Nil(t, err)
+	// The invalid claim files are skipped.
+	runs, err := readRunClaims(files)
+	assert.Nil(t, err)
+
+	return buildReport(runs, defaultFlappingThreshold, defaultDurationDriftThreshold)
+}
+
+func TestBuildReport(t *testing.T) {
+	report := getNightlyReport(t)
+
+	// The runs are sorted by start time.
+	assert.Len(t, report.Runs, 4)
+	expectedFiles := []string{"claim-c.json", "claim-a.json", "claim-d.json", "claim-b.json"}
+	for i := range report.Runs {
+		assert.Equal(t, filepath.Join(nightlyDir, expectedFiles[i]), report.Runs[i].File)
+	}
+	assert.Equal(t, testcases.TcResultsSummary{Passed: 2, Skipped: 1, Failed: 2}, report.Runs[0].Summary)
+	assert.Equal(t, 0, report.Runs[0].ChangedTestCases)
+	assert.Equal(t, testcases.TcResultsSummary{Passed: 3, Skipped: 1, Failed: 1}, report.Runs[3].Summary)
+	assert.Equal(t, 4, report.Runs[3].ChangedTestCases)
+
+	assert.Len(t, report.TestCases, 5)
+	assert.Equal(t, TestCaseSeries{
+		ID:               networkingTestID,
+		Suite:            "networking",
+		States:           []string{"failed", "passed", "failed", "passed"},
+		DurationsSeconds: []int{2, 2, 2, 2},
+		Changes:          3,
+	}, report.TestCases[2])
+	assert.Equal(t, []string{"skipped", "skipped", stateNotFound, "skipped"}, report.TestCases[4].States)
+
+	assert.Equal(t, []ObjectSeries{
+		{TestID: accessControlTestID, Object: "Container (Namespace: tnf, Pod Name: pod-1, Container Name: test): Non compliant capability detected in container", NonCompliant: []bool{false, false, false, true}},
+		{TestID: lifecycleTestID, Object: "Container (Namespace: tnf, Pod Name: pod-a, Container Name: test): No liveness probe", NonCompliant: []bool{true, true, true, false}},
+		{TestID: lifecycleTestID, Object: "Container (Namespace: tnf, Pod Name: pod-b, Container Name: test): No liveness probe", NonCompliant: []bool{false, false, true, false}},
+	}, report.NonCompliantObjects)
+
+	assert.Equal(t, []testcases.TcResultDifference{{Name: accessControlTestID, Claim1Result: "passed", Claim2Result: "failed"}}, report.Regressions)
+	assert.Equal(t, []testcases.TcResultDifference{
+		{Name: lifecycleTestID, Claim1Result: "failed", Claim2Result: "passed"},
+		{Name: networkingTestID, Claim1Result: "failed", Claim2Result: "passed"},
+	}, report.NewlyFixed)
+	assert.Equal(t, []ObjectChange{{TestID: accessControlTestID, Object: report.NonCompliantObjects[0].Object}}, report.NewNonCompliantObjects)
+	assert.Equal(t, []ObjectChange{
+		{TestID: lifecycleTestID, Object: report.NonCompliantObjects[1].Object},
+		{TestID: lifecycleTestID, Object: report.NonCompliantObjects[2].Object},
+	}, report.FixedObjects)
+
+	assert.Equal(t, []FlappingTestCase{{TestID: networkingTestID, Changes: 3}}, report.Flapping)
+	assert.Len(t, report.DurationDrifts, 1)
+	assert.Equal(t, observabilityTestID, report.DurationDrifts[0].TestID)
+	assert.Equal(t, 11.0, report.DurationDrifts[0].BaselineSeconds)
+	assert.Equal(t, 40, report.DurationDrifts[0].LatestSeconds)
+	assert.InDelta(t, 263.6, report.DurationDrifts[0].ChangePercent, 0.1)
+}
+
+func TestDurationDrift(t *testing.T) {
+	testCases := []struct {
+		states        []string
+		durations     []int
+		expectedDrift bool
+	}{
+		// The baseline is the median, so a single slow run doesn't change it.
+		{states: []string{"passed", "passed", "passed", "passed"}, durations: []int{10, 100, 10, 20}, expectedDrift: true},
+		// Under the minimum number of seconds.
+		{states: []string{"passed", "passed"}, durations: []int{1, 4}, expectedDrift: false},
+		// Under the threshold percentage.
+		{states: []string{"passed", "passed"}, durations: []int{100, 120}, expectedDrift: false},
+		// Faster runs drift too.
+		{states: []string{"passed", "failed"}, durations: []int{100, 20}, expectedDrift: true},
+		// The runs where the test case was skipped are ignored.
+		{states: []string{"skipped", "passed"}, durations: []int{0, 20}, expectedDrift: false},
+		{states: []string{"passed", "skipped"}, durations: []int{100, 0}, expectedDrift: false},
+	}
+
+	for _, tc := range testCases {
+		_, drift := getDurationDrift(&TestCaseSeries{States: tc.states, DurationsSeconds: tc.durations}, defaultDurationDriftThreshold)
+		assert.Equal(t, tc.expectedDrift, drift, "durations %v", tc.durations)
+	}
+}
+
+func TestReadRunClaimsErrors(t *testing.T) {
+	_, err := readRunClaims([]string{filepath.Join(nightlyDir, "claim-a.json"), filepath.Join(nightlyDir, "invalid.json")})
+	assert.Equal(t, "at least 2 valid claim files are needed, found 1", err.Error())
+
+	_, err = getClaimFiles([]string{"testdata/[nightly"})
+	assert.NotNil(t, err)
+}
+
+func TestRenderers(t *testing.T) {
+	report := getNightlyReport(t)
+
+	var text bytes.Buffer
+	assert.Nil(t, renderText(report, &text))
+	assert.Contains(t, text.String(), "REGRESSIONS\n-----------\n")
+	assert.Contains(t, text.String(), networkingTestID+"                                        3        FPFP\n")
+	assert.Contains(t, text.String(), platformTestID+"                                        SS-S\n")
+
+	var csvOutput bytes.Buffer
+	assert.Nil(t, renderCSV(report, &csvOutput))
+	rows, err := csv.NewReader(&csvOutput).ReadAll()
+	assert.Nil(t, err)
+	// Header, 5 test cases in 4 runs and 5 non compliant objects reports.
+	assert.Len(t, rows, 1+5*4+5)
+	assert.Equal(t, []string{"testCase", accessControlTestID, "access-control", "", "2024-01-04T02:00:00Z", filepath.Join(nightlyDir, "claim-b.json"), "failed", "1"}, rows[4])
+	assert.Equal(t, "nonCompliantObject", rows[21][0])
+
+	var jsonOutput bytes.Buffer
+	assert.Nil(t, renderJSON(report, &jsonOutput))
+	decodedReport := Report{}
+	assert.Nil(t, json.Unmarshal(jsonOutput.Bytes(), &decodedReport))
+	assert.Equal(t, report.Flapping, decodedReport.Flapping)
+
+	var html bytes.Buffer
+	assert.Nil(t, renderHTML(report, &html))
+	assert.Contains(t, html.String(), `<rect x="0" y="120" width="24" height="80" class="passed">`)
+	assert.Contains(t, html.String(), `<polyline points="0,30 80,28 160,29 240,0"/>`)
+	assert.Contains(t, html.String(), `<td class="cell not-found"`)
+
+	assert.NotNil(t, writeTrend([]string{nightlyDir}, "xml", &html))
+}
@@ -0,0 +1,259 @@
+package validate
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	officialClaimScheme "github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/common"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/plugins"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/policies"
+	"github.com/spf13/cobra"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	schemasDir = "schemas"
+	// The schema of each claim format version is stored in schemas/claim-<version>.schema.json
+	schemaFileFmt = "claim-%s.schema.json"
+)
+
+//go:embed schemas/*.json
+var schemas embed.FS
+
+var (
+	pluginsDirFlag   string
+	policiesFileFlag string
+
+	claimValidateCommand = &cobra.Command{
+		Use:   "validate <claim.json>",
+		Short: "Validates a claim file against the JSON schema of its claim format version.",
+		Long: `Validates a claim file against the JSON schema of the claim format version set in its "versions.claimFormat"
+field. The test cases of the results are also checked against the catalog of certsuite, including the
+checks of the plugins and policies when --plugins-dir and --policies-file are set.
+
+All the errors found are printed, with the path of the claim field where each one was found.
+`,
+		Example: `certsuite claim validate claim.json`,
+		Args:    cobra.ExactArgs(1),
+		RunE:    claimValidate,
+	}
+)
+
+func NewCommand() *cobra.Command {
+	claimValidateCommand.Flags().StringVar(&pluginsDirFlag, "plugins-dir", "",
+		"Directory with the plugins whose checks are added to the catalog.",
+	)
+	claimValidateCommand.Flags().StringVar(&policiesFileFlag, "policies-file", "",
+		"Policies file whose policies are added to the catalog.",
+	)
+
+	return claimValidateCommand
+}
+
+// ValidationError is an error found in a claim file.
+type ValidationError struct {
+	// Path of the claim field with the error, e.g. "claim.results.test-case1.state".
+	Field       string
+	Description string
+}
+
+func (e ValidationError) String() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Description)
+}
+
+func claimValidate(_ *cobra.Command, args []string) error {
+	if pluginsDirFlag != "" {
+		if err := plugins.AddCatalogEntries(pluginsDirFlag); err != nil {
+			return fmt.Errorf("failed to add the plugins' checks to the catalog: %v", err)
+		}
+	}
+
+	if policiesFileFlag != "" {
+		if err := policies.AddCatalogEntries(policiesFileFlag); err != nil {
+			return fmt.Errorf("failed to add the policies to the catalog: %v", err)
+		}
+	}
+
+	return writeValidation(args[0], os.Stdout)
+}
+
+// Prints the validation errors of the claim file, returning an error if there is any.
+func writeValidation(claimFilePath string, w io.Writer) error {
+	validationErrors, err := validateClaimFile(claimFilePath)
+	if err != nil {
+		return err
+	}
+
+	if len(validationErrors) == 0 {
+		fmt.Fprintf(w, "Claim file %s is valid.\n", claimFilePath)
+		return nil
+	}
+
+	for _, validationError := range validationErrors {
+		fmt.Fprintln(w, validationError)
+	}
+
+	return fmt.Errorf("claim file %s is not valid: %d errors found", claimFilePath, len(validationErrors))
+}
+
+func validateClaimFile(claimFilePath string) ([]ValidationError, error) {
+	data, err := os.ReadFile(claimFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read claim file %s: %v", claimFilePath, err)
+	}
+
+	return validateClaim(data)
+}
+
+// Returns the line and column of a byte offset in the data.
+func getPosition(data []byte, offset int64) (line, column int) {
+	line, column = 1, 1
+	for _, b := range data[:min(offset, int64(len(data)))] {
+		if b == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	return line, column
+}
+
+// Returns the claim format version, or the validation error found while getting it.
+func getClaimFormatVersion(data []byte) (string, *ValidationError) {
+	root := struct {
+		Claim struct {
+			Versions struct {
+				ClaimFormat string `json:"claimFormat"`
+			} `json:"versions"`
+		} `json:"claim"`
+	}{}
+
+	err := json.Unmarshal(data, &root)
+	var syntaxError *json.SyntaxError
+	var typeError *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxError):
+		line, column := getPosition(data, syntaxError.Offset)
+		return "", &ValidationError{Field: "(root)", Description: fmt.Sprintf("invalid JSON at line %d, column %d: %v", line, column, err)}
+	case errors.As(err, &typeError):
+		line, column := getPosition(data, typeError.Offset)
+		return "", &ValidationError{Field: "claim." + typeError.Field,
+			Description: fmt.Sprintf("invalid type %s at line %d, column %d, expected %s", typeError.Value, line, column, typeError.Type)}
+	case err != nil:
+		return "", &ValidationError{Field: "(root)", Description: fmt.Sprintf("invalid JSON: %v", err)}
+	}
+
+	if root.Claim.Versions.ClaimFormat == "" {
+		return "", &ValidationError{Field: "claim.versions.claimFormat", Description: "claim format version is required"}
+	}
+
+	return root.Claim.Versions.ClaimFormat, nil
+}
+
+// Returns the claim format versions that have a schema.
+func getSupportedVersions() []string {
+	entries, _ := schemas.ReadDir(schemasDir)
+	versions := []string{}
+	for _, entry := range entries {
+		versions = append(versions, strings.TrimSuffix(strings.TrimPrefix(entry.Name(), "claim-"), ".schema.json"))
+	}
+
+	sort.Strings(versions)
+	return versions
+}
+
+func validateClaim(data []byte) ([]ValidationError, error) {
+	version, validationError := getClaimFormatVersion(data)
+	if validationError != nil {
+		return []ValidationError{*validationError}, nil
+	}
+
+	schema, err := schemas.ReadFile(schemasDir + "/" + fmt.Sprintf(schemaFileFmt, version))
+	if err != nil {
+		return []ValidationError{{
+			Field: "claim.versions.claimFormat",
+			Description: fmt.Sprintf("unsupported claim format version %s, supported versions: %s",
+				version, strings.Join(getSupportedVersions(), ", ")),
+		}}, nil
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate claim against the %s schema: %v", version, err)
+	}
+
+	validationErrors := []ValidationError{}
+	for _, schemaError := range result.Errors() {
+		validationErrors = append(validationErrors, ValidationError{Field: schemaError.Field(), Description: schemaError.Description()})
+	}
+
+	// The cross-checks need a claim that follows the schema.
+	if len(validationErrors) > 0 {
+		return validationErrors, nil
+	}
+
+	root := officialClaimScheme.Root{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal claim: %v", err)
+	}
+
+	return checkClaim(root.Claim), nil
+}
+
+// Checks the claim fields that can't be checked with the JSON schema: the metadata times and
+// the test cases of the results, which must exist in the catalog.
+func checkClaim(c *officialClaimScheme.Claim) []ValidationError {
+	validationErrors := []ValidationError{}
+
+	for field, value := range map[string]string{"startTime": c.Metadata.StartTime, "endTime": c.Metadata.EndTime} {
+		if _, err := claim.ParseMetadataTime(value); err != nil {
+			validationErrors = append(validationErrors, ValidationError{Field: "claim.metadata." + field, Description: err.Error()})
+		}
+	}
+
+	catalogSuites := map[string]string{}
+	for id := range identifiers.Catalog {
+		catalogSuites[id.Id] = id.Suite
+	}
+
+	for testID := range c.Results {
+		result := c.Results[testID]
+		field := "claim.results." + testID + ".testID"
+		if result.TestID.Id != testID {
+			validationErrors = append(validationErrors, ValidationError{Field: field + ".id",
+				Description: fmt.Sprintf("test case id %s does not match its results key", result.TestID.Id)})
+		}
+
+		// The preflight checks are added to the catalog at run time.
+		if result.TestID.Suite == common.PreflightTestKey {
+			continue
+		}
+
+		suite, found := catalogSuites[testID]
+		switch {
+		case !found:
+			validationErrors = append(validationErrors, ValidationError{Field: field,
+				Description: fmt.Sprintf("test case %s not found in the catalog", testID)})
+		case suite != result.TestID.Suite:
+			validationErrors = append(validationErrors, ValidationError{Field: field + ".suite",
+				Description: fmt.Sprintf("test case %s belongs to suite %s, not %s", testID, suite, result.TestID.Suite)})
+		}
+	}
+
+	sort.Slice(validationErrors, func(i, j int) bool {
+		return validationErrors[i].Field < validationErrors[j].Field
+	})
+
+	return validationErrors
+}
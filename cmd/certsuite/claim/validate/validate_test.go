@@ -0,0 +1,69 @@
+package validate
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateClaimFile(t *testing.T) {
+	testCases := []struct {
+		claimFile      string
+		expectedErrors []ValidationError
+	}{
+		{
+			claimFile:      "testdata/claim_valid.json",
+			expectedErrors: []ValidationError{},
+		},
+		{
+			claimFile: "testdata/claim_syntax_error.json",
+			expectedErrors: []ValidationError{
+				{Field: "(root)", Description: "invalid JSON at line 78, column 19: invalid character 'p' looking for beginning of value"},
+			},
+		},
+		{
+			claimFile: "testdata/claim_unsupported_version.json",
+			expectedErrors: []ValidationError{
+				{Field: "claim.versions.claimFormat", Description: "unsupported claim format version v9.9.9, supported versions: v0.4.0"},
+			},
+		},
+		{
+			claimFile: "testdata/claim_schema_errors.json",
+			expectedErrors: []ValidationError{
+				{Field: "claim.metadata", Description: "endTime is required"},
+				{Field: "claim.results.networking-icmpv4-connectivity.state", Description: `claim.results.networking-icmpv4-connectivity.state must be one of the following: "passed", "failed", "skipped", "error", "aborted"`},
+				{Field: "claim.results.observability-container-logging.duration", Description: "Invalid type. Expected: integer, given: string"},
+			},
+		},
+		{
+			claimFile: "testdata/claim_catalog_errors.json",
+			expectedErrors: []ValidationError{
+				{Field: "claim.metadata.startTime", Description: `invalid claim time "yesterday"`},
+				{Field: "claim.results.access-control-sys-admin-capability-check.testID.id", Description: "test case id access-control-other-check does not match its results key"},
+				{Field: "claim.results.networking-unknown-check.testID", Description: "test case networking-unknown-check not found in the catalog"},
+				{Field: "claim.results.observability-container-logging.testID.suite", Description: "test case observability-container-logging belongs to suite observability, not networking"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		validationErrors, err := validateClaimFile(tc.claimFile)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, tc.expectedErrors, validationErrors, tc.claimFile)
+	}
+
+	_, err := validateClaimFile("testdata/non-existent.json")
+	assert.NotNil(t, err)
+}
+
+func TestWriteValidation(t *testing.T) {
+	var out bytes.Buffer
+	assert.Nil(t, writeValidation("testdata/claim_valid.json", &out))
+	assert.Equal(t, "Claim file testdata/claim_valid.json is valid.\n", out.String())
+
+	out.Reset()
+	err := writeValidation("testdata/claim_unsupported_version.json", &out)
+	assert.Equal(t, "claim file testdata/claim_unsupported_version.json is not valid: 1 errors found", err.Error())
+	assert.Equal(t, "claim.versions.claimFormat: unsupported claim format version v9.9.9, supported versions: v0.4.0\n", out.String())
+}
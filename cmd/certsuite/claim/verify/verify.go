@@ -0,0 +1,58 @@
+package verify
+
+import (
+	"fmt"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/claimhelper"
+	"github.com/spf13/cobra"
+)
+
+var (
+	publicKeyFlag     string
+	signatureFileFlag string
+
+	claimVerifyCommand = &cobra.Command{
+		Use:   "verify <claim.json>",
+		Short: "Verifies the signature of a claim file.",
+		Long: `Verifies that a claim file was signed with the private key of the given public key and that neither the
+claim nor its signature were modified afterwards. The claim files are signed at the end of the run when
+the --claim-signing-key flag of "certsuite run" is set.
+`,
+		Example: `certsuite claim verify claim.json --public-key key.pub.pem`,
+		Args:    cobra.ExactArgs(1),
+		RunE:    claimVerify,
+	}
+)
+
+func NewCommand() *cobra.Command {
+	claimVerifyCommand.Flags().StringVar(&publicKeyFlag, "public-key", "",
+		"PEM file with the ed25519 public key that verifies the signature.",
+	)
+	claimVerifyCommand.Flags().StringVar(&signatureFileFlag, "signature", "",
+		"Signature file of the claim. Defaults to the claim file path with the "+claimhelper.SignatureFileExtension+" extension.",
+	)
+
+	err := claimVerifyCommand.MarkFlagRequired("public-key")
+	if err != nil {
+		log.Error("Failed to mark flag public-key as required: %v", err)
+		return nil
+	}
+
+	return claimVerifyCommand
+}
+
+func claimVerify(_ *cobra.Command, args []string) error {
+	claimFile := args[0]
+	signatureFile := signatureFileFlag
+	if signatureFile == "" {
+		signatureFile = claimFile + claimhelper.SignatureFileExtension
+	}
+
+	if err := claimhelper.VerifyClaimFile(claimFile, signatureFile, publicKeyFlag); err != nil {
+		return fmt.Errorf("claim file %s verification failed: %v", claimFile, err)
+	}
+
+	fmt.Printf("Claim file %s verified: signature %s is valid.\n", claimFile, signatureFile)
+	return nil
+}
@@ -0,0 +1,25 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimVerify(t *testing.T) {
+	publicKeyFlag = "testdata/key.pub.pem"
+
+	signatureFileFlag = ""
+	assert.Nil(t, claimVerify(nil, []string{"testdata/claim.json"}))
+
+	signatureFileFlag = "testdata/claim.json.sig"
+	err := claimVerify(nil, []string{"testdata/claim_tampered.json"})
+	assert.NotNil(t, err)
+	assert.Equal(t, "claim file testdata/claim_tampered.json verification failed: claim file testdata/claim_tampered.json was modified after it was signed", err.Error())
+
+	// The default signature file does not exist.
+	signatureFileFlag = ""
+	err = claimVerify(nil, []string{"testdata/claim_tampered.json"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "failed to read claim signature file testdata/claim_tampered.json.sig")
+}
@@ -34,6 +34,8 @@ import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/arrayhelper"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/common"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/plugins"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/policies"
 
 	"github.com/spf13/cobra"
 )
@@ -58,6 +60,11 @@ var (
 		Short: "Generates the test catalog in markdown format.",
 		RunE:  runGenerateMarkdownCmd,
 	}
+
+	// Folder with the plugins whose checks are added to the catalog.
+	pluginsDir string
+	// File with the policies that are added to the catalog.
+	policiesFile string
 )
 
 type Entry struct {
@@ -182,6 +189,20 @@ func outputTestCases() (outString string, summary catalogSummary) { //nolint:fun
 	// Adds Preflight tests to catalog
 	addPreflightTestsToCatalog()
 
+	// Adds the plugins' checks to catalog
+	if pluginsDir != "" {
+		if err := plugins.AddCatalogEntries(pluginsDir); err != nil {
+			log.Error("Failed to add the plugins' checks to the catalog: %v", err)
+		}
+	}
+
+	// Adds the policies to catalog
+	if policiesFile != "" {
+		if err := policies.AddCatalogEntries(policiesFile); err != nil {
+			log.Error("Failed to add the policies to the catalog: %v", err)
+		}
+	}
+
 	// Building a separate data structure to store the key order for the map
 	keys := make([]claim.Identifier, 0, len(identifiers.Catalog))
 	for k := range identifiers.Catalog {
@@ -394,6 +415,8 @@ func runGenerateMarkdownCmd(_ *cobra.Command, _ []string) error {
 
 // Execute executes the "catalog" CLI.
 func NewCommand() *cobra.Command {
+	markdownGenerateCmd.Flags().StringVar(&pluginsDir, "plugins-dir", "", "Folder with the executables of the plugins providing custom checks to add to the catalog")
+	markdownGenerateCmd.Flags().StringVar(&policiesFile, "policies-file", "", "YAML file with custom checks defined as CEL expressions to add to the catalog")
 	generateCmd.AddCommand(markdownGenerateCmd)
 
 	generateCmd.AddCommand(markdownGenerateClassification)
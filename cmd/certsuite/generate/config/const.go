@@ -65,7 +65,9 @@ If a new label is used for this purpose make sure it is added to the CNF's Pods,
 ideally in the pod's definition as the on-the-fly labels are lost if the Pod gets
 rescheduled.
 For Pods own by a Deployment, the same label as the one defined in the
-"spec.selector.matchLabels" section of the Deployment can be used.`
+"spec.selector.matchLabels" section of the Deployment can be used.
+Label selectors with several requirements, e.g. "app in (a,b),tier!=debug", can be
+set by editing the generated config file.`
 
 	operatorLabelsHelp = `The labels that each operator's CSV of the CNF under test must have to be verified
 by the CNF Certification Suite.
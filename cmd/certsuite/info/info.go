@@ -10,6 +10,8 @@ import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/certsuite"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/plugins"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/policies"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
@@ -28,9 +30,11 @@ var (
 func showInfo(cmd *cobra.Command, _ []string) error {
 	testCaseFlag, _ := cmd.Flags().GetString("test-label")
 	listFlag, _ := cmd.Flags().GetBool("list")
+	pluginsDir, _ := cmd.Flags().GetString("plugins-dir")
+	policiesFile, _ := cmd.Flags().GetString("policies-file")
 
 	// Get a list of matching test cases names
-	testIDs, err := getMatchingTestIDs(testCaseFlag)
+	testIDs, err := getMatchingTestIDs(testCaseFlag, pluginsDir, policiesFile)
 	if err != nil {
 		return fmt.Errorf("could not get the matching test case list, err: %v", err)
 	}
@@ -61,6 +65,8 @@ func showInfo(cmd *cobra.Command, _ []string) error {
 func NewCommand() *cobra.Command {
 	infoCmd.PersistentFlags().StringP("test-label", "t", "", "The test label filter to select the test cases to show information about")
 	infoCmd.PersistentFlags().BoolP("list", "l", false, "Show only the names of the test cases for a given test label")
+	infoCmd.PersistentFlags().String("plugins-dir", "", "Folder with the executables of the plugins providing custom checks")
+	infoCmd.PersistentFlags().String("policies-file", "", "YAML file with custom checks defined as CEL expressions")
 	err := infoCmd.MarkPersistentFlagRequired("test-label")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Could not mark persistent flag \"test-case\" as required, err: %v", err)
@@ -121,11 +127,21 @@ func printTestList(testIDs []string) {
 	fmt.Println("------------------------------------------------------------")
 }
 
-func getMatchingTestIDs(labelExpr string) ([]string, error) {
+func getMatchingTestIDs(labelExpr, pluginsDir, policiesFile string) ([]string, error) {
 	if err := checksdb.InitLabelsExprEvaluator(labelExpr); err != nil {
 		return nil, fmt.Errorf("failed to initialize a test case label evaluator, err: %v", err)
 	}
 	certsuite.LoadInternalChecksDB()
+	if pluginsDir != "" {
+		if err := plugins.LoadChecks(pluginsDir); err != nil {
+			return nil, fmt.Errorf("could not load the plugins' checks, err: %v", err)
+		}
+	}
+	if policiesFile != "" {
+		if err := policies.LoadChecks(policiesFile); err != nil {
+			return nil, fmt.Errorf("could not load the policies, err: %v", err)
+		}
+	}
 	testIDs, err := checksdb.FilterCheckIDs()
 	if err != nil {
 		return nil, fmt.Errorf("could not list test cases, err: %v", err)
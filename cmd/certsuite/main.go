@@ -11,6 +11,7 @@ import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/generate"
 	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/info"
 	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/run"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/snapshot"
 	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/version"
 )
 
@@ -24,6 +25,7 @@ func newRootCmd() *cobra.Command {
 	rootCmd.AddCommand(generate.NewCommand())
 	rootCmd.AddCommand(check.NewCommand())
 	rootCmd.AddCommand(run.NewCommand())
+	rootCmd.AddCommand(snapshot.NewCommand())
 	rootCmd.AddCommand(info.NewCommand())
 	rootCmd.AddCommand(version.NewCommand())
 
@@ -3,7 +3,10 @@ package claim
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	officialClaimScheme "github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
@@ -11,6 +14,10 @@ import (
 
 const (
 	supportedClaimFormatVersion = "v0.4.0"
+	// Format of the claim metadata times, same as claimhelper.DateTimeFormatDirective.
+	metadataTimeFormat = "2006-01-02 15:04:05 -0700 MST"
+
+	outputFilePerms = 0o644
 )
 
 const (
@@ -74,6 +81,7 @@ type Configurations struct {
 	Config         interface{}    `json:"Config"`
 	AbnormalEvents []interface{}  `json:"AbnormalEvents"`
 	TestOperators  []TestOperator `json:"testOperators"`
+	ChecksOrder    []string       `json:"checksOrder,omitempty"`
 }
 
 type Schema struct {
@@ -94,9 +102,21 @@ type Schema struct {
 
 		Results  TestSuiteResults             `json:"results"`
 		Versions officialClaimScheme.Versions `json:"versions"`
+		Metadata officialClaimScheme.Metadata `json:"metadata"`
 	} `json:"claim"`
 }
 
+// ParseMetadataTime parses the start/end time of the claim metadata. Old claim files used RFC3339.
+func ParseMetadataTime(value string) (time.Time, error) {
+	for _, layout := range []string{metadataTimeFormat, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid claim time %q", value)
+}
+
 func CheckVersion(version string) error {
 	claimSemVersion, err := semver.NewVersion(version)
 	if err != nil {
@@ -130,3 +150,22 @@ func Parse(filePath string) (*Schema, error) {
 
 	return &claimFile, nil
 }
+
+// WriteOutput calls write with the standard output or, if outputFile is set, with a buffer that
+// is then saved to that file. Nothing is saved if write fails.
+func WriteOutput(outputFile string, write func(w io.Writer) error) error {
+	if outputFile == "" {
+		return write(os.Stdout)
+	}
+
+	var output strings.Builder
+	if err := write(&output); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(outputFile, []byte(output.String()), outputFilePerms); err != nil {
+		return fmt.Errorf("failed to write output file %s: %v", outputFile, err)
+	}
+
+	return nil
+}
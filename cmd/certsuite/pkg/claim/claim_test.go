@@ -1,7 +1,13 @@
 package claim
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -41,3 +47,37 @@ func TestIsClaimFormatVersionSupported(t *testing.T) {
 		}
 	}
 }
+
+func TestParseMetadataTime(t *testing.T) {
+	parsedTime, err := ParseMetadataTime("2024-01-10 10:00:00 +0000 UTC")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC), parsedTime.UTC())
+
+	parsedTime, err = ParseMetadataTime("2023-09-04T14:19:06+00:00")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2023, 9, 4, 14, 19, 6, 0, time.UTC), parsedTime.UTC())
+
+	_, err = ParseMetadataTime("yesterday")
+	assert.NotNil(t, err)
+}
+
+func TestWriteOutput(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "output.txt")
+
+	err := WriteOutput(outputFile, func(w io.Writer) error {
+		_, err := fmt.Fprint(w, "some output")
+		return err
+	})
+	assert.Nil(t, err)
+	contents, err := os.ReadFile(outputFile)
+	assert.Nil(t, err)
+	assert.Equal(t, "some output", string(contents))
+
+	// Nothing is saved if the output can't be written.
+	failedOutputFile := filepath.Join(t.TempDir(), "failed.txt")
+	err = WriteOutput(failedOutputFile, func(w io.Writer) error {
+		return errors.New("write failed")
+	})
+	assert.EqualError(t, err, "write failed")
+	assert.NoFileExists(t, failedOutputFile)
+}
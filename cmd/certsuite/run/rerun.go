@@ -0,0 +1,72 @@
+package run
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/claim/compare/testcases"
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/pkg/claim"
+)
+
+// Returns the sorted IDs of the checks that failed in the claim.
+func getFailedCheckIDs(claimScheme *claim.Schema) []string {
+	failedCheckIDs := []string{}
+	//nolint:gocritic
+	for _, result := range claimScheme.Claim.Results {
+		if result.State == claim.TestCaseResultFailed {
+			failedCheckIDs = append(failedCheckIDs, result.TestID.ID)
+		}
+	}
+
+	sort.Strings(failedCheckIDs)
+	return failedCheckIDs
+}
+
+// Every check has its own ID as label, so the labels filter only needs to OR them.
+func getCheckIDsLabelsFilter(checkIDs []string) string {
+	return strings.Join(checkIDs, " || ")
+}
+
+// Returns the results of the given check IDs only.
+func filterResultsByCheckIDs(results claim.TestSuiteResults, checkIDs []string) claim.TestSuiteResults {
+	checkIDsSet := map[string]bool{}
+	for _, checkID := range checkIDs {
+		checkIDsSet[checkID] = true
+	}
+
+	filteredResults := claim.TestSuiteResults{}
+	//nolint:gocritic
+	for key, result := range results {
+		if checkIDsSet[result.TestID.ID] {
+			filteredResults[key] = result
+		}
+	}
+
+	return filteredResults
+}
+
+// Returns the diff report between the results of the checks that failed in the previous
+// run and their results after being re-run.
+func getRerunDeltaReport(previousClaim, newClaim *claim.Schema, failedCheckIDs []string) *testcases.DiffReport {
+	return testcases.GetDiffReport(
+		filterResultsByCheckIDs(previousClaim.Claim.Results, failedCheckIDs),
+		filterResultsByCheckIDs(newClaim.Claim.Results, failedCheckIDs))
+}
+
+func printRerunDeltaReport(previousClaim *claim.Schema, previousClaimFilePath, newClaimFilePath string, failedCheckIDs []string) error {
+	newClaim, err := claim.Parse(newClaimFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse claim file %s: %v", newClaimFilePath, err)
+	}
+
+	report := getRerunDeltaReport(previousClaim, newClaim, failedCheckIDs)
+
+	fmt.Printf("\nRE-RUN OF FAILED CHECKS\n")
+	fmt.Printf("-----------------------\n")
+	fmt.Printf("Fixed checks: %d of %d (CLAIM-1: %s, CLAIM-2: %s)\n\n", report.Claim2ResultsSummary.Passed, len(failedCheckIDs),
+		previousClaimFilePath, newClaimFilePath)
+	fmt.Println(report)
+
+	return nil
+}
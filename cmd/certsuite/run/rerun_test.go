@@ -0,0 +1,55 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/pkg/claim"
+	"github.com/stretchr/testify/assert"
+)
+
+func newClaimWithResults(results map[string]string) *claim.Schema {
+	claimScheme := &claim.Schema{}
+	claimScheme.Claim.Results = claim.TestSuiteResults{}
+	for checkID, state := range results {
+		result := claim.TestCaseResult{State: state}
+		result.TestID.ID = checkID
+		claimScheme.Claim.Results[checkID] = result
+	}
+
+	return claimScheme
+}
+
+func TestGetFailedCheckIDs(t *testing.T) {
+	claimScheme := newClaimWithResults(map[string]string{
+		"check3": claim.TestCaseResultFailed,
+		"check1": claim.TestCaseResultFailed,
+		"check2": claim.TestCaseResultPassed,
+		"check4": claim.TestCaseResultSkipped,
+	})
+
+	failedCheckIDs := getFailedCheckIDs(claimScheme)
+	assert.Equal(t, []string{"check1", "check3"}, failedCheckIDs)
+	assert.Equal(t, "check1 || check3", getCheckIDsLabelsFilter(failedCheckIDs))
+}
+
+func TestGetRerunDeltaReport(t *testing.T) {
+	previousClaim := newClaimWithResults(map[string]string{
+		"check1": claim.TestCaseResultFailed,
+		"check2": claim.TestCaseResultFailed,
+		"check3": claim.TestCaseResultPassed,
+	})
+
+	// Checks that were not re-run appear as skipped in the new claim.
+	newClaim := newClaimWithResults(map[string]string{
+		"check1": claim.TestCaseResultPassed,
+		"check2": claim.TestCaseResultFailed,
+		"check3": claim.TestCaseResultSkipped,
+	})
+
+	report := getRerunDeltaReport(previousClaim, newClaim, []string{"check1", "check2"})
+	assert.Equal(t, 2, report.Claim1ResultsSummary.Failed)
+	assert.Equal(t, 1, report.Claim2ResultsSummary.Passed)
+	assert.Equal(t, 1, report.Claim2ResultsSummary.Failed)
+	assert.Equal(t, 1, report.DifferentTestCasesResults)
+	assert.Equal(t, "check1", report.TestCases[0].Name)
+}
@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/pkg/claim"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/certsuite"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/claimhelper"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
 	"github.com/redhat-best-practices-for-k8s/certsuite/webserver"
 	"github.com/spf13/cobra"
@@ -39,6 +43,13 @@ func NewCommand() *cobra.Command {
 	runCmd.PersistentFlags().Bool("include-web-files", false, "Save web files in the configured output folder")
 	runCmd.PersistentFlags().Bool("enable-data-collection", false, "Allow sending test results to an external data collector")
 	runCmd.PersistentFlags().Bool("create-xml-junit-file", false, "Create a JUnit file with the test results")
+	runCmd.PersistentFlags().Bool("expand-xml-junit-file", false, "Create a JUnit testcase for each object checked by the test cases, instead of one for each test case. Used with --create-xml-junit-file")
+	runCmd.PersistentFlags().Bool("create-sarif-file", false, "Create a SARIF file with the test results")
+	runCmd.PersistentFlags().String("events-file", "", "Write the run progress events to this file as JSON Lines. Use \"-\" for the standard output")
+	runCmd.PersistentFlags().String("otlp-endpoint", "", "URL of an OTLP/gRPC collector to send the traces of the run to (e.g. http://localhost:4317)")
+	runCmd.PersistentFlags().String("traces-file", "", "Write the traces of the run to this file, one JSON span per line")
+	runCmd.PersistentFlags().String("metrics-textfile", "", "Write the Prometheus metrics of the run to this file, in the format of the node exporter's textfile collector")
+	runCmd.PersistentFlags().String("metrics-pushgateway", "", "URL of a Prometheus Pushgateway to push the metrics of the run to")
 	runCmd.PersistentFlags().String("tnf-image-repository", "quay.io/redhat-best-practices-for-k8s", "The repository where TNF images are stored")
 	runCmd.PersistentFlags().String("tnf-debug-image", "certsuite-probe:v0.0.5", "Name of the certsuite-probe image")
 	runCmd.PersistentFlags().String("daemonset-cpu-req", "100m", "CPU request for the debug DaemonSet container")
@@ -46,6 +57,22 @@ func NewCommand() *cobra.Command {
 	runCmd.PersistentFlags().String("daemonset-mem-req", "100M", "Memory request for the debug DaemonSet container")
 	runCmd.PersistentFlags().String("daemonset-mem-lim", "100M", "Memory limit for the debug DaemonSet container")
 	runCmd.PersistentFlags().Bool("sanitize-claim", false, "Sanitize the claim.json file before sending it to the collector")
+	runCmd.PersistentFlags().String("claim-signing-key", "", "PEM file with the ed25519 private key used to sign the claim file. The signature is saved in the claim.json.sig file")
+	runCmd.PersistentFlags().Int("parallelism", 1, "Maximum number of non-intrusive checks that can run at the same time")
+	runCmd.PersistentFlags().String("resume-from", "", "Claim file of a previous interrupted run. Only its aborted, errored or missing checks will run again")
+	runCmd.PersistentFlags().String("rerun-failed", "", "Claim file of a previous run. Only its failed checks will run again")
+	runCmd.PersistentFlags().String("waivers-file", "", "YAML file with the waivers to accept known non-compliant objects")
+	runCmd.PersistentFlags().Bool("fail-on-expired-waivers", false, "Do not run the checks if the waivers file has expired waivers")
+	runCmd.PersistentFlags().String("from-snapshot", "", "Cluster snapshot archive created with \"certsuite snapshot capture\" to run the checks against, instead of a live cluster")
+	runCmd.PersistentFlags().String("plugins-dir", "", "Folder with the executables of the plugins providing custom checks")
+	runCmd.PersistentFlags().String("policies-file", "", "YAML file with custom checks defined as CEL expressions")
+	runCmd.PersistentFlags().String("exit-code-policy", certsuite.ExitCodePolicyNone, "Sets when the run exits with a non-zero code: \"none\", \"failures\" (exit code 2 if any check failed) or \"score\" (exit code 3 if the readiness score gate did not pass)")
+	runCmd.PersistentFlags().Bool("record-execs", false, "Record the outputs of all the commands run in the containers in the exec-records.jsonl file of the output folder")
+
+	runCmd.MarkFlagsMutuallyExclusive("rerun-failed", "label-filter")
+	runCmd.MarkFlagsMutuallyExclusive("rerun-failed", "resume-from")
+	runCmd.MarkFlagsMutuallyExclusive("from-snapshot", "kubeconfig")
+	runCmd.MarkFlagsMutuallyExclusive("from-snapshot", "record-execs")
 
 	return runCmd
 }
@@ -68,6 +95,13 @@ func initTestParamsFromFlags(cmd *cobra.Command) error {
 	testParams.IncludeWebFilesInOutputFolder, _ = cmd.Flags().GetBool("include-web-files")
 	testParams.EnableDataCollection, _ = cmd.Flags().GetBool("enable-data-collection")
 	testParams.EnableXMLCreation, _ = cmd.Flags().GetBool("create-xml-junit-file")
+	testParams.ExpandXMLTestCases, _ = cmd.Flags().GetBool("expand-xml-junit-file")
+	testParams.EnableSARIFCreation, _ = cmd.Flags().GetBool("create-sarif-file")
+	testParams.EventsFile, _ = cmd.Flags().GetString("events-file")
+	testParams.OTLPEndpoint, _ = cmd.Flags().GetString("otlp-endpoint")
+	testParams.TracesFile, _ = cmd.Flags().GetString("traces-file")
+	testParams.MetricsTextfile, _ = cmd.Flags().GetString("metrics-textfile")
+	testParams.MetricsPushgatewayURL, _ = cmd.Flags().GetString("metrics-pushgateway")
 	testParams.TnfImageRepo, _ = cmd.Flags().GetString("tnf-image-repository")
 	testParams.TnfDebugImage, _ = cmd.Flags().GetString("tnf-debug-image")
 	testParams.DaemonsetCPUReq, _ = cmd.Flags().GetString("daemonset-cpu-req")
@@ -75,6 +109,17 @@ func initTestParamsFromFlags(cmd *cobra.Command) error {
 	testParams.DaemonsetMemReq, _ = cmd.Flags().GetString("daemonset-mem-req")
 	testParams.DaemonsetMemLim, _ = cmd.Flags().GetString("daemonset-mem-lim")
 	testParams.SanitizeClaim, _ = cmd.Flags().GetBool("sanitize-claim")
+	testParams.ClaimSigningKey, _ = cmd.Flags().GetString("claim-signing-key")
+	testParams.ExitCodePolicy, _ = cmd.Flags().GetString("exit-code-policy")
+	testParams.Parallelism, _ = cmd.Flags().GetInt("parallelism")
+	testParams.ResumeFrom, _ = cmd.Flags().GetString("resume-from")
+	testParams.RerunFailed, _ = cmd.Flags().GetString("rerun-failed")
+	testParams.WaiversFile, _ = cmd.Flags().GetString("waivers-file")
+	testParams.FailOnExpiredWaivers, _ = cmd.Flags().GetBool("fail-on-expired-waivers")
+	testParams.FromSnapshot, _ = cmd.Flags().GetString("from-snapshot")
+	testParams.RecordExecs, _ = cmd.Flags().GetBool("record-execs")
+	testParams.PluginsDir, _ = cmd.Flags().GetString("plugins-dir")
+	testParams.PoliciesFile, _ = cmd.Flags().GetString("policies-file")
 	timeoutStr, _ := cmd.Flags().GetString("timeout")
 
 	// Check if the output directory exists and, if not, create it
@@ -88,6 +133,17 @@ func initTestParamsFromFlags(cmd *cobra.Command) error {
 		return fmt.Errorf("could not check directory %q, err: %v", testParams.OutputDir, err)
 	}
 
+	if !certsuite.IsValidExitCodePolicy(testParams.ExitCodePolicy) {
+		return fmt.Errorf("invalid exit code policy %q, valid policies: %v", testParams.ExitCodePolicy, certsuite.ExitCodePolicies)
+	}
+
+	// Fail before running the checks if the claim can't be signed at the end
+	if testParams.ClaimSigningKey != "" {
+		if _, err := claimhelper.ReadSigningKey(testParams.ClaimSigningKey); err != nil {
+			return fmt.Errorf("invalid claim signing key, err: %v", err)
+		}
+	}
+
 	// Process the timeout flag
 	const timeoutDefaultvalue = 24 * time.Hour
 	timeout, err := time.ParseDuration(timeoutStr)
@@ -106,10 +162,29 @@ func runTestSuite(cmd *cobra.Command, _ []string) error {
 		log.Fatal("Failed to initialize the test parameters, err: %v", err)
 	}
 
+	testParams := configuration.GetTestParameters()
+
+	// Only the checks that failed in the previous run will run again.
+	var previousClaim *claim.Schema
+	var failedCheckIDs []string
+	if testParams.RerunFailed != "" {
+		previousClaim, err = claim.Parse(testParams.RerunFailed)
+		if err != nil {
+			log.Fatal("Failed to parse claim file %s, err: %v", testParams.RerunFailed, err)
+		}
+
+		failedCheckIDs = getFailedCheckIDs(previousClaim)
+		if len(failedCheckIDs) == 0 {
+			fmt.Printf("No failed checks found in claim file %s\n", testParams.RerunFailed)
+			return nil
+		}
+
+		testParams.LabelsFilter = getCheckIDsLabelsFilter(failedCheckIDs)
+	}
+
 	certsuite.Startup()
 	defer certsuite.Shutdown()
 
-	testParams := configuration.GetTestParameters()
 	if testParams.ServerMode {
 		log.Info("Running CNF Certification Suite in web server mode")
 		webserver.StartServer(testParams.OutputDir)
@@ -119,6 +194,20 @@ func runTestSuite(cmd *cobra.Command, _ []string) error {
 		if err != nil {
 			log.Fatal("Failed to run CNF Certification Suite: %v", err) //nolint:gocritic // exitAfterDefer
 		}
+
+		if previousClaim != nil {
+			newClaimFilePath := filepath.Join(testParams.OutputDir, certsuite.ClaimFileName)
+			if err := printRerunDeltaReport(previousClaim, testParams.RerunFailed, newClaimFilePath, failedCheckIDs); err != nil {
+				log.Error("Failed to print the re-run delta report: %v", err)
+			}
+		}
+
+		exitCode := certsuite.GetExitCode(testParams.ExitCodePolicy, checksdb.GetReconciledResults(), checksdb.GetScore())
+		if exitCode != 0 {
+			log.Warn("Exiting with code %d because of the %q exit code policy", exitCode, testParams.ExitCodePolicy)
+			certsuite.Shutdown()
+			os.Exit(exitCode) //nolint:gocritic // exitAfterDefer, the shutdown was done
+		}
 	}
 
 	return nil
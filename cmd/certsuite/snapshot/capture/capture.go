@@ -0,0 +1,85 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/certsuite"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/spf13/cobra"
+)
+
+const (
+	timeoutFlagDefaultvalue = 24 * time.Hour
+	dirPerm                 = 0o755
+)
+
+var (
+	captureCommand = &cobra.Command{
+		Use:   "capture",
+		Short: "Captures a snapshot of the workload under test so the checks can run later without the cluster.",
+		Long: `Discovers the workload under test as the run command does and runs the non-intrusive checks only to record
+the outputs of the commands they run in the containers. Everything is saved in the output folder in the
+` + certsuite.SnapshotFileName + ` archive, which can be used later with "certsuite run --from-snapshot".`,
+		Example: `certsuite snapshot capture --config-file config/tnf_config.yml --output-dir snapshot`,
+		RunE:    runCapture,
+	}
+)
+
+func NewCommand() *cobra.Command {
+	captureCommand.Flags().StringP("output-dir", "o", "results", "The directory where the snapshot archive and the log file will be placed")
+	captureCommand.Flags().StringP("label-filter", "l", "all", "Label expression to filter the checks whose command outputs will be recorded")
+	captureCommand.Flags().String("timeout", timeoutFlagDefaultvalue.String(), "Time allowed for the checks to complete (e.g. --timeout 30m  or -timeout 1h30m)")
+	captureCommand.Flags().StringP("config-file", "c", "config/tnf_config.yml", "The workload configuration file")
+	captureCommand.Flags().StringP("kubeconfig", "k", "", "The target cluster's Kubeconfig file")
+	captureCommand.Flags().String("log-level", "debug", "Sets the log level")
+	captureCommand.Flags().String("tnf-image-repository", "quay.io/redhat-best-practices-for-k8s", "The repository where TNF images are stored")
+	captureCommand.Flags().String("tnf-debug-image", "certsuite-probe:v0.0.5", "Name of the certsuite-probe image")
+	captureCommand.Flags().String("daemonset-cpu-req", "100m", "CPU request for the debug DaemonSet container")
+	captureCommand.Flags().String("daemonset-cpu-lim", "100m", "CPU limit for the debug DaemonSet container")
+	captureCommand.Flags().String("daemonset-mem-req", "100M", "Memory request for the debug DaemonSet container")
+	captureCommand.Flags().String("daemonset-mem-lim", "100M", "Memory limit for the debug DaemonSet container")
+	captureCommand.Flags().Int("parallelism", 1, "Maximum number of checks that can run at the same time")
+
+	return captureCommand
+}
+
+func initTestParamsFromFlags(cmd *cobra.Command) error {
+	testParams := configuration.GetTestParameters()
+
+	testParams.OutputDir, _ = cmd.Flags().GetString("output-dir")
+	testParams.LabelsFilter, _ = cmd.Flags().GetString("label-filter")
+	testParams.ConfigFile, _ = cmd.Flags().GetString("config-file")
+	testParams.Kubeconfig, _ = cmd.Flags().GetString("kubeconfig")
+	testParams.LogLevel, _ = cmd.Flags().GetString("log-level")
+	testParams.TnfImageRepo, _ = cmd.Flags().GetString("tnf-image-repository")
+	testParams.TnfDebugImage, _ = cmd.Flags().GetString("tnf-debug-image")
+	testParams.DaemonsetCPUReq, _ = cmd.Flags().GetString("daemonset-cpu-req")
+	testParams.DaemonsetCPULim, _ = cmd.Flags().GetString("daemonset-cpu-lim")
+	testParams.DaemonsetMemReq, _ = cmd.Flags().GetString("daemonset-mem-req")
+	testParams.DaemonsetMemLim, _ = cmd.Flags().GetString("daemonset-mem-lim")
+	testParams.Parallelism, _ = cmd.Flags().GetInt("parallelism")
+	timeoutStr, _ := cmd.Flags().GetString("timeout")
+
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse timeout flag %q, err: %v", timeoutStr, err)
+	}
+	testParams.Timeout = timeout
+
+	if err := os.MkdirAll(testParams.OutputDir, dirPerm); err != nil {
+		return fmt.Errorf("could not create directory %q, err: %v", testParams.OutputDir, err)
+	}
+
+	return nil
+}
+
+func runCapture(cmd *cobra.Command, _ []string) error {
+	if err := initTestParamsFromFlags(cmd); err != nil {
+		return err
+	}
+
+	_, err := certsuite.CaptureSnapshot()
+	return err
+}
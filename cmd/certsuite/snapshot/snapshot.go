@@ -0,0 +1,19 @@
+package snapshot
+
+import (
+	"github.com/redhat-best-practices-for-k8s/certsuite/cmd/certsuite/snapshot/capture"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotCommand = &cobra.Command{
+		Use:   "snapshot",
+		Short: "Tools for working with cluster snapshots.",
+	}
+)
+
+func NewCommand() *cobra.Command {
+	snapshotCommand.AddCommand(capture.NewCommand())
+
+	return snapshotCommand
+}
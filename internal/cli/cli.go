@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/term"
@@ -47,15 +48,41 @@ const (
 
 	tickerPeriodSeconds = 10
 	lineLength          = 5
+
+	scoreNotAvailable = "n/a"
 )
 
 var CliCheckLogSniffer = &cliCheckLogSniffer{}
 
+// ReadinessScore holds the values of the readiness score printed along with the results table.
+type ReadinessScore struct {
+	Profile string
+	// Overall score, nil if all the checks were skipped.
+	Score *float64
+	// Scores of the suites that have checks that were not skipped.
+	SuiteScores  map[string]float64
+	GatePassed   bool
+	GateFailures []string
+}
+
 var (
 	checkLoggerChan chan string
 	stopChan        chan bool
+
+	// When several checks can run at the same time, the running check line can't be
+	// updated in place, so every check's status is printed in its own line.
+	concurrentChecks bool
+	checkLinesMutex  sync.Mutex
 )
 
+// SetConcurrentChecks must be called with true before running several checks at the same time.
+func SetConcurrentChecks(enabled bool) {
+	checkLinesMutex.Lock()
+	defer checkLinesMutex.Unlock()
+
+	concurrentChecks = enabled
+}
+
 func PrintBanner() {
 	fmt.Print(banner)
 }
@@ -144,19 +171,55 @@ func (c *cliCheckLogSniffer) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
-func PrintResultsTable(results map[string][]int) {
+// PrintResultsTable prints the results of every suite and, if set, their readiness scores and gate.
+func PrintResultsTable(results map[string][]int, score *ReadinessScore) {
+	suiteScores := map[string]string{}
+	if score != nil {
+		for suite, suiteScore := range score.SuiteScores {
+			suiteScores[suite] = formatScore(&suiteScore)
+		}
+	}
+
 	fmt.Printf("\n")
-	fmt.Println("-----------------------------------------------------------")
-	fmt.Printf("| %-27s %-9s %-9s %s |\n", "SUITE", "PASSED", "FAILED", "SKIPPED")
-	fmt.Println("-----------------------------------------------------------")
+	fmt.Println("---------------------------------------------------------------------")
+	fmt.Printf("| %-27s %-9s %-9s %-9s %s |\n", "SUITE", "PASSED", "FAILED", "SKIPPED", "SCORE")
+	fmt.Println("---------------------------------------------------------------------")
 	for groupName, groupResults := range results {
-		fmt.Printf("| %-25s %8d %9d %10d |\n", groupName,
+		suiteScore, found := suiteScores[groupName]
+		if !found {
+			suiteScore = scoreNotAvailable
+		}
+		fmt.Printf("| %-25s %8d %9d %10d %9s |\n", groupName,
 			groupResults[0],
 			groupResults[1],
-			groupResults[2])
-		fmt.Println("-----------------------------------------------------------")
+			groupResults[2],
+			suiteScore)
+		fmt.Println("---------------------------------------------------------------------")
 	}
 	fmt.Printf("\n")
+
+	if score == nil {
+		return
+	}
+
+	gate := Green + "PASSED" + Reset
+	if !score.GatePassed {
+		gate = Red + "FAILED" + Reset
+	}
+	fmt.Printf("Readiness score (%s profile): %s - gate %s\n", score.Profile, formatScore(score.Score), gate)
+	for _, gateFailure := range score.GateFailures {
+		fmt.Printf("  - %s\n", gateFailure)
+	}
+	fmt.Printf("\n")
+}
+
+// Returns the score as a percentage, or n/a if it's not available.
+func formatScore(score *float64) string {
+	if score == nil {
+		return scoreNotAvailable
+	}
+
+	return fmt.Sprintf("%.1f%%", *score)
 }
 
 func stopCheckLineGoroutine() {
@@ -170,19 +233,34 @@ func stopCheckLineGoroutine() {
 	stopChan = nil
 }
 
+func printCheckResultLine(line string) {
+	checkLinesMutex.Lock()
+	defer checkLinesMutex.Unlock()
+
+	stopCheckLineGoroutine()
+
+	fmt.Print(ClearLineCode + line + "\n")
+}
+
 func PrintCheckSkipped(checkName, reason string) {
 	// It shouldn't happen too often, but some checks might be set as skipped inside the checkFn
 	// if neither compliant objects nor non-compliant objects were found.
-	stopCheckLineGoroutine()
-
-	fmt.Print(ClearLineCode + "[ " + CheckResultTagSkip + " ] " + checkName + "  (" + reason + ")\n")
+	printCheckResultLine("[ " + CheckResultTagSkip + " ] " + checkName + "  (" + reason + ")")
 }
 
 func PrintCheckRunning(checkName string) {
+	checkLinesMutex.Lock()
+	defer checkLinesMutex.Unlock()
+
+	line := "[ " + CheckResultTagRunning + " ] " + checkName
+	if concurrentChecks {
+		fmt.Print(line + "\n")
+		return
+	}
+
 	stopChan = make(chan bool)
 	checkLoggerChan = make(chan string)
 
-	line := "[ " + CheckResultTagRunning + " ] " + checkName
 	if !isTTY() {
 		line += "\n"
 	}
@@ -193,27 +271,23 @@ func PrintCheckRunning(checkName string) {
 }
 
 func PrintCheckPassed(checkName string) {
-	stopCheckLineGoroutine()
+	printCheckResultLine("[ " + CheckResultTagPass + " ] " + checkName)
+}
 
-	fmt.Print(ClearLineCode + "[ " + CheckResultTagPass + " ] " + checkName + "\n")
+func PrintCheckPassedWithWaivers(checkName string, waivedObjects int) {
+	printCheckResultLine(fmt.Sprintf("[ %s ] %s  (passed with %d waived objects)", CheckResultTagPass, checkName, waivedObjects))
 }
 
 func PrintCheckFailed(checkName string) {
-	stopCheckLineGoroutine()
-
-	fmt.Print(ClearLineCode + "[ " + CheckResultTagFail + " ] " + checkName + "\n")
+	printCheckResultLine("[ " + CheckResultTagFail + " ] " + checkName)
 }
 
 func PrintCheckAborted(checkName, reason string) {
-	stopCheckLineGoroutine()
-
-	fmt.Print(ClearLineCode + "[ " + CheckResultTagAborted + " ] " + checkName + "  (" + reason + ")\n")
+	printCheckResultLine("[ " + CheckResultTagAborted + " ] " + checkName + "  (" + reason + ")")
 }
 
 func PrintCheckErrored(checkName string) {
-	stopCheckLineGoroutine()
-
-	fmt.Print(ClearLineCode + "[ " + CheckResultTagError + " ] " + checkName + "\n")
+	printCheckResultLine("[ " + CheckResultTagError + " ] " + checkName)
 }
 
 func WrapLines(text string, maxWidth int) []string {
@@ -17,6 +17,7 @@
 package clientsholder
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -25,6 +26,7 @@ import (
 	olmClient "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
 	olmFakeClient "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/fake"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/tracing"
 
 	apiextv1c "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextv1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
@@ -39,6 +41,7 @@ import (
 	ocpMachine "github.com/openshift/client-go/machineconfiguration/clientset/versioned"
 	appsv1 "k8s.io/api/apps/v1"
 	scalingv1 "k8s.io/api/autoscaling/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -116,6 +119,14 @@ func GetTestClientsHolder(k8sMockObjects []runtime.Object) *ClientsHolder {
 			k8sClientObjects = append(k8sClientObjects, v)
 		case *appsv1.StatefulSet:
 			k8sClientObjects = append(k8sClientObjects, v)
+		case *appsv1.DaemonSet:
+			k8sClientObjects = append(k8sClientObjects, v)
+		case *appsv1.ReplicaSet:
+			k8sClientObjects = append(k8sClientObjects, v)
+		case *batchv1.Job:
+			k8sClientObjects = append(k8sClientObjects, v)
+		case *batchv1.CronJob:
+			k8sClientObjects = append(k8sClientObjects, v)
 		case *corev1.ResourceQuota:
 			k8sClientObjects = append(k8sClientObjects, v)
 		case *corev1.PersistentVolume:
@@ -272,6 +283,7 @@ func newClientsHolder(filenames ...string) (*ClientsHolder, error) { //nolint:fu
 		return nil, fmt.Errorf("failed to get rest.Config: %v", err)
 	}
 	clientsHolder.RestConfig.Timeout = DefaultTimeout
+	clientsHolder.RestConfig.Wrap(tracing.WrapTransport)
 
 	clientsHolder.DynamicClient, err = dynamic.NewForConfig(clientsHolder.RestConfig)
 	if err != nil {
@@ -338,6 +350,10 @@ type Context struct {
 	namespace     string
 	podName       string
 	containerName string
+	// Optional, only used to trace the commands run in the probe pods.
+	nodeName string
+	// Optional, the commands are stopped once it's cancelled.
+	cancelCtx context.Context
 }
 
 func NewContext(namespace, podName, containerName string) Context {
@@ -359,3 +375,28 @@ func (c *Context) GetPodName() string {
 func (c *Context) GetContainerName() string {
 	return c.containerName
 }
+
+// WithNodeName returns a copy of the context with the name of the node where the pod runs.
+func (c Context) WithNodeName(nodeName string) Context {
+	c.nodeName = nodeName
+	return c
+}
+
+func (c *Context) GetNodeName() string {
+	return c.nodeName
+}
+
+// WithContext returns a copy of the context whose commands are stopped once ctx is cancelled, e.g.
+// when the check running them times out.
+func (c Context) WithContext(ctx context.Context) Context {
+	c.cancelCtx = ctx
+	return c
+}
+
+// GetContext returns the context that stops the commands, which is never cancelled if none was set.
+func (c *Context) GetContext() context.Context {
+	if c.cancelCtx == nil {
+		return context.TODO()
+	}
+	return c.cancelCtx
+}
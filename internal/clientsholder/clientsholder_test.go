@@ -15,3 +15,23 @@
 // 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
 
 package clientsholder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextWithContext(t *testing.T) {
+	ctx := NewContext("ns", "pod", "container")
+	assert.NotNil(t, ctx.GetContext())
+	assert.Nil(t, ctx.GetContext().Done())
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	withCancel := ctx.WithContext(cancelCtx)
+	cancel()
+	assert.Error(t, withCancel.GetContext().Err())
+	// The original context is not modified.
+	assert.Nil(t, ctx.GetContext().Done())
+}
@@ -18,11 +18,12 @@ package clientsholder
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/tracing"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/remotecommand"
 	"k8s.io/kubectl/pkg/scheme"
@@ -33,8 +34,62 @@ type Command interface {
 	ExecCommandContainer(Context, string) (string, string, error)
 }
 
+var (
+	// When set, the commands are run through this executor instead of the cluster's containers.
+	commandExecutor      Command
+	commandExecutorMutex sync.RWMutex
+)
+
+// SetCommandExecutor makes ExecCommandContainer run the commands through the given executor
+// instead of running them in the cluster's containers, e.g. to record or replay their outputs.
+// A nil executor restores the default behavior.
+func SetCommandExecutor(executor Command) {
+	commandExecutorMutex.Lock()
+	defer commandExecutorMutex.Unlock()
+
+	commandExecutor = executor
+}
+
+// Runs the commands in the cluster's containers, whatever the command executor is.
+type remoteExecutor struct {
+	clientsholder *ClientsHolder
+}
+
+func (e *remoteExecutor) ExecCommandContainer(ctx Context, command string) (stdout, stderr string, err error) {
+	return e.clientsholder.execCommandInCluster(ctx, command)
+}
+
+// GetRemoteExecutor returns a Command that always runs the commands in the cluster's containers,
+// so it can be wrapped by other command executors.
+func (clientsholder *ClientsHolder) GetRemoteExecutor() Command {
+	return &remoteExecutor{clientsholder: clientsholder}
+}
+
 // ExecCommand runs command in the pod and returns buffer output.
 func (clientsholder *ClientsHolder) ExecCommandContainer(
+	ctx Context, command string) (stdout, stderr string, err error) {
+	_, span := tracing.Start(tracing.CurrentContext(), "clientsholder.ExecCommandContainer",
+		tracing.AttrNamespace.String(ctx.GetNamespace()),
+		tracing.AttrPod.String(ctx.GetPodName()),
+		tracing.AttrContainer.String(ctx.GetContainerName()),
+	)
+	if ctx.GetNodeName() != "" {
+		span.SetAttributes(tracing.AttrNode.String(ctx.GetNodeName()))
+	}
+	defer func() { tracing.End(span, err) }()
+
+	commandExecutorMutex.RLock()
+	executor := commandExecutor
+	commandExecutorMutex.RUnlock()
+
+	if executor != nil {
+		return executor.ExecCommandContainer(ctx, command)
+	}
+
+	return clientsholder.execCommandInCluster(ctx, command)
+}
+
+func (clientsholder *ClientsHolder) execCommandInCluster(
 	ctx Context, command string) (stdout, stderr string, err error) {
 	commandStr := []string{"sh", "-c", command}
 	var buffOut bytes.Buffer
@@ -60,7 +115,7 @@ func (clientsholder *ClientsHolder) ExecCommandContainer(
 		log.Error("%v", err)
 		return stdout, stderr, err
 	}
-	err = exec.StreamWithContext(context.TODO(), remotecommand.StreamOptions{
+	err = exec.StreamWithContext(ctx.GetContext(), remotecommand.StreamOptions{
 		Stdout: &buffOut,
 		Stderr: &buffErr,
 	})
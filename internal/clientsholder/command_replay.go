@@ -0,0 +1,271 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package clientsholder
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+)
+
+const execRecordsFilePerm = 0o644
+
+// ExecRecord holds the outputs of a command that was run in a container.
+type ExecRecord struct {
+	Namespace  string `json:"namespace"`
+	Pod        string `json:"pod"`
+	Container  string `json:"container"`
+	Command    string `json:"command"`
+	Stdout     string `json:"stdout"`
+	Stderr     string `json:"stderr"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// WriteExecRecords writes the records in JSON Lines format, one record per line.
+func WriteExecRecords(w io.Writer, records []ExecRecord) error {
+	encoder := json.NewEncoder(w)
+	for i := range records {
+		if err := encoder.Encode(&records[i]); err != nil {
+			return fmt.Errorf("failed to write exec record: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// ReadExecRecords reads the records written in JSON Lines format by WriteExecRecords or by a
+// CommandRecorder with an output file.
+func ReadExecRecords(r io.Reader) ([]ExecRecord, error) {
+	records := []ExecRecord{}
+	scanner := bufio.NewScanner(r)
+	// Some outputs, like the ones of "podman diff", can be quite long.
+	const maxLineSize = 64 * 1024 * 1024
+	scanner.Buffer(nil, maxLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		record := ExecRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse exec record in line %d: %v", line, err)
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read exec records: %v", err)
+	}
+
+	return records, nil
+}
+
+// LoadExecRecords reads the records from a JSON Lines file.
+func LoadExecRecords(filePath string) ([]ExecRecord, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open exec records file %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	records, err := ReadExecRecords(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exec records file %s: %v", filePath, err)
+	}
+
+	return records, nil
+}
+
+func (r *ExecRecord) key() string {
+	return r.Namespace + "/" + r.Pod + "/" + r.Container + ": " + r.Command
+}
+
+func newExecRecord(ctx Context, command string) ExecRecord {
+	return ExecRecord{
+		Namespace: ctx.GetNamespace(),
+		Pod:       ctx.GetPodName(),
+		Container: ctx.GetContainerName(),
+		Command:   command,
+	}
+}
+
+// CommandRecorder runs the commands through another Command and keeps their outputs. If it has
+// an output file, every record is appended to it as soon as the command finishes instead of being
+// kept in memory, so long runs don't grow the memory usage and the records are not lost in case
+// the program is interrupted.
+type CommandRecorder struct {
+	executor Command
+	// Only used when there's no output file.
+	records      []ExecRecord
+	recordsCount int
+	outputFile   *os.File
+	mutex        sync.Mutex
+}
+
+func NewCommandRecorder(executor Command) *CommandRecorder {
+	return &CommandRecorder{executor: executor}
+}
+
+// NewCommandRecorderWithOutputFile creates a recorder that also writes the records to the given
+// file in JSON Lines format. The file is truncated if it already exists.
+func NewCommandRecorderWithOutputFile(executor Command, filePath string) (*CommandRecorder, error) {
+	outputFile, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, execRecordsFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exec records file %s: %v", filePath, err)
+	}
+
+	return &CommandRecorder{executor: executor, outputFile: outputFile}, nil
+}
+
+func (r *CommandRecorder) ExecCommandContainer(ctx Context, command string) (stdout, stderr string, err error) {
+	start := time.Now()
+	stdout, stderr, err = r.executor.ExecCommandContainer(ctx, command)
+
+	record := newExecRecord(ctx, command)
+	record.Stdout, record.Stderr = stdout, stderr
+	record.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.recordsCount++
+
+	if r.outputFile == nil {
+		r.records = append(r.records, record)
+	} else if writeErr := WriteExecRecords(r.outputFile, []ExecRecord{record}); writeErr != nil {
+		log.Error("Failed to record the output of command %q: %v", command, writeErr)
+	}
+
+	return stdout, stderr, err
+}
+
+// Close closes the recorder's output file, if any.
+func (r *CommandRecorder) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.outputFile == nil {
+		return nil
+	}
+
+	err := r.outputFile.Close()
+	r.outputFile = nil
+	return err
+}
+
+// GetRecordsCount returns the number of commands run so far.
+func (r *CommandRecorder) GetRecordsCount() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.recordsCount
+}
+
+// GetRecords returns the outputs of all the commands run so far, in the order they were run. The
+// recorders with an output file don't keep them, so they must be read from that file instead.
+func (r *CommandRecorder) GetRecords() []ExecRecord {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]ExecRecord{}, r.records...)
+}
+
+// CommandReplayer serves the outputs of previously recorded commands instead of running them.
+// When the same command was recorded several times in the same container, its outputs are served
+// in the same order they were recorded, repeating the last one once all of them were served.
+type CommandReplayer struct {
+	recordsByKey map[string][]ExecRecord
+	nextIdxByKey map[string]int
+	missing      map[string]bool
+	mutex        sync.Mutex
+}
+
+func NewCommandReplayer(records []ExecRecord) *CommandReplayer {
+	replayer := &CommandReplayer{
+		recordsByKey: map[string][]ExecRecord{},
+		nextIdxByKey: map[string]int{},
+		missing:      map[string]bool{},
+	}
+
+	for i := range records {
+		key := records[i].key()
+		replayer.recordsByKey[key] = append(replayer.recordsByKey[key], records[i])
+	}
+
+	return replayer
+}
+
+// NewCommandReplayerFromFile creates a replayer serving the records of a JSON Lines file, like
+// the ones written by a CommandRecorder with an output file.
+func NewCommandReplayerFromFile(filePath string) (*CommandReplayer, error) {
+	records, err := LoadExecRecords(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCommandReplayer(records), nil
+}
+
+func (r *CommandReplayer) ExecCommandContainer(ctx Context, command string) (stdout, stderr string, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	requested := newExecRecord(ctx, command)
+	key := requested.key()
+	records, found := r.recordsByKey[key]
+	if !found {
+		r.missing[key] = true
+		log.Warn("No recorded output for command %q in pod %s/%s container %s", command,
+			requested.Namespace, requested.Pod, requested.Container)
+		return "", "", fmt.Errorf("the output of command %q in pod %s/%s container %s was not recorded",
+			command, requested.Namespace, requested.Pod, requested.Container)
+	}
+
+	idx := r.nextIdxByKey[key]
+	if idx < len(records)-1 {
+		r.nextIdxByKey[key] = idx + 1
+	}
+
+	record := records[idx]
+	if record.Error != "" {
+		err = errors.New(record.Error)
+	}
+
+	return record.Stdout, record.Stderr, err
+}
+
+// GetMissingCommands returns the number of different commands that were requested but had no
+// recorded output.
+func (r *CommandReplayer) GetMissingCommands() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.missing)
+}
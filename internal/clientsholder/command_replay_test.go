@@ -0,0 +1,155 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package clientsholder
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandRecorderAndReplayer(t *testing.T) {
+	outputs := []struct {
+		stdout string
+		err    error
+	}{{"first", nil}, {"second", nil}, {"", errors.New("command failed")}}
+
+	callIdx := 0
+	mock := &CommandMock{
+		ExecCommandContainerFunc: func(ctx Context, command string) (string, string, error) {
+			output := outputs[callIdx]
+			callIdx++
+			return output.stdout, "", output.err
+		},
+	}
+
+	recorder := NewCommandRecorder(mock)
+	ctx := NewContext("ns1", "pod1", "container1")
+	otherCtx := NewContext("ns1", "pod2", "container1")
+	_, _, _ = recorder.ExecCommandContainer(ctx, "ps")
+	_, _, _ = recorder.ExecCommandContainer(ctx, "ps")
+	_, _, _ = recorder.ExecCommandContainer(otherCtx, "lsmod")
+
+	assert.Equal(t, 3, recorder.GetRecordsCount())
+	records := recorder.GetRecords()
+	for i := range records {
+		assert.GreaterOrEqual(t, records[i].DurationMs, int64(0))
+		records[i].DurationMs = 0
+	}
+	assert.Equal(t, []ExecRecord{
+		{Namespace: "ns1", Pod: "pod1", Container: "container1", Command: "ps", Stdout: "first"},
+		{Namespace: "ns1", Pod: "pod1", Container: "container1", Command: "ps", Stdout: "second"},
+		{Namespace: "ns1", Pod: "pod2", Container: "container1", Command: "lsmod", Error: "command failed"},
+	}, records)
+
+	replayer := NewCommandReplayer(records)
+
+	// Outputs are served in the recorded order, repeating the last one.
+	for _, expected := range []string{"first", "second", "second"} {
+		stdout, _, err := replayer.ExecCommandContainer(ctx, "ps")
+		assert.Nil(t, err)
+		assert.Equal(t, expected, stdout)
+	}
+
+	_, _, err := replayer.ExecCommandContainer(otherCtx, "lsmod")
+	assert.EqualError(t, err, "command failed")
+
+	// Commands not recorded in the same container.
+	_, _, err = replayer.ExecCommandContainer(otherCtx, "ps")
+	assert.NotNil(t, err)
+	_, _, err = replayer.ExecCommandContainer(ctx, "sysctl -a")
+	assert.NotNil(t, err)
+	_, _, err = replayer.ExecCommandContainer(ctx, "sysctl -a")
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, replayer.GetMissingCommands())
+}
+
+func TestSetCommandExecutor(t *testing.T) {
+	defer SetCommandExecutor(nil)
+
+	SetCommandExecutor(NewCommandReplayer([]ExecRecord{
+		{Namespace: "ns1", Pod: "pod1", Container: "container1", Command: "uname -r", Stdout: "5.14.0"},
+	}))
+
+	stdout, _, err := (&ClientsHolder{}).ExecCommandContainer(NewContext("ns1", "pod1", "container1"), "uname -r")
+	assert.Nil(t, err)
+	assert.Equal(t, "5.14.0", stdout)
+}
+
+func TestCommandRecorderWithOutputFile(t *testing.T) {
+	mock := &CommandMock{
+		ExecCommandContainerFunc: func(ctx Context, command string) (string, string, error) {
+			if command == "lsmod" {
+				return "", "", errors.New("command failed")
+			}
+			return "line1\nline2\n", "warning", nil
+		},
+	}
+
+	filePath := filepath.Join(t.TempDir(), "exec-records.jsonl")
+	recorder, err := NewCommandRecorderWithOutputFile(mock, filePath)
+	assert.Nil(t, err)
+
+	ctx := NewContext("ns1", "pod1", "container1")
+	_, _, _ = recorder.ExecCommandContainer(ctx, "ps -ef")
+	_, _, _ = recorder.ExecCommandContainer(ctx, "lsmod")
+
+	// Records are written as soon as the commands finish.
+	contents, err := os.ReadFile(filePath)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, strings.Count(string(contents), "\n"))
+
+	assert.Nil(t, recorder.Close())
+	assert.Nil(t, recorder.Close())
+
+	// The records are only in the file.
+	assert.Equal(t, 2, recorder.GetRecordsCount())
+	assert.Empty(t, recorder.GetRecords())
+
+	records, err := LoadExecRecords(filePath)
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "ps -ef", records[0].Command)
+	assert.Equal(t, "command failed", records[1].Error)
+
+	replayer, err := NewCommandReplayerFromFile(filePath)
+	assert.Nil(t, err)
+	stdout, stderr, err := replayer.ExecCommandContainer(ctx, "ps -ef")
+	assert.Nil(t, err)
+	assert.Equal(t, "line1\nline2\n", stdout)
+	assert.Equal(t, "warning", stderr)
+	_, _, err = replayer.ExecCommandContainer(ctx, "lsmod")
+	assert.EqualError(t, err, "command failed")
+}
+
+func TestReadExecRecords(t *testing.T) {
+	records, err := ReadExecRecords(strings.NewReader(
+		`{"namespace":"ns1","pod":"pod1","container":"c1","command":"uname -r","stdout":"5.14.0\n","stderr":"","durationMs":15}` + "\n\n" +
+			`{"namespace":"ns1","pod":"pod1","container":"c1","command":"lsmod","stdout":"","stderr":"","error":"exit code 1","durationMs":3}` + "\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, []ExecRecord{
+		{Namespace: "ns1", Pod: "pod1", Container: "c1", Command: "uname -r", Stdout: "5.14.0\n", DurationMs: 15},
+		{Namespace: "ns1", Pod: "pod1", Container: "c1", Command: "lsmod", Error: "exit code 1", DurationMs: 3},
+	}, records)
+
+	_, err = ReadExecRecords(strings.NewReader("{\"namespace\":\"ns1\"}\nnot json\n"))
+	assert.EqualError(t, err, "failed to parse exec record in line 2: invalid character 'o' in literal null (expecting 'u')")
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package clientsholder
+
+import (
+	cncfNetworkAttachmentFake "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/fake"
+	ocpConfigFake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	ocpMachineFake "github.com/openshift/client-go/machineconfiguration/clientset/versioned/fake"
+	olmFakeClient "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/fake"
+	apiextv1fake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	k8sFakeClient "k8s.io/client-go/kubernetes/fake"
+)
+
+// OfflineObjects holds the objects served by each of the fake clients of an offline clients holder.
+type OfflineObjects struct {
+	K8sObjects            []runtime.Object
+	APIExtObjects         []runtime.Object
+	OlmObjects            []runtime.Object
+	MachineConfigObjects  []runtime.Object
+	CNCFNetworkingObjects []runtime.Object
+	// Unstructured objects served by the dynamic client, along with the list kinds of
+	// their resources, e.g. "MyCrList" for the resource "mycrs" of a CRD.
+	DynamicObjects   []runtime.Object
+	DynamicListKinds map[schema.GroupVersionResource]string
+	GroupResources   []*metav1.APIResourceList
+}
+
+// SetOfflineClientsHolder overwrites the clients holder with fake clients serving the given objects,
+// so the test environment can be built and the checks can run without access to a cluster.
+func SetOfflineClientsHolder(objects *OfflineObjects) *ClientsHolder {
+	k8sClient := k8sFakeClient.NewSimpleClientset(objects.K8sObjects...)
+
+	clientsHolder.K8sClient = k8sClient
+	clientsHolder.K8sNetworkingClient = k8sClient.NetworkingV1()
+	clientsHolder.APIExtClient = apiextv1fake.NewSimpleClientset(objects.APIExtObjects...)
+	clientsHolder.OlmClient = olmFakeClient.NewSimpleClientset(objects.OlmObjects...)
+	clientsHolder.OcpClient = ocpConfigFake.NewSimpleClientset().ConfigV1()
+	clientsHolder.MachineCfg = ocpMachineFake.NewSimpleClientset(objects.MachineConfigObjects...)
+	clientsHolder.CNCFNetworkingClient = cncfNetworkAttachmentFake.NewSimpleClientset(objects.CNCFNetworkingObjects...).K8sCniCncfIoV1()
+	clientsHolder.DynamicClient = dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		objects.DynamicListKinds, objects.DynamicObjects...)
+	clientsHolder.GroupResources = objects.GroupResources
+
+	clientsHolder.ready = true
+	return &clientsHolder
+}
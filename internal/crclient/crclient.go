@@ -17,6 +17,7 @@
 package crclient
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -45,14 +46,15 @@ func (p *Process) String() string {
 
 // Helper function to create the clientsholder.Context of the first container of the debug pod
 // that runs in the give node. This context is usually needed to run shell commands that get
-// information from a node where a pod/container under test is running.
-func GetNodeDebugPodContext(node string, env *provider.TestEnvironment) (clientsholder.Context, error) {
+// information from a node where a pod/container under test is running. The commands are stopped
+// once ctx is cancelled, e.g. when the check running them times out.
+func GetNodeDebugPodContext(ctx context.Context, node string, env *provider.TestEnvironment) (clientsholder.Context, error) {
 	debugPod := env.DebugPods[node]
 	if debugPod == nil {
 		return clientsholder.Context{}, fmt.Errorf("debug pod not found on node %s", node)
 	}
 
-	return clientsholder.NewContext(debugPod.Namespace, debugPod.Name, debugPod.Spec.Containers[0].Name), nil
+	return clientsholder.NewContext(debugPod.Namespace, debugPod.Name, debugPod.Spec.Containers[0].Name).WithNodeName(node).WithContext(ctx), nil
 }
 
 func GetPidFromContainer(cut *provider.Container, ctx clientsholder.Context) (int, error) {
@@ -83,9 +85,9 @@ func GetPidFromContainer(cut *provider.Container, ctx clientsholder.Context) (in
 }
 
 // To get the pid namespace of the container
-func GetContainerPidNamespace(testContainer *provider.Container, env *provider.TestEnvironment) (string, error) {
+func GetContainerPidNamespace(ctx context.Context, testContainer *provider.Container, env *provider.TestEnvironment) (string, error) {
 	// Get the container pid
-	ocpContext, err := GetNodeDebugPodContext(testContainer.NodeName, env)
+	ocpContext, err := GetNodeDebugPodContext(ctx, testContainer.NodeName, env)
 	if err != nil {
 		return "", fmt.Errorf("failed to get debug pod's context for container %s: %v", testContainer, err)
 	}
@@ -105,20 +107,20 @@ func GetContainerPidNamespace(testContainer *provider.Container, env *provider.T
 	return strings.Fields(stdout)[0], nil
 }
 
-func GetContainerProcesses(container *provider.Container, env *provider.TestEnvironment) ([]*Process, error) {
-	pidNs, err := GetContainerPidNamespace(container, env)
+func GetContainerProcesses(ctx context.Context, container *provider.Container, env *provider.TestEnvironment) ([]*Process, error) {
+	pidNs, err := GetContainerPidNamespace(ctx, container, env)
 	if err != nil {
 		return nil, fmt.Errorf("could not get the containers' pid namespace, err: %v", err)
 	}
 
-	return GetPidsFromPidNamespace(pidNs, container)
+	return GetPidsFromPidNamespace(ctx, pidNs, container)
 }
 
 // ExecCommandContainerNSEnter executes a command in the specified container namespace using nsenter
-func ExecCommandContainerNSEnter(command string,
+func ExecCommandContainerNSEnter(ctx context.Context, command string,
 	aContainer *provider.Container) (outStr, errStr string, err error) {
 	env := provider.GetTestEnvironment()
-	ctx, err := GetNodeDebugPodContext(aContainer.NodeName, &env)
+	debugPodCtx, err := GetNodeDebugPodContext(ctx, aContainer.NodeName, &env)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get debug pod's context for container %s: %v", aContainer, err)
 	}
@@ -126,7 +128,7 @@ func ExecCommandContainerNSEnter(command string,
 	ch := clientsholder.GetClientsHolder()
 
 	// Get the container PID to build the nsenter command
-	containerPid, err := GetPidFromContainer(aContainer, ctx)
+	containerPid, err := GetPidFromContainer(aContainer, debugPodCtx)
 	if err != nil {
 		return "", "", fmt.Errorf("cannot get PID from: %s, err: %v", aContainer, err)
 	}
@@ -135,7 +137,7 @@ func ExecCommandContainerNSEnter(command string,
 	nsenterCommand := "nsenter -t " + strconv.Itoa(containerPid) + " -n " + command
 
 	// Run the nsenter command on the debug pod
-	outStr, errStr, err = ch.ExecCommandContainer(ctx, nsenterCommand)
+	outStr, errStr, err = ch.ExecCommandContainer(debugPodCtx, nsenterCommand)
 	if err != nil {
 		return "", "", fmt.Errorf("cannot execute command: \" %s \"  on %s err:%s", command, aContainer, err)
 	}
@@ -143,17 +145,17 @@ func ExecCommandContainerNSEnter(command string,
 	return outStr, errStr, err
 }
 
-func GetPidsFromPidNamespace(pidNamespace string, container *provider.Container) (p []*Process, err error) {
+func GetPidsFromPidNamespace(ctx context.Context, pidNamespace string, container *provider.Container) (p []*Process, err error) {
 	const command = "trap \"\" SIGURG ; ps -e -o pidns,pid,ppid,args"
 	env := provider.GetTestEnvironment()
-	ctx, err := GetNodeDebugPodContext(container.NodeName, &env)
+	debugPodCtx, err := GetNodeDebugPodContext(ctx, container.NodeName, &env)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get debug pod's context for container %s: %v", container, err)
 	}
 
-	stdout, stderr, err := clientsholder.GetClientsHolder().ExecCommandContainer(ctx, command)
+	stdout, stderr, err := clientsholder.GetClientsHolder().ExecCommandContainer(debugPodCtx, command)
 	if err != nil || stderr != "" {
-		return nil, fmt.Errorf("command %q failed to run in debug pod=%s (node=%s): %v", command, ctx.GetPodName(), container.NodeName, err)
+		return nil, fmt.Errorf("command %q failed to run in debug pod=%s (node=%s): %v", command, debugPodCtx.GetPodName(), container.NodeName, err)
 	}
 
 	re := regexp.MustCompile(PsRegex)
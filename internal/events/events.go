@@ -0,0 +1,172 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Types of the events emitted while the checks run.
+const (
+	TypeRunStarted        = "run-started"
+	TypeDiscoveryFinished = "discovery-finished"
+	TypeCheckStarted      = "check-started"
+	TypeCheckFinished     = "check-finished"
+	TypeGroupAborted      = "group-aborted"
+	TypeRunFinished       = "run-finished"
+)
+
+// Stdout can be used as the events file name to write the events to the standard output.
+const Stdout = "-"
+
+const (
+	eventsFilePermissions = 0o644
+
+	// Max number of events a subscriber can fall behind before new events are discarded for it.
+	subscriberBufferSize = 1000
+)
+
+// Event is a JSON Lines record of the run progress. Only the fields that apply to each event type are set.
+type Event struct {
+	Type string `json:"type"`
+	Time string `json:"time"`
+
+	// run-started
+	LabelsFilter string `json:"labelsFilter,omitempty"`
+	// discovery-finished: objects found by kind. run-finished: checks by result.
+	Counts map[string]int `json:"counts,omitempty"`
+
+	// check-started and check-finished
+	CheckID    string `json:"checkId,omitempty"`
+	Suite      string `json:"suite,omitempty"`
+	Result     string `json:"result,omitempty"`
+	SkipReason string `json:"skipReason,omitempty"`
+	// check-finished and run-finished
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+
+	// group-aborted
+	Reason string `json:"reason,omitempty"`
+}
+
+var (
+	mutex       sync.Mutex
+	output      io.Writer
+	outputFile  *os.File
+	subscribers = map[chan []byte]struct{}{}
+)
+
+// OpenFile makes the events to be written to a file, or to the standard output if the file name is Stdout.
+func OpenFile(fileName string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if fileName == Stdout {
+		output = os.Stdout
+		return nil
+	}
+
+	file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, eventsFilePermissions)
+	if err != nil {
+		return fmt.Errorf("could not open events file %s, err: %v", fileName, err)
+	}
+
+	output = file
+	outputFile = file
+	return nil
+}
+
+// CloseFile stops writing the events to the events file.
+func CloseFile() error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	output = nil
+	if outputFile == nil {
+		return nil
+	}
+
+	file := outputFile
+	outputFile = nil
+	return file.Close()
+}
+
+// Subscribe returns a channel where the JSON encoding of every new event is sent, and the function
+// to stop receiving them. Events are discarded for subscribers that don't keep up.
+func Subscribe() (eventsChan <-chan []byte, unsubscribe func()) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	subscriberChan := make(chan []byte, subscriberBufferSize)
+	subscribers[subscriberChan] = struct{}{}
+
+	return subscriberChan, func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if _, exists := subscribers[subscriberChan]; exists {
+			delete(subscribers, subscriberChan)
+			close(subscriberChan)
+		}
+	}
+}
+
+// Emit sets the time of the event and sends it to the events file and to the subscribers.
+func Emit(event *Event) {
+	event.Time = time.Now().UTC().Format(time.RFC3339Nano)
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not encode event %s, err: %v\n", event.Type, err)
+		return
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if output != nil {
+		if _, err := output.Write(append(data, '\n')); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not write event %s, err: %v\n", event.Type, err)
+		}
+	}
+
+	for subscriberChan := range subscribers {
+		select {
+		case subscriberChan <- data:
+		default:
+		}
+	}
+}
+
+func EmitRunStarted(labelsFilter string) {
+	Emit(&Event{Type: TypeRunStarted, LabelsFilter: labelsFilter})
+}
+
+func EmitDiscoveryFinished(objectsCounts map[string]int) {
+	Emit(&Event{Type: TypeDiscoveryFinished, Counts: objectsCounts})
+}
+
+func EmitCheckStarted(checkID, suite string) {
+	Emit(&Event{Type: TypeCheckStarted, CheckID: checkID, Suite: suite})
+}
+
+func EmitCheckFinished(checkID, suite, result, skipReason string, duration time.Duration) {
+	Emit(&Event{
+		Type:            TypeCheckFinished,
+		CheckID:         checkID,
+		Suite:           suite,
+		Result:          result,
+		SkipReason:      skipReason,
+		DurationSeconds: duration.Seconds(),
+	})
+}
+
+func EmitGroupAborted(suite, reason string) {
+	Emit(&Event{Type: TypeGroupAborted, Suite: suite, Reason: reason})
+}
+
+func EmitRunFinished(resultsCounts map[string]int, duration time.Duration) {
+	Emit(&Event{Type: TypeRunFinished, Counts: resultsCounts, DurationSeconds: duration.Seconds()})
+}
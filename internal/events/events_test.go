@@ -0,0 +1,89 @@
+package events
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventsFile(t *testing.T) {
+	eventsFile := filepath.Join(t.TempDir(), "events.jsonl")
+	assert.Nil(t, OpenFile(eventsFile))
+
+	EmitRunStarted("common")
+	EmitDiscoveryFinished(map[string]int{"pods": 2})
+	EmitCheckStarted("check1", "suite1")
+	EmitCheckFinished("check1", "suite1", "skipped", "no pods", 1500*time.Millisecond)
+	EmitGroupAborted("suite1", "global time-out")
+	EmitRunFinished(map[string]int{"skipped": 1}, 2*time.Second)
+	assert.Nil(t, CloseFile())
+
+	// Events emitted after closing the file are not written.
+	EmitRunStarted("common")
+
+	contents, err := os.ReadFile(eventsFile)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n")
+	assert.Len(t, lines, 6)
+
+	expectedEvents := []Event{
+		{Type: TypeRunStarted, LabelsFilter: "common"},
+		{Type: TypeDiscoveryFinished, Counts: map[string]int{"pods": 2}},
+		{Type: TypeCheckStarted, CheckID: "check1", Suite: "suite1"},
+		{Type: TypeCheckFinished, CheckID: "check1", Suite: "suite1", Result: "skipped", SkipReason: "no pods", DurationSeconds: 1.5},
+		{Type: TypeGroupAborted, Suite: "suite1", Reason: "global time-out"},
+		{Type: TypeRunFinished, Counts: map[string]int{"skipped": 1}, DurationSeconds: 2},
+	}
+	for i, line := range lines {
+		event := Event{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &event))
+
+		_, err := time.Parse(time.RFC3339Nano, event.Time)
+		assert.Nil(t, err)
+
+		event.Time = ""
+		assert.Equal(t, expectedEvents[i], event)
+	}
+}
+
+func TestOpenFileError(t *testing.T) {
+	assert.NotNil(t, OpenFile(filepath.Join(t.TempDir(), "non-existent-dir", "events.jsonl")))
+	assert.Nil(t, CloseFile())
+}
+
+func TestSubscribe(t *testing.T) {
+	eventsChan1, unsubscribe1 := Subscribe()
+	eventsChan2, unsubscribe2 := Subscribe()
+	defer unsubscribe2()
+
+	EmitCheckStarted("check1", "suite1")
+	assert.Contains(t, string(<-eventsChan1), `"checkId":"check1"`)
+	assert.Contains(t, string(<-eventsChan2), `"checkId":"check1"`)
+
+	// The channel is closed after unsubscribing, and calling it again has no effect.
+	unsubscribe1()
+	unsubscribe1()
+	_, open := <-eventsChan1
+	assert.False(t, open)
+
+	EmitCheckStarted("check2", "suite1")
+	assert.Contains(t, string(<-eventsChan2), `"checkId":"check2"`)
+}
+
+func TestSubscriberFallingBehind(t *testing.T) {
+	eventsChan, unsubscribe := Subscribe()
+	defer unsubscribe()
+
+	// Emitting doesn't block when the subscriber doesn't read the events.
+	for i := 0; i < subscriberBufferSize+10; i++ {
+		EmitCheckStarted("check1", "suite1")
+	}
+
+	assert.Len(t, eventsChan, subscriberBufferSize)
+}
@@ -17,6 +17,11 @@ const (
 //go:embed html/results.html
 var htmlResultsFileContent []byte
 
+// Appended to the claimjson.js file, as the results page doesn't show the waivers.
+//
+//go:embed html/waivers.js
+var waiversJSContent []byte
+
 // Creates the claimjson.js file from the claim.json file.
 func createClaimJSFile(claimFilePath, outputDir string) (filePath string, err error) {
 	// Read claim.json content.
@@ -25,8 +30,8 @@ func createClaimJSFile(claimFilePath, outputDir string) (filePath string, err er
 		return "", fmt.Errorf("failed to read claim file %s content in %s: %v", claimFilePath, outputDir, err)
 	}
 
-	// Add the content as the value for the js variable.
-	jsClaimContent := "var initialjson = " + string(claimContent)
+	// Add the content as the value for the js variable, followed by the script that shows the waivers.
+	jsClaimContent := "var initialjson = " + string(claimContent) + "\n" + string(waiversJSContent)
 
 	filePath = filepath.Join(outputDir, jsClaimVarFileName)
 	err = os.WriteFile(filePath, []byte(jsClaimContent), writeFilePerms)
@@ -0,0 +1,78 @@
+// Shows the waivers of the run and the objects they waived in the results page, below its tabs.
+// The results page is downloaded from the parser repository, so it's added to the claimjson.js file.
+(function () {
+  function addTable(parent, title, headers, rows) {
+    const header = document.createElement('h4');
+    header.textContent = title;
+    parent.appendChild(header);
+
+    const table = document.createElement('table');
+    table.className = 'table';
+    const headerRow = table.createTHead().insertRow();
+    for (const text of headers) {
+      const cell = document.createElement('th');
+      cell.textContent = text;
+      headerRow.appendChild(cell);
+    }
+
+    const body = table.createTBody();
+    for (const row of rows) {
+      const bodyRow = body.insertRow();
+      for (const text of row) {
+        bodyRow.insertCell().textContent = text;
+      }
+    }
+    parent.appendChild(table);
+  }
+
+  function getWaivedObjectsRows(results) {
+    const rows = [];
+    for (const [testID, result] of Object.entries(results || {})) {
+      if (!result.checkDetails) {
+        continue;
+      }
+
+      let checkDetails;
+      try {
+        checkDetails = JSON.parse(result.checkDetails);
+      } catch (e) {
+        continue;
+      }
+
+      for (const object of checkDetails.WaivedObjectsOut || []) {
+        const fields = (object.ObjectFieldsKeys || []).map((key, i) => key + ': ' + object.ObjectFieldsValues[i]);
+        rows.push([testID, object.ObjectType, fields.join(', ')]);
+      }
+    }
+    return rows;
+  }
+
+  function showWaivers() {
+    if (typeof initialjson === 'undefined' || !initialjson.claim) {
+      return;
+    }
+
+    const claim = initialjson.claim;
+    const waivers = (claim.configurations && claim.configurations.waivers) || [];
+    const waivedObjectsRows = getWaivedObjectsRows(claim.results);
+    if (waivers.length === 0 && waivedObjectsRows.length === 0) {
+      return;
+    }
+
+    const section = document.createElement('section');
+    section.id = 'waivers';
+    addTable(section, 'Waivers', ['Test ID', 'Namespace', 'Kind', 'Name', 'Reason', 'Approved By', 'Expires'],
+      waivers.map((w) => [w.testID, w.namespace || '*', w.kind || '*', w.name || '*', w.reason, w.approvedBy, w.expires]));
+    addTable(section, 'Waived Objects', ['Test ID', 'Object Type', 'Object Fields'], waivedObjectsRows);
+    (document.querySelector('main') || document.body).appendChild(section);
+  }
+
+  if (typeof document === 'undefined') {
+    return;
+  }
+  if (document.readyState === 'loading') {
+    document.addEventListener('DOMContentLoaded', showWaivers);
+  } else {
+    showWaivers();
+  }
+})();
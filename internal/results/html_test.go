@@ -1 +1,29 @@
 package results
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateClaimJSFile(t *testing.T) {
+	outputDir := t.TempDir()
+	claimFilePath := filepath.Join(outputDir, "claim.json")
+	assert.Nil(t, os.WriteFile(claimFilePath, []byte(`{"claim":{}}`), writeFilePerms))
+
+	filePath, err := createClaimJSFile(claimFilePath, outputDir)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(outputDir, jsClaimVarFileName), filePath)
+
+	contents, err := os.ReadFile(filePath)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(contents), "var initialjson = {\"claim\":{}}\n"))
+	// The waivers are shown by the script appended to the claim.
+	assert.True(t, strings.HasSuffix(string(contents), string(waiversJSContent)))
+
+	_, err = createClaimJSFile(filepath.Join(outputDir, "missing.json"), outputDir)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,100 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const spansFilePermissions = 0o644
+
+// Span as written to the spans file.
+type FileSpan struct {
+	Name            string         `json:"name"`
+	TraceID         string         `json:"traceId"`
+	SpanID          string         `json:"spanId"`
+	ParentSpanID    string         `json:"parentSpanId,omitempty"`
+	StartTime       time.Time      `json:"startTime"`
+	EndTime         time.Time      `json:"endTime"`
+	DurationSeconds float64        `json:"durationSeconds"`
+	Attributes      map[string]any `json:"attributes,omitempty"`
+	Status          string         `json:"status"`
+	StatusMessage   string         `json:"statusMessage,omitempty"`
+}
+
+// Writes the spans to a file, one JSON object per line.
+type fileExporter struct {
+	mutex   sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+func newFileExporter(fileName string) (*fileExporter, error) {
+	file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, spansFilePermissions)
+	if err != nil {
+		return nil, fmt.Errorf("could not open spans file %s, err: %v", fileName, err)
+	}
+
+	return &fileExporter{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+func toFileSpan(span sdktrace.ReadOnlySpan) *FileSpan {
+	fileSpan := &FileSpan{
+		Name:            span.Name(),
+		TraceID:         span.SpanContext().TraceID().String(),
+		SpanID:          span.SpanContext().SpanID().String(),
+		StartTime:       span.StartTime(),
+		EndTime:         span.EndTime(),
+		DurationSeconds: span.EndTime().Sub(span.StartTime()).Seconds(),
+		Status:          span.Status().Code.String(),
+		StatusMessage:   span.Status().Description,
+	}
+
+	if span.Parent().IsValid() {
+		fileSpan.ParentSpanID = span.Parent().SpanID().String()
+	}
+
+	if attrs := span.Attributes(); len(attrs) > 0 {
+		fileSpan.Attributes = map[string]any{}
+		for _, attr := range attrs {
+			fileSpan.Attributes[string(attr.Key)] = attr.Value.AsInterface()
+		}
+	}
+
+	return fileSpan
+}
+
+func (e *fileExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.file == nil {
+		return nil
+	}
+
+	for _, span := range spans {
+		if err := e.encoder.Encode(toFileSpan(span)); err != nil {
+			return fmt.Errorf("could not write span %s, err: %v", span.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+func (e *fileExporter) Shutdown(_ context.Context) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.file == nil {
+		return nil
+	}
+
+	err := e.file.Close()
+	e.file = nil
+	return err
+}
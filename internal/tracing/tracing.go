@@ -0,0 +1,179 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName  = "github.com/redhat-best-practices-for-k8s/certsuite"
+	serviceName = "certsuite"
+
+	shutdownTimeout = 10 * time.Second
+)
+
+// Attribute keys of the certsuite spans. The k8s ones follow the OpenTelemetry semantic conventions.
+const (
+	AttrCheckID        = attribute.Key("certsuite.check.id")
+	AttrCheckResult    = attribute.Key("certsuite.check.result")
+	AttrSuite          = attribute.Key("certsuite.suite")
+	AttrLabelsFilter   = attribute.Key("certsuite.labels_filter")
+	AttrImage          = attribute.Key("certsuite.image")
+	AttrNamespace      = attribute.Key("k8s.namespace.name")
+	AttrPod            = attribute.Key("k8s.pod.name")
+	AttrContainer      = attribute.Key("k8s.container.name")
+	AttrNode           = attribute.Key("k8s.node.name")
+	AttrHTTPMethod     = attribute.Key("http.request.method")
+	AttrHTTPPath       = attribute.Key("url.path")
+	AttrHTTPStatus     = attribute.Key("http.response.status_code")
+	attrServiceName    = attribute.Key("service.name")
+	attrServiceVersion = attribute.Key("service.version")
+)
+
+var (
+	tracerProvider *sdktrace.TracerProvider
+
+	// Parent of the spans started by code that has no context to pass them, like the API calls
+	// or the commands run in the containers.
+	currentCtx      = context.Background()
+	currentCtxMutex sync.RWMutex
+)
+
+// Setup starts exporting the spans to an OTLP/gRPC collector, e.g. "http://localhost:4317", and/or
+// to a file, one JSON span per line. Until then, spans are not recorded.
+func Setup(otlpEndpoint, fileName, version string) error {
+	opts := []sdktrace.TracerProviderOption{}
+
+	if otlpEndpoint != "" {
+		exporter, err := otlptracegrpc.New(context.Background(), otlptracegrpc.WithEndpointURL(otlpEndpoint))
+		if err != nil {
+			return fmt.Errorf("could not create the OTLP exporter for %s, err: %v", otlpEndpoint, err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	if fileName != "" {
+		exporter, err := newFileExporter(fileName)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	if len(opts) == 0 {
+		return nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attrServiceName.String(serviceName),
+		attrServiceVersion.String(version),
+	))
+	if err != nil {
+		return fmt.Errorf("could not create the tracing resource, err: %v", err)
+	}
+
+	tracerProvider = sdktrace.NewTracerProvider(append(opts, sdktrace.WithResource(res))...)
+	otel.SetTracerProvider(tracerProvider)
+
+	return nil
+}
+
+// Shutdown sends the pending spans and stops the exporters.
+func Shutdown() error {
+	if tracerProvider == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	err := tracerProvider.Shutdown(ctx)
+	tracerProvider = nil
+	if err != nil {
+		return fmt.Errorf("could not send the pending spans, err: %v", err)
+	}
+
+	return nil
+}
+
+// Start starts a span, child of the span in ctx, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends the span, setting its status to error if err is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SetCurrentContext sets the parent of the spans started with CurrentContext until the returned
+// function is called. It must not be used by code that runs at the same time as other code setting it.
+func SetCurrentContext(ctx context.Context) (restore func()) {
+	currentCtxMutex.Lock()
+	defer currentCtxMutex.Unlock()
+
+	previousCtx := currentCtx
+	currentCtx = ctx
+
+	return func() {
+		currentCtxMutex.Lock()
+		defer currentCtxMutex.Unlock()
+
+		currentCtx = previousCtx
+	}
+}
+
+// CurrentContext returns the context set with SetCurrentContext.
+func CurrentContext() context.Context {
+	currentCtxMutex.RLock()
+	defer currentCtxMutex.RUnlock()
+
+	return currentCtx
+}
+
+type tracingRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt *tracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = CurrentContext()
+	}
+
+	_, span := Start(ctx, "k8s.api "+req.Method,
+		AttrHTTPMethod.String(req.Method),
+		AttrHTTPPath.String(req.URL.Path),
+	)
+
+	resp, err := rt.next.RoundTrip(req)
+	if err == nil {
+		span.SetAttributes(AttrHTTPStatus.Int(resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	End(span, err)
+
+	return resp, err
+}
+
+// WrapTransport adds a span for every request sent to the API server.
+func WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &tracingRoundTripper{next: rt}
+}
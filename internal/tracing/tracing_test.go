@@ -0,0 +1,209 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// Replaces the global tracer provider by one that keeps the spans in memory.
+func setupInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previousProvider) })
+
+	return exporter
+}
+
+func readSpansFile(t *testing.T, fileName string) []FileSpan {
+	file, err := os.Open(fileName)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	spans := []FileSpan{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		span := FileSpan{}
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &span))
+		spans = append(spans, span)
+	}
+
+	return spans
+}
+
+func TestSetupFile(t *testing.T) {
+	previousProvider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previousProvider)
+
+	spansFile := filepath.Join(t.TempDir(), "traces.jsonl")
+	assert.Nil(t, Setup("", spansFile, "v5.0.0"))
+
+	ctx, parentSpan := Start(context.Background(), "parent", AttrSuite.String("suite1"))
+	_, childSpan := Start(ctx, "child", AttrCheckID.String("check1"), AttrHTTPStatus.Int(http.StatusOK))
+	End(childSpan, errors.New("check failed"))
+	End(parentSpan, nil)
+	assert.Nil(t, Shutdown())
+
+	spans := readSpansFile(t, spansFile)
+	assert.Len(t, spans, 2)
+
+	child, parent := spans[0], spans[1]
+	assert.Equal(t, "parent", parent.Name)
+	assert.Empty(t, parent.ParentSpanID)
+	assert.Equal(t, map[string]any{"certsuite.suite": "suite1"}, parent.Attributes)
+	assert.Equal(t, codes.Unset.String(), parent.Status)
+
+	assert.Equal(t, "child", child.Name)
+	assert.Equal(t, parent.TraceID, child.TraceID)
+	assert.Equal(t, parent.SpanID, child.ParentSpanID)
+	// JSON numbers are decoded as float64.
+	assert.Equal(t, map[string]any{"certsuite.check.id": "check1", "http.response.status_code": float64(http.StatusOK)}, child.Attributes)
+	assert.Equal(t, codes.Error.String(), child.Status)
+	assert.Equal(t, "check failed", child.StatusMessage)
+	assert.True(t, child.DurationSeconds >= 0)
+}
+
+func TestSetupErrors(t *testing.T) {
+	// Nothing to set up.
+	assert.Nil(t, Setup("", "", "v5.0.0"))
+	assert.Nil(t, Shutdown())
+
+	assert.NotNil(t, Setup("", filepath.Join(t.TempDir(), "non-existent-dir", "traces.jsonl"), "v5.0.0"))
+}
+
+// OTLP collector stand-in that keeps the names of the spans it receives.
+type fakeCollector struct {
+	collectortrace.UnimplementedTraceServiceServer
+
+	mutex        sync.Mutex
+	spanNames    []string
+	serviceNames []string
+}
+
+func (c *fakeCollector) Export(_ context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, resourceSpans := range req.ResourceSpans {
+		for _, attr := range resourceSpans.Resource.Attributes {
+			if attr.Key == string(attrServiceName) {
+				c.serviceNames = append(c.serviceNames, attr.Value.GetStringValue())
+			}
+		}
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				c.spanNames = append(c.spanNames, span.Name)
+			}
+		}
+	}
+
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+func TestSetupOTLP(t *testing.T) {
+	previousProvider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previousProvider)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	collector := &fakeCollector{}
+	server := grpc.NewServer()
+	collectortrace.RegisterTraceServiceServer(server, collector)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	assert.Nil(t, Setup("http://"+listener.Addr().String(), "", "v5.0.0"))
+
+	ctx, runSpan := Start(context.Background(), "certsuite.Run")
+	_, checkSpan := Start(ctx, "checksdb.Check")
+	checkSpan.End()
+	runSpan.End()
+
+	// The pending spans are sent on shutdown.
+	assert.Nil(t, Shutdown())
+
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	assert.ElementsMatch(t, []string{"certsuite.Run", "checksdb.Check"}, collector.spanNames)
+	assert.Contains(t, collector.serviceNames, serviceName)
+}
+
+func TestCurrentContext(t *testing.T) {
+	exporter := setupInMemoryExporter(t)
+
+	ctx, span := Start(context.Background(), "parent")
+	restore := SetCurrentContext(ctx)
+	assert.Equal(t, ctx, CurrentContext())
+
+	_, childSpan := Start(CurrentContext(), "child")
+	childSpan.End()
+	span.End()
+
+	restore()
+	assert.Equal(t, context.Background(), CurrentContext())
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+}
+
+func TestWrapTransport(t *testing.T) {
+	exporter := setupInMemoryExporter(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/nodes" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	// Requests without a span in their context are children of the current context.
+	ctx, parentSpan := Start(context.Background(), "parent")
+	restore := SetCurrentContext(ctx)
+	defer restore()
+
+	client := &http.Client{Transport: WrapTransport(http.DefaultTransport)}
+	for _, path := range []string{"/api/v1/nodes", "/api/v1/namespaces/ns1/pods/pod1"} {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+path, http.NoBody)
+		assert.Nil(t, err)
+		resp, err := client.Do(req)
+		assert.Nil(t, err)
+		resp.Body.Close()
+	}
+	parentSpan.End()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 3)
+
+	okSpan, notFoundSpan := spans[0], spans[1]
+	assert.Equal(t, "k8s.api GET", okSpan.Name)
+	assert.Equal(t, parentSpan.SpanContext().SpanID(), okSpan.Parent.SpanID())
+	assert.Contains(t, okSpan.Attributes, AttrHTTPPath.String("/api/v1/nodes"))
+	assert.Contains(t, okSpan.Attributes, AttrHTTPStatus.Int(http.StatusOK))
+	assert.Equal(t, codes.Unset, okSpan.Status.Code)
+
+	assert.Contains(t, notFoundSpan.Attributes, AttrHTTPStatus.Int(http.StatusNotFound))
+	assert.Equal(t, codes.Error, notFoundSpan.Status.Code)
+}
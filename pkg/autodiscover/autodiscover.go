@@ -20,8 +20,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -29,12 +29,15 @@ import (
 	olmv1Alpha "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/tracing"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/compatibility"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/podhelper"
 	"helm.sh/helm/v3/pkg/release"
 	appsv1 "k8s.io/api/apps/v1"
 	scalingv1 "k8s.io/api/autoscaling/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
@@ -43,6 +46,7 @@ import (
 	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -74,6 +78,10 @@ type DiscoveredTestData struct {
 	AllCatalogSources      []*olmv1Alpha.CatalogSource
 	Deployments            []appsv1.Deployment
 	StatefulSet            []appsv1.StatefulSet
+	DaemonSets             []appsv1.DaemonSet
+	ReplicaSets            []appsv1.ReplicaSet
+	Jobs                   []batchv1.Job
+	CronJobs               []batchv1.CronJob
 	PersistentVolumes      []corev1.PersistentVolume
 	PersistentVolumeClaims []corev1.PersistentVolumeClaim
 	ClusterRoleBindings    []rbacv1.ClusterRoleBinding
@@ -97,166 +105,267 @@ type DiscoveredTestData struct {
 	PartnerName            string
 	CollectorAppPassword   string
 	CollectorAppEndpoint   string
-}
-
-type labelObject struct {
-	LabelKey   string
-	LabelValue string
+	DiscoveryReport        DiscoveryReport
 }
 
 var data = DiscoveredTestData{}
 
-const labelRegex = `(\S*)\s*:\s*(\S*)`
-const labelRegexMatches = 3
+// Guards data.Namespaces, which is set by a collector while the permissions of the others, that
+// may not depend on it, are resolved.
+var namespacesMutex sync.Mutex
 
-func createLabels(labelStrings []string) (labelObjects []labelObject) {
-	for _, label := range labelStrings {
-		r := regexp.MustCompile(labelRegex)
+func getNamespaces() []string {
+	namespacesMutex.Lock()
+	defer namespacesMutex.Unlock()
+	return data.Namespaces
+}
+
+func setNamespaces(namespaces []string) {
+	namespacesMutex.Lock()
+	defer namespacesMutex.Unlock()
+	data.Namespaces = namespaces
+}
 
-		values := r.FindStringSubmatch(label)
-		if len(values) != labelRegexMatches {
-			log.Error("Failed to parse label %q. It will not be used!, ", label)
+// Returns the selectors of the labels under test. An object is under test if it matches at least one
+// of them.
+func createLabelSelectors(labelStrings []string) (selectors []labels.Selector) {
+	for _, label := range labelStrings {
+		selector, err := configuration.ParseLabelSelector(label)
+		if err != nil {
+			log.Error("Failed to parse label %q. It will not be used!, err: %v", label, err)
 			continue
 		}
-		var aLabel labelObject
-		aLabel.LabelKey = values[1]
-		aLabel.LabelValue = values[2]
-		labelObjects = append(labelObjects, aLabel)
+		selectors = append(selectors, selector)
 	}
-	return labelObjects
+	return selectors
 }
 
-// DoAutoDiscover finds objects under test
-//
-//nolint:funlen
+// DoAutoDiscover finds objects under test. The objects are discovered by independent collectors that
+// run concurrently. A collector that fails does not stop the discovery: its error is added to the
+// discovery report and its objects are left empty.
 func DoAutoDiscover(config *configuration.TestConfiguration) DiscoveredTestData {
-	oc := clientsholder.GetClientsHolder()
+	ctx, endDiscovery := startDiscoveryStep(tracing.CurrentContext(), "autodiscover.DoAutoDiscover")
+	defer endDiscovery()
 
-	var err error
-	data.StorageClasses, err = getAllStorageClasses(oc.K8sClient.StorageV1())
-	if err != nil {
-		log.Fatal("Failed to retrieve storageClasses - err: %v", err)
-	}
+	data = DiscoveredTestData{Nodes: &corev1.NodeList{}}
 
-	podsUnderTestLabelsObjects := createLabels(config.PodsUnderTestLabels)
-	operatorsUnderTestLabelsObjects := createLabels(config.OperatorsUnderTestLabels)
+	podsUnderTestSelectors := createLabelSelectors(config.PodsUnderTestLabels)
+	operatorsUnderTestSelectors := createLabelSelectors(config.OperatorsUnderTestLabels)
 
-	log.Debug("Pods under test labels: %+v", podsUnderTestLabelsObjects)
-	log.Debug("Operators under test labels: %+v", operatorsUnderTestLabelsObjects)
+	log.Debug("Pods under test labels: %v, fields: %q", podsUnderTestSelectors, config.PodsUnderTestFieldSelector)
+	log.Debug("Operators under test labels: %v, fields: %q", operatorsUnderTestSelectors, config.OperatorsUnderTestFieldSelector)
 
-	data.AllNamespaces, err = getAllNamespaces(oc.K8sClient.CoreV1())
-	if err != nil {
-		log.Fatal("Cannot get namespaces, err: %v", err)
-	}
-	data.AllSubscriptions = findSubscriptions(oc.OlmClient, []string{""})
-	data.AllCsvs, err = getAllOperators(oc.OlmClient)
-	if err != nil {
-		log.Error("Cannot get operators, err: %v", err)
-	}
-	data.AllInstallPlans = getAllInstallPlans(oc.OlmClient)
-	data.AllCatalogSources = getAllCatalogSources(oc.OlmClient)
-	data.Namespaces = namespacesListToStringList(config.TargetNameSpaces)
-	data.Pods, data.AllPods = findPodsByLabels(oc.K8sClient.CoreV1(), podsUnderTestLabelsObjects, data.Namespaces)
-	data.AbnormalEvents = findAbnormalEvents(oc.K8sClient.CoreV1(), data.Namespaces)
-	debugLabels := []labelObject{{LabelKey: debugHelperPodsLabelName, LabelValue: debugHelperPodsLabelValue}}
-	debugNS := []string{config.DebugDaemonSetNamespace}
-	data.DebugPods, _ = findPodsByLabels(oc.K8sClient.CoreV1(), debugLabels, debugNS)
-	data.ResourceQuotaItems, err = getResourceQuotas(oc.K8sClient.CoreV1())
-	if err != nil {
-		log.Fatal("Cannot get resource quotas, err: %v", err)
-	}
-	data.PodDisruptionBudgets, err = getPodDisruptionBudgets(oc.K8sClient.PolicyV1(), data.Namespaces)
-	if err != nil {
-		log.Fatal("Cannot get pod disruption budgets, err: %v", err)
-	}
-	data.NetworkPolicies, err = getNetworkPolicies(oc.K8sNetworkingClient)
-	if err != nil {
-		log.Fatal("Cannot get network policies, err: %v", err)
-	}
+	data.ValidProtocolNames = config.ValidProtocolNames
+	data.ServicesIgnoreList = config.ServicesIgnoreList
+	data.ExecutedBy = config.ExecutedBy
+	data.PartnerName = config.PartnerName
+	data.CollectorAppPassword = config.CollectorAppPassword
+	data.CollectorAppEndpoint = config.CollectorAppEndpoint
 
-	// Get cluster crds
-	data.AllCrds, err = getClusterCrdNames()
-	if err != nil {
-		log.Fatal("Cannot get cluster CRD names, err: %v", err)
+	reviewer := permissions.NewReviewer(clientsholder.GetClientsHolder().K8sClient.AuthorizationV1())
+	getMissing := func(perms []permissions.Permission) ([]permissions.Permission, error) {
+		return reviewer.GetMissing(permissions.Resolve(perms, getNamespaces(), config.DebugDaemonSetNamespace))
 	}
-	data.Crds = FindTestCrdNames(data.AllCrds, config.CrdFilters)
 
-	data.ScaleCrUnderTest = GetScaleCrUnderTest(data.Namespaces, data.Crds)
-	data.Csvs = findOperatorsByLabels(oc.OlmClient, operatorsUnderTestLabelsObjects, config.TargetNameSpaces)
-	data.Subscriptions = findSubscriptions(oc.OlmClient, data.Namespaces)
-	data.HelmChartReleases = getHelmList(oc.RestConfig, data.Namespaces)
+	collectors := getCollectors(config, podsUnderTestSelectors, operatorsUnderTestSelectors)
+	data.DiscoveryReport = runCollectors(ctx, collectors, collectorsParallelism, getMissing)
 
-	// Get all operator pods
-	data.CSVToPodListMap, err = getOperatorCsvPods(data.Csvs)
-	if err != nil {
-		log.Fatal("Failed to get the operator pods, err: %v", err)
-	}
+	return data
+}
 
-	openshiftVersion, err := getOpenshiftVersion(oc.OcpClient)
-	if err != nil {
-		log.Fatal("Failed to get the OpenShift version, err: %v", err)
-	}
+// Returns the collectors that fill the discovered data. Every collector sets its own fields.
+//
+//nolint:funlen
+func getCollectors(config *configuration.TestConfiguration, podsUnderTestSelectors, operatorsUnderTestSelectors []labels.Selector) []collector {
+	oc := clientsholder.GetClientsHolder()
 
-	data.OpenshiftVersion = openshiftVersion
-	k8sVersion, err := oc.K8sClient.Discovery().ServerVersion()
-	if err != nil {
-		log.Fatal("Cannot get the K8s version, err: %v", err)
+	collectors := []collector{
+		{name: CollectorTargetNamespaces, collect: func() error {
+			namespaces, err := GetTargetNamespaces(oc.K8sClient.CoreV1(), config)
+			if err != nil {
+				return err
+			}
+			log.Info("Namespaces under test: %v", namespaces)
+			setNamespaces(namespaces)
+			return nil
+		}},
+		{name: CollectorStorageClasses, collect: func() (err error) {
+			data.StorageClasses, err = getAllStorageClasses(oc.K8sClient.StorageV1())
+			return err
+		}},
+		{name: CollectorNamespaces, collect: func() (err error) {
+			data.AllNamespaces, err = getAllNamespaces(oc.K8sClient.CoreV1())
+			return err
+		}},
+		{name: CollectorOperators, collect: func() (err error) {
+			if data.AllSubscriptions, err = findSubscriptions(oc.OlmClient, []string{""}); err != nil {
+				return err
+			}
+			if data.AllInstallPlans, err = getAllInstallPlans(oc.OlmClient); err != nil {
+				return err
+			}
+			if data.AllCatalogSources, err = getAllCatalogSources(oc.OlmClient); err != nil {
+				return err
+			}
+			data.AllCsvs, err = getAllOperators(oc.OlmClient)
+			return err
+		}},
+		{name: CollectorPods, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.Pods, data.AllPods, err = findPodsByLabels(oc.K8sClient.CoreV1(), podsUnderTestSelectors, config.PodsUnderTestFieldSelector, data.Namespaces)
+			return err
+		}},
+		{name: CollectorAbnormalEvents, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.AbnormalEvents, err = findAbnormalEvents(oc.K8sClient.CoreV1(), data.Namespaces)
+			return err
+		}},
+		{name: CollectorDebugPods, collect: func() (err error) {
+			debugLabels := []labels.Selector{labels.SelectorFromSet(labels.Set{debugHelperPodsLabelName: debugHelperPodsLabelValue})}
+			debugNS := []string{config.DebugDaemonSetNamespace}
+			data.DebugPods, _, err = findPodsByLabels(oc.K8sClient.CoreV1(), debugLabels, "", debugNS)
+			return err
+		}},
+		{name: CollectorResourceQuotas, collect: func() (err error) {
+			data.ResourceQuotaItems, err = getResourceQuotas(oc.K8sClient.CoreV1())
+			return err
+		}},
+		{name: CollectorPodDisruptionBudgets, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.PodDisruptionBudgets, err = getPodDisruptionBudgets(oc.K8sClient.PolicyV1(), data.Namespaces)
+			return err
+		}},
+		{name: CollectorNetworkPolicies, collect: func() (err error) {
+			data.NetworkPolicies, err = getNetworkPolicies(oc.K8sNetworkingClient)
+			return err
+		}},
+		{name: CollectorCrds, collect: func() (err error) {
+			data.AllCrds, err = getClusterCrdNames()
+			if err != nil {
+				return err
+			}
+			data.Crds = FindTestCrdNames(data.AllCrds, config.CrdFilters)
+			return nil
+		}},
+		{name: CollectorScaleCrs, dependsOn: []string{CollectorCrds, CollectorTargetNamespaces}, collect: func() (err error) {
+			data.ScaleCrUnderTest, err = GetScaleCrUnderTest(data.Namespaces, data.Crds, config.CrdFilters)
+			return err
+		}},
+		{name: CollectorOperatorsUnderTest, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.Csvs, err = findOperatorsByLabels(oc.OlmClient, operatorsUnderTestSelectors, config.OperatorsUnderTestFieldSelector, data.Namespaces)
+			if err != nil {
+				return err
+			}
+			data.Subscriptions, err = findSubscriptions(oc.OlmClient, data.Namespaces)
+			return err
+		}},
+		{name: CollectorOperatorPods, dependsOn: []string{CollectorOperatorsUnderTest}, collect: func() (err error) {
+			data.CSVToPodListMap, err = getOperatorCsvPods(data.Csvs)
+			return err
+		}},
+		{name: CollectorHelmCharts, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.HelmChartReleases, err = getHelmList(oc.RestConfig, data.Namespaces)
+			return err
+		}},
+		{name: CollectorOpenshiftVersion, collect: func() (err error) {
+			data.OpenshiftVersion, err = getOpenshiftVersion(oc.OcpClient)
+			if err != nil {
+				return err
+			}
+			// Find the status of the OCP version (pre-ga, end-of-life, maintenance, or generally available)
+			data.OCPStatus = compatibility.DetermineOCPStatus(data.OpenshiftVersion, time.Now())
+			return nil
+		}},
+		{name: CollectorK8sVersion, collect: func() error {
+			k8sVersion, err := oc.K8sClient.Discovery().ServerVersion()
+			if err != nil {
+				return err
+			}
+			data.K8sVersion = k8sVersion.GitVersion
+			return nil
+		}},
+		{name: CollectorDeployments, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.Deployments, err = findDeploymentsByLabels(oc.K8sClient.AppsV1(), podsUnderTestSelectors, data.Namespaces)
+			return err
+		}},
+		{name: CollectorStatefulSets, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.StatefulSet, err = findStatefulSetsByLabels(oc.K8sClient.AppsV1(), podsUnderTestSelectors, data.Namespaces)
+			return err
+		}},
+		{name: CollectorDaemonSets, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.DaemonSets, err = findDaemonSetsByLabels(oc.K8sClient.AppsV1(), podsUnderTestSelectors, data.Namespaces)
+			return err
+		}},
+		{name: CollectorReplicaSets, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.ReplicaSets, err = findReplicaSetsByLabels(oc.K8sClient.AppsV1(), podsUnderTestSelectors, data.Namespaces)
+			return err
+		}},
+		{name: CollectorJobs, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.Jobs, err = findJobsByLabels(oc.K8sClient.BatchV1(), podsUnderTestSelectors, data.Namespaces)
+			return err
+		}},
+		{name: CollectorCronJobs, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.CronJobs, err = findCronJobsByLabels(oc.K8sClient.BatchV1(), podsUnderTestSelectors, data.Namespaces)
+			return err
+		}},
+		{name: CollectorHorizontalPodAutoscalers, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.Hpas, err = findHpaControllers(oc.K8sClient, data.Namespaces)
+			return err
+		}},
+		// Check if the Istio Service Mesh is present
+		{name: CollectorIstio, dependsOn: []string{CollectorNamespaces}, collect: func() error {
+			data.IstioServiceMeshFound = isIstioServiceMeshInstalled(oc.K8sClient.AppsV1(), data.AllNamespaces)
+			return nil
+		}},
+		{name: CollectorClusterRoleBindings, collect: func() (err error) {
+			data.ClusterRoleBindings, err = getClusterRoleBindings(oc.K8sClient.RbacV1())
+			return err
+		}},
+		{name: CollectorRoleBindings, collect: func() (err error) {
+			data.RoleBindings, err = getRoleBindings(oc.K8sClient.RbacV1())
+			return err
+		}},
+		{name: CollectorRoles, collect: func() (err error) {
+			data.Roles, err = getRoles(oc.K8sClient.RbacV1())
+			return err
+		}},
+		{name: CollectorNodes, collect: func() error {
+			nodes, err := oc.K8sClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				return err
+			}
+			data.Nodes = nodes
+			return nil
+		}},
+		{name: CollectorPersistentVolumes, collect: func() (err error) {
+			data.PersistentVolumes, err = getPersistentVolumes(oc.K8sClient.CoreV1())
+			return err
+		}},
+		{name: CollectorPersistentVolumeClaims, collect: func() (err error) {
+			data.PersistentVolumeClaims, err = getPersistentVolumeClaims(oc.K8sClient.CoreV1())
+			return err
+		}},
+		{name: CollectorServices, dependsOn: []string{CollectorTargetNamespaces}, collect: func() (err error) {
+			data.Services, err = getServices(oc.K8sClient.CoreV1(), data.Namespaces, data.ServicesIgnoreList)
+			return err
+		}},
 	}
-	data.ValidProtocolNames = config.ValidProtocolNames
-	data.ServicesIgnoreList = config.ServicesIgnoreList
 
-	// Find the status of the OCP version (pre-ga, end-of-life, maintenance, or generally available)
-	data.OCPStatus = compatibility.DetermineOCPStatus(openshiftVersion, time.Now())
+	perms := GetCollectorPermissions(config, IsOpenshiftConfigAPIServed(oc.K8sClient.Discovery()))
+	for i := range collectors {
+		collectors[i].permissions = perms[collectors[i].name]
+	}
 
-	data.K8sVersion = k8sVersion.GitVersion
-	data.Deployments = findDeploymentsByLabels(oc.K8sClient.AppsV1(), podsUnderTestLabelsObjects, data.Namespaces)
-	data.StatefulSet = findStatefulSetsByLabels(oc.K8sClient.AppsV1(), podsUnderTestLabelsObjects, data.Namespaces)
+	return collectors
+}
 
-	// Check if the Istio Service Mesh is present
-	data.IstioServiceMeshFound = isIstioServiceMeshInstalled(oc.K8sClient.AppsV1(), data.AllNamespaces)
+// Starts the span of a discovery step. The API calls made until the returned function is called
+// are children of the span.
+func startDiscoveryStep(ctx context.Context, name string) (stepCtx context.Context, end func()) {
+	stepCtx, span := tracing.Start(ctx, name)
+	restore := tracing.SetCurrentContext(stepCtx)
 
-	// Find ClusterRoleBindings
-	clusterRoleBindings, err := getClusterRoleBindings(oc.K8sClient.RbacV1())
-	if err != nil {
-		log.Fatal("Cannot get cluster role bindings, err: %v", err)
+	return stepCtx, func() {
+		restore()
+		span.End()
 	}
-	data.ClusterRoleBindings = clusterRoleBindings
-	// Find RoleBindings
-	roleBindings, err := getRoleBindings(oc.K8sClient.RbacV1())
-	if err != nil {
-		log.Fatal("Cannot get role bindings, error: %v", err)
-	}
-	data.RoleBindings = roleBindings
-	// find roles
-	roles, err := getRoles(oc.K8sClient.RbacV1())
-	if err != nil {
-		log.Fatal("Cannot get roles, err: %v", err)
-	}
-	data.Roles = roles
-	data.Hpas = findHpaControllers(oc.K8sClient, data.Namespaces)
-	data.Nodes, err = oc.K8sClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		log.Fatal("Cannot get list of nodes, err: %v", err)
-	}
-	data.PersistentVolumes, err = getPersistentVolumes(oc.K8sClient.CoreV1())
-	if err != nil {
-		log.Fatal("Cannot get list of persistent volumes, error: %v", err)
-	}
-	data.PersistentVolumeClaims, err = getPersistentVolumeClaims(oc.K8sClient.CoreV1())
-	if err != nil {
-		log.Fatal("Cannot get list of persistent volume claims, err: %v", err)
-	}
-	data.Services, err = getServices(oc.K8sClient.CoreV1(), data.Namespaces, data.ServicesIgnoreList)
-	if err != nil {
-		log.Fatal("Cannot get list of services, err: %v", err)
-	}
-
-	data.ExecutedBy = config.ExecutedBy
-	data.PartnerName = config.PartnerName
-	data.CollectorAppPassword = config.CollectorAppPassword
-	data.CollectorAppEndpoint = config.CollectorAppEndpoint
-
-	return data
 }
 
 func namespacesListToStringList(namespaceList []configuration.Namespace) (stringList []string) {
@@ -0,0 +1,274 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package autodiscover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/tracing"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
+)
+
+// Names of the discovery collectors, as shown in the discovery report.
+const (
+	CollectorTargetNamespaces         = "targetNamespaces"
+	CollectorStorageClasses           = "storageClasses"
+	CollectorNamespaces               = "namespaces"
+	CollectorOperators                = "operators"
+	CollectorPods                     = "pods"
+	CollectorAbnormalEvents           = "abnormalEvents"
+	CollectorDebugPods                = "debugPods"
+	CollectorResourceQuotas           = "resourceQuotas"
+	CollectorPodDisruptionBudgets     = "podDisruptionBudgets"
+	CollectorNetworkPolicies          = "networkPolicies"
+	CollectorCrds                     = "crds"
+	CollectorScaleCrs                 = "scaleCrs"
+	CollectorOperatorsUnderTest       = "operatorsUnderTest"
+	CollectorOperatorPods             = "operatorPods"
+	CollectorHelmCharts               = "helmCharts"
+	CollectorOpenshiftVersion         = "openshiftVersion"
+	CollectorK8sVersion               = "k8sVersion"
+	CollectorDeployments              = "deployments"
+	CollectorStatefulSets             = "statefulSets"
+	CollectorDaemonSets               = "daemonSets"
+	CollectorReplicaSets              = "replicaSets"
+	CollectorJobs                     = "jobs"
+	CollectorCronJobs                 = "cronJobs"
+	CollectorHorizontalPodAutoscalers = "horizontalPodAutoscalers"
+	CollectorIstio                    = "istio"
+	CollectorClusterRoleBindings      = "clusterRoleBindings"
+	CollectorRoleBindings             = "roleBindings"
+	CollectorRoles                    = "roles"
+	CollectorNodes                    = "nodes"
+	CollectorPersistentVolumes        = "persistentVolumes"
+	CollectorPersistentVolumeClaims   = "persistentVolumeClaims"
+	CollectorServices                 = "services"
+)
+
+// Permissions needed by every collector, with the namespace placeholders unresolved.
+var collectorPermissions = map[string][]permissions.Permission{
+	CollectorTargetNamespaces: {permissions.ListNamespaces},
+	CollectorStorageClasses:   {permissions.ListStorageClasses},
+	CollectorNamespaces:       {permissions.ListNamespaces},
+	CollectorOperators: {
+		permissions.ListAllClusterServiceVersions, permissions.ListAllSubscriptions,
+		permissions.ListAllInstallPlans, permissions.ListAllCatalogSources,
+	},
+	CollectorPods:                     {permissions.ListPods},
+	CollectorAbnormalEvents:           {permissions.ListEvents},
+	CollectorDebugPods:                {permissions.ListProbePods},
+	CollectorResourceQuotas:           {permissions.ListResourceQuotas},
+	CollectorPodDisruptionBudgets:     {permissions.ListPodDisruptionBudgets},
+	CollectorNetworkPolicies:          {permissions.ListNetworkPolicies},
+	CollectorCrds:                     {permissions.ListCrds},
+	CollectorOperatorsUnderTest:       {permissions.ListClusterServiceVersions, permissions.ListSubscriptions},
+	CollectorOperatorPods:             {permissions.ListPods},
+	CollectorHelmCharts:               {permissions.ListHelmReleases},
+	CollectorOpenshiftVersion:         {permissions.GetClusterOperators},
+	CollectorDeployments:              {permissions.ListDeployments},
+	CollectorStatefulSets:             {permissions.ListStatefulSets},
+	CollectorDaemonSets:               {permissions.ListDaemonSets},
+	CollectorReplicaSets:              {permissions.ListReplicaSets},
+	CollectorJobs:                     {permissions.ListJobs},
+	CollectorCronJobs:                 {permissions.ListCronJobs},
+	CollectorHorizontalPodAutoscalers: {permissions.ListHorizontalPodAutoscalers},
+	CollectorIstio:                    {permissions.GetIstioDeployments},
+	CollectorClusterRoleBindings:      {permissions.ListClusterRoleBindings},
+	CollectorRoleBindings:             {permissions.ListRoleBindings},
+	CollectorRoles:                    {permissions.ListRoles},
+	CollectorNodes:                    {permissions.ListNodes},
+	CollectorPersistentVolumes:        {permissions.ListPersistentVolumes},
+	CollectorPersistentVolumeClaims:   {permissions.ListPersistentVolumeClaims},
+	CollectorServices:                 {permissions.ListServices},
+}
+
+// GetCollectorPermissions returns the permissions needed by every collector with the given
+// configuration, with the namespace placeholders unresolved. The namespaces are only listed to
+// resolve the namespace selectors, so no permission is needed for them when there are none. The
+// cluster operators are only read in OpenShift clusters.
+func GetCollectorPermissions(config *configuration.TestConfiguration, isOpenshift bool) map[string][]permissions.Permission {
+	perms := map[string][]permissions.Permission{}
+	for name, collectorPerms := range collectorPermissions {
+		perms[name] = append([]permissions.Permission{}, collectorPerms...)
+	}
+
+	if !hasNamespaceSelectors(config) {
+		perms[CollectorTargetNamespaces] = []permissions.Permission{}
+	}
+
+	if !isOpenshift {
+		perms[CollectorOpenshiftVersion] = []permissions.Permission{}
+	}
+
+	return perms
+}
+
+// IsOpenshiftConfigAPIServed returns whether the cluster serves the OpenShift config API, which has
+// the cluster operators. It is assumed to be served if the API discovery fails for any other reason.
+func IsOpenshiftConfigAPIServed(client discovery.DiscoveryInterface) bool {
+	_, err := client.ServerResourcesForGroupVersion(configv1.GroupVersion.String())
+	if err != nil && kerrors.IsNotFound(err) {
+		log.Info("The %s API is not served. Running in a non-OCP cluster.", configv1.GroupVersion)
+		return false
+	}
+
+	return true
+}
+
+// Maximum number of collectors that run at the same time.
+const collectorsParallelism = 8
+
+// CollectorReport is the outcome of a discovery collector.
+type CollectorReport struct {
+	Name            string  `json:"name"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// DiscoveryReport has the outcome of every discovery collector, sorted by name.
+type DiscoveryReport struct {
+	Collectors []CollectorReport `json:"collectors"`
+}
+
+// GetError returns the error of the collector, or nil if it succeeded or did not run.
+func (r *DiscoveryReport) GetError(collector string) error {
+	for i := range r.Collectors {
+		if r.Collectors[i].Name == collector && r.Collectors[i].Error != "" {
+			return errors.New(r.Collectors[i].Error)
+		}
+	}
+
+	return nil
+}
+
+// FailedCollectors returns the reports of the collectors that failed.
+func (r *DiscoveryReport) FailedCollectors() []CollectorReport {
+	failed := []CollectorReport{}
+	for i := range r.Collectors {
+		if r.Collectors[i].Error != "" {
+			failed = append(failed, r.Collectors[i])
+		}
+	}
+
+	return failed
+}
+
+// A collector discovers a set of objects. It does not start until the collectors it depends on
+// have finished, and it fails without running if any of them failed or if any of its permissions
+// is missing.
+type collector struct {
+	name        string
+	dependsOn   []string
+	permissions []permissions.Permission
+	collect     func() error
+}
+
+// Returns the permissions, from the given ones, that are not granted.
+type missingPermissionsFn func([]permissions.Permission) ([]permissions.Permission, error)
+
+// Runs the collectors, at most parallelism at the same time, and returns their report. The
+// permissions of the collectors are not checked if getMissing is nil. Every
+// collector has its own span, but the API calls are children of the span in ctx, as the current
+// context can't be changed while other collectors are running.
+func runCollectors(ctx context.Context, collectors []collector, parallelism int, getMissing missingPermissionsFn) DiscoveryReport {
+	done := map[string]chan struct{}{}
+	for i := range collectors {
+		done[collectors[i].name] = make(chan struct{})
+	}
+
+	var mutex sync.Mutex
+	errs := map[string]error{}
+	report := DiscoveryReport{Collectors: []CollectorReport{}}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, parallelism)
+	for i := range collectors {
+		wg.Add(1)
+		go func(c *collector) {
+			defer wg.Done()
+			defer close(done[c.name])
+
+			for _, dependency := range c.dependsOn {
+				if ch, exists := done[dependency]; exists {
+					<-ch
+				}
+			}
+
+			semaphore <- struct{}{}
+			start := time.Now()
+			err := runCollector(ctx, c, getMissing, func(dependency string) error {
+				mutex.Lock()
+				defer mutex.Unlock()
+				return errs[dependency]
+			})
+			<-semaphore
+
+			collectorReport := CollectorReport{Name: c.name, DurationSeconds: time.Since(start).Seconds()}
+			if err != nil {
+				log.Error("Discovery of %s failed: %v", c.name, err)
+				collectorReport.Error = err.Error()
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			errs[c.name] = err
+			report.Collectors = append(report.Collectors, collectorReport)
+		}(&collectors[i])
+	}
+	wg.Wait()
+
+	sort.Slice(report.Collectors, func(i, j int) bool { return report.Collectors[i].Name < report.Collectors[j].Name })
+	return report
+}
+
+// Runs a collector whose dependencies have finished, turning its panics into errors.
+func runCollector(ctx context.Context, c *collector, getMissing missingPermissionsFn, getError func(string) error) (err error) {
+	_, span := tracing.Start(ctx, "autodiscover."+c.name)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		tracing.End(span, err)
+	}()
+
+	for _, dependency := range c.dependsOn {
+		if depErr := getError(dependency); depErr != nil {
+			return fmt.Errorf("discovery of %s failed: %v", dependency, depErr)
+		}
+	}
+
+	if getMissing != nil && len(c.permissions) > 0 {
+		missing, reviewErr := getMissing(c.permissions)
+		if reviewErr != nil {
+			log.Warn("Could not review the permissions of collector %s: %v", c.name, reviewErr)
+		} else if len(missing) > 0 {
+			return fmt.Errorf("missing permissions: %s", permissions.Join(missing))
+		}
+	}
+
+	return c.collect()
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package autodiscover
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRunCollectors(t *testing.T) {
+	var running, maxRunning int32
+	slowCollect := func() error {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			maxSoFar := atomic.LoadInt32(&maxRunning)
+			if current <= maxSoFar || atomic.CompareAndSwapInt32(&maxRunning, maxSoFar, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	var dependencyDone atomic.Bool
+	collectors := []collector{
+		{name: "pods", collect: slowCollect},
+		{name: "nodes", collect: slowCollect},
+		{name: "services", collect: slowCollect},
+		{name: "roles", collect: func() error { return errors.New("roles are forbidden") }},
+		{name: "roleBindings", dependsOn: []string{"roles"}, collect: func() error { return nil }},
+		{name: "crds", collect: func() error {
+			time.Sleep(10 * time.Millisecond)
+			dependencyDone.Store(true)
+			return nil
+		}},
+		{name: "scaleCrs", dependsOn: []string{"crds"}, collect: func() error {
+			if !dependencyDone.Load() {
+				return errors.New("started before its dependency")
+			}
+			return nil
+		}},
+		{name: "helmCharts", collect: func() error { panic("no helm client") }},
+		{name: "nodeList", permissions: []permissions.Permission{permissions.ListNodes}, collect: func() error { return nil }},
+		{name: "events", permissions: []permissions.Permission{permissions.ListEvents}, collect: func() error {
+			return errors.New("must not run")
+		}},
+	}
+
+	getMissing := func(perms []permissions.Permission) ([]permissions.Permission, error) {
+		if perms[0] == permissions.ListEvents {
+			return permissions.Resolve(perms, []string{"tnf"}, ""), nil
+		}
+		return nil, nil
+	}
+
+	report := runCollectors(context.TODO(), collectors, 2, getMissing)
+
+	assert.LessOrEqual(t, maxRunning, int32(2))
+	assert.Len(t, report.Collectors, len(collectors))
+
+	names := []string{}
+	for _, c := range report.Collectors {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"crds", "events", "helmCharts", "nodeList", "nodes", "pods", "roleBindings", "roles", "scaleCrs", "services"}, names)
+
+	assert.Nil(t, report.GetError("pods"))
+	assert.Nil(t, report.GetError("scaleCrs"))
+	assert.Nil(t, report.GetError("unknown"))
+	assert.Nil(t, report.GetError("nodeList"))
+	assert.Equal(t, "missing permissions: list events in namespace tnf", report.GetError("events").Error())
+	assert.Equal(t, "roles are forbidden", report.GetError("roles").Error())
+	assert.Equal(t, "discovery of roles failed: roles are forbidden", report.GetError("roleBindings").Error())
+	assert.Equal(t, "panic: no helm client", report.GetError("helmCharts").Error())
+
+	failed := report.FailedCollectors()
+	assert.Len(t, failed, 4)
+	assert.Equal(t, "events", failed[0].Name)
+	assert.Equal(t, "helmCharts", failed[1].Name)
+	assert.Equal(t, "roleBindings", failed[2].Name)
+	assert.Equal(t, "roles", failed[3].Name)
+}
+
+func TestGetCollectorPermissions(t *testing.T) {
+	perms := GetCollectorPermissions(&configuration.TestConfiguration{}, true)
+	assert.Empty(t, perms[CollectorTargetNamespaces])
+	assert.Equal(t, []permissions.Permission{permissions.ListPods}, perms[CollectorPods])
+
+	perms = GetCollectorPermissions(&configuration.TestConfiguration{
+		ExcludedNameSpaceSelectors: []configuration.NamespaceSelector{{LabelSelector: "debug=true"}},
+	}, true)
+	assert.Equal(t, []permissions.Permission{permissions.ListNamespaces}, perms[CollectorTargetNamespaces])
+	assert.Equal(t, []permissions.Permission{permissions.GetClusterOperators}, perms[CollectorOpenshiftVersion])
+
+	// The cluster operators are not read in non-OCP clusters.
+	perms = GetCollectorPermissions(&configuration.TestConfiguration{}, false)
+	assert.Empty(t, perms[CollectorOpenshiftVersion])
+}
+
+func TestIsOpenshiftConfigAPIServed(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	assert.False(t, IsOpenshiftConfigAPIServed(client.Discovery()))
+
+	client.Resources = []*metav1.APIResourceList{{GroupVersion: "config.openshift.io/v1"}}
+	assert.True(t, IsOpenshiftConfigAPIServed(client.Discovery()))
+}
+
+// With a literal list of namespaces, the collectors depending on the target namespaces must run even
+// if the namespaces can't be listed.
+func TestRunCollectorsWithoutNamespaceSelectors(t *testing.T) {
+	perms := GetCollectorPermissions(&configuration.TestConfiguration{TargetNameSpaces: []configuration.Namespace{{Name: "tnf"}}}, true)
+	collectors := []collector{
+		{name: CollectorTargetNamespaces, permissions: perms[CollectorTargetNamespaces], collect: func() error { return nil }},
+		{name: CollectorPods, dependsOn: []string{CollectorTargetNamespaces}, permissions: perms[CollectorPods], collect: func() error { return nil }},
+	}
+
+	getMissing := func(perms []permissions.Permission) ([]permissions.Permission, error) {
+		missing := []permissions.Permission{}
+		for _, p := range perms {
+			if p == permissions.ListNamespaces {
+				missing = append(missing, p)
+			}
+		}
+		return missing, nil
+	}
+
+	report := runCollectors(context.TODO(), collectors, 2, getMissing)
+	assert.Empty(t, report.FailedCollectors())
+}
@@ -46,6 +46,16 @@ func getClusterCrdNames() ([]*apiextv1.CustomResourceDefinition, error) {
 	return crdList, nil
 }
 
+// GetCrdsUnderTest returns the CRDs of the cluster matching the CRD filters.
+func GetCrdsUnderTest(crdFilters []configuration.CrdFilter) ([]*apiextv1.CustomResourceDefinition, error) {
+	crds, err := getClusterCrdNames()
+	if err != nil {
+		return nil, err
+	}
+
+	return FindTestCrdNames(crds, crdFilters), nil
+}
+
 // FindTestCrdNames gets a list of CRD names based on configured groups.
 func FindTestCrdNames(clusterCrds []*apiextv1.CustomResourceDefinition, crdFilters []configuration.CrdFilter) (targetCrds []*apiextv1.CustomResourceDefinition) {
 	if len(clusterCrds) == 0 {
@@ -53,12 +63,19 @@ func FindTestCrdNames(clusterCrds []*apiextv1.CustomResourceDefinition, crdFilte
 		return []*apiextv1.CustomResourceDefinition{}
 	}
 	for _, crd := range clusterCrds {
-		for _, crdFilter := range crdFilters {
-			if strings.HasSuffix(crd.Name, crdFilter.NameSuffix) {
-				targetCrds = append(targetCrds, crd)
-				break
-			}
+		if getCrdFilter(crd.Name, crdFilters) != nil {
+			targetCrds = append(targetCrds, crd)
 		}
 	}
 	return targetCrds
 }
+
+// Returns the first filter matching the CRD name, or nil if none does.
+func getCrdFilter(crdName string, crdFilters []configuration.CrdFilter) *configuration.CrdFilter {
+	for i := range crdFilters {
+		if strings.HasSuffix(crdName, crdFilters[i].NameSuffix) {
+			return &crdFilters[i]
+		}
+	}
+	return nil
+}
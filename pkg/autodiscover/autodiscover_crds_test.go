@@ -130,3 +130,14 @@ func TestGetClusterCrdNames(t *testing.T) {
 		assert.Equal(t, tc.expectedTargetCRDs, crdNames)
 	}
 }
+
+func TestGetCrdFilter(t *testing.T) {
+	crdFilters := []configuration.CrdFilter{
+		{NameSuffix: "example.com", LabelSelector: "app=web"},
+		{NameSuffix: "com", FieldSelector: "metadata.name=cr1"},
+	}
+
+	assert.Equal(t, &crdFilters[0], getCrdFilter("crs.example.com", crdFilters))
+	assert.Equal(t, &crdFilters[1], getCrdFilter("crs.other.com", crdFilters))
+	assert.Nil(t, getCrdFilter("crs.example.org", crdFilters))
+}
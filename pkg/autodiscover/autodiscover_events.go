@@ -18,22 +18,21 @@ package autodiscover
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
-func findAbnormalEvents(oc corev1client.CoreV1Interface, namespaces []string) (abnormalEvents []corev1.Event) {
+func findAbnormalEvents(oc corev1client.CoreV1Interface, namespaces []string) (abnormalEvents []corev1.Event, err error) {
 	abnormalEvents = []corev1.Event{}
 	for _, ns := range namespaces {
 		someAbnormalEvents, err := oc.Events(ns).List(context.TODO(), metav1.ListOptions{FieldSelector: "type!=Normal"})
 		if err != nil {
-			log.Error("Failed to get event list for namespace %q, err: %v", ns, err)
-			continue
+			return nil, fmt.Errorf("failed to get event list for namespace %q, err: %v", ns, err)
 		}
 		abnormalEvents = append(abnormalEvents, someAbnormalEvents.Items...)
 	}
-	return abnormalEvents
+	return abnormalEvents, nil
 }
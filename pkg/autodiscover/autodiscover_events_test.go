@@ -52,7 +52,8 @@ func TestFindAbnormalEvents(t *testing.T) {
 
 		// Create fake client
 		client := k8sfake.NewSimpleClientset(runtimeObjects...)
-		abnormalEvents := findAbnormalEvents(client.CoreV1(), []string{"test-namespace"})
+		abnormalEvents, err := findAbnormalEvents(client.CoreV1(), []string{"test-namespace"})
+		assert.Nil(t, err)
 		assert.Len(t, abnormalEvents, len(testCase.expectedEvents))
 
 		for _, event := range abnormalEvents {
@@ -0,0 +1,81 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package autodiscover
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+func isNamespaceSelected(name string, namespaceLabels map[string]string, selectors []configuration.NamespaceSelector) bool {
+	for i := range selectors {
+		if selectors[i].Matches(name, namespaceLabels) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasNamespaceSelectors(config *configuration.TestConfiguration) bool {
+	return len(config.TargetNameSpaceSelectors) > 0 || len(config.ExcludedNameSpaceSelectors) > 0
+}
+
+// GetTargetNamespaces returns the namespaces under test: the ones in targetNameSpaces followed by
+// the ones matching any of the targetNameSpaceSelectors, without the ones matching any of the
+// excludedNameSpaceSelectors. The namespaces are only listed when there are selectors.
+func GetTargetNamespaces(oc corev1client.CoreV1Interface, config *configuration.TestConfiguration) ([]string, error) {
+	namespaces := namespacesListToStringList(config.TargetNameSpaces)
+	if !hasNamespaceSelectors(config) {
+		return namespaces, nil
+	}
+
+	nsList, err := oc.Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the namespaces to resolve the namespace selectors: %v", err)
+	}
+
+	namespacesLabels := map[string]map[string]string{}
+	for i := range nsList.Items {
+		ns := &nsList.Items[i]
+		namespacesLabels[ns.Name] = ns.Labels
+		if isNamespaceSelected(ns.Name, ns.Labels, config.TargetNameSpaceSelectors) {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+
+	targetNamespaces := []string{}
+	found := map[string]bool{}
+	for _, ns := range namespaces {
+		if found[ns] {
+			continue
+		}
+		found[ns] = true
+
+		if isNamespaceSelected(ns, namespacesLabels[ns], config.ExcludedNameSpaceSelectors) {
+			log.Info("Namespace %s is excluded from the namespaces under test", ns)
+			continue
+		}
+		targetNamespaces = append(targetNamespaces, ns)
+	}
+
+	return targetNamespaces, nil
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package autodiscover
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetTargetNamespaces(t *testing.T) {
+	generateNamespace := func(name string, nsLabels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nsLabels}}
+	}
+
+	client := fake.NewSimpleClientset(
+		generateNamespace("cnf-acme-1", map[string]string{"tenant": "acme"}),
+		generateNamespace("cnf-acme-2", map[string]string{"tenant": "acme", "debug": "true"}),
+		generateNamespace("cnf-other-1", map[string]string{"tenant": "other"}),
+		generateNamespace("tnf", nil),
+		generateNamespace("kube-system", nil),
+	)
+
+	testCases := []struct {
+		config   configuration.TestConfiguration
+		expected []string
+	}{
+		{
+			config:   configuration.TestConfiguration{TargetNameSpaces: []configuration.Namespace{{Name: "tnf"}, {Name: "missing"}}},
+			expected: []string{"tnf", "missing"},
+		},
+		{
+			config: configuration.TestConfiguration{
+				TargetNameSpaces:         []configuration.Namespace{{Name: "tnf"}},
+				TargetNameSpaceSelectors: []configuration.NamespaceSelector{{LabelSelector: "tenant=acme"}, {NamePattern: "cnf-*-1"}},
+			},
+			expected: []string{"tnf", "cnf-acme-1", "cnf-acme-2", "cnf-other-1"},
+		},
+		{
+			config: configuration.TestConfiguration{
+				TargetNameSpaces:           []configuration.Namespace{{Name: "tnf"}, {Name: "cnf-acme-1"}},
+				TargetNameSpaceSelectors:   []configuration.NamespaceSelector{{NameRegex: "cnf-[a-z]+-[0-9]+"}},
+				ExcludedNameSpaceSelectors: []configuration.NamespaceSelector{{LabelSelector: "debug=true"}, {NamePattern: "cnf-other-*"}},
+			},
+			expected: []string{"tnf", "cnf-acme-1"},
+		},
+		{
+			config: configuration.TestConfiguration{
+				TargetNameSpaces:           []configuration.Namespace{{Name: "tnf"}, {Name: "kube-system"}},
+				ExcludedNameSpaceSelectors: []configuration.NamespaceSelector{{NamePattern: "kube-*"}},
+			},
+			expected: []string{"tnf"},
+		},
+	}
+
+	for _, tc := range testCases {
+		namespaces, err := GetTargetNamespaces(client.CoreV1(), &tc.config)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expected, namespaces)
+	}
+
+	// The namespaces are not listed when there are no selectors.
+	failingClient := fake.NewSimpleClientset([]runtime.Object{}...)
+	failingClient.PrependReactor("list", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+
+	namespaces, err := GetTargetNamespaces(failingClient.CoreV1(), &configuration.TestConfiguration{TargetNameSpaces: []configuration.Namespace{{Name: "tnf"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"tnf"}, namespaces)
+
+	_, err = GetTargetNamespaces(failingClient.CoreV1(), &configuration.TestConfiguration{TargetNameSpaceSelectors: []configuration.NamespaceSelector{{NamePattern: "cnf-*"}}})
+	assert.EqualError(t, err, "failed to list the namespaces to resolve the namespace selectors: forbidden")
+}
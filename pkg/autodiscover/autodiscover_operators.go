@@ -24,11 +24,11 @@ import (
 	olmv1Alpha "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	clientOlm "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
-	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/stringhelper"
 	"helm.sh/helm/v3/pkg/release"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	appv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
@@ -61,36 +61,44 @@ func isIstioServiceMeshInstalled(appClient appv1client.AppsV1Interface, allNs []
 	return true
 }
 
-func findOperatorsMatchingAtLeastOneLabel(olmClient clientOlm.Interface, labels []labelObject, namespace configuration.Namespace) *olmv1Alpha.ClusterServiceVersionList {
+// Returns the CSVs matching at least one of the label selectors and the field selector, each only once.
+func findOperatorsMatchingAtLeastOneLabel(olmClient clientOlm.Interface, selectors []labels.Selector, fieldSelector, namespace string) (*olmv1Alpha.ClusterServiceVersionList, error) {
 	csvList := &olmv1Alpha.ClusterServiceVersionList{}
-	for _, l := range labels {
-		log.Debug("Searching CSVs in namespace %q with label %q", namespace, l)
-		csv, err := olmClient.OperatorsV1alpha1().ClusterServiceVersions(namespace.Name).List(context.TODO(), metav1.ListOptions{
-			LabelSelector: l.LabelKey + "=" + l.LabelValue,
+	found := map[string]bool{}
+	for _, selector := range selectors {
+		log.Debug("Searching CSVs in namespace %q with label selector %q", namespace, selector)
+		csv, err := olmClient.OperatorsV1alpha1().ClusterServiceVersions(namespace).List(context.TODO(), metav1.ListOptions{
+			LabelSelector: selector.String(),
+			FieldSelector: fieldSelector,
 		})
 		if err != nil {
-			log.Error("Error when listing csvs in namespace %q with label %q, err: %v", namespace, l.LabelKey+"="+l.LabelValue, err)
-			continue
+			return nil, fmt.Errorf("failed to list csvs in namespace %q with label %q, err: %v", namespace, selector, err)
+		}
+		for i := range csv.Items {
+			if !found[csv.Items[i].Name] {
+				found[csv.Items[i].Name] = true
+				csvList.Items = append(csvList.Items, csv.Items[i])
+			}
 		}
-		csvList.Items = append(csvList.Items, csv.Items...)
 	}
-	return csvList
+	return csvList, nil
 }
 
-func findOperatorsByLabels(olmClient clientOlm.Interface, labels []labelObject, namespaces []configuration.Namespace) (csvs []*olmv1Alpha.ClusterServiceVersion) {
+func findOperatorsByLabels(olmClient clientOlm.Interface, selectors []labels.Selector, fieldSelector string, namespaces []string) (csvs []*olmv1Alpha.ClusterServiceVersion, err error) {
 	csvs = []*olmv1Alpha.ClusterServiceVersion{}
 	var csvList *olmv1Alpha.ClusterServiceVersionList
 	for _, ns := range namespaces {
-		if len(labels) > 0 {
-			csvList = findOperatorsMatchingAtLeastOneLabel(olmClient, labels, ns)
+		if len(selectors) > 0 {
+			csvList, err = findOperatorsMatchingAtLeastOneLabel(olmClient, selectors, fieldSelector, ns)
+			if err != nil {
+				return nil, err
+			}
 		} else {
 			// If labels are not provided in the namespace under test, they are tested by the CNF suite
 			log.Debug("Searching CSVs in namespace %s without label", ns)
-			var err error
-			csvList, err = olmClient.OperatorsV1alpha1().ClusterServiceVersions(ns.Name).List(context.TODO(), metav1.ListOptions{})
+			csvList, err = olmClient.OperatorsV1alpha1().ClusterServiceVersions(ns).List(context.TODO(), metav1.ListOptions{FieldSelector: fieldSelector})
 			if err != nil {
-				log.Error("Error when listing csvs in namespace %q , err: %v", ns, err)
-				continue
+				return nil, fmt.Errorf("failed to list csvs in namespace %q, err: %v", ns, err)
 			}
 		}
 		for i := range csvList.Items {
@@ -100,7 +108,7 @@ func findOperatorsByLabels(olmClient clientOlm.Interface, labels []labelObject,
 	for i := range csvs {
 		log.Info("Found CSV %q (namespace %q)", csvs[i].Name, csvs[i].Namespace)
 	}
-	return csvs
+	return csvs, nil
 }
 
 func getAllNamespaces(oc corev1client.CoreV1Interface) (allNs []string, err error) {
@@ -130,7 +138,7 @@ func getAllOperators(olmClient clientOlm.Interface) ([]*olmv1Alpha.ClusterServic
 	return csvs, nil
 }
 
-func findSubscriptions(olmClient clientOlm.Interface, namespaces []string) []olmv1Alpha.Subscription {
+func findSubscriptions(olmClient clientOlm.Interface, namespaces []string) ([]olmv1Alpha.Subscription, error) {
 	subscriptions := []olmv1Alpha.Subscription{}
 	for _, ns := range namespaces {
 		displayNs := ns
@@ -140,8 +148,7 @@ func findSubscriptions(olmClient clientOlm.Interface, namespaces []string) []olm
 		log.Debug("Searching subscriptions in namespace %q", displayNs)
 		subscription, err := olmClient.OperatorsV1alpha1().Subscriptions(ns).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
-			log.Error("Error when listing subscriptions in namespace %q", ns)
-			continue
+			return nil, fmt.Errorf("failed to list subscriptions in namespace %q, err: %v", displayNs, err)
 		}
 		subscriptions = append(subscriptions, subscription.Items...)
 	}
@@ -149,10 +156,10 @@ func findSubscriptions(olmClient clientOlm.Interface, namespaces []string) []olm
 	for i := range subscriptions {
 		log.Info("Found subscription %q (ns %q)", subscriptions[i].Name, subscriptions[i].Namespace)
 	}
-	return subscriptions
+	return subscriptions, nil
 }
 
-func getHelmList(restConfig *rest.Config, namespaces []string) map[string][]*release.Release {
+func getHelmList(restConfig *rest.Config, namespaces []string) (map[string][]*release.Release, error) {
 	helmChartReleases := map[string][]*release.Release{}
 	for _, ns := range namespaces {
 		opt := &helmclient.RestConfClientOptions{
@@ -169,36 +176,34 @@ func getHelmList(restConfig *rest.Config, namespaces []string) map[string][]*rel
 
 		helmClient, err := helmclient.NewClientFromRestConf(opt)
 		if err != nil {
-			panic(err)
+			return nil, fmt.Errorf("failed to create the helm client for namespace %q, err: %v", ns, err)
 		}
 		nsHelmchartreleases, _ := helmClient.ListDeployedReleases()
 		helmChartReleases[ns] = nsHelmchartreleases
 	}
-	return helmChartReleases
+	return helmChartReleases, nil
 }
 
 // getAllInstallPlans is a helper function to get the all the installPlans in a cluster.
-func getAllInstallPlans(olmClient clientOlm.Interface) (out []*olmv1Alpha.InstallPlan) {
+func getAllInstallPlans(olmClient clientOlm.Interface) (out []*olmv1Alpha.InstallPlan, err error) {
 	installPlanList, err := olmClient.OperatorsV1alpha1().InstallPlans("").List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
-		log.Error("Unable get installplans in cluster, err: %v", err)
-		return out
+		return nil, fmt.Errorf("unable get installplans in cluster, err: %v", err)
 	}
 	for index := range installPlanList.Items {
 		out = append(out, &installPlanList.Items[index])
 	}
-	return out
+	return out, nil
 }
 
 // getAllCatalogSources is a helper function to get the all the CatalogSources in a cluster.
-func getAllCatalogSources(olmClient clientOlm.Interface) (out []*olmv1Alpha.CatalogSource) {
+func getAllCatalogSources(olmClient clientOlm.Interface) (out []*olmv1Alpha.CatalogSource, err error) {
 	catalogSourcesList, err := olmClient.OperatorsV1alpha1().CatalogSources("").List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
-		log.Error("Unable get CatalogSources in cluster, err: %v", err)
-		return out
+		return nil, fmt.Errorf("unable get CatalogSources in cluster, err: %v", err)
 	}
 	for index := range catalogSourcesList.Items {
 		out = append(out, &catalogSourcesList.Items[index])
 	}
-	return out
+	return out, nil
 }
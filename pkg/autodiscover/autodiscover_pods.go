@@ -18,45 +18,55 @@ package autodiscover
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
-func findPodsMatchingAtLeastOneLabel(oc corev1client.CoreV1Interface, labels []labelObject, namespace string) *corev1.PodList {
+// Returns the pods matching at least one of the label selectors and the field selector, each only once.
+func findPodsMatchingAtLeastOneLabel(oc corev1client.CoreV1Interface, selectors []labels.Selector, fieldSelector, namespace string) (*corev1.PodList, error) {
 	allPods := &corev1.PodList{}
-	for _, l := range labels {
-		log.Debug("Searching Pods in namespace %s with label %q", namespace, l)
+	found := map[string]bool{}
+	for _, selector := range selectors {
+		log.Debug("Searching Pods in namespace %s with label selector %q", namespace, selector)
 		pods, err := oc.Pods(namespace).List(context.TODO(), metav1.ListOptions{
-			LabelSelector: l.LabelKey + "=" + l.LabelValue,
+			LabelSelector: selector.String(),
+			FieldSelector: fieldSelector,
 		})
 		if err != nil {
-			log.Error("Error when listing pods in ns=%s label=%s, err: %v", namespace, l.LabelKey+"="+l.LabelValue, err)
-			continue
+			return nil, fmt.Errorf("failed to list pods in ns=%s label=%s, err: %v", namespace, selector, err)
+		}
+		for i := range pods.Items {
+			if !found[pods.Items[i].Name] {
+				found[pods.Items[i].Name] = true
+				allPods.Items = append(allPods.Items, pods.Items[i])
+			}
 		}
-		allPods.Items = append(allPods.Items, pods.Items...)
 	}
-	return allPods
+	return allPods, nil
 }
 
-func findPodsByLabels(oc corev1client.CoreV1Interface, labels []labelObject, namespaces []string) (runningPods, allPods []corev1.Pod) {
+func findPodsByLabels(oc corev1client.CoreV1Interface, selectors []labels.Selector, fieldSelector string, namespaces []string) (runningPods, allPods []corev1.Pod, err error) {
 	runningPods = []corev1.Pod{}
 	allPods = []corev1.Pod{}
 	// Iterate through namespaces
 	for _, ns := range namespaces {
 		var pods *corev1.PodList
-		if len(labels) > 0 {
-			pods = findPodsMatchingAtLeastOneLabel(oc, labels, ns)
+		if len(selectors) > 0 {
+			pods, err = findPodsMatchingAtLeastOneLabel(oc, selectors, fieldSelector, ns)
+			if err != nil {
+				return nil, nil, err
+			}
 		} else {
 			// If labels are not provided in the namespace under test, they are tested by the CNF suite
 			log.Debug("Searching Pods in namespace %s without label", ns)
-			var err error
-			pods, err = oc.Pods(ns).List(context.TODO(), metav1.ListOptions{})
+			pods, err = oc.Pods(ns).List(context.TODO(), metav1.ListOptions{FieldSelector: fieldSelector})
 			if err != nil {
-				log.Error("Error when listing pods in ns=%s, err: %v", ns, err)
-				continue
+				return nil, nil, fmt.Errorf("failed to list pods in ns=%s, err: %v", ns, err)
 			}
 		}
 		// Filter out any pod set to be deleted
@@ -68,5 +78,5 @@ func findPodsByLabels(oc corev1client.CoreV1Interface, labels []labelObject, nam
 		}
 	}
 
-	return runningPods, allPods
+	return runningPods, allPods, nil
 }
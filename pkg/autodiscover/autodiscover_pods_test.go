@@ -17,12 +17,16 @@
 package autodiscover
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
 )
@@ -83,7 +87,7 @@ func TestFindPodsUnderTest(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		testLabel := []labelObject{{LabelKey: "testLabel", LabelValue: tc.testPodLabel}}
+		testLabel := []labels.Selector{labels.SelectorFromSet(labels.Set{"testLabel": tc.testPodLabel})}
 		testNamespaces := []string{
 			tc.testPodNamespace,
 		}
@@ -91,7 +95,48 @@ func TestFindPodsUnderTest(t *testing.T) {
 		testRuntimeObjects = append(testRuntimeObjects, generatePod(tc.testPodName, tc.testPodNamespace, tc.queryLabel))
 		oc := clientsholder.GetTestClientsHolder(testRuntimeObjects)
 
-		podResult, _ := findPodsByLabels(oc.K8sClient.CoreV1(), testLabel, testNamespaces)
+		podResult, _, err := findPodsByLabels(oc.K8sClient.CoreV1(), testLabel, "", testNamespaces)
+		assert.Nil(t, err)
 		assert.Equal(t, tc.expectedResults, podResult)
 	}
 }
+
+func TestFindPodsByLabelSelectors(t *testing.T) {
+	generatePod := func(name string, podLabels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tnf", Labels: podLabels},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	oc := clientsholder.GetTestClientsHolder([]runtime.Object{
+		generatePod("web-a", map[string]string{"app": "a", "tier": "web"}),
+		generatePod("debug-a", map[string]string{"app": "a", "tier": "debug"}),
+		generatePod("web-c", map[string]string{"app": "c", "tier": "web"}),
+	})
+
+	// The pods matching several selectors are found only once.
+	selectors := createLabelSelectors([]string{"app in (a,b),tier!=debug", "tier: web"})
+	runningPods, allPods, err := findPodsByLabels(oc.K8sClient.CoreV1(), selectors, "", []string{"tnf"})
+	assert.Nil(t, err)
+	assert.Len(t, allPods, 2)
+	podNames := []string{}
+	for i := range runningPods {
+		podNames = append(podNames, runningPods[i].Name)
+	}
+	assert.ElementsMatch(t, []string{"web-a", "web-c"}, podNames)
+}
+
+func TestFindPodsByLabelsListError(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+
+	_, _, err := findPodsByLabels(client.CoreV1(), nil, "", []string{"tnf"})
+	assert.EqualError(t, err, "failed to list pods in ns=tnf, err: forbidden")
+
+	selectors := []labels.Selector{labels.SelectorFromSet(labels.Set{"app": "web"})}
+	_, _, err = findPodsByLabels(client.CoreV1(), selectors, "", []string{"tnf"})
+	assert.EqualError(t, err, "failed to list pods in ns=tnf label=app=web, err: forbidden")
+}
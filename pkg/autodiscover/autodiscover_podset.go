@@ -17,12 +17,14 @@ package autodiscover
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
 	appsv1 "k8s.io/api/apps/v1"
 	scalingv1 "k8s.io/api/autoscaling/v1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
 	appv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
@@ -55,37 +57,26 @@ func FindCrObjectByNameByNamespace(scalesGetter scale.ScalesGetter, ns, name str
 	return crScale, nil
 }
 
-func isDeploymentsPodsMatchingAtLeastOneLabel(labels []labelObject, namespace string, deployment *appsv1.Deployment) bool {
-	for _, aLabelObject := range labels {
-		log.Debug("Searching pods in deployment %q found in ns %q using label %s=%s", deployment.Name, namespace, aLabelObject.LabelKey, aLabelObject.LabelValue)
-		if deployment.Spec.Template.ObjectMeta.Labels[aLabelObject.LabelKey] == aLabelObject.LabelValue {
-			log.Info("Deployment %s found in ns=%s", deployment.Name, namespace)
-			return true
-		}
-	}
-	return false
-}
-
 //nolint:dupl
 func findDeploymentsByLabels(
 	appClient appv1client.AppsV1Interface,
-	labels []labelObject,
+	selectors []labels.Selector,
 	namespaces []string,
-) []appsv1.Deployment {
+) ([]appsv1.Deployment, error) {
 	allDeployments := []appsv1.Deployment{}
 	for _, ns := range namespaces {
 		dps, err := appClient.Deployments(ns).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
-			log.Error("Failed to list deployments in ns=%s, err: %v . Trying to proceed.", ns, err)
-			continue
+			return nil, fmt.Errorf("failed to list deployments in ns=%s, err: %v", ns, err)
 		}
 		if len(dps.Items) == 0 {
 			log.Warn("Did not find any deployments in ns=%s", ns)
 		}
 		for i := 0; i < len(dps.Items); i++ {
-			if len(labels) > 0 {
-				// The deployment is added only once if at least one pod matches one label in the Deployment
-				if isDeploymentsPodsMatchingAtLeastOneLabel(labels, ns, &dps.Items[i]) {
+			if len(selectors) > 0 {
+				// The deployment is added only once if its pods match at least one label selector
+				if isPodTemplateMatchingAtLeastOneLabel(selectors, &dps.Items[i].Spec.Template) {
+					log.Info("Deployment %s found in ns=%s", dps.Items[i].Name, ns)
 					allDeployments = append(allDeployments, dps.Items[i])
 					continue
 				}
@@ -100,40 +91,29 @@ func findDeploymentsByLabels(
 	if len(allDeployments) == 0 {
 		log.Warn("Did not find any deployment in the configured namespaces %v", namespaces)
 	}
-	return allDeployments
-}
-
-func isStatefulSetsMatchingAtLeastOneLabel(labels []labelObject, namespace string, statefulSet *appsv1.StatefulSet) bool {
-	for _, aLabelObject := range labels {
-		log.Debug("Searching pods in statefulset %q found in ns %q using label %s=%s", statefulSet.Name, namespace, aLabelObject.LabelKey, aLabelObject.LabelValue)
-		if statefulSet.Spec.Template.ObjectMeta.Labels[aLabelObject.LabelKey] == aLabelObject.LabelValue {
-			log.Info("StatefulSet %s found in ns=%s", statefulSet.Name, namespace)
-			return true
-		}
-	}
-	return false
+	return allDeployments, nil
 }
 
 //nolint:dupl
 func findStatefulSetsByLabels(
 	appClient appv1client.AppsV1Interface,
-	labels []labelObject,
+	selectors []labels.Selector,
 	namespaces []string,
-) []appsv1.StatefulSet {
+) ([]appsv1.StatefulSet, error) {
 	allStatefulSets := []appsv1.StatefulSet{}
 	for _, ns := range namespaces {
 		statefulSet, err := appClient.StatefulSets(ns).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
-			log.Error("Failed to list statefulsets in ns=%s, err: %v . Trying to proceed.", ns, err)
-			continue
+			return nil, fmt.Errorf("failed to list statefulsets in ns=%s, err: %v", ns, err)
 		}
 		if len(statefulSet.Items) == 0 {
 			log.Warn("Did not find any statefulSet in ns=%s", ns)
 		}
 		for i := 0; i < len(statefulSet.Items); i++ {
-			if len(labels) > 0 {
-				// The StatefulSet is added only once if at least one pod matches one label in the Statefulset
-				if isStatefulSetsMatchingAtLeastOneLabel(labels, ns, &statefulSet.Items[i]) {
+			if len(selectors) > 0 {
+				// The StatefulSet is added only once if its pods match at least one label selector
+				if isPodTemplateMatchingAtLeastOneLabel(selectors, &statefulSet.Items[i].Spec.Template) {
+					log.Info("StatefulSet %s found in ns=%s", statefulSet.Items[i].Name, ns)
 					allStatefulSets = append(allStatefulSets, statefulSet.Items[i])
 					continue
 				}
@@ -148,16 +128,15 @@ func findStatefulSetsByLabels(
 	if len(allStatefulSets) == 0 {
 		log.Warn("Did not find any statefulset in the configured namespaces %v", namespaces)
 	}
-	return allStatefulSets
+	return allStatefulSets, nil
 }
 
-func findHpaControllers(cs kubernetes.Interface, namespaces []string) []*scalingv1.HorizontalPodAutoscaler {
+func findHpaControllers(cs kubernetes.Interface, namespaces []string) ([]*scalingv1.HorizontalPodAutoscaler, error) {
 	var m []*scalingv1.HorizontalPodAutoscaler
 	for _, ns := range namespaces {
 		hpas, err := cs.AutoscalingV1().HorizontalPodAutoscalers(ns).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
-			log.Error("Cannot list HorizontalPodAutoscalers on namespace %q, err: %v", ns, err)
-			return m
+			return nil, fmt.Errorf("cannot list HorizontalPodAutoscalers on namespace %q, err: %v", ns, err)
 		}
 		for i := 0; i < len(hpas.Items); i++ {
 			m = append(m, &hpas.Items[i])
@@ -166,5 +145,5 @@ func findHpaControllers(cs kubernetes.Interface, namespaces []string) []*scaling
 	if len(m) == 0 {
 		log.Info("Cannot find any deployed HorizontalPodAutoscaler")
 	}
-	return m
+	return m, nil
 }
@@ -16,6 +16,7 @@
 package autodiscover
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -23,7 +24,10 @@ import (
 	scalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
 )
@@ -90,7 +94,7 @@ func TestFindDeploymentsUnderTest(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		testLabel := []labelObject{{LabelKey: "testLabel", LabelValue: tc.testDeploymentLabel}}
+		testLabel := []labels.Selector{labels.SelectorFromSet(labels.Set{"testLabel": tc.testDeploymentLabel})}
 
 		testNamespaces := []string{
 			tc.testDeploymentNamespace,
@@ -99,7 +103,8 @@ func TestFindDeploymentsUnderTest(t *testing.T) {
 		testRuntimeObjects = append(testRuntimeObjects, generateDeployment(tc.testDeploymentName, tc.testDeploymentNamespace, tc.queryLabel))
 		oc := clientsholder.GetTestClientsHolder(testRuntimeObjects)
 
-		deployments := findDeploymentsByLabels(oc.K8sClient.AppsV1(), testLabel, testNamespaces)
+		deployments, err := findDeploymentsByLabels(oc.K8sClient.AppsV1(), testLabel, testNamespaces)
+		assert.Nil(t, err)
 		assert.Equal(t, tc.expectedResults, deployments)
 	}
 }
@@ -166,7 +171,7 @@ func TestFindStatefulSetsUnderTest(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		testLabel := []labelObject{{LabelKey: "testLabel", LabelValue: tc.testStatefulSetLabel}}
+		testLabel := []labels.Selector{labels.SelectorFromSet(labels.Set{"testLabel": tc.testStatefulSetLabel})}
 		testNamespaces := []string{
 			tc.testStatefulSetNamespace,
 		}
@@ -174,7 +179,8 @@ func TestFindStatefulSetsUnderTest(t *testing.T) {
 		testRuntimeObjects = append(testRuntimeObjects, generateStatefulSet(tc.testStatefulSetName, tc.testStatefulSetNamespace, tc.queryLabel))
 		oc := clientsholder.GetTestClientsHolder(testRuntimeObjects)
 
-		statefulSets := findStatefulSetsByLabels(oc.K8sClient.AppsV1(), testLabel, testNamespaces)
+		statefulSets, err := findStatefulSetsByLabels(oc.K8sClient.AppsV1(), testLabel, testNamespaces)
+		assert.Nil(t, err)
 		assert.Equal(t, tc.expectedResults, statefulSets)
 	}
 }
@@ -213,7 +219,8 @@ func TestFindHpaControllers(t *testing.T) {
 		testRuntimeObjects = append(testRuntimeObjects, generateHpa(tc.testHpaName, tc.testHpaNamespace))
 		oc := clientsholder.GetTestClientsHolder(testRuntimeObjects)
 
-		hpas := findHpaControllers(oc.K8sClient, []string{tc.testHpaNamespace})
+		hpas, err := findHpaControllers(oc.K8sClient, []string{tc.testHpaNamespace})
+		assert.Nil(t, err)
 		assert.Equal(t, tc.expectedResults, hpas)
 	}
 }
@@ -293,3 +300,19 @@ func TestFindStatefulSetByNameByNamespace(t *testing.T) {
 		assert.Equal(t, tc.expectedResults, statefulSet)
 	}
 }
+
+func TestFindPodSetsListErrors(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+
+	_, err := findDeploymentsByLabels(client.AppsV1(), nil, []string{"tnf"})
+	assert.EqualError(t, err, "failed to list deployments in ns=tnf, err: forbidden")
+
+	_, err = findStatefulSetsByLabels(client.AppsV1(), nil, []string{"tnf"})
+	assert.EqualError(t, err, "failed to list statefulsets in ns=tnf, err: forbidden")
+
+	_, err = findHpaControllers(client, []string{"tnf"})
+	assert.EqualError(t, err, "cannot list HorizontalPodAutoscalers on namespace \"tnf\", err: forbidden")
+}
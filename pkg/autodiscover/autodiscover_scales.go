@@ -2,9 +2,12 @@ package autodiscover
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
 	scalingv1 "k8s.io/api/autoscaling/v1"
 	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,7 +20,7 @@ type ScaleObject struct {
 	GroupResourceSchema schema.GroupResource
 }
 
-func GetScaleCrUnderTest(namespaces []string, crds []*apiextv1.CustomResourceDefinition) []ScaleObject {
+func GetScaleCrUnderTest(namespaces []string, crds []*apiextv1.CustomResourceDefinition, crdFilters []configuration.CrdFilter) ([]ScaleObject, error) {
 	dynamicClient := clientsholder.GetClientsHolder().DynamicClient
 
 	var scaleObjects []ScaleObject
@@ -27,6 +30,13 @@ func GetScaleCrUnderTest(namespaces []string, crds []*apiextv1.CustomResourceDef
 			continue
 		}
 
+		// The CRs under test are the ones matching the selectors of the CRD filter, if any.
+		listOptions := metav1.ListOptions{}
+		if crdFilter := getCrdFilter(crd.Name, crdFilters); crdFilter != nil {
+			listOptions.LabelSelector = crdFilter.LabelSelector
+			listOptions.FieldSelector = crdFilter.FieldSelector
+		}
+
 		for i := range crd.Spec.Versions {
 			crdVersion := crd.Spec.Versions[i]
 			gvr := schema.GroupVersionResource{
@@ -45,13 +55,17 @@ func GetScaleCrUnderTest(namespaces []string, crds []*apiextv1.CustomResourceDef
 				crd.Name, crdVersion.Name, crd.Spec.Group, crd.Spec.Names.Plural)
 
 			for _, ns := range namespaces {
-				crs, err := dynamicClient.Resource(gvr).Namespace(ns).List(context.TODO(), metav1.ListOptions{})
+				crs, err := dynamicClient.Resource(gvr).Namespace(ns).List(context.TODO(), listOptions)
 				if err != nil {
-					log.Fatal("Error getting CRs of CRD %q in namespace %q, err: %v", crd.Name, ns, err)
+					return nil, fmt.Errorf("error getting CRs of CRD %q in namespace %q, err: %v", crd.Name, ns, err)
 				}
 
 				if len(crs.Items) > 0 {
-					scaleObjects = append(scaleObjects, getCrScaleObjects(crs.Items, crd)...)
+					crScaleObjects, err := getCrScaleObjects(crs.Items, crd)
+					if err != nil {
+						return nil, err
+					}
+					scaleObjects = append(scaleObjects, crScaleObjects...)
 				} else {
 					log.Warn("No CRs of CRD %q found in the target namespaces.", crd.Name)
 				}
@@ -59,10 +73,10 @@ func GetScaleCrUnderTest(namespaces []string, crds []*apiextv1.CustomResourceDef
 		}
 	}
 
-	return scaleObjects
+	return scaleObjects, nil
 }
 
-func getCrScaleObjects(crs []unstructured.Unstructured, crd *apiextv1.CustomResourceDefinition) []ScaleObject {
+func getCrScaleObjects(crs []unstructured.Unstructured, crd *apiextv1.CustomResourceDefinition) ([]ScaleObject, error) {
 	var scaleObjects []ScaleObject
 	clients := clientsholder.GetClientsHolder()
 	for _, cr := range crs {
@@ -75,10 +89,40 @@ func getCrScaleObjects(crs []unstructured.Unstructured, crd *apiextv1.CustomReso
 		namespace := cr.GetNamespace()
 		crScale, err := clients.ScalingClient.Scales(namespace).Get(context.TODO(), groupResourceSchema, name, metav1.GetOptions{})
 		if err != nil {
-			log.Fatal("Error while getting the scale of CR=%s (CRD=%s) in namespace %s: %v", name, crd.Name, namespace, err)
+			return nil, fmt.Errorf("error while getting the scale of CR=%s (CRD=%s) in namespace %s: %v", name, crd.Name, namespace, err)
 		}
 
 		scaleObjects = append(scaleObjects, ScaleObject{Scale: crScale, GroupResourceSchema: groupResourceSchema})
 	}
-	return scaleObjects
+	return scaleObjects, nil
+}
+
+// Returns whether any version of the CRD has the scale subresource.
+func hasScaleSubresource(crd *apiextv1.CustomResourceDefinition) bool {
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Subresources != nil && crd.Spec.Versions[i].Subresources.Scale != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetCrScalePermissions returns the permissions needed to scale the CRs of the given CRDs, i.e. get
+// and update their scale subresource in the target namespaces. Only the CRs of the namespaced CRDs
+// with a scale subresource are scaled.
+func GetCrScalePermissions(crds []*apiextv1.CustomResourceDefinition) []permissions.Permission {
+	perms := []permissions.Permission{}
+	for _, crd := range crds {
+		if crd.Spec.Scope != apiextv1.NamespaceScoped || !hasScaleSubresource(crd) {
+			continue
+		}
+
+		for _, verb := range []string{"get", "update"} {
+			perms = append(perms, permissions.Permission{Verb: verb, Group: crd.Spec.Group, Resource: crd.Spec.Names.Plural,
+				Subresource: "scale", Namespace: permissions.TargetNamespaces})
+		}
+	}
+
+	return perms
 }
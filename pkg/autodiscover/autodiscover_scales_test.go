@@ -1 +1,36 @@
 package autodiscover
+
+import (
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
+	"github.com/stretchr/testify/assert"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestGetCrScalePermissions(t *testing.T) {
+	generateCrd := func(group, plural string, scope apiextv1.ResourceScope, scalable bool) *apiextv1.CustomResourceDefinition {
+		version := apiextv1.CustomResourceDefinitionVersion{Name: "v1"}
+		if scalable {
+			version.Subresources = &apiextv1.CustomResourceSubresources{Scale: &apiextv1.CustomResourceSubresourceScale{}}
+		}
+		return &apiextv1.CustomResourceDefinition{Spec: apiextv1.CustomResourceDefinitionSpec{
+			Group:    group,
+			Names:    apiextv1.CustomResourceDefinitionNames{Plural: plural},
+			Scope:    scope,
+			Versions: []apiextv1.CustomResourceDefinitionVersion{version},
+		}}
+	}
+
+	crds := []*apiextv1.CustomResourceDefinition{
+		generateCrd("acme.com", "memcacheds", apiextv1.NamespaceScoped, true),
+		generateCrd("acme.com", "configs", apiextv1.NamespaceScoped, false),
+		generateCrd("acme.com", "clusters", apiextv1.ClusterScoped, true),
+	}
+
+	assert.Equal(t, []permissions.Permission{
+		{Verb: "get", Group: "acme.com", Resource: "memcacheds", Subresource: "scale", Namespace: permissions.TargetNamespaces},
+		{Verb: "update", Group: "acme.com", Resource: "memcacheds", Subresource: "scale", Namespace: permissions.TargetNamespaces},
+	}, GetCrScalePermissions(crds))
+	assert.Empty(t, GetCrScalePermissions(nil))
+}
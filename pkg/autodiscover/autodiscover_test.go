@@ -17,43 +17,40 @@
 package autodiscover
 
 import (
-	"reflect"
 	"testing"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestCreateLabels(t *testing.T) {
-	type args struct {
+func TestCreateLabelSelectors(t *testing.T) {
+	testCases := []struct {
 		labelStrings []string
-	}
-	tests := []struct {
-		name             string
-		args             args
-		wantLabelObjects []labelObject
+		expected     []string
 	}{
 		{
-			name:             "ok",
-			args:             args{labelStrings: []string{"redhat-best-practices-for-k8s.com/generic: target"}},
-			wantLabelObjects: []labelObject{{LabelKey: "redhat-best-practices-for-k8s.com/generic", LabelValue: "target"}},
+			labelStrings: []string{"redhat-best-practices-for-k8s.com/generic: target"},
+			expected:     []string{"redhat-best-practices-for-k8s.com/generic=target"},
+		},
+		{
+			labelStrings: []string{"redhat-best-practices-for-k8s.com/generic   : 1", "app in (a,b),tier!=debug"},
+			expected:     []string{"redhat-best-practices-for-k8s.com/generic=1", "app in (a,b),tier!=debug"},
 		},
 		{
-			name:             "ok1",
-			args:             args{labelStrings: []string{"redhat-best-practices-for-k8s.com/generic   : 1"}},
-			wantLabelObjects: []labelObject{{LabelKey: "redhat-best-practices-for-k8s.com/generic", LabelValue: "1"}},
+			labelStrings: []string{"redhat-best-practices-for-k8s.com/generic= target"},
+			expected:     []string{"redhat-best-practices-for-k8s.com/generic=target"},
 		},
 		{
-			name: "nok",
-			args: args{labelStrings: []string{"redhat-best-practices-for-k8s.com/generic= target"}},
+			labelStrings: []string{"app in a", "app: web: x"},
 		},
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if gotLabelObjects := createLabels(tt.args.labelStrings); !reflect.DeepEqual(gotLabelObjects, tt.wantLabelObjects) {
-				t.Errorf("createLabels() = %v, want %v", gotLabelObjects, tt.wantLabelObjects)
-			}
-		})
+
+	for _, tc := range testCases {
+		var selectors []string
+		for _, selector := range createLabelSelectors(tc.labelStrings) {
+			selectors = append(selectors, selector.String())
+		}
+		assert.Equal(t, tc.expected, selectors)
 	}
 }
 
@@ -0,0 +1,128 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package autodiscover
+
+import (
+	"context"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	appv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
+)
+
+// Returns whether the pods created from the template match at least one of the label selectors. With
+// no selectors, every pod is under test.
+func isPodTemplateMatchingAtLeastOneLabel(selectors []labels.Selector, template *corev1.PodTemplateSpec) bool {
+	if len(selectors) == 0 {
+		return true
+	}
+
+	for _, selector := range selectors {
+		if selector.Matches(labels.Set(template.ObjectMeta.Labels)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns whether the object is managed by a controller, like the ReplicaSets of a Deployment or the
+// Jobs of a CronJob.
+func hasController(object *metav1.ObjectMeta) bool {
+	return metav1.GetControllerOfNoCopy(object) != nil
+}
+
+func findDaemonSetsByLabels(appClient appv1client.AppsV1Interface, selectors []labels.Selector, namespaces []string) ([]appsv1.DaemonSet, error) {
+	daemonSets := []appsv1.DaemonSet{}
+	for _, ns := range namespaces {
+		list, err := appClient.DaemonSets(ns).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			if isPodTemplateMatchingAtLeastOneLabel(selectors, &list.Items[i].Spec.Template) {
+				log.Info("DaemonSet %s found in ns=%s", list.Items[i].Name, ns)
+				daemonSets = append(daemonSets, list.Items[i])
+			}
+		}
+	}
+
+	return daemonSets, nil
+}
+
+// Only the ReplicaSets with no controller are returned, as the ones of the Deployments are tested
+// through them.
+func findReplicaSetsByLabels(appClient appv1client.AppsV1Interface, selectors []labels.Selector, namespaces []string) ([]appsv1.ReplicaSet, error) {
+	replicaSets := []appsv1.ReplicaSet{}
+	for _, ns := range namespaces {
+		list, err := appClient.ReplicaSets(ns).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			if !hasController(&list.Items[i].ObjectMeta) && isPodTemplateMatchingAtLeastOneLabel(selectors, &list.Items[i].Spec.Template) {
+				log.Info("ReplicaSet %s found in ns=%s", list.Items[i].Name, ns)
+				replicaSets = append(replicaSets, list.Items[i])
+			}
+		}
+	}
+
+	return replicaSets, nil
+}
+
+// Only the Jobs with no controller are returned, as the ones of the CronJobs are tested through
+// the job template of the CronJob.
+func findJobsByLabels(batchClient batchv1client.BatchV1Interface, selectors []labels.Selector, namespaces []string) ([]batchv1.Job, error) {
+	jobs := []batchv1.Job{}
+	for _, ns := range namespaces {
+		list, err := batchClient.Jobs(ns).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			if !hasController(&list.Items[i].ObjectMeta) && isPodTemplateMatchingAtLeastOneLabel(selectors, &list.Items[i].Spec.Template) {
+				log.Info("Job %s found in ns=%s", list.Items[i].Name, ns)
+				jobs = append(jobs, list.Items[i])
+			}
+		}
+	}
+
+	return jobs, nil
+}
+
+func findCronJobsByLabels(batchClient batchv1client.BatchV1Interface, selectors []labels.Selector, namespaces []string) ([]batchv1.CronJob, error) {
+	cronJobs := []batchv1.CronJob{}
+	for _, ns := range namespaces {
+		list, err := batchClient.CronJobs(ns).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			if isPodTemplateMatchingAtLeastOneLabel(selectors, &list.Items[i].Spec.JobTemplate.Spec.Template) {
+				log.Info("CronJob %s found in ns=%s", list.Items[i].Name, ns)
+				cronJobs = append(cronJobs, list.Items[i])
+			}
+		}
+	}
+
+	return cronJobs, nil
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package autodiscover
+
+import (
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func generateTemplate(label string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"testLabel": label}}}
+}
+
+func generateObjectMeta(name, namespace string, controlled bool) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{Name: name, Namespace: namespace}
+	if controlled {
+		isController := true
+		meta.OwnerReferences = []metav1.OwnerReference{{Kind: "Owner", Name: "owner", Controller: &isController}}
+	}
+	return meta
+}
+
+func TestIsPodTemplateMatchingAtLeastOneLabel(t *testing.T) {
+	template := generateTemplate("mylabel")
+
+	assert.True(t, isPodTemplateMatchingAtLeastOneLabel(nil, &template))
+	assert.True(t, isPodTemplateMatchingAtLeastOneLabel(createLabelSelectors([]string{"other: x", "testLabel: mylabel"}), &template))
+	assert.False(t, isPodTemplateMatchingAtLeastOneLabel(createLabelSelectors([]string{"testLabel: badlabel"}), &template))
+	assert.True(t, isPodTemplateMatchingAtLeastOneLabel(createLabelSelectors([]string{"testLabel in (mylabel,other),!debug"}), &template))
+	// All the requirements of a selector must match.
+	assert.False(t, isPodTemplateMatchingAtLeastOneLabel(createLabelSelectors([]string{"testLabel=mylabel,tier=web"}), &template))
+	assert.False(t, isPodTemplateMatchingAtLeastOneLabel(createLabelSelectors([]string{"testLabel notin (mylabel)"}), &template))
+}
+
+func TestFindWorkloadsByLabels(t *testing.T) {
+	selectors := createLabelSelectors([]string{"testLabel: mylabel"})
+	namespaces := []string{"ns1", "ns2"}
+
+	testRuntimeObjects := []runtime.Object{
+		&appsv1.DaemonSet{ObjectMeta: generateObjectMeta("ds1", "ns1", false), Spec: appsv1.DaemonSetSpec{Template: generateTemplate("mylabel")}},
+		&appsv1.DaemonSet{ObjectMeta: generateObjectMeta("ds2", "ns2", false), Spec: appsv1.DaemonSetSpec{Template: generateTemplate("badlabel")}},
+		&appsv1.DaemonSet{ObjectMeta: generateObjectMeta("ds3", "ns3", false), Spec: appsv1.DaemonSetSpec{Template: generateTemplate("mylabel")}},
+		&appsv1.ReplicaSet{ObjectMeta: generateObjectMeta("rs1", "ns1", false), Spec: appsv1.ReplicaSetSpec{Template: generateTemplate("mylabel")}},
+		&appsv1.ReplicaSet{ObjectMeta: generateObjectMeta("rs2", "ns1", true), Spec: appsv1.ReplicaSetSpec{Template: generateTemplate("mylabel")}},
+		&batchv1.Job{ObjectMeta: generateObjectMeta("job1", "ns2", false), Spec: batchv1.JobSpec{Template: generateTemplate("mylabel")}},
+		&batchv1.Job{ObjectMeta: generateObjectMeta("job2", "ns2", true), Spec: batchv1.JobSpec{Template: generateTemplate("mylabel")}},
+		&batchv1.CronJob{ObjectMeta: generateObjectMeta("cj1", "ns1", false), Spec: batchv1.CronJobSpec{
+			JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{Template: generateTemplate("mylabel")}},
+		}},
+		&batchv1.CronJob{ObjectMeta: generateObjectMeta("cj2", "ns1", false), Spec: batchv1.CronJobSpec{
+			JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{Template: generateTemplate("badlabel")}},
+		}},
+	}
+	oc := clientsholder.GetTestClientsHolder(testRuntimeObjects)
+
+	daemonSets, err := findDaemonSetsByLabels(oc.K8sClient.AppsV1(), selectors, namespaces)
+	assert.Nil(t, err)
+	assert.Len(t, daemonSets, 1)
+	assert.Equal(t, "ds1", daemonSets[0].Name)
+
+	daemonSets, err = findDaemonSetsByLabels(oc.K8sClient.AppsV1(), nil, namespaces)
+	assert.Nil(t, err)
+	assert.Len(t, daemonSets, 2)
+
+	replicaSets, err := findReplicaSetsByLabels(oc.K8sClient.AppsV1(), selectors, namespaces)
+	assert.Nil(t, err)
+	assert.Len(t, replicaSets, 1)
+	assert.Equal(t, "rs1", replicaSets[0].Name)
+
+	jobs, err := findJobsByLabels(oc.K8sClient.BatchV1(), selectors, namespaces)
+	assert.Nil(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, "job1", jobs[0].Name)
+
+	cronJobs, err := findCronJobsByLabels(oc.K8sClient.BatchV1(), selectors, namespaces)
+	assert.Nil(t, err)
+	assert.Len(t, cronJobs, 1)
+	assert.Equal(t, "cj1", cronJobs[0].Name)
+}
@@ -1,22 +1,29 @@
 package certsuite
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/cli"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/events"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/results"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/tracing"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/claimhelper"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/collector"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/metrics"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/scoring"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/versions"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/waivers"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/accesscontrol"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/certification"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/lifecycle"
@@ -26,6 +33,8 @@ import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/operator"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/performance"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/platform"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/plugins"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/policies"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/preflight"
 )
 
@@ -44,14 +53,41 @@ func LoadInternalChecksDB() {
 func LoadChecksDB(labelsExpr string) {
 	LoadInternalChecksDB()
 
+	if err := loadCustomChecksDB(); err != nil {
+		log.Fatal("%v", err)
+	}
+
+	if provider.IsOfflineMode() {
+		log.Warn("The preflight checks cannot run offline from a cluster snapshot.")
+		return
+	}
+
 	if preflight.ShouldRun(labelsExpr) {
 		preflight.LoadChecks()
 	}
 }
 
+// Loads the checks of the plugins and the policies set in the test parameters.
+func loadCustomChecksDB() error {
+	if pluginsDir := configuration.GetTestParameters().PluginsDir; pluginsDir != "" {
+		if err := plugins.LoadChecks(pluginsDir); err != nil {
+			return fmt.Errorf("failed to load the checks of the plugins in %s: %v", pluginsDir, err)
+		}
+	}
+
+	if policiesFile := configuration.GetTestParameters().PoliciesFile; policiesFile != "" {
+		if err := policies.LoadChecks(policiesFile); err != nil {
+			return fmt.Errorf("failed to load the policies from %s: %v", policiesFile, err)
+		}
+	}
+
+	return nil
+}
+
 const (
 	junitXMLOutputFileName = "cnf-certification-tests_junit.xml"
-	claimFileName          = "claim.json"
+	sarifOutputFileName    = "results.sarif"
+	ClaimFileName          = "claim.json"
 	collectorAppURL        = "http://claims-collector.cnf-certifications.sysdeseng.com"
 	timeoutDefaultvalue    = 24 * time.Hour
 	noLabelsFilterExpr     = "none"
@@ -80,6 +116,47 @@ func getK8sClientsConfigFileNames() []string {
 	return fileNames
 }
 
+func getCheckTimeoutsByID(checkTimeouts []configuration.CheckTimeoutInfo) map[string]time.Duration {
+	timeoutsByID := map[string]time.Duration{}
+	for _, checkTimeout := range checkTimeouts {
+		timeoutsByID[checkTimeout.ID] = checkTimeout.Timeout
+	}
+
+	return timeoutsByID
+}
+
+func getResultsCountsByState(results map[string]claim.Result) map[string]int {
+	counts := map[string]int{}
+	for checkID := range results {
+		counts[results[checkID].State]++
+	}
+
+	return counts
+}
+
+// Loads the waivers file. Expired waivers don't waive anything, so the checks using them will fail,
+// but they're reported before running any check so they don't go unnoticed, and they're fatal if
+// failOnExpired is set.
+func loadWaivers(waiversFile string, failOnExpired bool) error {
+	waiversList, err := waivers.LoadWaivers(waiversFile)
+	if err != nil {
+		return err
+	}
+
+	expiredWaivers := checksdb.SetWaivers(waiversList)
+	fmt.Printf("Waivers file: %s (%d waivers)\n", waiversFile, len(waiversList))
+	for i := range expiredWaivers {
+		fmt.Fprintf(os.Stderr, "%sEXPIRED WAIVER%s: %s\n", cli.Red, cli.Reset, expiredWaivers[i].String())
+	}
+	fmt.Printf("\n")
+
+	if failOnExpired && len(expiredWaivers) > 0 {
+		return fmt.Errorf("the waivers file %s has %d expired waivers", waiversFile, len(expiredWaivers))
+	}
+
+	return nil
+}
+
 func Startup() {
 	testParams := configuration.GetTestParameters()
 
@@ -94,19 +171,52 @@ func Startup() {
 		os.Exit(1)
 	}
 
+	if testParams.EventsFile != "" {
+		if err := events.OpenFile(testParams.EventsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not create the events file, err: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := tracing.Setup(testParams.OTLPEndpoint, testParams.TracesFile, versions.GitVersion()); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not set up the tracing, err: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Diagnostic functions will run when no labels are provided.
 	if testParams.LabelsFilter == noLabelsFilterExpr {
 		log.Warn("The Best Practices Test Suite will run in diagnostic mode so no test case will be launched")
 	}
 
-	// Set clientsholder singleton with the filenames from the env vars.
-	_ = clientsholder.GetClientsHolder(getK8sClientsConfigFileNames()...)
+	if testParams.FromSnapshot != "" {
+		// The clientsholder singleton is set with fake clients serving the snapshot's objects.
+		if err := loadSnapshot(testParams.FromSnapshot); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load the cluster snapshot, err: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		// Set clientsholder singleton with the filenames from the env vars.
+		client := clientsholder.GetClientsHolder(getK8sClientsConfigFileNames()...)
+		if testParams.RecordExecs {
+			if err := startExecsRecording(client, testParams.OutputDir); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to start recording the commands run in the containers, err: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
 	LoadChecksDB(testParams.LabelsFilter)
 
+	suitesOrder, err := checksdb.ResolveChecksOrder()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve the checks order, err: %v\n", err)
+		os.Exit(1)
+	}
+
 	log.Info("Certsuite Version: %v", versions.GitVersion())
 	log.Info("Claim Format Version: %s", versions.ClaimFormatVersion)
 	log.Info("Labels filter: %v", testParams.LabelsFilter)
 	log.Info("Log level: %s", strings.ToUpper(testParams.LogLevel))
+	log.Info("Checks order: %s", strings.Join(checksdb.GetChecksOrder(), ", "))
 
 	log.Debug("Test parameters: %#v", *configuration.GetTestParameters())
 
@@ -115,12 +225,29 @@ func Startup() {
 	fmt.Printf("Certsuite version: %s\n", versions.GitVersion())
 	fmt.Printf("Claim file version: %s\n", versions.ClaimFormatVersion)
 	fmt.Printf("Checks filter: %s\n", testParams.LabelsFilter)
+	fmt.Printf("Suites order: %s\n", strings.Join(suitesOrder, ", "))
+	if testParams.FromSnapshot != "" {
+		fmt.Printf("Cluster snapshot: %s\n", testParams.FromSnapshot)
+	}
+	if execRecorder != nil {
+		fmt.Printf("Recorded commands file: %s\n", filepath.Join(testParams.OutputDir, ExecRecordsFileName))
+	}
 	fmt.Printf("Output folder: %s\n", testParams.OutputDir)
 	fmt.Printf("Log file: %s (level=%s)\n", log.LogFileName, testParams.LogLevel)
 	fmt.Printf("\n")
 }
 
 func Shutdown() {
+	stopExecsRecording()
+
+	if err := events.CloseFile(); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not close the events file, err: %v\n", err)
+	}
+
+	if err := tracing.Shutdown(); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not shut down the tracing, err: %v\n", err)
+	}
+
 	err := log.CloseGlobalLogFile()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Could not close the log file, err: %v\n", err)
@@ -132,26 +259,74 @@ func Shutdown() {
 func Run(labelsFilter, outputFolder string) error {
 	testParams := configuration.GetTestParameters()
 
+	runStartTime := time.Now()
+	events.EmitRunStarted(labelsFilter)
+
+	ctx, span := tracing.Start(context.Background(), "certsuite.Run", tracing.AttrLabelsFilter.String(labelsFilter))
+	defer span.End()
+	restoreTracingCtx := tracing.SetCurrentContext(ctx)
+	defer restoreTracingCtx()
+
 	fmt.Println("Running discovery of CNF target resources...")
 	fmt.Print("\n")
 
 	env := provider.GetTestEnvironment()
+	events.EmitDiscoveryFinished(env.GetDiscoveredObjectsCounts())
 
 	claimBuilder, err := claimhelper.NewClaimBuilder()
 	if err != nil {
 		log.Fatal("Failed to get claim builder: %v", err)
 	}
 
-	claimOutputFile := filepath.Join(outputFolder, claimFileName)
+	claimOutputFile := filepath.Join(outputFolder, ClaimFileName)
+
+	checksdb.SetCheckTimeouts(env.Config.DefaultCheckTimeout, getCheckTimeoutsByID(env.Config.CheckTimeouts))
+	checksdb.SetParallelism(testParams.Parallelism)
+
+	// Checks whose permissions are not granted are skipped instead of failing on API errors.
+	if !provider.IsOfflineMode() {
+		checksdb.SetPermissionsChecker(getPermissionsChecker(&env))
+		defer checksdb.SetPermissionsChecker(nil)
+	}
+
+	scoringPolicy, err := scoring.NewPolicy(&env.Config.Scoring)
+	if err != nil {
+		return fmt.Errorf("invalid scoring config: %v", err)
+	}
+	checksdb.SetScoringPolicy(scoringPolicy)
+
+	if testParams.ResumeFrom != "" {
+		previousResults, err := claimhelper.GetResultsFromClaimFile(testParams.ResumeFrom)
+		if err != nil {
+			return fmt.Errorf("failed to resume from claim file %s: %v", testParams.ResumeFrom, err)
+		}
+
+		reusedResults := checksdb.SetResumedResults(previousResults)
+		fmt.Printf("Resuming run from %s: %d checks will not run again.\n\n", testParams.ResumeFrom, reusedResults)
+	}
+
+	if testParams.WaiversFile != "" {
+		if err := loadWaivers(testParams.WaiversFile, testParams.FailOnExpiredWaivers); err != nil {
+			return err
+		}
+	}
+
+	// Save the results after every check so the run can be resumed in case it's interrupted.
+	checksdb.SetCheckpointFn(func(results map[string]claim.Result) {
+		claimBuilder.Checkpoint(claimOutputFile, results)
+	})
 
 	log.Info("Running checks matching labels expr %q with timeout %v", labelsFilter, testParams.Timeout)
 	startTime := time.Now()
 	failedCtr, err := checksdb.RunChecks(testParams.Timeout)
+	// Checks still running after an abort must not overwrite the final claim file.
+	checksdb.SetCheckpointFn(nil)
 	if err != nil {
 		log.Error("%v", err)
 	}
 	endTime := time.Now()
 	log.Info("Finished running checks in %v", endTime.Sub(startTime))
+	reportSnapshotMissingCommands()
 
 	if failedCtr > 0 {
 		log.Warn("Some checks failed. See %s for details", claimOutputFile)
@@ -164,7 +339,25 @@ func Run(labelsFilter, outputFolder string) error {
 	if configuration.GetTestParameters().EnableXMLCreation {
 		junitOutputFileName := filepath.Join(outputFolder, junitXMLOutputFileName)
 		log.Info("JUnit XML file creation is enabled. Creating JUnit XML file: %s", junitOutputFileName)
-		claimBuilder.ToJUnitXML(junitOutputFileName, startTime, endTime)
+		claimBuilder.ToJUnitXML(junitOutputFileName, startTime, endTime, configuration.GetTestParameters().ExpandXMLTestCases)
+	}
+
+	checksResults := checksdb.GetReconciledResults()
+	events.EmitRunFinished(getResultsCountsByState(checksResults), time.Since(runStartTime))
+
+	// Update the metrics served in server mode and export them if required
+	metrics.Update(checksResults, &env, startTime, endTime)
+	if testParams.MetricsTextfile != "" {
+		log.Info("Writing metrics to %s", testParams.MetricsTextfile)
+		if err := metrics.WriteTextfile(testParams.MetricsTextfile); err != nil {
+			log.Error("%v", err)
+		}
+	}
+	if testParams.MetricsPushgatewayURL != "" {
+		log.Info("Pushing metrics to %s", testParams.MetricsPushgatewayURL)
+		if err := metrics.Push(testParams.MetricsPushgatewayURL); err != nil {
+			log.Error("%v", err)
+		}
 	}
 
 	if configuration.GetTestParameters().SanitizeClaim {
@@ -174,6 +367,24 @@ func Run(labelsFilter, outputFolder string) error {
 		}
 	}
 
+	// Create SARIF file if required, from the final claim file so its lines match the results
+	if configuration.GetTestParameters().EnableSARIFCreation {
+		sarifOutputFile := filepath.Join(outputFolder, sarifOutputFileName)
+		log.Info("SARIF file creation is enabled. Creating SARIF file: %s", sarifOutputFile)
+		claimhelper.ClaimFileToSARIF(claimOutputFile, sarifOutputFile)
+	}
+
+	// Sign the claim file if required
+	claimSignatureFile := ""
+	if testParams.ClaimSigningKey != "" {
+		claimSignatureFile, err = claimhelper.SignClaimFile(claimOutputFile, testParams.ClaimSigningKey)
+		if err != nil {
+			log.Error("Failed to sign claim file: %v", err)
+		} else {
+			log.Info("Claim file signature saved in %s", claimSignatureFile)
+		}
+	}
+
 	// Send claim file to the collector if specified by env var
 	if configuration.GetTestParameters().EnableDataCollection {
 		if env.CollectorAppEndpoint == "" {
@@ -188,12 +399,17 @@ func Run(labelsFilter, outputFolder string) error {
 
 	// Create HTML artifacts for the web results viewer/parser.
 	resultsOutputDir := outputFolder
-	webFilePaths, err := results.CreateResultsWebFiles(resultsOutputDir, claimFileName)
+	webFilePaths, err := results.CreateResultsWebFiles(resultsOutputDir, ClaimFileName)
 	if err != nil {
 		log.Error("Failed to create results web files: %v", err)
 	}
 
-	allArtifactsFilePaths := []string{filepath.Join(outputFolder, claimFileName)}
+	allArtifactsFilePaths := []string{filepath.Join(outputFolder, ClaimFileName)}
+
+	// Add the claim signature file path
+	if claimSignatureFile != "" {
+		allArtifactsFilePaths = append(allArtifactsFilePaths, claimSignatureFile)
+	}
 
 	// Add all the web artifacts file paths.
 	allArtifactsFilePaths = append(allArtifactsFilePaths, webFilePaths...)
@@ -201,6 +417,11 @@ func Run(labelsFilter, outputFolder string) error {
 	// Add the log file path
 	allArtifactsFilePaths = append(allArtifactsFilePaths, filepath.Join(outputFolder, log.LogFileName))
 
+	// Add the recorded commands file path
+	if execRecorder != nil {
+		allArtifactsFilePaths = append(allArtifactsFilePaths, filepath.Join(outputFolder, ExecRecordsFileName))
+	}
+
 	// tar.gz file creation with results and html artifacts, unless omitted by env var.
 	if !configuration.GetTestParameters().OmitArtifactsZipFile {
 		err = results.CompressResultsArtifacts(resultsOutputDir, allArtifactsFilePaths)
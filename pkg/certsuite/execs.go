@@ -0,0 +1,38 @@
+package certsuite
+
+import (
+	"path/filepath"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+)
+
+const ExecRecordsFileName = "exec-records.jsonl"
+
+// Records the commands run in the containers when the run was started with --record-execs.
+var execRecorder *clientsholder.CommandRecorder
+
+// Makes every command run in the containers from now on to be recorded in the exec records file
+// of the output folder. That file can be used later to replay those commands' outputs.
+func startExecsRecording(client *clientsholder.ClientsHolder, outputDir string) error {
+	recorder, err := clientsholder.NewCommandRecorderWithOutputFile(client.GetRemoteExecutor(), filepath.Join(outputDir, ExecRecordsFileName))
+	if err != nil {
+		return err
+	}
+
+	clientsholder.SetCommandExecutor(recorder)
+	execRecorder = recorder
+	return nil
+}
+
+func stopExecsRecording() {
+	if execRecorder == nil {
+		return
+	}
+
+	clientsholder.SetCommandExecutor(nil)
+	log.Info("Recorded %d commands run in the containers", execRecorder.GetRecordsCount())
+	if err := execRecorder.Close(); err != nil {
+		log.Error("Failed to close the exec records file: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package certsuite
+
+import (
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/scoring"
+)
+
+const (
+	// The exit code doesn't depend on the results.
+	ExitCodePolicyNone = "none"
+	// Non-zero exit code if any check failed, ended with an error or was aborted.
+	ExitCodePolicyFailures = "failures"
+	// Non-zero exit code if the readiness score gate did not pass.
+	ExitCodePolicyScore = "score"
+
+	// Exit codes of the run besides 0 (success) and 1 (the test suite could not run).
+	ExitCodeChecksFailed    = 2
+	ExitCodeScoreGateFailed = 3
+)
+
+var ExitCodePolicies = []string{ExitCodePolicyNone, ExitCodePolicyFailures, ExitCodePolicyScore}
+
+// IsValidExitCodePolicy returns whether the policy is one of the ExitCodePolicies.
+func IsValidExitCodePolicy(policy string) bool {
+	for _, p := range ExitCodePolicies {
+		if p == policy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetExitCode returns the exit code of the run for the policy, given the checks results and their readiness score.
+func GetExitCode(policy string, results map[string]claim.Result, score *scoring.Score) int {
+	switch policy {
+	case ExitCodePolicyFailures:
+		for testID := range results {
+			switch results[testID].State {
+			case checksdb.CheckResultFailed, checksdb.CheckResultError, checksdb.CheckResultAborted:
+				return ExitCodeChecksFailed
+			}
+		}
+	case ExitCodePolicyScore:
+		if score == nil || !score.GatePassed {
+			return ExitCodeScoreGateFailed
+		}
+	}
+
+	return 0
+}
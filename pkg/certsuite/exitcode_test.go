@@ -0,0 +1,37 @@
+package certsuite
+
+import (
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/scoring"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetExitCode(t *testing.T) {
+	passedResults := map[string]claim.Result{"check-1": {State: "passed"}, "check-2": {State: "skipped"}}
+	abortedResults := map[string]claim.Result{"check-1": {State: "passed"}, "check-2": {State: "aborted"}}
+	gatePassed := &scoring.Score{GatePassed: true}
+	gateFailed := &scoring.Score{GatePassed: false}
+
+	testCases := []struct {
+		policy           string
+		results          map[string]claim.Result
+		score            *scoring.Score
+		expectedExitCode int
+	}{
+		{policy: ExitCodePolicyNone, results: abortedResults, score: gateFailed, expectedExitCode: 0},
+		{policy: ExitCodePolicyFailures, results: passedResults, score: gateFailed, expectedExitCode: 0},
+		{policy: ExitCodePolicyFailures, results: abortedResults, score: gatePassed, expectedExitCode: ExitCodeChecksFailed},
+		{policy: ExitCodePolicyScore, results: abortedResults, score: gatePassed, expectedExitCode: 0},
+		{policy: ExitCodePolicyScore, results: passedResults, score: gateFailed, expectedExitCode: ExitCodeScoreGateFailed},
+		{policy: ExitCodePolicyScore, results: passedResults, score: nil, expectedExitCode: ExitCodeScoreGateFailed},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedExitCode, GetExitCode(tc.policy, tc.results, tc.score))
+	}
+
+	assert.True(t, IsValidExitCodePolicy(ExitCodePolicyScore))
+	assert.False(t, IsValidExitCodePolicy("never"))
+}
@@ -0,0 +1,133 @@
+package certsuite
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/preflight"
+)
+
+// Names of what needs a permission, other than the checks.
+const (
+	ProbeDaemonSetUser  = "probe daemonset"
+	DiscoveryUserPrefix = "discovery of "
+	PreflightUser       = "preflight checks"
+)
+
+// RequiredPermission is a permission needed by the test suite, with the probe daemonset, discovery
+// collectors and checks that need it.
+type RequiredPermission struct {
+	permissions.Permission
+	NeededBy []string
+	Missing  bool
+}
+
+// Returns the function that finds out the missing permissions of the checks, resolved for the
+// namespaces of the test environment.
+func getPermissionsChecker(env *provider.TestEnvironment) func([]permissions.Permission) ([]permissions.Permission, error) {
+	reviewer := permissions.NewReviewer(clientsholder.GetClientsHolder().K8sClient.AuthorizationV1())
+	return func(perms []permissions.Permission) ([]permissions.Permission, error) {
+		return reviewer.GetMissing(permissions.Resolve(perms, env.Namespaces, env.Config.DebugDaemonSetNamespace))
+	}
+}
+
+// Returns the permissions needed by every user, resolved for the given namespaces and sorted.
+func getRequiredPermissions(targetNamespaces []string, probeNamespace string, permsByUser map[string][]permissions.Permission) []RequiredPermission {
+	users := []string{}
+	for user := range permsByUser {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	usersByPermission := map[permissions.Permission][]string{}
+	for _, user := range users {
+		for _, p := range permissions.Resolve(permsByUser[user], targetNamespaces, probeNamespace) {
+			usersByPermission[p] = append(usersByPermission[p], user)
+		}
+	}
+
+	perms := []permissions.Permission{}
+	for p := range usersByPermission {
+		perms = append(perms, p)
+	}
+	permissions.Sort(perms)
+
+	required := []RequiredPermission{}
+	for _, p := range perms {
+		required = append(required, RequiredPermission{Permission: p, NeededBy: usersByPermission[p]})
+	}
+
+	return required
+}
+
+// CheckPermissions reviews the permissions needed by the probe daemonset, the discovery and the
+// checks matching the labels filter of the test parameters, without running any of them.
+func CheckPermissions() ([]RequiredPermission, error) {
+	testParams := configuration.GetTestParameters()
+
+	if err := checksdb.InitLabelsExprEvaluator(testParams.LabelsFilter); err != nil {
+		return nil, fmt.Errorf("failed to initialize a test case label evaluator, err: %v", err)
+	}
+
+	// The preflight checks are only known once the preflight lib has run on the discovered workload.
+	LoadInternalChecksDB()
+	if err := loadCustomChecksDB(); err != nil {
+		return nil, err
+	}
+
+	// Loaded after the custom checks, whose suites can be used in the scoring config.
+	config, err := configuration.LoadConfiguration(testParams.ConfigFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load configuration file: %v", err)
+	}
+
+	// The namespace selectors are resolved as in the discovery, which needs to list the namespaces.
+	client := clientsholder.GetClientsHolder(getK8sClientsConfigFileNames()...)
+	targetNamespaces, err := autodiscover.GetTargetNamespaces(client.K8sClient.CoreV1(), &config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Some checks need permissions on the CRs under test, which depend on the CRDs found.
+	crds, err := autodiscover.GetCrdsUnderTest(config.CrdFilters)
+	if err != nil {
+		log.Warn("The permissions on the CRs under test cannot be listed: %v", err)
+	}
+
+	checksPermissions, err := checksdb.GetFilteredChecksPermissions(&provider.TestEnvironment{Config: config, Crds: crds})
+	if err != nil {
+		return nil, err
+	}
+
+	permsByUser := map[string][]permissions.Permission{ProbeDaemonSetUser: provider.ProbePermissions}
+	isOpenshift := autodiscover.IsOpenshiftConfigAPIServed(client.K8sClient.Discovery())
+	for collector, perms := range autodiscover.GetCollectorPermissions(&config, isOpenshift) {
+		permsByUser[DiscoveryUserPrefix+collector] = perms
+	}
+	for checkID, perms := range checksPermissions {
+		permsByUser[checkID] = perms
+	}
+	if preflight.IsSelected(testParams.LabelsFilter) {
+		permsByUser[PreflightUser] = preflight.GetPermissions()
+	}
+
+	required := getRequiredPermissions(targetNamespaces, config.DebugDaemonSetNamespace, permsByUser)
+
+	reviewer := permissions.NewReviewer(client.K8sClient.AuthorizationV1())
+	for i := range required {
+		allowed, err := reviewer.IsAllowed(required[i].Permission)
+		if err != nil {
+			return nil, err
+		}
+		required[i].Missing = !allowed
+	}
+
+	return required, nil
+}
@@ -0,0 +1,27 @@
+package certsuite
+
+import (
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRequiredPermissions(t *testing.T) {
+	required := getRequiredPermissions([]string{"tnf"}, "certsuite", map[string][]permissions.Permission{
+		ProbeDaemonSetUser:                {permissions.ListProbePods},
+		DiscoveryUserPrefix + "pods":      {permissions.ListPods},
+		DiscoveryUserPrefix + "nodes":     {permissions.ListNodes},
+		"lifecycle-pod-recreation":        {permissions.ListNodes, permissions.ListPods, permissions.DeletePods},
+		"access-control-ssh-daemons":      {permissions.ListPods, permissions.ExecProbePods},
+		"observability-container-logging": nil,
+	})
+
+	assert.Equal(t, []RequiredPermission{
+		{Permission: permissions.Permission{Verb: "list", Resource: "nodes"}, NeededBy: []string{"discovery of nodes", "lifecycle-pod-recreation"}},
+		{Permission: permissions.Permission{Verb: "list", Resource: "pods", Namespace: "certsuite"}, NeededBy: []string{"probe daemonset"}},
+		{Permission: permissions.Permission{Verb: "create", Resource: "pods", Subresource: "exec", Namespace: "certsuite"}, NeededBy: []string{"access-control-ssh-daemons"}},
+		{Permission: permissions.Permission{Verb: "delete", Resource: "pods", Namespace: "tnf"}, NeededBy: []string{"lifecycle-pod-recreation"}},
+		{Permission: permissions.Permission{Verb: "list", Resource: "pods", Namespace: "tnf"}, NeededBy: []string{"access-control-ssh-daemons", "discovery of pods", "lifecycle-pod-recreation"}},
+	}, required)
+}
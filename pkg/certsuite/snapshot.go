@@ -0,0 +1,104 @@
+package certsuite
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/cli"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/claimhelper"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/snapshot"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/versions"
+)
+
+const SnapshotFileName = "cluster-snapshot.tar.gz"
+
+// Serves the recorded command outputs when running from a cluster snapshot.
+var snapshotReplayer *clientsholder.CommandReplayer
+
+// Sets everything up to run the checks against a cluster snapshot instead of a live cluster.
+func loadSnapshot(archivePath string) error {
+	snap, err := snapshot.Load(archivePath)
+	if err != nil {
+		return err
+	}
+
+	snapshotReplayer = snap.Restore()
+	checksdb.SetOfflineMode(true)
+
+	log.Info("Running from cluster snapshot %s (created %v with certsuite %s)", archivePath, snap.CreatedAt, snap.CertsuiteVersion)
+	return nil
+}
+
+// Warns about the commands that could not be replayed, as the checks that ran them don't
+// have the same result they would have had against the cluster.
+func reportSnapshotMissingCommands() {
+	if snapshotReplayer == nil {
+		return
+	}
+
+	if missing := snapshotReplayer.GetMissingCommands(); missing > 0 {
+		log.Warn("%d commands run by the checks had no recorded output in the cluster snapshot", missing)
+		fmt.Printf("%sWARNING%s: %d commands run by the checks had no recorded output in the cluster snapshot. "+
+			"See the log file for details.\n", cli.Yellow, cli.Reset, missing)
+	}
+}
+
+// CaptureSnapshot discovers the workload as a regular run would do and runs the non-intrusive checks
+// matching the labels filter to record the outputs of the commands they run in the containers. Then,
+// everything is saved in a snapshot archive in the output folder, so the checks can run later against
+// it with "certsuite run --from-snapshot".
+func CaptureSnapshot() (archivePath string, err error) {
+	testParams := configuration.GetTestParameters()
+	// Intrusive checks cannot run offline anyway.
+	testParams.NonIntrusiveOnly = true
+
+	if err := checksdb.InitLabelsExprEvaluator(testParams.LabelsFilter); err != nil {
+		return "", fmt.Errorf("failed to initialize a test case label evaluator, err: %v", err)
+	}
+
+	if err := log.CreateGlobalLogFile(testParams.OutputDir, testParams.LogLevel); err != nil {
+		return "", fmt.Errorf("could not create the log file, err: %v", err)
+	}
+	defer Shutdown()
+
+	log.Info("Certsuite Version: %v", versions.GitVersion())
+	log.Info("Labels filter: %v", testParams.LabelsFilter)
+
+	// Every command run in the containers from now on is recorded.
+	client := clientsholder.GetClientsHolder(getK8sClientsConfigFileNames()...)
+	recorder := clientsholder.NewCommandRecorder(client.GetRemoteExecutor())
+	clientsholder.SetCommandExecutor(recorder)
+	defer clientsholder.SetCommandExecutor(nil)
+
+	fmt.Println("Running discovery of CNF target resources...")
+	env := provider.GetTestEnvironment()
+
+	LoadInternalChecksDB()
+	checksdb.SetCheckTimeouts(env.Config.DefaultCheckTimeout, getCheckTimeoutsByID(env.Config.CheckTimeouts))
+	checksdb.SetParallelism(testParams.Parallelism)
+
+	fmt.Printf("Running checks to record the outputs of the commands run in the containers...\n\n")
+	if _, err := checksdb.RunChecks(testParams.Timeout); err != nil {
+		log.Error("%v", err)
+	}
+
+	// The claim file's nodes info is also obtained running commands in the debug pods.
+	_ = claimhelper.GenerateNodes()
+
+	config, data := provider.GetDiscoveredTestData()
+	snap := snapshot.New(&config, &data, recorder.GetRecords())
+
+	archivePath = filepath.Join(testParams.OutputDir, SnapshotFileName)
+	if err := snap.Save(archivePath); err != nil {
+		return "", err
+	}
+
+	log.Info("Cluster snapshot saved in %s (%d recorded commands)", archivePath, len(snap.ExecRecords))
+	fmt.Printf("\nCluster snapshot saved in %s (%d recorded commands)\n", archivePath, len(snap.ExecRecords))
+	return archivePath, nil
+}
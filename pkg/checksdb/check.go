@@ -1,6 +1,7 @@
 package checksdb
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -8,7 +9,10 @@ import (
 	"time"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/cli"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/events"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
 )
 
@@ -29,6 +33,27 @@ const (
 
 type CheckResult string
 
+// Log archive that can be read while the check is still writing to it, e.g. when its result is
+// recorded after the run was aborted.
+type checkLogArchive struct {
+	mutex   sync.Mutex
+	builder strings.Builder
+}
+
+func (a *checkLogArchive) Write(p []byte) (int, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.builder.Write(p)
+}
+
+func (a *checkLogArchive) String() string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.builder.String()
+}
+
 func (cr CheckResult) String() string {
 	return string(cr)
 }
@@ -44,18 +69,42 @@ type Check struct {
 	SkipCheckFns []func() (skip bool, reason string)
 	SkipMode     skipMode
 
+	// API permissions the check needs, with the namespace placeholders unresolved. The check is
+	// skipped if any of them is missing.
+	Permissions []permissions.Permission
+	// Returns the permissions that depend on the discovered objects, like the CRs under test.
+	EnvPermissionsFn func(env *provider.TestEnvironment) []permissions.Permission
+
+	// Intrusive checks disrupt the workload, so they never run at the same time as any other check.
+	Intrusive bool
+
+	// Ordering constraints among the checks of the same group. Checks with higher priority run
+	// first when no other constraint applies. RunsAfter/RunsBefore hold check IDs.
+	Priority              int
+	RunsAfter, RunsBefore []string
+	registrationIdx       int
+	// Name of the group the check was added to.
+	groupName string
+
 	Result         CheckResult
 	CapturedOutput string
 	details        string
 	skipReason     string
 
 	logger     *log.Logger
-	logArchive *strings.Builder
+	logArchive *checkLogArchive
 
 	StartTime, EndTime time.Time
 	Timeout            time.Duration
 	Error              error
 	abortChan          chan string
+	timedOut           bool
+	// Cancelled when the check times out or finishes.
+	ctx context.Context
+	// The result comes from a previous run.
+	resumed bool
+	// Number of non-compliant objects that were waived.
+	waivedObjects int
 }
 
 func NewCheck(id string, labels []string) *Check {
@@ -63,7 +112,7 @@ func NewCheck(id string, labels []string) *Check {
 		ID:         id,
 		Labels:     labels,
 		Result:     CheckResultPassed,
-		logArchive: &strings.Builder{},
+		logArchive: &checkLogArchive{},
 	}
 
 	check.logger = log.GetMultiLogger(check.logArchive, cli.CliCheckLogSniffer).With("check", check.ID)
@@ -77,10 +126,35 @@ func (check *Check) Abort(reason string) {
 
 	abortMsg := check.ID + " issued non-graceful abort: " + reason
 
-	check.abortChan <- abortMsg
+	// A check that timed out was already aborted, so it must not abort the rest of the run. It
+	// still panics so its function stops right away.
+	if !check.timedOut {
+		check.abortChan <- abortMsg
+	}
 	panic(AbortPanicMsg(abortMsg))
 }
 
+// Context returns the context of the check's run, which is cancelled once the check times out or
+// finishes. Long running operations of the check function should use it so they don't keep running
+// after the check's timeout.
+func (check *Check) Context() context.Context {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	if check.ctx == nil {
+		return context.Background()
+	}
+
+	return check.ctx
+}
+
+func (check *Check) setContext(ctx context.Context) {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	check.ctx = ctx
+}
+
 func (check *Check) SetAbortChan(abortChan chan string) {
 	check.abortChan = abortChan
 }
@@ -174,6 +248,82 @@ func (check *Check) WithSkipModeAll() *Check {
 	return check
 }
 
+func (check *Check) WithIntrusive() *Check {
+	if check.Error != nil {
+		return check
+	}
+
+	check.Intrusive = true
+
+	return check
+}
+
+// WithPriority sets the check's priority. Checks with higher priority run first
+// when no other ordering constraint applies.
+func (check *Check) WithPriority(priority int) *Check {
+	if check.Error != nil {
+		return check
+	}
+
+	check.Priority = priority
+
+	return check
+}
+
+// WithRunsAfter makes the check run after the given checks of the same group.
+func (check *Check) WithRunsAfter(checkIDs ...string) *Check {
+	if check.Error != nil {
+		return check
+	}
+
+	check.RunsAfter = append(check.RunsAfter, checkIDs...)
+
+	return check
+}
+
+// WithRunsBefore makes the check run before the given checks of the same group.
+func (check *Check) WithRunsBefore(checkIDs ...string) *Check {
+	if check.Error != nil {
+		return check
+	}
+
+	check.RunsBefore = append(check.RunsBefore, checkIDs...)
+
+	return check
+}
+
+// WithPermissions adds API permissions that the check needs to run.
+func (check *Check) WithPermissions(perms ...permissions.Permission) *Check {
+	if check.Error != nil {
+		return check
+	}
+
+	check.Permissions = append(check.Permissions, perms...)
+
+	return check
+}
+
+// WithEnvPermissionsFn sets the function that returns the API permissions that the check needs
+// on the objects found by the discovery.
+func (check *Check) WithEnvPermissionsFn(permissionsFn func(env *provider.TestEnvironment) []permissions.Permission) *Check {
+	if check.Error != nil {
+		return check
+	}
+
+	check.EnvPermissionsFn = permissionsFn
+
+	return check
+}
+
+// GetPermissions returns all the API permissions that the check needs in the given test environment.
+func (check *Check) GetPermissions(env *provider.TestEnvironment) []permissions.Permission {
+	if check.EnvPermissionsFn == nil {
+		return check.Permissions
+	}
+
+	return append(append([]permissions.Permission{}, check.Permissions...), check.EnvPermissionsFn(env)...)
+}
+
 func (check *Check) WithTimeout(duration time.Duration) *Check {
 	if check.Error != nil {
 		return check
@@ -192,7 +342,10 @@ func (check *Check) SetResult(compliantObjects, nonCompliantObjects []*testhelpe
 		return
 	}
 
-	resultObjectsStr, err := testhelper.ResultObjectsToString(compliantObjects, nonCompliantObjects)
+	nonCompliantObjects, waivedObjects := check.applyWaivers(nonCompliantObjects)
+	check.waivedObjects = len(waivedObjects)
+
+	resultObjectsStr, err := testhelper.ResultObjectsWithWaivedToString(compliantObjects, nonCompliantObjects, waivedObjects)
 	if err != nil {
 		check.LogError("Failed to get result objects string for check %s: %v", check.ID, err)
 	}
@@ -207,7 +360,7 @@ func (check *Check) SetResult(compliantObjects, nonCompliantObjects []*testhelpe
 	if len(nonCompliantObjects) > 0 {
 		check.Result = CheckResultFailed
 		check.skipReason = ""
-	} else if len(compliantObjects) == 0 {
+	} else if len(compliantObjects) == 0 && len(waivedObjects) == 0 {
 		// Mark this check as skipped.
 		check.LogWarn("Check %s marked as skipped as both compliant and non-compliant objects lists are empty.", check.ID)
 		check.skipReason = "compliant and non-compliant objects lists are empty"
@@ -251,6 +404,34 @@ func (check *Check) SetResultAborted(reason string) {
 	check.skipReason = reason
 }
 
+// Marks the check as aborted because it didn't finish before its timeout. The
+// check function might still be running in the background, so any result it
+// sets afterwards will be discarded and calling check.Abort() won't abort the run.
+func (check *Check) setResultTimedOut(timeout time.Duration) {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	check.timedOut = true
+	check.EndTime = time.Now()
+	check.Result = CheckResultAborted
+	check.skipReason = fmt.Sprintf("check timed out after %v", timeout)
+}
+
+// Returns the check's result. Unlike check.Result, it can be read while the check is running.
+func (check *Check) getResult() CheckResult {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	return check.Result
+}
+
+func (check *Check) isTimedOut() bool {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	return check.timedOut
+}
+
 func (check *Check) Run() error {
 	if check == nil {
 		return fmt.Errorf("check is a nil pointer")
@@ -261,10 +442,18 @@ func (check *Check) Run() error {
 	}
 
 	cli.PrintCheckRunning(check.ID)
+	events.EmitCheckStarted(check.ID, check.groupName)
 
+	check.mutex.Lock()
 	check.StartTime = time.Now()
+	check.mutex.Unlock()
 	defer func() {
-		check.EndTime = time.Now()
+		check.mutex.Lock()
+		defer check.mutex.Unlock()
+		// The end time was already set when the timeout expired.
+		if !check.timedOut {
+			check.EndTime = time.Now()
+		}
 	}()
 
 	check.LogInfo("Running check (labels: %v)", check.Labels)
@@ -278,28 +467,60 @@ func (check *Check) Run() error {
 		return fmt.Errorf("check %s failed in check function: %v", check.ID, err)
 	}
 
-	if check.AfterCheckFn != nil {
+	// The after check function must not run once the check timed out, as the next checks may be
+	// running already.
+	if check.AfterCheckFn != nil && !check.isTimedOut() {
 		if err := check.AfterCheckFn(check); err != nil {
 			return fmt.Errorf("check %s failed in after check function: %v", check.ID, err)
 		}
 	}
 
-	printCheckResult(check)
+	// The result of a timed out check was already printed.
+	if !check.isTimedOut() {
+		printCheckResult(check)
+	}
 
 	return nil
 }
 
 func printCheckResult(check *Check) {
-	switch check.Result {
+	check.mutex.Lock()
+	result, skipReason, waivedObjects := check.Result, check.skipReason, check.waivedObjects
+	check.mutex.Unlock()
+
+	switch result {
 	case CheckResultPassed:
-		cli.PrintCheckPassed(check.ID)
+		if waivedObjects > 0 {
+			cli.PrintCheckPassedWithWaivers(check.ID, waivedObjects)
+		} else {
+			cli.PrintCheckPassed(check.ID)
+		}
 	case CheckResultFailed:
 		cli.PrintCheckFailed(check.ID)
 	case CheckResultSkipped:
-		cli.PrintCheckSkipped(check.ID, check.skipReason)
+		cli.PrintCheckSkipped(check.ID, skipReason)
 	case CheckResultAborted:
-		cli.PrintCheckAborted(check.ID, check.skipReason)
+		cli.PrintCheckAborted(check.ID, skipReason)
 	case CheckResultError:
 		cli.PrintCheckErrored(check.ID)
 	}
+
+	emitCheckFinished(check)
+}
+
+func emitCheckFinished(check *Check) {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	var duration time.Duration
+	if !check.StartTime.IsZero() {
+		// The end time is not set yet when the check function has just returned.
+		endTime := check.EndTime
+		if endTime.IsZero() {
+			endTime = time.Now()
+		}
+		duration = endTime.Sub(check.StartTime)
+	}
+
+	events.EmitCheckFinished(check.ID, check.groupName, check.Result.String(), check.skipReason, duration)
 }
@@ -21,19 +21,39 @@ import (
 var (
 	dbLock    sync.Mutex
 	dbByGroup map[string]*ChecksGroup
+	// Groups in the order they run, as resolved by resolveChecksOrder().
+	orderedGroups []*ChecksGroup
 
 	resultsDB = map[string]claim.Result{}
+	// Groups running concurrently record their checks results at the same time.
+	resultsDBLock sync.Mutex
 
 	labelsExprEvaluator labels.LabelsExprEvaluator
+
+	// Timeout for the checks that don't set their own one, nor their group does. Zero means no timeout.
+	defaultCheckTimeout time.Duration
+	// Timeouts by check ID, usually set in the config file. They take precedence over any other timeout.
+	checkTimeoutsByID = map[string]time.Duration{}
+
+	// Maximum number of checks that can run at the same time. Intrusive checks always run alone.
+	parallelism = 1
+
+	// Set when the checks run without access to a cluster, e.g. against a cluster snapshot.
+	offlineMode bool
 )
 
+const offlineModeSkipReason = "intrusive checks need a live cluster, they cannot run offline from a cluster snapshot"
+
 type AbortPanicMsg string
 
-//nolint:funlen
 func RunChecks(timeout time.Duration) (failedCtr int, err error) {
 	dbLock.Lock()
 	defer dbLock.Unlock()
 
+	if err := resolveChecksOrder(); err != nil {
+		return 0, fmt.Errorf("failed to resolve the checks order: %v", err)
+	}
+
 	// Timeout channel
 	timeOutChan := time.After(timeout)
 	// SIGINT(ctrl+c)/SIGTERM capture channel.
@@ -43,10 +63,30 @@ func RunChecks(timeout time.Duration) (failedCtr int, err error) {
 	// turn off ctrl-c capture on exit
 	defer signal.Stop(sigIntChan)
 
+	var errs []error
+	if parallelism > 1 {
+		log.Info("Running checks with parallelism %d", parallelism)
+		failedCtr, errs = runGroupsConcurrently(timeOutChan, sigIntChan)
+	} else {
+		failedCtr, errs = runGroupsSequentially(timeOutChan, sigIntChan)
+	}
+
+	// Print the results in the CLI
+	cli.PrintResultsTable(getResultsSummary(), getCliReadinessScore(computeScore()))
+	printFailedChecksLog()
+
+	if len(errs) > 0 {
+		log.Error("RunChecks errors: %v", errs)
+		return 0, fmt.Errorf("%d errors found in checks/groups", len(errs))
+	}
+
+	return failedCtr, nil
+}
+
+func runGroupsSequentially(timeOutChan <-chan time.Time, sigIntChan <-chan os.Signal) (failedCtr int, errs []error) {
 	abort := false
 	var abortReason string
-	var errs []error
-	for _, group := range dbByGroup {
+	for _, group := range orderedGroups {
 		if abort {
 			_ = group.OnAbort(abortReason)
 			group.RecordChecksResults()
@@ -72,38 +112,32 @@ func RunChecks(timeout time.Duration) (failedCtr int, err error) {
 		case abortReason = <-abortChan:
 			log.Warn("Group %s aborted.", group.name)
 			stopChan <- true
+			stopCheckpoints()
 
 			abort = true
 			_ = group.OnAbort(abortReason)
 		case <-timeOutChan:
 			log.Warn("Running all checks timed-out.")
 			stopChan <- true
+			stopCheckpoints()
 
 			abort = true
-			abortReason = "global time-out"
+			abortReason = abortReasonGlobalTimeout
 			_ = group.OnAbort(abortReason)
 		case <-sigIntChan:
 			log.Warn("SIGINT/SIGTERM received.")
 			stopChan <- true
+			stopCheckpoints()
 
 			abort = true
-			abortReason = "SIGINT/SIGTERM"
+			abortReason = abortReasonSigInt
 			_ = group.OnAbort(abortReason)
 		}
 
 		group.RecordChecksResults()
 	}
 
-	// Print the results in the CLI
-	cli.PrintResultsTable(getResultsSummary())
-	printFailedChecksLog()
-
-	if len(errs) > 0 {
-		log.Error("RunChecks errors: %v", errs)
-		return 0, fmt.Errorf("%d errors found in checks/groups", len(errs))
-	}
-
-	return failedCtr, nil
+	return failedCtr, errs
 }
 
 func recordCheckResult(check *Check) {
@@ -112,16 +146,31 @@ func recordCheckResult(check *Check) {
 		check.LogFatal("TestID %s has no corresponding Claim ID", check.ID)
 	}
 
-	check.LogInfo("Recording result %q, claimID: %+v", strings.ToUpper(check.Result.String()), claimID)
+	// The check may still be running if the run was aborted, so its fields are read with its mutex.
+	check.mutex.Lock()
+	result, skipReason, details := check.Result, check.skipReason, check.details
+	startTime, endTime := check.StartTime, check.EndTime
+	check.mutex.Unlock()
+
+	check.LogInfo("Recording result %q, claimID: %+v", strings.ToUpper(result.String()), claimID)
+
+	resultsDBLock.Lock()
+	defer resultsDBLock.Unlock()
+
+	if check.resumed {
+		resultsDB[check.ID] = resumedResults[check.ID]
+		return
+	}
+
 	resultsDB[check.ID] = claim.Result{
 		TestID:             &claimID,
-		State:              check.Result.String(),
-		StartTime:          check.StartTime.String(),
-		EndTime:            check.EndTime.String(),
-		Duration:           int(check.EndTime.Sub(check.StartTime).Seconds()),
-		SkipReason:         check.skipReason,
+		State:              result.String(),
+		StartTime:          startTime.String(),
+		EndTime:            endTime.String(),
+		Duration:           int(endTime.Sub(startTime).Seconds()),
+		SkipReason:         skipReason,
 		CapturedTestOutput: check.GetLogs(),
-		CheckDetails:       check.details,
+		CheckDetails:       details,
 
 		CategoryClassification: &claim.CategoryClassification{
 			Extended: identifiers.Catalog[claimID].CategoryClassification[identifiers.Extended],
@@ -140,6 +189,9 @@ func recordCheckResult(check *Check) {
 // GetReconciledResults is a function added to aggregate a Claim's results.  Due to the limitations of
 // certsuite-claim's Go Client, results are generalized to map[string]interface{}.
 func GetReconciledResults() map[string]claim.Result {
+	resultsDBLock.Lock()
+	defer resultsDBLock.Unlock()
+
 	resultMap := make(map[string]claim.Result)
 	//nolint:gocritic
 	for key, val := range resultsDB {
@@ -181,7 +233,7 @@ func getResultsSummary() map[string][]int {
 const nbColorSymbols = 9
 
 func printFailedChecksLog() {
-	for _, group := range dbByGroup {
+	for _, group := range orderedGroups {
 		for _, check := range group.checks {
 			if check.Result != CheckResultFailed {
 				continue
@@ -233,8 +285,15 @@ func GetTestsCountByState(state string) int {
 }
 
 func FilterCheckIDs() ([]string, error) {
+	dbLock.Lock()
+	defer dbLock.Unlock()
+
+	if err := resolveChecksOrder(); err != nil {
+		return nil, err
+	}
+
 	filteredCheckIDs := []string{}
-	for _, group := range dbByGroup {
+	for _, group := range orderedGroups {
 		for _, check := range group.checks {
 			if labelsExprEvaluator.Eval(check.Labels) {
 				filteredCheckIDs = append(filteredCheckIDs, check.ID)
@@ -245,6 +304,40 @@ func FilterCheckIDs() ([]string, error) {
 	return filteredCheckIDs, nil
 }
 
+// SetCheckTimeouts sets the timeout for the checks that don't have any other timeout, plus
+// the timeouts for specific check IDs, which will override any other timeout.
+func SetCheckTimeouts(defaultTimeout time.Duration, timeoutsByID map[string]time.Duration) {
+	dbLock.Lock()
+	defer dbLock.Unlock()
+
+	defaultCheckTimeout = defaultTimeout
+	checkTimeoutsByID = map[string]time.Duration{}
+	for checkID, timeout := range timeoutsByID {
+		if _, exists := identifiers.TestIDToClaimID[checkID]; !exists {
+			log.Warn("Timeout set for unknown check %q.", checkID)
+		}
+		checkTimeoutsByID[checkID] = timeout
+	}
+}
+
+// SetParallelism sets the maximum number of checks that can run at the same time. Values
+// lower than 2 make the checks and groups run sequentially.
+func SetParallelism(maxParallelChecks int) {
+	dbLock.Lock()
+	defer dbLock.Unlock()
+
+	parallelism = maxParallelChecks
+}
+
+// SetOfflineMode sets whether the checks run without access to a cluster. In offline mode,
+// intrusive checks are always skipped, as they need to modify the workload.
+func SetOfflineMode(offline bool) {
+	dbLock.Lock()
+	defer dbLock.Unlock()
+
+	offlineMode = offline
+}
+
 func InitLabelsExprEvaluator(labelsFilter string) error {
 	// Expand the abstract "all" label into actual existing labels
 	if labelsFilter == "all" {
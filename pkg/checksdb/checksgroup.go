@@ -1,17 +1,26 @@
 package checksdb
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"runtime/debug"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/cli"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/events"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/tracing"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
 )
 
 const (
 	checkIdxNone = -1
+
+	skipReasonNoMatchingLabels         = "no matching labels"
+	skipReasonMissingPermissionsPrefix = "missing permissions: "
 )
 
 type ChecksGroup struct {
@@ -22,7 +31,22 @@ type ChecksGroup struct {
 
 	beforeEachFn, afterEachFn func(check *Check) error
 
+	// Timeout for the group's checks that don't set their own one.
+	checkTimeout time.Duration
+
+	// Ordering constraints among groups. RunsAfter/RunsBefore hold group names.
+	priority              int
+	runsAfter, runsBefore []string
+	registrationIdx       int
+
 	currentRunningCheckIdx int
+
+	// Parent of the spans of the group's checks.
+	traceCtx context.Context
+
+	// Only used when checks run concurrently.
+	checkRunStates      map[*Check]checkRunState
+	checkRunStatesMutex sync.Mutex
 }
 
 func NewChecksGroup(groupName string) *ChecksGroup {
@@ -42,6 +66,7 @@ func NewChecksGroup(groupName string) *ChecksGroup {
 		name:                   groupName,
 		checks:                 []*Check{},
 		currentRunningCheckIdx: checkIdxNone,
+		registrationIdx:        len(dbByGroup),
 	}
 	dbByGroup[groupName] = group
 
@@ -72,13 +97,70 @@ func (group *ChecksGroup) WithAfterAllFn(afterAllFn func(checks []*Check) error)
 	return group
 }
 
+// WithCheckTimeout sets the default timeout for all the checks in the group that
+// don't set their own timeout with Check.WithTimeout().
+func (group *ChecksGroup) WithCheckTimeout(timeout time.Duration) *ChecksGroup {
+	group.checkTimeout = timeout
+
+	return group
+}
+
+// WithPriority sets the group's priority. Groups with higher priority run first
+// when no other ordering constraint applies.
+func (group *ChecksGroup) WithPriority(priority int) *ChecksGroup {
+	group.priority = priority
+
+	return group
+}
+
+// WithRunsAfter makes the group run after the given groups.
+func (group *ChecksGroup) WithRunsAfter(groupNames ...string) *ChecksGroup {
+	group.runsAfter = append(group.runsAfter, groupNames...)
+
+	return group
+}
+
+// WithRunsBefore makes the group run before the given groups.
+func (group *ChecksGroup) WithRunsBefore(groupNames ...string) *ChecksGroup {
+	group.runsBefore = append(group.runsBefore, groupNames...)
+
+	return group
+}
+
 func (group *ChecksGroup) Add(check *Check) {
 	dbLock.Lock()
 	defer dbLock.Unlock()
 
+	check.registrationIdx = len(group.checks)
+	check.groupName = group.name
 	group.checks = append(group.checks, check)
 }
 
+// Starts the group's span. Unless the checks run concurrently, it's also the parent of the spans
+// started while the group runs, like the ones of the API calls in the group's hooks.
+func (group *ChecksGroup) startSpan(concurrent bool) (end func()) {
+	ctx, span := tracing.Start(tracing.CurrentContext(), "checksdb.ChecksGroup", tracing.AttrSuite.String(group.name))
+	group.traceCtx = ctx
+
+	restore := func() {}
+	if !concurrent {
+		restore = tracing.SetCurrentContext(ctx)
+	}
+
+	return func() {
+		restore()
+		span.End()
+	}
+}
+
+func (group *ChecksGroup) getTraceContext() context.Context {
+	if group.traceCtx == nil {
+		return tracing.CurrentContext()
+	}
+
+	return group.traceCtx
+}
+
 func skipCheck(check *Check, reason string) {
 	check.LogInfo("Skipping check %s, reason: %s", check.ID, reason)
 	check.SetResultSkipped(reason)
@@ -91,12 +173,19 @@ func skipAll(checks []*Check, reason string) {
 	}
 }
 
+// Returns the prefix of the skip reason of the checks that were not run because another check of
+// the group, or one of the group's functions, errored or panicked.
+func getGroupFailureSkipReasonPrefix(groupName string) string {
+	return "group " + groupName + " "
+}
+
 func onFailure(failureType, failureMsg string, group *ChecksGroup, currentCheck *Check, remainingChecks []*Check) error {
 	// Set current Check's result as error.
 	fmt.Printf("\r[ %s ] %-60s\n", cli.CheckResultTagError, currentCheck.ID)
 	currentCheck.SetResultError(failureType + ": " + failureMsg)
+	emitCheckFinished(currentCheck)
 	// Set the remaining checks as skipped, using a simplified reason msg.
-	reason := "group " + group.name + " " + failureType
+	reason := getGroupFailureSkipReasonPrefix(group.name) + failureType
 	skipAll(remainingChecks, reason)
 	// Return generic error using the reason.
 	return errors.New(reason)
@@ -204,6 +293,14 @@ func runAfterEachFn(group *ChecksGroup, check *Check, remainingChecks []*Check)
 }
 
 func shouldSkipCheck(check *Check) (skip bool, reasons []string) {
+	if offlineMode && check.Intrusive {
+		return true, []string{offlineModeSkipReason}
+	}
+
+	if missing := getMissingPermissions(check); len(missing) > 0 {
+		return true, []string{skipReasonMissingPermissionsPrefix + permissions.Join(missing)}
+	}
+
 	if len(check.SkipCheckFns) == 0 {
 		return false, []string{}
 	}
@@ -253,26 +350,124 @@ func shouldSkipCheck(check *Check) (skip bool, reasons []string) {
 	return false, []string{}
 }
 
-func runCheck(check *Check, group *ChecksGroup, remainingChecks []*Check) (err error) {
+// Holds the reason why a check function errored or panicked.
+type checkFailure struct {
+	failureType string
+	failureMsg  string
+}
+
+// Runs the check function, recovering from any panic. Returns a non-nil abortErr in case the
+// check was manually aborted with check.Abort(), or a non-nil failure in case the check
+// function returned an error or panicked.
+func runCheckFn(check *Check) (abortErr error, failure *checkFailure) {
 	defer func() {
 		if r := recover(); r != nil {
 			// Don't do anything in case the check was manually aborted by check.Abort().
 			if msg, ok := r.(AbortPanicMsg); ok {
 				log.Warn("Check was manually aborted, msg: %v", msg)
-				err = fmt.Errorf("%v", msg)
+				abortErr = fmt.Errorf("%v", msg)
 				return
 			}
 
 			stackTrace := fmt.Sprint(r) + "\n" + string(debug.Stack())
 
 			check.LogError("Panic while running check %s function:\n%v", check.ID, stackTrace)
-			err = onFailure(fmt.Sprintf("check %s function panic", check.ID), stackTrace, group, check, remainingChecks)
+			failure = &checkFailure{fmt.Sprintf("check %s function panic", check.ID), stackTrace}
 		}
 	}()
 
 	if err := check.Run(); err != nil {
 		check.LogError("Unexpected error while running check %s function: %v", check.ID, err.Error())
-		return onFailure(fmt.Sprintf("check %s function unexpected error", check.ID), err.Error(), group, check, remainingChecks)
+		return nil, &checkFailure{fmt.Sprintf("check %s function unexpected error", check.ID), err.Error()}
+	}
+
+	return nil, nil
+}
+
+// Returns the maximum time the check is allowed to run. The timeouts set for the check's ID in the
+// config file have the highest precedence, followed by the check's own timeout, the group's check
+// timeout and the default check timeout. Zero means no timeout.
+func getCheckTimeout(check *Check, group *ChecksGroup) time.Duration {
+	if timeout, exists := checkTimeoutsByID[check.ID]; exists {
+		return timeout
+	}
+
+	if check.Timeout > 0 {
+		return check.Timeout
+	}
+
+	if group.checkTimeout > 0 {
+		return group.checkTimeout
+	}
+
+	return defaultCheckTimeout
+}
+
+func runCheck(check *Check, group *ChecksGroup, remainingChecks []*Check) (err error) {
+	ctx, span := tracing.Start(group.getTraceContext(), "checksdb.Check",
+		tracing.AttrCheckID.String(check.ID),
+		tracing.AttrSuite.String(group.name),
+	)
+	defer func() {
+		span.SetAttributes(tracing.AttrCheckResult.String(check.getResult().String()))
+		tracing.End(span, err)
+	}()
+
+	// Checks running concurrently can't be the parents of the spans of the API calls and
+	// commands they run, as they would be mixed up.
+	if group.checkRunStates == nil {
+		restore := tracing.SetCurrentContext(ctx)
+		defer restore()
+	}
+
+	var abortErr error
+	var failure *checkFailure
+
+	timeout := getCheckTimeout(check, group)
+
+	// The check's context is cancelled once it times out, so the operations that use it stop too.
+	checkCtx, cancel := context.WithCancel(context.Background())
+	if timeout > 0 {
+		checkCtx, cancel = context.WithTimeout(context.Background(), timeout)
+	}
+	defer cancel()
+	check.setContext(checkCtx)
+
+	if timeout <= 0 {
+		abortErr, failure = runCheckFn(check)
+	} else {
+		// Run the check function in its own goroutine so it can be left behind in case it
+		// doesn't finish on time. Its context is cancelled, but it may still be running for
+		// a while. Any result it sets or any error/panic that happens after the timeout is
+		// discarded, and it can't abort the run anymore.
+		type checkFnOutcome struct {
+			abortErr error
+			failure  *checkFailure
+		}
+
+		doneChan := make(chan checkFnOutcome, 1)
+		go func() {
+			abortErr, failure := runCheckFn(check)
+			doneChan <- checkFnOutcome{abortErr, failure}
+		}()
+
+		select {
+		case outcome := <-doneChan:
+			abortErr, failure = outcome.abortErr, outcome.failure
+		case <-checkCtx.Done():
+			check.LogError("Check %s did not finish after %v, aborting it.", check.ID, timeout)
+			check.setResultTimedOut(timeout)
+			printCheckResult(check)
+			return nil
+		}
+	}
+
+	if abortErr != nil {
+		return abortErr
+	}
+
+	if failure != nil {
+		return onFailure(failure.failureType, failure.failureMsg, group, check, remainingChecks)
 	}
 
 	return nil
@@ -300,8 +495,11 @@ func (group *ChecksGroup) RunChecks(stopChan <-chan bool, abortChan chan string)
 	// Get checks to run based on the label expr.
 	checks := []*Check{}
 	for _, check := range group.checks {
+		if restoreCheckResult(check) {
+			continue
+		}
 		if !labelsExprEvaluator.Eval(check.Labels) {
-			skipCheck(check, "no matching labels")
+			skipCheck(check, skipReasonNoMatchingLabels)
 			continue
 		}
 		checks = append(checks, check)
@@ -311,6 +509,9 @@ func (group *ChecksGroup) RunChecks(stopChan <-chan bool, abortChan chan string)
 		return nil, 0
 	}
 
+	endSpan := group.startSpan(false)
+	defer endSpan()
+
 	// Run afterAllFn always, no matter previous panics/crashes.
 	defer func() {
 		if err := runAfterAllFn(group, checks); err != nil {
@@ -361,13 +562,15 @@ func (group *ChecksGroup) RunChecks(stopChan <-chan bool, abortChan chan string)
 			errs = append(errs, err)
 		}
 
+		checkpointCheckResult(check)
+
 		// Don't run more checks if any of beforeEach, the checkFn or afterEach functions errored/panicked.
 		if len(errs) > 0 {
 			break
 		}
 
 		// Increment the failed checks counter.
-		if check.Result.String() == CheckResultFailed {
+		if check.getResult() == CheckResultFailed {
 			failedChecks++
 		}
 
@@ -378,16 +581,29 @@ func (group *ChecksGroup) RunChecks(stopChan <-chan bool, abortChan chan string)
 }
 
 func (group *ChecksGroup) OnAbort(abortReason string) error {
+	events.EmitGroupAborted(group.name, abortReason)
+
+	if group.checkRunStates != nil {
+		group.onAbortConcurrent(abortReason)
+		return nil
+	}
+
 	// If this wasn't the group with the aborted check.
 	if group.currentRunningCheckIdx == checkIdxNone {
 		fmt.Printf("Skipping checks from suite %s\n", strings.ToUpper(group.name))
 	}
 
-	for i, check := range group.checks {
+	// Index of the check among the ones that were selected to run.
+	i := -1
+	for _, check := range group.checks {
+		if check.resumed || restoreCheckResult(check) {
+			continue
+		}
 		if !labelsExprEvaluator.Eval(check.Labels) {
-			check.SetResultSkipped("not matching labels")
+			check.SetResultSkipped(skipReasonNoMatchingLabels)
 			continue
 		}
+		i++
 
 		// If none of this group's checks was running yet, skip all.
 		if group.currentRunningCheckIdx == checkIdxNone {
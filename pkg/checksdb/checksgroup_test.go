@@ -1 +1,163 @@
 package checksdb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/events"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCheckTimeout(t *testing.T) {
+	defer func() {
+		defaultCheckTimeout = 0
+		checkTimeoutsByID = map[string]time.Duration{}
+	}()
+
+	group := &ChecksGroup{name: "myGroup"}
+	check := NewCheck("myID", []string{"label1"})
+
+	// No timeout at all.
+	assert.Equal(t, time.Duration(0), getCheckTimeout(check, group))
+
+	defaultCheckTimeout = 1 * time.Minute
+	assert.Equal(t, 1*time.Minute, getCheckTimeout(check, group))
+
+	group.WithCheckTimeout(2 * time.Minute)
+	assert.Equal(t, 2*time.Minute, getCheckTimeout(check, group))
+
+	check.WithTimeout(3 * time.Minute)
+	assert.Equal(t, 3*time.Minute, getCheckTimeout(check, group))
+
+	checkTimeoutsByID["myID"] = 4 * time.Minute
+	assert.Equal(t, 4*time.Minute, getCheckTimeout(check, group))
+}
+
+func TestRunCheckTimeout(t *testing.T) {
+	group := &ChecksGroup{name: "myGroup"}
+
+	release := make(chan bool)
+	defer close(release)
+
+	check := NewCheck("myID", []string{"label1"}).
+		WithTimeout(50 * time.Millisecond).
+		WithCheckFn(func(c *Check) error {
+			<-release
+			c.SetResult(nil, nil)
+			return nil
+		})
+
+	remainingCheck := NewCheck("myID2", []string{"label1"})
+
+	err := runCheck(check, group, []*Check{remainingCheck})
+	assert.Nil(t, err)
+	assert.Equal(t, CheckResult(CheckResultAborted), check.Result)
+	assert.Equal(t, "check timed out after 50ms", check.skipReason)
+	// The remaining checks must not be affected.
+	assert.Equal(t, CheckResult(CheckResultPassed), remainingCheck.Result)
+}
+
+func TestRunCheckTimeoutCancelsContext(t *testing.T) {
+	group := &ChecksGroup{name: "myGroup"}
+	abortChan := make(chan string, 1)
+	afterCheckFnCalled := make(chan bool, 1)
+	checkFnDone := make(chan bool)
+
+	check := NewCheck("myID", []string{"label1"}).
+		WithTimeout(50 * time.Millisecond).
+		WithCheckFn(func(c *Check) error {
+			defer close(checkFnDone)
+			// The context is cancelled once the check times out.
+			<-c.Context().Done()
+			// Wait for the timeout to be handled before aborting.
+			for !c.isTimedOut() {
+				time.Sleep(time.Millisecond)
+			}
+			c.Abort("too late")
+			return nil
+		}).
+		WithAfterCheckFn(func(c *Check) error {
+			afterCheckFnCalled <- true
+			return nil
+		})
+	check.SetAbortChan(abortChan)
+
+	err := runCheck(check, group, nil)
+	assert.Nil(t, err)
+	<-checkFnDone
+
+	assert.Equal(t, CheckResult(CheckResultAborted), check.getResult())
+	// A timed out check can't abort the run, nor run its after check function.
+	assert.Empty(t, abortChan)
+	assert.Empty(t, afterCheckFnCalled)
+}
+
+func TestRunCheckFinishesBeforeTimeout(t *testing.T) {
+	group := &ChecksGroup{name: "myGroup"}
+
+	check := NewCheck("myID", []string{"label1"}).
+		WithTimeout(1 * time.Minute).
+		WithCheckFn(func(c *Check) error {
+			return nil
+		})
+
+	err := runCheck(check, group, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, CheckResult(CheckResultPassed), check.Result)
+}
+
+func TestShouldSkipCheckOfflineMode(t *testing.T) {
+	defer func() {
+		offlineMode = false
+	}()
+
+	intrusiveCheck := NewCheck("intrusiveCheck", nil).WithIntrusive()
+	check := NewCheck("check", nil)
+
+	skip, _ := shouldSkipCheck(intrusiveCheck)
+	assert.False(t, skip)
+
+	SetOfflineMode(true)
+	skip, reasons := shouldSkipCheck(intrusiveCheck)
+	assert.True(t, skip)
+	assert.Equal(t, []string{offlineModeSkipReason}, reasons)
+
+	skip, _ = shouldSkipCheck(check)
+	assert.False(t, skip)
+}
+
+func TestRunCheckEvents(t *testing.T) {
+	eventsChan, unsubscribe := events.Subscribe()
+	defer unsubscribe()
+
+	group := &ChecksGroup{name: "myGroup"}
+	check := NewCheck("myID", []string{"label1"}).
+		WithCheckFn(func(c *Check) error {
+			c.SetResult(nil, []*testhelper.ReportObject{testhelper.NewPodReportObject("ns1", "pod1", "reason", false)})
+			return nil
+		})
+	group.Add(check)
+
+	assert.Nil(t, runCheck(check, group, nil))
+
+	startedEvent := &events.Event{}
+	assert.Nil(t, json.Unmarshal(<-eventsChan, startedEvent))
+	assert.Equal(t, events.TypeCheckStarted, startedEvent.Type)
+	assert.Equal(t, "myID", startedEvent.CheckID)
+	assert.Equal(t, "myGroup", startedEvent.Suite)
+
+	finishedEvent := &events.Event{}
+	assert.Nil(t, json.Unmarshal(<-eventsChan, finishedEvent))
+	assert.Equal(t, events.TypeCheckFinished, finishedEvent.Type)
+	assert.Equal(t, "myID", finishedEvent.CheckID)
+	assert.Equal(t, "myGroup", finishedEvent.Suite)
+	assert.Equal(t, CheckResultFailed, finishedEvent.Result)
+
+	// Skipped checks only have the finished event, with the skip reason.
+	skipCheck(NewCheck("myID2", nil), "not needed")
+	skippedEvent := &events.Event{}
+	assert.Nil(t, json.Unmarshal(<-eventsChan, skippedEvent))
+	assert.Equal(t, events.Event{Type: events.TypeCheckFinished, Time: skippedEvent.Time, CheckID: "myID2", Result: CheckResultSkipped, SkipReason: "not needed"}, *skippedEvent)
+}
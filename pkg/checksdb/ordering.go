@@ -0,0 +1,224 @@
+package checksdb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+)
+
+// Ordering constraints of a group or a check. Items are identified by name (group name or check ID)
+// and their index is the order in which they were registered, which is used to break ties.
+type orderItem struct {
+	name       string
+	idx        int
+	priority   int
+	runsLast   bool
+	runsAfter  []string
+	runsBefore []string
+}
+
+// Returns true if item a must be picked before item b when both are ready to run: disruptive
+// items go last, then higher priorities go first, then the registration order is kept.
+func (a *orderItem) goesBefore(b *orderItem) bool {
+	if a.runsLast != b.runsLast {
+		return !a.runsLast
+	}
+
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+
+	return a.idx < b.idx
+}
+
+// Sorts the items topologically based on their runsAfter/runsBefore constraints. Among the items
+// whose constraints are satisfied, the one that goes first is picked, so the resulting order is
+// always the same for the same set of items. Constraints referring to unknown items are ignored.
+// Returns the indexes of the items in the resolved order, or an error in case of cycles.
+func sortItems(kind string, items []orderItem) ([]int, error) {
+	idxByName := map[string]int{}
+	for i := range items {
+		idxByName[items[i].name] = i
+	}
+
+	// successors[i] holds the items that must run after item i.
+	successors := make([][]int, len(items))
+	predecessorsCount := make([]int, len(items))
+	addEdge := func(from, to int) {
+		successors[from] = append(successors[from], to)
+		predecessorsCount[to]++
+	}
+
+	for i := range items {
+		for _, name := range items[i].runsAfter {
+			if j, exists := idxByName[name]; !exists {
+				log.Warn("Ignoring unknown %s %q in RunsAfter of %s %q", kind, name, kind, items[i].name)
+			} else if j != i {
+				addEdge(j, i)
+			}
+		}
+		for _, name := range items[i].runsBefore {
+			if j, exists := idxByName[name]; !exists {
+				log.Warn("Ignoring unknown %s %q in RunsBefore of %s %q", kind, name, kind, items[i].name)
+			} else if j != i {
+				addEdge(i, j)
+			}
+		}
+	}
+
+	order := make([]int, 0, len(items))
+	done := make([]bool, len(items))
+	for len(order) < len(items) {
+		next := -1
+		for i := range items {
+			if done[i] || predecessorsCount[i] > 0 {
+				continue
+			}
+			if next == -1 || items[i].goesBefore(&items[next]) {
+				next = i
+			}
+		}
+
+		if next == -1 {
+			pending := []string{}
+			for i := range items {
+				if !done[i] {
+					pending = append(pending, items[i].name)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle found among %ss: %s", kind, strings.Join(pending, ", "))
+		}
+
+		done[next] = true
+		order = append(order, next)
+		for _, j := range successors[next] {
+			predecessorsCount[j]--
+		}
+	}
+
+	return order, nil
+}
+
+func (group *ChecksGroup) isDisruptive() bool {
+	for _, check := range group.checks {
+		if check.Intrusive {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Sorts the group's checks based on their ordering constraints. Intrusive checks run last unless
+// some other check explicitly needs to run after them.
+func (group *ChecksGroup) sortChecks() error {
+	// Always start from the registration order.
+	sort.SliceStable(group.checks, func(i, j int) bool {
+		return group.checks[i].registrationIdx < group.checks[j].registrationIdx
+	})
+
+	items := make([]orderItem, len(group.checks))
+	for i, check := range group.checks {
+		items[i] = orderItem{
+			name:       check.ID,
+			idx:        check.registrationIdx,
+			priority:   check.Priority,
+			runsLast:   check.Intrusive,
+			runsAfter:  check.RunsAfter,
+			runsBefore: check.RunsBefore,
+		}
+	}
+
+	order, err := sortItems("check", items)
+	if err != nil {
+		return fmt.Errorf("group %s: %v", group.name, err)
+	}
+
+	sortedChecks := make([]*Check, len(order))
+	for i, idx := range order {
+		sortedChecks[i] = group.checks[idx]
+	}
+	group.checks = sortedChecks
+
+	return nil
+}
+
+// Resolves the order in which groups and their checks will run. Groups with intrusive
+// checks run after the rest of groups unless other groups explicitly need to run after them.
+// Must be called with dbLock held.
+func resolveChecksOrder() error {
+	groups := make([]*ChecksGroup, 0, len(dbByGroup))
+	for _, group := range dbByGroup {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].registrationIdx < groups[j].registrationIdx
+	})
+
+	items := make([]orderItem, len(groups))
+	for i, group := range groups {
+		if err := group.sortChecks(); err != nil {
+			return err
+		}
+
+		items[i] = orderItem{
+			name:       group.name,
+			idx:        group.registrationIdx,
+			priority:   group.priority,
+			runsLast:   group.isDisruptive(),
+			runsAfter:  group.runsAfter,
+			runsBefore: group.runsBefore,
+		}
+	}
+
+	order, err := sortItems("group", items)
+	if err != nil {
+		return err
+	}
+
+	orderedGroups = make([]*ChecksGroup, len(order))
+	for i, idx := range order {
+		orderedGroups[i] = groups[idx]
+	}
+
+	return nil
+}
+
+// ResolveChecksOrder resolves the order in which the groups and checks will run, based on their
+// RunsAfter/RunsBefore constraints, priorities and whether they're intrusive. It returns the names
+// of the groups in the order they will run.
+func ResolveChecksOrder() ([]string, error) {
+	dbLock.Lock()
+	defer dbLock.Unlock()
+
+	if err := resolveChecksOrder(); err != nil {
+		return nil, err
+	}
+
+	groupNames := make([]string, len(orderedGroups))
+	for i, group := range orderedGroups {
+		groupNames[i] = group.name
+	}
+
+	return groupNames, nil
+}
+
+// GetChecksOrder returns the IDs of the checks matching the labels filter in the order
+// they run. ResolveChecksOrder or RunChecks must have been called before.
+func GetChecksOrder() []string {
+	dbLock.Lock()
+	defer dbLock.Unlock()
+
+	checkIDs := []string{}
+	for _, group := range orderedGroups {
+		for _, check := range group.checks {
+			if labelsExprEvaluator == nil || labelsExprEvaluator.Eval(check.Labels) {
+				checkIDs = append(checkIDs, check.ID)
+			}
+		}
+	}
+
+	return checkIDs
+}
@@ -0,0 +1,92 @@
+package checksdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortItems(t *testing.T) {
+	testCases := []struct {
+		items         []orderItem
+		expectedOrder []int
+		expectedError bool
+	}{
+		{ // Registration order is kept when there are no constraints.
+			items:         []orderItem{{name: "a", idx: 0}, {name: "b", idx: 1}, {name: "c", idx: 2}},
+			expectedOrder: []int{0, 1, 2},
+		},
+		{ // Disruptive items go last.
+			items:         []orderItem{{name: "a", idx: 0, runsLast: true}, {name: "b", idx: 1}, {name: "c", idx: 2}},
+			expectedOrder: []int{1, 2, 0},
+		},
+		{ // Higher priorities go first.
+			items:         []orderItem{{name: "a", idx: 0}, {name: "b", idx: 1}, {name: "c", idx: 2, priority: 1}},
+			expectedOrder: []int{2, 0, 1},
+		},
+		{ // Explicit constraints take precedence over priorities and disruptive items.
+			items: []orderItem{
+				{name: "a", idx: 0, priority: 10, runsAfter: []string{"c"}},
+				{name: "b", idx: 1, runsLast: true, runsBefore: []string{"c"}},
+				{name: "c", idx: 2},
+			},
+			expectedOrder: []int{1, 2, 0},
+		},
+		{ // Unknown items and self references are ignored.
+			items:         []orderItem{{name: "a", idx: 0, runsAfter: []string{"x", "a"}}, {name: "b", idx: 1}},
+			expectedOrder: []int{0, 1},
+		},
+		{ // Cycles.
+			items: []orderItem{
+				{name: "a", idx: 0, runsAfter: []string{"b"}},
+				{name: "b", idx: 1, runsAfter: []string{"a"}},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		order, err := sortItems("item", tc.items)
+		if tc.expectedError {
+			assert.NotNil(t, err)
+			continue
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expectedOrder, order)
+	}
+}
+
+func TestResolveChecksOrder(t *testing.T) {
+	savedDBByGroup := dbByGroup
+	defer func() {
+		dbByGroup = savedDBByGroup
+		orderedGroups = nil
+	}()
+	dbByGroup = nil
+
+	group1 := NewChecksGroup("group1")
+	group1.Add(NewCheck("check1", nil).WithIntrusive())
+	group1.Add(NewCheck("check2", nil))
+	group2 := NewChecksGroup("group2")
+	group2.Add(NewCheck("check3", nil))
+	group2.Add(NewCheck("check4", nil).WithRunsBefore("check3"))
+	group3 := NewChecksGroup("group3").WithRunsAfter("group2")
+	group3.Add(NewCheck("check5", nil))
+
+	groupNames, err := ResolveChecksOrder()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"group2", "group3", "group1"}, groupNames)
+	assert.Equal(t, []string{"check4", "check3", "check5", "check2", "check1"}, GetChecksOrder())
+
+	// Resolving the order again gives the same result.
+	groupNames, err = ResolveChecksOrder()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"group2", "group3", "group1"}, groupNames)
+	assert.Equal(t, []string{"check4", "check3", "check5", "check2", "check1"}, GetChecksOrder())
+
+	group1.WithRunsBefore("group3")
+	group3.WithRunsBefore("group1")
+	_, err = ResolveChecksOrder()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,77 @@
+package checksdb
+
+import (
+	"sync"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+)
+
+var (
+	// Returns the permissions, from the given unresolved ones, that are not granted. The permissions
+	// of the checks are not verified if not set.
+	permissionsChecker func([]permissions.Permission) ([]permissions.Permission, error)
+	permissionsLock    sync.RWMutex
+)
+
+// SetPermissionsChecker sets the function used to find out the permissions that a check needs but
+// are not granted, so the check is skipped. Pass nil to stop verifying the permissions.
+func SetPermissionsChecker(checker func([]permissions.Permission) ([]permissions.Permission, error)) {
+	permissionsLock.Lock()
+	defer permissionsLock.Unlock()
+
+	permissionsChecker = checker
+}
+
+// Returns the permissions of the check that are not granted. If they can't be verified, a warning
+// is logged and the check is allowed to run.
+func getMissingPermissions(check *Check) []permissions.Permission {
+	permissionsLock.RLock()
+	defer permissionsLock.RUnlock()
+
+	if permissionsChecker == nil {
+		return nil
+	}
+
+	perms := check.Permissions
+	if check.EnvPermissionsFn != nil {
+		// The checks run once the test environment has been discovered.
+		env := provider.GetTestEnvironment()
+		perms = check.GetPermissions(&env)
+	}
+
+	if len(perms) == 0 {
+		return nil
+	}
+
+	missing, err := permissionsChecker(perms)
+	if err != nil {
+		log.Warn("Could not verify the permissions of check %s: %v", check.ID, err)
+		return nil
+	}
+
+	return missing
+}
+
+// GetFilteredChecksPermissions returns the permissions, with the namespace placeholders unresolved,
+// needed by every check that matches the labels filter in the given test environment.
+func GetFilteredChecksPermissions(env *provider.TestEnvironment) (map[string][]permissions.Permission, error) {
+	dbLock.Lock()
+	defer dbLock.Unlock()
+
+	if err := resolveChecksOrder(); err != nil {
+		return nil, err
+	}
+
+	checksPermissions := map[string][]permissions.Permission{}
+	for _, group := range orderedGroups {
+		for _, check := range group.checks {
+			if labelsExprEvaluator.Eval(check.Labels) {
+				checksPermissions[check.ID] = check.GetPermissions(env)
+			}
+		}
+	}
+
+	return checksPermissions, nil
+}
@@ -0,0 +1,57 @@
+package checksdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldSkipCheckMissingPermissions(t *testing.T) {
+	defer SetPermissionsChecker(nil)
+
+	check := NewCheck("check", nil).WithPermissions(permissions.ListNodes, permissions.ListPods)
+	noPermsCheck := NewCheck("noPermsCheck", nil)
+
+	// Permissions are not verified until a checker is set.
+	skip, _ := shouldSkipCheck(check)
+	assert.False(t, skip)
+
+	SetPermissionsChecker(func(perms []permissions.Permission) ([]permissions.Permission, error) {
+		return permissions.Resolve(perms[1:], []string{"tnf"}, ""), nil
+	})
+	skip, reasons := shouldSkipCheck(check)
+	assert.True(t, skip)
+	assert.Equal(t, []string{"missing permissions: list pods in namespace tnf"}, reasons)
+
+	skip, _ = shouldSkipCheck(noPermsCheck)
+	assert.False(t, skip)
+
+	// The check runs if its permissions can't be verified.
+	SetPermissionsChecker(func(perms []permissions.Permission) ([]permissions.Permission, error) {
+		return nil, errors.New("review failed")
+	})
+	skip, _ = shouldSkipCheck(check)
+	assert.False(t, skip)
+}
+
+func TestCheckGetPermissions(t *testing.T) {
+	check := NewCheck("check", nil).WithPermissions(permissions.ListNodes)
+	assert.Equal(t, []permissions.Permission{permissions.ListNodes}, check.GetPermissions(&provider.TestEnvironment{}))
+
+	// The permissions on the discovered objects are added to the static ones.
+	check.WithEnvPermissionsFn(func(env *provider.TestEnvironment) []permissions.Permission {
+		perms := []permissions.Permission{}
+		for _, ns := range env.Namespaces {
+			perms = append(perms, permissions.Permission{Verb: "get", Resource: "configmaps", Namespace: ns})
+		}
+		return perms
+	})
+	assert.Equal(t, []permissions.Permission{
+		permissions.ListNodes,
+		{Verb: "get", Resource: "configmaps", Namespace: "tnf"},
+	}, check.GetPermissions(&provider.TestEnvironment{Namespaces: []string{"tnf"}}))
+	assert.Equal(t, []permissions.Permission{permissions.ListNodes}, check.Permissions)
+}
@@ -0,0 +1,156 @@
+package checksdb
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+)
+
+const (
+	abortReasonGlobalTimeout = "global time-out"
+	abortReasonSigInt        = "SIGINT/SIGTERM"
+)
+
+var (
+	// Results from a previous run that don't need to be run again, by check ID.
+	resumedResults = map[string]claim.Result{}
+
+	// Called after every check with the results recorded so far.
+	checkpointFn    func(results map[string]claim.Result)
+	checkpointMutex sync.Mutex
+	// Set once the run is aborted, so the checks that are still running don't save their results.
+	checkpointsStopped bool
+)
+
+// SetResumedResults sets the results of a previous, probably interrupted, run. Checks whose
+// previous result was passed, failed or skipped by their own skip functions won't run again and
+// their previous result will be used instead. The rest of the checks will run as usual. The reused
+// results are recorded right away, so they're saved in every checkpoint even before their group
+// runs. Returns the number of results that will be reused.
+func SetResumedResults(results map[string]claim.Result) int {
+	dbLock.Lock()
+	defer dbLock.Unlock()
+
+	// Skip reasons of the checks that were skipped because the run was interrupted.
+	abortReasons := map[string]bool{
+		abortReasonGlobalTimeout: true,
+		abortReasonSigInt:        true,
+	}
+	//nolint:gocritic
+	for _, result := range results {
+		if result.State == CheckResultAborted {
+			abortReasons[result.SkipReason] = true
+		}
+	}
+
+	groupNames := getGroupNamesByCheckID()
+
+	resumedResults = map[string]claim.Result{}
+	//nolint:gocritic
+	for checkID, result := range results {
+		switch result.State {
+		case CheckResultPassed, CheckResultFailed:
+		case CheckResultSkipped:
+			if abortReasons[result.SkipReason] || !isReusableSkipReason(result.SkipReason, groupNames[checkID]) {
+				continue
+			}
+		default:
+			continue
+		}
+
+		resumedResults[checkID] = result
+	}
+
+	resultsDBLock.Lock()
+	defer resultsDBLock.Unlock()
+	//nolint:gocritic
+	for checkID, result := range resumedResults {
+		resultsDB[checkID] = result
+	}
+
+	log.Info("Reusing %d results out of %d from the previous run.", len(resumedResults), len(results))
+	return len(resumedResults)
+}
+
+func getGroupNamesByCheckID() map[string]string {
+	groupNames := map[string]string{}
+	for groupName, group := range dbByGroup {
+		for _, check := range group.checks {
+			groupNames[check.ID] = groupName
+		}
+	}
+
+	return groupNames
+}
+
+// Returns whether the skip reason comes from the check's own skip functions or from not having any
+// objects to check. Checks skipped because of the labels filter, missing permissions, the offline
+// mode or a failure of another check of their group must run again, as things may have changed.
+func isReusableSkipReason(reason, groupName string) bool {
+	switch {
+	case reason == skipReasonNoMatchingLabels, reason == offlineModeSkipReason:
+		return false
+	case strings.HasPrefix(reason, skipReasonMissingPermissionsPrefix):
+		return false
+	case strings.HasPrefix(reason, getGroupFailureSkipReasonPrefix(groupName)):
+		return false
+	}
+
+	return true
+}
+
+// SetCheckpointFn sets the function that will be called after every check with all the results
+// recorded so far, so they can be saved to disk in case the run is interrupted.
+func SetCheckpointFn(fn func(results map[string]claim.Result)) {
+	checkpointMutex.Lock()
+	defer checkpointMutex.Unlock()
+
+	checkpointFn = fn
+	checkpointsStopped = false
+}
+
+// Stops saving the results after every check. Used when the run is aborted, as the results of
+// the checks that are still running will be set by the groups' OnAbort functions instead.
+func stopCheckpoints() {
+	checkpointMutex.Lock()
+	defer checkpointMutex.Unlock()
+
+	checkpointsStopped = true
+}
+
+// Records the check's result and saves all the results recorded so far. Nothing is done
+// if no checkpoint function was set or the run was aborted, as results are recorded anyway
+// once the group finishes.
+func checkpointCheckResult(check *Check) {
+	checkpointMutex.Lock()
+	defer checkpointMutex.Unlock()
+
+	if checkpointFn == nil || checkpointsStopped {
+		return
+	}
+
+	recordCheckResult(check)
+	checkpointFn(GetReconciledResults())
+}
+
+// Sets the check's result from the previous run, if it does not need to run again.
+// Returns false if the check needs to run.
+func restoreCheckResult(check *Check) bool {
+	result, exists := resumedResults[check.ID]
+	if !exists {
+		return false
+	}
+
+	check.mutex.Lock()
+	check.resumed = true
+	check.Result = CheckResult(result.State)
+	check.skipReason = result.SkipReason
+	check.details = result.CheckDetails
+	check.mutex.Unlock()
+
+	check.LogInfo("Reusing result %q of check %s from the previous run.", result.State, check.ID)
+	printCheckResult(check)
+	return true
+}
@@ -0,0 +1,86 @@
+package checksdb
+
+import (
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetResumedResults(t *testing.T) {
+	defer func() {
+		resumedResults = map[string]claim.Result{}
+		resultsDB = map[string]claim.Result{}
+		dbByGroup = nil
+	}()
+
+	group := NewChecksGroup("resumed-group")
+	group.Add(NewCheck("skipped-by-group-failure", nil))
+
+	results := map[string]claim.Result{
+		"passed-check":          {State: CheckResultPassed},
+		"failed-check":          {State: CheckResultFailed},
+		"skipped-check":         {State: CheckResultSkipped, SkipReason: "no pods to test"},
+		"error-check":           {State: CheckResultError, SkipReason: "check panicked"},
+		"aborted-check":         {State: CheckResultAborted, SkipReason: "check aborted by user"},
+		"skipped-by-abort":      {State: CheckResultSkipped, SkipReason: "check aborted by user"},
+		"skipped-by-sigint":     {State: CheckResultSkipped, SkipReason: abortReasonSigInt},
+		"skipped-by-gl-timeout": {State: CheckResultSkipped, SkipReason: abortReasonGlobalTimeout},
+		"skipped-by-labels":     {State: CheckResultSkipped, SkipReason: skipReasonNoMatchingLabels},
+		"skipped-by-perms":      {State: CheckResultSkipped, SkipReason: skipReasonMissingPermissionsPrefix + "list pods"},
+		"skipped-offline":       {State: CheckResultSkipped, SkipReason: offlineModeSkipReason},
+		"skipped-by-group-failure": {State: CheckResultSkipped,
+			SkipReason: "group resumed-group check other-check function panic"},
+		"skipped-empty": {State: CheckResultSkipped, SkipReason: "compliant and non-compliant objects lists are empty"},
+	}
+
+	assert.Equal(t, 4, SetResumedResults(results))
+	assert.Contains(t, resumedResults, "passed-check")
+	assert.Contains(t, resumedResults, "failed-check")
+	assert.Contains(t, resumedResults, "skipped-check")
+	assert.Contains(t, resumedResults, "skipped-empty")
+
+	// The reused results are saved in the checkpoints before their groups run.
+	assert.Equal(t, resumedResults, GetReconciledResults())
+
+	check := NewCheck("failed-check", nil)
+	assert.True(t, restoreCheckResult(check))
+	assert.True(t, check.resumed)
+	assert.Equal(t, CheckResult(CheckResultFailed), check.Result)
+
+	check = NewCheck("aborted-check", nil)
+	assert.False(t, restoreCheckResult(check))
+	assert.False(t, check.resumed)
+	assert.Equal(t, CheckResult(CheckResultPassed), check.Result)
+}
+
+func TestCheckpointCheckResult(t *testing.T) {
+	defer func() {
+		SetCheckpointFn(nil)
+		resultsDB = map[string]claim.Result{}
+	}()
+
+	checkpoints := 0
+	SetCheckpointFn(func(_ map[string]claim.Result) {
+		checkpoints++
+	})
+
+	testID, _ := identifiers.GetTestIDAndLabels(identifiers.TestICMPv4ConnectivityIdentifier)
+	check := NewCheck(testID, nil)
+	checkpointCheckResult(check)
+	assert.Equal(t, 1, checkpoints)
+	assert.Contains(t, GetReconciledResults(), check.ID)
+
+	// Checks still running after the run was aborted don't save their results.
+	stopCheckpoints()
+	checkpointCheckResult(check)
+	assert.Equal(t, 1, checkpoints)
+
+	// A new checkpoint function is used in a new run.
+	SetCheckpointFn(func(_ map[string]claim.Result) {
+		checkpoints++
+	})
+	checkpointCheckResult(check)
+	assert.Equal(t, 2, checkpoints)
+}
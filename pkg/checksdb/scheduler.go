@@ -0,0 +1,474 @@
+package checksdb
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/cli"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/stringhelper"
+)
+
+type checkRunState int
+
+// Time the groups have to stop once the run is aborted, before the checks results are recorded.
+const abortGracePeriod = 30 * time.Second
+
+const (
+	checkRunPending checkRunState = iota
+	checkRunRunning
+	checkRunDone
+)
+
+// Limits the number of checks running at the same time. Intrusive checks need exclusive
+// access: they wait for all the running checks to finish, and no other check can start
+// until they are done.
+type checksScheduler struct {
+	slots     chan struct{}
+	exclusive sync.RWMutex
+}
+
+func newChecksScheduler(maxParallelChecks int) *checksScheduler {
+	return &checksScheduler{
+		slots: make(chan struct{}, maxParallelChecks),
+	}
+}
+
+func (s *checksScheduler) acquire(intrusive bool) {
+	if intrusive {
+		s.exclusive.Lock()
+		return
+	}
+
+	s.exclusive.RLock()
+	s.slots <- struct{}{}
+}
+
+func (s *checksScheduler) release(intrusive bool) {
+	if intrusive {
+		s.exclusive.Unlock()
+		return
+	}
+
+	<-s.slots
+	s.exclusive.RUnlock()
+}
+
+// Runs all the groups at the same time. The scheduler makes sure that no more than "parallelism"
+// checks are running at any time, and that intrusive checks run alone. Groups with intrusive checks
+// are started once the previous groups have finished, so disruptive checks always run last.
+func runGroupsConcurrently(timeOutChan <-chan time.Time, sigIntChan <-chan os.Signal) (failedCtr int, errs []error) {
+	cli.SetConcurrentChecks(true)
+	defer cli.SetConcurrentChecks(false)
+
+	scheduler := newChecksScheduler(parallelism)
+	// Closed to stop all the groups at once.
+	stopChan := make(chan bool)
+	// Several running checks could be aborted at the same time.
+	abortChan := make(chan string, parallelism)
+
+	// Must be done before any group starts running, as OnAbort relies on these states.
+	for _, group := range orderedGroups {
+		group.initCheckRunStates()
+	}
+
+	abortReason := ""
+	for _, batch := range splitGroupsBatches(orderedGroups) {
+		var batchFailedCtr int
+		var batchErrs []error
+		batchFailedCtr, batchErrs, abortReason = runGroupsBatch(batch, scheduler, stopChan, abortChan, timeOutChan, sigIntChan)
+		failedCtr += batchFailedCtr
+		errs = append(errs, batchErrs...)
+		if abortReason != "" {
+			break
+		}
+	}
+
+	if abortReason != "" {
+		for _, group := range orderedGroups {
+			_ = group.OnAbort(abortReason)
+		}
+	}
+
+	for _, group := range orderedGroups {
+		group.RecordChecksResults()
+	}
+
+	return failedCtr, errs
+}
+
+// Splits the ordered groups in two batches: the groups before the first disruptive one, and the rest.
+func splitGroupsBatches(groups []*ChecksGroup) [][]*ChecksGroup {
+	for i, group := range groups {
+		if group.isDisruptive() && i > 0 {
+			return [][]*ChecksGroup{groups[:i], groups[i:]}
+		}
+	}
+
+	return [][]*ChecksGroup{groups}
+}
+
+// Runs a batch of groups concurrently and waits for all of them to finish. In case the run is
+// aborted, the groups are stopped by closing the stop channel, and they're given some time to
+// finish their running checks and their afterAll functions before the abort reason is returned.
+func runGroupsBatch(groups []*ChecksGroup, scheduler *checksScheduler, stopChan chan bool, abortChan chan string,
+	timeOutChan <-chan time.Time, sigIntChan <-chan os.Signal) (failedCtr int, errs []error, abortReason string) {
+	var resultsMutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, group := range groups {
+		wg.Add(1)
+		go func(group *ChecksGroup) {
+			defer wg.Done()
+			groupErrs, groupFailedChecks := group.runChecksConcurrently(scheduler, stopChan, abortChan)
+
+			resultsMutex.Lock()
+			defer resultsMutex.Unlock()
+			failedCtr += groupFailedChecks
+			errs = append(errs, groupErrs...)
+		}(group)
+	}
+
+	allGroupsDone := make(chan bool)
+	go func() {
+		wg.Wait()
+		close(allGroupsDone)
+	}()
+
+	select {
+	case <-allGroupsDone:
+		log.Debug("All groups finished running checks.")
+	case abortReason = <-abortChan:
+		log.Warn("Check aborted: %s", abortReason)
+	case <-timeOutChan:
+		log.Warn("Running all checks timed-out.")
+		abortReason = abortReasonGlobalTimeout
+	case <-sigIntChan:
+		log.Warn("SIGINT/SIGTERM received.")
+		abortReason = abortReasonSigInt
+	}
+
+	if abortReason != "" {
+		stopCheckpoints()
+		close(stopChan)
+		select {
+		case <-allGroupsDone:
+			log.Debug("All groups stopped after the abort.")
+		case <-time.After(abortGracePeriod):
+			log.Warn("Some groups did not stop %v after the abort.", abortGracePeriod)
+		}
+	}
+
+	resultsMutex.Lock()
+	defer resultsMutex.Unlock()
+	return failedCtr, errs, abortReason
+}
+
+func (group *ChecksGroup) initCheckRunStates() {
+	group.checkRunStatesMutex.Lock()
+	defer group.checkRunStatesMutex.Unlock()
+
+	group.checkRunStates = map[*Check]checkRunState{}
+	for _, check := range group.checks {
+		group.checkRunStates[check] = checkRunPending
+	}
+}
+
+// Moves a check from one state to another. Returns false if the check was not in the expected
+// state, which can happen if the check was skipped/aborted by OnAbort() in the meantime.
+func (group *ChecksGroup) moveCheckRunState(check *Check, from, to checkRunState) bool {
+	group.checkRunStatesMutex.Lock()
+	defer group.checkRunStatesMutex.Unlock()
+
+	if group.checkRunStates[check] != from {
+		return false
+	}
+
+	group.checkRunStates[check] = to
+	return true
+}
+
+// Runs the check along with the group's beforeEach and afterEach functions.
+func (group *ChecksGroup) runCheckWithHooks(check *Check, abortChan chan string) (errs []error) {
+	if err := runBeforeEachFn(group, check, nil); err != nil {
+		errs = append(errs, err)
+	} else {
+		skip, reasons := shouldSkipCheck(check)
+		if skip {
+			skipCheck(check, strings.Join(reasons, ", "))
+		} else {
+			check.SetAbortChan(abortChan)
+			if err := runCheck(check, group, nil); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	// afterEach func must run even if the check was skipped or panicked/unexpected error.
+	if err := runAfterEachFn(group, check, nil); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// Concurrent version of group.RunChecks(), used when several groups run at the same time. Each
+// check waits for the scheduler to let it run. The group's non-intrusive checks run at the same time
+// too, in batches of consecutive non-intrusive checks, as only intrusive checks change the test
+// environment. As in the sequential version, no more checks are started after any check errors or
+// panics, and the checks that were not started yet are skipped.
+func (group *ChecksGroup) runChecksConcurrently(scheduler *checksScheduler, stopChan <-chan bool, abortChan chan string) (errs []error, failedChecks int) {
+	log.Info("Running group %q checks.", group.name)
+	fmt.Printf("Running suite %s\n", strings.ToUpper(group.name))
+
+	// Get checks to run based on the label expr.
+	checks := []*Check{}
+	for _, check := range group.checks {
+		if restoreCheckResult(check) {
+			group.moveCheckRunState(check, checkRunPending, checkRunDone)
+			continue
+		}
+		if !labelsExprEvaluator.Eval(check.Labels) {
+			group.moveCheckRunState(check, checkRunPending, checkRunDone)
+			skipCheck(check, skipReasonNoMatchingLabels)
+			continue
+		}
+		checks = append(checks, check)
+	}
+
+	if len(checks) == 0 {
+		return nil, 0
+	}
+
+	endSpan := group.startSpan(true)
+	defer endSpan()
+
+	// Run afterAllFn always, no matter previous panics/crashes.
+	defer func() {
+		if err := runAfterAllFn(group, checks); err != nil {
+			errs = append(errs, err)
+		}
+	}()
+
+	if err := runBeforeAllFn(group, checks); err != nil {
+		for _, check := range checks {
+			group.moveCheckRunState(check, checkRunPending, checkRunDone)
+		}
+		return []error{err}, 0
+	}
+
+	log.Info("Checks to run: %d (group's total=%d)", len(checks), len(group.checks))
+
+	for i := 0; i < len(checks) && len(errs) == 0 && !isStopped(stopChan); {
+		batchEnd := i + 1
+		if !checks[i].Intrusive {
+			for batchEnd < len(checks) && !checks[batchEnd].Intrusive {
+				batchEnd++
+			}
+		}
+
+		var batchErrs []error
+		var batchFailedChecks int
+		if checks[i].Intrusive {
+			batchErrs, batchFailedChecks = group.runIntrusiveCheck(checks[i], scheduler, stopChan, abortChan)
+		} else {
+			batchErrs, batchFailedChecks = group.runChecksBatch(checks[i:batchEnd], scheduler, stopChan, abortChan)
+		}
+		errs = append(errs, batchErrs...)
+		failedChecks += batchFailedChecks
+		i = batchEnd
+	}
+
+	// Skip the checks that couldn't be started due to previous errors.
+	if len(errs) > 0 {
+		for _, check := range checks {
+			if group.moveCheckRunState(check, checkRunPending, checkRunDone) {
+				skipCheck(check, errs[0].Error())
+			}
+		}
+	}
+
+	return errs, failedChecks
+}
+
+// Runs an intrusive check along with the group's beforeEach and afterEach functions. No other
+// check runs at the same time.
+func (group *ChecksGroup) runIntrusiveCheck(check *Check, scheduler *checksScheduler, stopChan <-chan bool, abortChan chan string) (errs []error, failedChecks int) {
+	scheduler.acquire(true)
+	// Things might have changed while waiting for the scheduler.
+	if isStopped(stopChan) || !group.moveCheckRunState(check, checkRunPending, checkRunRunning) {
+		scheduler.release(true)
+		return nil, 0
+	}
+
+	errs = group.runCheckWithHooks(check, abortChan)
+	scheduler.release(true)
+	group.moveCheckRunState(check, checkRunRunning, checkRunDone)
+	checkpointCheckResult(check)
+
+	if len(errs) == 0 && check.getResult() == CheckResultFailed {
+		failedChecks++
+	}
+
+	return errs, failedChecks
+}
+
+// Runs a batch of non-intrusive checks of the group at the same time, as long as the scheduler lets
+// them. The beforeEach functions of all the checks run first, as they refresh the test environment
+// variable the checks read, and the afterEach functions run once all the checks have finished. The
+// checks start in their order, but a check never starts before the ones it runs after have finished.
+//
+//nolint:funlen
+func (group *ChecksGroup) runChecksBatch(checks []*Check, scheduler *checksScheduler, stopChan <-chan bool, abortChan chan string) (errs []error, failedChecks int) {
+	// Checks whose beforeEach function was run, so their afterEach function must run too.
+	hookedChecks := []*Check{}
+	defer func() {
+		scheduler.acquire(false)
+		defer scheduler.release(false)
+		for _, check := range hookedChecks {
+			if err := runAfterEachFn(group, check, nil); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}()
+
+	scheduler.acquire(false)
+	for _, check := range checks {
+		hookedChecks = append(hookedChecks, check)
+		if err := runBeforeEachFn(group, check, nil); err != nil {
+			group.moveCheckRunState(check, checkRunPending, checkRunDone)
+			checkpointCheckResult(check)
+			scheduler.release(false)
+			return []error{err}, 0
+		}
+	}
+	scheduler.release(false)
+
+	// Closed once the check has finished or won't run at all.
+	checkDone := map[string]chan struct{}{}
+	for _, check := range checks {
+		checkDone[check.ID] = make(chan struct{})
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	hasErrors := func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(errs) > 0
+	}
+
+	for _, check := range checks {
+		if !waitForChecks(getBatchPrerequisites(check, checks), checkDone, stopChan) || hasErrors() {
+			break
+		}
+
+		scheduler.acquire(false)
+		// Things might have changed while waiting for the scheduler.
+		if isStopped(stopChan) || hasErrors() || !group.moveCheckRunState(check, checkRunPending, checkRunRunning) {
+			scheduler.release(false)
+			close(checkDone[check.ID])
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(checkDone[check.ID])
+
+			var checkErrs []error
+			skip, reasons := shouldSkipCheck(check)
+			if skip {
+				skipCheck(check, strings.Join(reasons, ", "))
+			} else {
+				check.SetAbortChan(abortChan)
+				if err := runCheck(check, group, nil); err != nil {
+					checkErrs = append(checkErrs, err)
+				}
+			}
+			scheduler.release(false)
+			group.moveCheckRunState(check, checkRunRunning, checkRunDone)
+			checkpointCheckResult(check)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			errs = append(errs, checkErrs...)
+			if len(checkErrs) == 0 && check.getResult() == CheckResultFailed {
+				failedChecks++
+			}
+		}()
+	}
+
+	wg.Wait()
+	return errs, failedChecks
+}
+
+// Returns the checks of the batch that must finish before the given check can start.
+func getBatchPrerequisites(check *Check, batch []*Check) []*Check {
+	prerequisites := []*Check{}
+	for _, other := range batch {
+		if other == check {
+			continue
+		}
+		if stringhelper.StringInSlice(check.RunsAfter, other.ID, false) ||
+			stringhelper.StringInSlice(other.RunsBefore, check.ID, false) {
+			prerequisites = append(prerequisites, other)
+		}
+	}
+
+	return prerequisites
+}
+
+// Waits for the given checks to finish. Returns false if the run was stopped in the meantime.
+func waitForChecks(checks []*Check, checkDone map[string]chan struct{}, stopChan <-chan bool) bool {
+	for _, check := range checks {
+		select {
+		case <-checkDone[check.ID]:
+		case <-stopChan:
+			return false
+		}
+	}
+
+	return !isStopped(stopChan)
+}
+
+// Sets the results of the group's checks after the run has been aborted by a check or by the
+// global timeout/SIGINT. Checks that were running are marked as aborted and the ones that were
+// not started yet are marked as skipped.
+func (group *ChecksGroup) onAbortConcurrent(abortReason string) {
+	group.checkRunStatesMutex.Lock()
+	defer group.checkRunStatesMutex.Unlock()
+
+	for _, check := range group.checks {
+		if check.resumed || restoreCheckResult(check) {
+			continue
+		}
+		if !labelsExprEvaluator.Eval(check.Labels) {
+			check.SetResultSkipped(skipReasonNoMatchingLabels)
+			continue
+		}
+
+		switch group.checkRunStates[check] {
+		case checkRunPending:
+			check.SetResultSkipped(abortReason)
+		case checkRunRunning:
+			check.SetResultAborted(abortReason)
+		default:
+			continue
+		}
+
+		group.checkRunStates[check] = checkRunDone
+		printCheckResult(check)
+	}
+}
+
+func isStopped(stopChan <-chan bool) bool {
+	select {
+	case <-stopChan:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,244 @@
+package checksdb
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+// Helper to keep track of the number of checks running at the same time.
+type runningChecksCounter struct {
+	mutex      sync.Mutex
+	running    int
+	maxRunning int
+}
+
+func (c *runningChecksCounter) start() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.running++
+	if c.running > c.maxRunning {
+		c.maxRunning = c.running
+	}
+	return c.running
+}
+
+func (c *runningChecksCounter) stop() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.running--
+}
+
+func TestRunChecksConcurrently(t *testing.T) {
+	cli.SetConcurrentChecks(true)
+	defer cli.SetConcurrentChecks(false)
+
+	assert.Nil(t, InitLabelsExprEvaluator("label1"))
+
+	const numGroups = 3
+	counter := runningChecksCounter{}
+	var started atomic.Int32
+
+	groups := []*ChecksGroup{}
+	for _, groupName := range []string{"group1", "group2", "group3"} {
+		group := &ChecksGroup{name: groupName, currentRunningCheckIdx: checkIdxNone}
+		for _, id := range []string{groupName + "-check1", groupName + "-check2"} {
+			group.checks = append(group.checks, NewCheck(id, []string{"label1"}).
+				WithCheckFn(func(c *Check) error {
+					counter.start()
+					defer counter.stop()
+					// Wait for the first check of every group to be running at the same time.
+					started.Add(1)
+					deadline := time.Now().Add(2 * time.Second)
+					for started.Load() < numGroups && time.Now().Before(deadline) {
+						time.Sleep(time.Millisecond)
+					}
+					c.SetResult(nil, nil)
+					return nil
+				}))
+		}
+		group.initCheckRunStates()
+		groups = append(groups, group)
+	}
+
+	// Intrusive checks must run alone.
+	intrusiveRunningAlone := false
+	groups[0].checks = append(groups[0].checks, NewCheck("intrusive", []string{"label1"}).
+		WithIntrusive().
+		WithCheckFn(func(c *Check) error {
+			intrusiveRunningAlone = counter.start() == 1
+			defer counter.stop()
+			return nil
+		}))
+	groups[0].initCheckRunStates()
+
+	failedChecks, errs, abortReason := runGroupsBatch(groups, newChecksScheduler(numGroups), make(chan bool),
+		make(chan string, numGroups), make(chan time.Time), make(chan os.Signal))
+
+	assert.Empty(t, errs)
+	assert.Empty(t, abortReason)
+	assert.Equal(t, 0, failedChecks)
+	assert.Equal(t, numGroups, counter.maxRunning)
+	assert.True(t, intrusiveRunningAlone)
+	for _, group := range groups {
+		for _, check := range group.checks {
+			assert.Equal(t, checkRunDone, group.checkRunStates[check])
+		}
+	}
+}
+
+func TestRunGroupsBatchAborted(t *testing.T) {
+	cli.SetConcurrentChecks(true)
+	defer cli.SetConcurrentChecks(false)
+
+	assert.Nil(t, InitLabelsExprEvaluator("label1"))
+
+	checkStarted := make(chan bool)
+	afterAllDone := false
+	group := &ChecksGroup{name: "myGroup", currentRunningCheckIdx: checkIdxNone}
+	group.WithAfterAllFn(func(checks []*Check) error {
+		afterAllDone = true
+		return nil
+	})
+	group.checks = []*Check{
+		NewCheck("check1", []string{"label1"}).
+			WithCheckFn(func(c *Check) error {
+				close(checkStarted)
+				time.Sleep(100 * time.Millisecond)
+				return nil
+			}),
+		NewCheck("check2", []string{"label1"}).WithRunsAfter("check1"),
+	}
+	group.initCheckRunStates()
+
+	sigIntChan := make(chan os.Signal, 1)
+	go func() {
+		<-checkStarted
+		sigIntChan <- os.Interrupt
+	}()
+
+	_, _, abortReason := runGroupsBatch([]*ChecksGroup{group}, newChecksScheduler(2), make(chan bool),
+		make(chan string, 2), make(chan time.Time), sigIntChan)
+
+	assert.Equal(t, abortReasonSigInt, abortReason)
+	// The group had time to finish its running check and its afterAll function.
+	assert.True(t, afterAllDone)
+	assert.Equal(t, checkRunDone, group.checkRunStates[group.checks[0]])
+	assert.Equal(t, checkRunPending, group.checkRunStates[group.checks[1]])
+}
+
+func TestRunChecksConcurrentlyWithError(t *testing.T) {
+	cli.SetConcurrentChecks(true)
+	defer cli.SetConcurrentChecks(false)
+
+	assert.Nil(t, InitLabelsExprEvaluator("label1"))
+
+	group := &ChecksGroup{name: "myGroup", currentRunningCheckIdx: checkIdxNone}
+	group.checks = []*Check{
+		NewCheck("check1", []string{"label1"}).
+			WithCheckFn(func(c *Check) error {
+				panic("check1 panic")
+			}),
+		NewCheck("check2", []string{"label1"}).
+			WithIntrusive().
+			WithCheckFn(func(c *Check) error {
+				return nil
+			}),
+	}
+
+	group.initCheckRunStates()
+	errs, _ := group.runChecksConcurrently(newChecksScheduler(2), make(chan bool), make(chan string, 2))
+
+	assert.Len(t, errs, 1)
+	assert.Equal(t, CheckResult(CheckResultError), group.checks[0].Result)
+	// Intrusive checks wait for the previous checks, so check2 is skipped after the panic of check1.
+	assert.Equal(t, CheckResult(CheckResultSkipped), group.checks[1].Result)
+}
+
+func TestRunChecksConcurrentlySameGroup(t *testing.T) {
+	cli.SetConcurrentChecks(true)
+	defer cli.SetConcurrentChecks(false)
+
+	assert.Nil(t, InitLabelsExprEvaluator("label1"))
+
+	counter := runningChecksCounter{}
+	var started atomic.Int32
+	var beforeEachRuns atomic.Int32
+	var hooksNotRunFirst atomic.Bool
+
+	waitForBothChecks := func(c *Check) error {
+		counter.start()
+		defer counter.stop()
+		// The beforeEach functions of the batch run before any of its checks.
+		if beforeEachRuns.Load() != 3 {
+			hooksNotRunFirst.Store(true)
+		}
+		started.Add(1)
+		deadline := time.Now().Add(2 * time.Second)
+		for started.Load() < 2 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		return nil
+	}
+
+	check1Done := false
+	check3RanAfterCheck1 := false
+	group := &ChecksGroup{name: "myGroup", currentRunningCheckIdx: checkIdxNone}
+	group.WithBeforeEachFn(func(c *Check) error {
+		if !c.Intrusive {
+			beforeEachRuns.Add(1)
+		}
+		return nil
+	})
+	group.checks = []*Check{
+		NewCheck("check1", []string{"label1"}).WithCheckFn(func(c *Check) error {
+			defer func() { check1Done = true }()
+			return waitForBothChecks(c)
+		}),
+		NewCheck("check2", []string{"label1"}).WithCheckFn(waitForBothChecks),
+		NewCheck("check3", []string{"label1"}).WithRunsAfter("check1").WithCheckFn(func(c *Check) error {
+			check3RanAfterCheck1 = check1Done
+			return nil
+		}),
+		NewCheck("intrusive", []string{"label1"}).WithIntrusive().WithCheckFn(func(c *Check) error {
+			return nil
+		}),
+	}
+	group.initCheckRunStates()
+	errs, failedChecks := group.runChecksConcurrently(newChecksScheduler(2), make(chan bool), make(chan string, 2))
+
+	assert.Empty(t, errs)
+	assert.Equal(t, 0, failedChecks)
+	// The non-intrusive checks of the same group run at the same time.
+	assert.Equal(t, 2, counter.maxRunning)
+	assert.False(t, hooksNotRunFirst.Load())
+	assert.True(t, check3RanAfterCheck1)
+	for _, check := range group.checks {
+		assert.Equal(t, checkRunDone, group.checkRunStates[check])
+		assert.Equal(t, CheckResult(CheckResultPassed), check.Result)
+	}
+}
+
+func TestOnAbortConcurrent(t *testing.T) {
+	assert.Nil(t, InitLabelsExprEvaluator("label1"))
+
+	pendingCheck := NewCheck("check1", []string{"label1"})
+	runningCheck := NewCheck("check2", []string{"label1"})
+	doneCheck := NewCheck("check3", []string{"label1"})
+
+	group := &ChecksGroup{name: "myGroup", checks: []*Check{pendingCheck, runningCheck, doneCheck}}
+	group.initCheckRunStates()
+	group.moveCheckRunState(runningCheck, checkRunPending, checkRunRunning)
+	group.moveCheckRunState(doneCheck, checkRunPending, checkRunDone)
+
+	assert.Nil(t, group.OnAbort("my abort reason"))
+
+	assert.Equal(t, CheckResult(CheckResultSkipped), pendingCheck.Result)
+	assert.Equal(t, CheckResult(CheckResultAborted), runningCheck.Result)
+	assert.Equal(t, CheckResult(CheckResultPassed), doneCheck.Result)
+}
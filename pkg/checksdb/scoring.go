@@ -0,0 +1,72 @@
+package checksdb
+
+import (
+	"sync"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/cli"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/scoring"
+)
+
+var (
+	// Policy used to compute the readiness score once the checks have run. No score is computed if not set.
+	scoringPolicy *scoring.Policy
+	// Readiness score of the last run.
+	score       *scoring.Score
+	scoringLock sync.RWMutex
+)
+
+// SetScoringPolicy sets the policy used to compute the readiness score of the checks results.
+func SetScoringPolicy(policy *scoring.Policy) {
+	scoringLock.Lock()
+	defer scoringLock.Unlock()
+
+	scoringPolicy = policy
+	score = nil
+}
+
+// GetScore returns the readiness score of the checks results, or nil if no scoring policy was set.
+func GetScore() *scoring.Score {
+	scoringLock.RLock()
+	defer scoringLock.RUnlock()
+
+	return score
+}
+
+// Computes the readiness score of the current checks results.
+func computeScore() *scoring.Score {
+	scoringLock.Lock()
+	defer scoringLock.Unlock()
+
+	if scoringPolicy == nil {
+		return nil
+	}
+
+	score = scoring.Compute(GetReconciledResults(), scoringPolicy)
+	return score
+}
+
+// Returns the values of the score printed with the results table, or nil if there's no score.
+func getCliReadinessScore(score *scoring.Score) *cli.ReadinessScore {
+	if score == nil {
+		return nil
+	}
+
+	readinessScore := cli.ReadinessScore{
+		Profile:      score.Profile,
+		SuiteScores:  map[string]float64{},
+		GatePassed:   score.GatePassed,
+		GateFailures: score.GateFailures,
+	}
+
+	// The scores without weight are zero but not available, as all the checks were skipped.
+	if score.Weight > 0 {
+		readinessScore.Score = &score.Score
+	}
+	for i := range score.Suites {
+		if score.Suites[i].Weight > 0 {
+			readinessScore.SuiteScores[score.Suites[i].Suite] = score.Suites[i].Score
+		}
+	}
+
+	return &readinessScore
+}
@@ -0,0 +1,39 @@
+package checksdb
+
+import (
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/cli"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/scoring"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCliReadinessScore(t *testing.T) {
+	assert.Nil(t, getCliReadinessScore(nil))
+
+	overallScore := 50.0
+	assert.Equal(t, &cli.ReadinessScore{
+		Profile:      "Telco",
+		Score:        &overallScore,
+		SuiteScores:  map[string]float64{"networking": 50},
+		GatePassed:   false,
+		GateFailures: []string{"score 50.0% of suite networking is below its minimum score 60.0%"},
+	}, getCliReadinessScore(&scoring.Score{
+		Profile: "Telco",
+		Score:   50,
+		Weight:  4,
+		Suites: []scoring.SuiteScore{
+			{Suite: "networking", Score: 50, Weight: 4},
+			// All its checks were skipped.
+			{Suite: "platform-alteration", Skipped: 2},
+		},
+		GatePassed:   false,
+		GateFailures: []string{"score 50.0% of suite networking is below its minimum score 60.0%"},
+	}))
+
+	// No score if all the checks were skipped.
+	readinessScore := getCliReadinessScore(&scoring.Score{Profile: "Telco", GatePassed: true})
+	assert.Nil(t, readinessScore.Score)
+	assert.Empty(t, readinessScore.SuiteScores)
+	assert.True(t, readinessScore.GatePassed)
+}
@@ -0,0 +1,79 @@
+package checksdb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/waivers"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+)
+
+var (
+	// Waivers accepting known non-compliant objects, usually set from the waivers file.
+	waiversList        []waivers.Waiver
+	expiredWaiversList []waivers.Waiver
+	waiversLock        sync.RWMutex
+)
+
+// SetWaivers sets the waivers that will be applied to the non-compliant objects of the checks.
+// Returns the waivers that are already expired, which won't waive any object.
+func SetWaivers(newWaivers []waivers.Waiver) (expiredWaivers []waivers.Waiver) {
+	waiversLock.Lock()
+	defer waiversLock.Unlock()
+
+	now := time.Now()
+	for i := range newWaivers {
+		if _, exists := identifiers.TestIDToClaimID[newWaivers[i].TestID]; !exists {
+			log.Warn("Waiver set for unknown check %q.", newWaivers[i].TestID)
+		}
+
+		if newWaivers[i].IsExpired(now) {
+			log.Error("Waiver %s has expired.", newWaivers[i].String())
+			expiredWaivers = append(expiredWaivers, newWaivers[i])
+		}
+	}
+
+	waiversList = newWaivers
+	expiredWaiversList = expiredWaivers
+	return expiredWaivers
+}
+
+// GetWaivers returns the waivers that are applied to the checks.
+func GetWaivers() []waivers.Waiver {
+	waiversLock.RLock()
+	defer waiversLock.RUnlock()
+
+	return waiversList
+}
+
+// GetExpiredWaivers returns the waivers that were already expired when they were set.
+func GetExpiredWaivers() []waivers.Waiver {
+	waiversLock.RLock()
+	defer waiversLock.RUnlock()
+
+	return expiredWaiversList
+}
+
+// Moves the non-compliant objects matching a waiver to the waived objects list. Non-compliant
+// objects matching expired waivers are not waived, and an error is logged for each of them.
+func (check *Check) applyWaivers(nonCompliantObjects []*testhelper.ReportObject) (remainingObjects, waivedObjects []*testhelper.ReportObject) {
+	waiversLock.RLock()
+	defer waiversLock.RUnlock()
+
+	if len(waiversList) == 0 {
+		return nonCompliantObjects, nil
+	}
+
+	remainingObjects, waivedObjects, expiredWaivers := waivers.Apply(check.ID, waiversList, nonCompliantObjects, time.Now())
+	for _, expiredWaiver := range expiredWaivers {
+		check.LogError("Non-compliant object matches expired waiver %s", expiredWaiver.String())
+	}
+
+	if len(waivedObjects) > 0 {
+		check.LogInfo("%d non-compliant objects were waived.", len(waivedObjects))
+	}
+
+	return remainingObjects, waivedObjects
+}
@@ -0,0 +1,45 @@
+package checksdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/waivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetResultWithWaivers(t *testing.T) {
+	defer SetWaivers(nil)
+
+	expiredWaivers := SetWaivers([]waivers.Waiver{
+		{TestID: "myID", Name: "pod1", Reason: "reason", ApprovedBy: "user", Expires: time.Now().Add(time.Hour)},
+		{TestID: "myID", Name: "pod2", Reason: "reason", ApprovedBy: "user", Expires: time.Now().Add(-time.Hour)},
+	})
+	assert.Len(t, expiredWaivers, 1)
+	assert.Equal(t, expiredWaivers, GetExpiredWaivers())
+
+	// All the non-compliant objects are waived.
+	check := NewCheck("myID", nil)
+	check.SetResult(nil, []*testhelper.ReportObject{testhelper.NewPodReportObject("tnf", "pod1", "reason", false)})
+	assert.Equal(t, CheckResult(CheckResultPassed), check.Result)
+	assert.Equal(t, 1, check.waivedObjects)
+	assert.Contains(t, check.details, "WaivedObjectsOut")
+
+	// Expired waivers don't waive anything.
+	check = NewCheck("myID", nil)
+	check.SetResult(nil, []*testhelper.ReportObject{
+		testhelper.NewPodReportObject("tnf", "pod1", "reason", false),
+		testhelper.NewPodReportObject("tnf", "pod2", "reason", false),
+	})
+	assert.Equal(t, CheckResult(CheckResultFailed), check.Result)
+	assert.Equal(t, 1, check.waivedObjects)
+	assert.Contains(t, check.details, waivers.ExpiredWaiverField)
+
+	// Waivers of other checks don't apply.
+	check = NewCheck("otherID", nil)
+	check.SetResult(nil, []*testhelper.ReportObject{testhelper.NewPodReportObject("tnf", "pod1", "reason", false)})
+	assert.Equal(t, CheckResult(CheckResultFailed), check.Result)
+	assert.Equal(t, 0, check.waivedObjects)
+	assert.NotContains(t, check.details, "WaivedObjectsOut")
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/diagnostics"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/labels"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/versions"
 )
 
@@ -44,10 +45,27 @@ const (
 	CNFFeatureValidationReportKey        = "cnf-feature-validation"
 	// dateTimeFormatDirective is the directive used to format date/time according to ISO 8601.
 	DateTimeFormatDirective = "2006-01-02 15:04:05 -0700 MST"
+	// Claim configurations field with the IDs of the checks in the order they ran.
+	ChecksOrderField = "checksOrder"
+	// Claim configurations field with the waivers used in the run.
+	WaiversField = "waivers"
+	// Claim configurations field with the waivers that were already expired when the run started.
+	ExpiredWaiversField = "expiredWaivers"
+	// Claim configurations field with the number of waived objects of each check, by test ID.
+	WaivedObjectsField = "waivedObjects"
+	// Claim configurations field with the source claims of a merged claim.
+	MergedFromField = "mergedFrom"
+	// Claim configurations field with the readiness score of the results.
+	ScoreField = "score"
 
 	// States for test cases
+	TestStatePassed  = "passed"
 	TestStateFailed  = "failed"
 	TestStateSkipped = "skipped"
+	TestStateError   = "error"
+	TestStateAborted = "aborted"
+	// JUnit status for passed test cases whose non-compliant objects were all waived.
+	TestStatePassedWithWaivers = "passed-with-waivers"
 )
 
 type SkippedMessage struct {
@@ -61,37 +79,50 @@ type FailureMessage struct {
 	Type    string `xml:"type,attr,omitempty"`
 }
 
+type ErrorMessage struct {
+	Text    string `xml:",chardata"`
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+type Property struct {
+	Text  string `xml:",chardata"`
+	Name  string `xml:"name,attr,omitempty"`
+	Value string `xml:"value,attr,omitempty"`
+}
+
+type Properties struct {
+	Text     string     `xml:",chardata"`
+	Property []Property `xml:"property"`
+}
+
 type TestCase struct {
-	Text      string          `xml:",chardata"`
-	Name      string          `xml:"name,attr,omitempty"`
-	Classname string          `xml:"classname,attr,omitempty"`
-	Status    string          `xml:"status,attr,omitempty"`
-	Time      string          `xml:"time,attr,omitempty"`
-	SystemErr string          `xml:"system-err,omitempty"`
-	Skipped   *SkippedMessage `xml:"skipped"`
-	Failure   *FailureMessage `xml:"failure"`
+	Text       string          `xml:",chardata"`
+	Name       string          `xml:"name,attr,omitempty"`
+	Classname  string          `xml:"classname,attr,omitempty"`
+	Status     string          `xml:"status,attr,omitempty"`
+	Time       string          `xml:"time,attr,omitempty"`
+	Properties *Properties     `xml:"properties"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+	SystemErr  string          `xml:"system-err,omitempty"`
+	Skipped    *SkippedMessage `xml:"skipped"`
+	Error      *ErrorMessage   `xml:"error"`
+	Failure    *FailureMessage `xml:"failure"`
 }
 
 type Testsuite struct {
-	Text       string `xml:",chardata"`
-	Name       string `xml:"name,attr,omitempty"`
-	Package    string `xml:"package,attr,omitempty"`
-	Tests      string `xml:"tests,attr,omitempty"`
-	Disabled   string `xml:"disabled,attr,omitempty"`
-	Skipped    string `xml:"skipped,attr,omitempty"`
-	Errors     string `xml:"errors,attr,omitempty"`
-	Failures   string `xml:"failures,attr,omitempty"`
-	Time       string `xml:"time,attr,omitempty"`
-	Timestamp  string `xml:"timestamp,attr,omitempty"`
-	Properties struct {
-		Text     string `xml:",chardata"`
-		Property []struct {
-			Text  string `xml:",chardata"`
-			Name  string `xml:"name,attr,omitempty"`
-			Value string `xml:"value,attr,omitempty"`
-		} `xml:"property"`
-	} `xml:"properties"`
-	Testcase []TestCase `xml:"testcase"`
+	Text       string     `xml:",chardata"`
+	Name       string     `xml:"name,attr,omitempty"`
+	Package    string     `xml:"package,attr,omitempty"`
+	Tests      string     `xml:"tests,attr,omitempty"`
+	Disabled   string     `xml:"disabled,attr,omitempty"`
+	Skipped    string     `xml:"skipped,attr,omitempty"`
+	Errors     string     `xml:"errors,attr,omitempty"`
+	Failures   string     `xml:"failures,attr,omitempty"`
+	Time       string     `xml:"time,attr,omitempty"`
+	Timestamp  string     `xml:"timestamp,attr,omitempty"`
+	Properties Properties `xml:"properties"`
+	Testcase   []TestCase `xml:"testcase"`
 }
 
 type TestSuitesXML struct {
@@ -148,6 +179,10 @@ func (c *ClaimBuilder) Build(outputFile string) {
 
 	c.claimRoot.Claim.Metadata.EndTime = endTime.UTC().Format(DateTimeFormatDirective)
 	c.claimRoot.Claim.Results = checksdb.GetReconciledResults()
+	c.setRunConfigurations()
+	if score := checksdb.GetScore(); score != nil {
+		c.claimRoot.Claim.Configurations[ScoreField] = score
+	}
 
 	// Marshal the claim and output to file
 	payload := MarshalClaimOutput(c.claimRoot)
@@ -156,8 +191,198 @@ func (c *ClaimBuilder) Build(outputFile string) {
 	log.Info("Claim file created at %s", outputFile)
 }
 
-//nolint:funlen
-func populateXMLFromClaim(c claim.Claim, startTime, endTime time.Time) TestSuitesXML {
+// Checkpoint writes a partial claim file with the results recorded so far. The file is written to a
+// temporary file first so a valid claim file is always available, even if the run is interrupted.
+func (c *ClaimBuilder) Checkpoint(outputFile string, results map[string]claim.Result) {
+	c.claimRoot.Claim.Results = results
+	c.setRunConfigurations()
+
+	payload := MarshalClaimOutput(c.claimRoot)
+	tmpFile := outputFile + ".tmp"
+	if err := os.WriteFile(tmpFile, payload, claimFilePermissions); err != nil {
+		log.Error("Failed to write claim checkpoint file %s: %v", tmpFile, err)
+		return
+	}
+
+	if err := os.Rename(tmpFile, outputFile); err != nil {
+		log.Error("Failed to rename claim checkpoint file %s to %s: %v", tmpFile, outputFile, err)
+	}
+}
+
+// Sets the configurations fields that depend on the checks run: their order, the waivers and the
+// number of waived objects of each check.
+func (c *ClaimBuilder) setRunConfigurations() {
+	if c.claimRoot.Claim.Configurations == nil {
+		c.claimRoot.Claim.Configurations = map[string]interface{}{}
+	}
+
+	// Keep the order in which the checks ran, as the results are stored in a map.
+	c.claimRoot.Claim.Configurations[ChecksOrderField] = checksdb.GetChecksOrder()
+	if waiversList := checksdb.GetWaivers(); len(waiversList) > 0 {
+		c.claimRoot.Claim.Configurations[WaiversField] = waiversList
+	}
+	if expiredWaivers := checksdb.GetExpiredWaivers(); len(expiredWaivers) > 0 {
+		c.claimRoot.Claim.Configurations[ExpiredWaiversField] = expiredWaivers
+	}
+	if waivedObjects := GetWaivedObjectsCounts(c.claimRoot.Claim.Results); len(waivedObjects) > 0 {
+		c.claimRoot.Claim.Configurations[WaivedObjectsField] = waivedObjects
+	}
+}
+
+// GetWaivedObjectsCounts returns the number of waived objects of the checks that have any, by test ID.
+func GetWaivedObjectsCounts(results map[string]claim.Result) map[string]int {
+	counts := map[string]int{}
+	for testID := range results {
+		if waived := len(getResultObjects(results[testID].CheckDetails).WaivedObjectsOut); waived > 0 {
+			counts[testID] = waived
+		}
+	}
+
+	return counts
+}
+
+// Returns the properties with the versions used in the run.
+func getTestSuiteProperties(c *claim.Claim) Properties {
+	properties := Properties{}
+	if c.Versions == nil {
+		return properties
+	}
+
+	for _, property := range []Property{
+		{Name: "certsuite-version", Value: c.Versions.Tnf},
+		{Name: "certsuite-git-commit", Value: c.Versions.TnfGitCommit},
+		{Name: "claim-format-version", Value: c.Versions.ClaimFormat},
+		{Name: "ocp-version", Value: c.Versions.Ocp},
+		{Name: "k8s-version", Value: c.Versions.K8s},
+	} {
+		if property.Value != "" {
+			properties.Property = append(properties.Property, property)
+		}
+	}
+
+	return properties
+}
+
+func getTestCaseProperties(result *claim.Result) *Properties {
+	if result.TestID == nil {
+		return nil
+	}
+
+	return &Properties{Property: []Property{
+		{Name: "suite", Value: result.TestID.Suite},
+		{Name: "labels", Value: result.TestID.Tags},
+	}}
+}
+
+func newTestCase(testID, testSuiteName string, result *claim.Result) TestCase {
+	testCase := TestCase{}
+	testCase.Name = testID
+	testCase.Classname = testSuiteName
+	testCase.Status = result.State
+	testCase.Properties = getTestCaseProperties(result)
+
+	// Calculate the duration of the test case
+	difference, err := GetResultDuration(result)
+	if err != nil {
+		log.Error("%v", err)
+	}
+	testCase.Time = strconv.FormatFloat(difference.Seconds(), 'f', 10, 64)
+
+	switch testCase.Status {
+	case TestStateSkipped:
+		// Populate the skipped message if the test case was skipped
+		testCase.Skipped = &SkippedMessage{}
+		testCase.Skipped.Text = result.SkipReason
+	case TestStateError, TestStateAborted:
+		// Populate the error message if the test case could not finish.
+		testCase.Error = &ErrorMessage{
+			Message: result.SkipReason,
+			Type:    testCase.Status,
+			Text:    result.SkipReason,
+		}
+	}
+
+	// List the waived objects, if any.
+	resultObjects := getResultObjects(result.CheckDetails)
+	if len(resultObjects.WaivedObjectsOut) > 0 {
+		if testCase.Status == TestStatePassed {
+			testCase.Status = TestStatePassedWithWaivers
+		}
+		testCase.SystemOut = waivedObjectsToString(resultObjects.WaivedObjectsOut)
+	}
+
+	// Populate the failure message if the test case failed
+	if testCase.Status == TestStateFailed {
+		testCase.Failure = &FailureMessage{}
+		if len(resultObjects.NonCompliantObjectsOut) > 0 {
+			testCase.Failure.Message = fmt.Sprintf("Non-compliant objects: %d", len(resultObjects.NonCompliantObjectsOut))
+			testCase.Failure.Text = reportObjectsToString("Non-compliant objects:\n", resultObjects.NonCompliantObjectsOut)
+		} else {
+			testCase.Failure.Message = result.SkipReason
+			testCase.Failure.Text = result.CheckDetails
+		}
+	}
+
+	return testCase
+}
+
+// Returns the name of the test case of a report object: the test case id followed by the
+// namespace and the name of the object, e.g. access-control-pod-host-network/ns1/pod1.
+func getReportObjectTestCaseName(testID string, obj *testhelper.ReportObject) string {
+	if namespace := obj.GetNamespace(); namespace != "" {
+		return testID + "/" + namespace + "/" + obj.GetName()
+	}
+
+	return testID + "/" + obj.GetName()
+}
+
+// Returns a test case for each object reported by the test case, so the results of every object
+// can be tracked separately. Test cases without objects are returned as they are.
+func expandTestCase(testCase *TestCase, result *claim.Result) []TestCase {
+	resultObjects := getResultObjects(result.CheckDetails)
+	if len(resultObjects.CompliantObjectsOut)+len(resultObjects.NonCompliantObjectsOut)+len(resultObjects.WaivedObjectsOut) == 0 {
+		return []TestCase{*testCase}
+	}
+
+	newObjectTestCase := func(obj *testhelper.ReportObject, status string) TestCase {
+		return TestCase{
+			Name:       getReportObjectTestCaseName(testCase.Name, obj),
+			Classname:  testCase.Classname,
+			Status:     status,
+			Properties: testCase.Properties,
+		}
+	}
+
+	testCases := []TestCase{}
+	for _, obj := range resultObjects.CompliantObjectsOut {
+		objTestCase := newObjectTestCase(obj, TestStatePassed)
+		objTestCase.SystemOut = reportObjectsToString("", []*testhelper.ReportObject{obj})
+		testCases = append(testCases, objTestCase)
+	}
+
+	for _, obj := range resultObjects.WaivedObjectsOut {
+		objTestCase := newObjectTestCase(obj, TestStatePassedWithWaivers)
+		objTestCase.SystemOut = waivedObjectsToString([]*testhelper.ReportObject{obj})
+		testCases = append(testCases, objTestCase)
+	}
+
+	for _, obj := range resultObjects.NonCompliantObjectsOut {
+		objTestCase := newObjectTestCase(obj, TestStateFailed)
+		reason, _ := getReportObjectReasonAndFields(obj)
+		objTestCase.Failure = &FailureMessage{
+			Message: reason,
+			Text:    reportObjectsToString("", []*testhelper.ReportObject{obj}),
+		}
+		testCases = append(testCases, objTestCase)
+	}
+
+	sort.SliceStable(testCases, func(i, j int) bool { return testCases[i].Name < testCases[j].Name })
+	return testCases
+}
+
+// Creates the JUnit XML of the claim's results. In expanded mode, there is a test case for each
+// object reported by the checks instead of a test case for each check.
+func populateXMLFromClaim(c claim.Claim, startTime, endTime time.Time, expanded bool) TestSuitesXML {
 	const (
 		TestSuiteName = "CNF Certification Test Suite"
 	)
@@ -171,92 +396,125 @@ func populateXMLFromClaim(c claim.Claim, startTime, endTime time.Time) TestSuite
 	// Sort the test IDs
 	sort.Strings(allTestIDs)
 
-	xmlOutput := TestSuitesXML{}
-	// <testsuites>
-	xmlOutput.Tests = strconv.Itoa(len(c.Results))
-
-	// Count all of the failed tests in the suite
-	failedTests := 0
-	for testID := range c.Results {
-		if c.Results[testID].State == TestStateFailed {
-			failedTests++
+	// <testcase>
+	// Loop through all of the sorted test IDs
+	testCases := []TestCase{}
+	for _, testID := range allTestIDs {
+		result := c.Results[testID]
+		testCase := newTestCase(testID, TestSuiteName, &result)
+		if expanded {
+			testCases = append(testCases, expandTestCase(&testCase, &result)...)
+		} else {
+			testCases = append(testCases, testCase)
 		}
 	}
 
-	// Count all of the skipped tests in the suite
-	skippedTests := 0
-	for testID := range c.Results {
-		if c.Results[testID].State == TestStateSkipped {
+	// Count the failed, skipped and errored test cases in the suite
+	failedTests, skippedTests, erroredTests := 0, 0, 0
+	for i := range testCases {
+		switch {
+		case testCases[i].Failure != nil:
+			failedTests++
+		case testCases[i].Skipped != nil:
 			skippedTests++
+		case testCases[i].Error != nil:
+			erroredTests++
 		}
 	}
 
+	xmlOutput := TestSuitesXML{}
+	// <testsuites>
+	xmlOutput.Tests = strconv.Itoa(len(testCases))
 	xmlOutput.Failures = strconv.Itoa(failedTests)
 	xmlOutput.Disabled = strconv.Itoa(skippedTests)
-	xmlOutput.Errors = strconv.Itoa(0)
+	xmlOutput.Errors = strconv.Itoa(erroredTests)
 	xmlOutput.Time = strconv.FormatFloat(endTime.Sub(startTime).Seconds(), 'f', 5, 64)
 
 	// <testsuite>
 	xmlOutput.Testsuite.Name = TestSuiteName
-	xmlOutput.Testsuite.Tests = strconv.Itoa(len(c.Results))
-	// Counters for failed and skipped tests
+	xmlOutput.Testsuite.Tests = strconv.Itoa(len(testCases))
+	// Counters for failed, skipped and errored tests
 	xmlOutput.Testsuite.Failures = strconv.Itoa(failedTests)
 	xmlOutput.Testsuite.Skipped = strconv.Itoa(skippedTests)
-	xmlOutput.Testsuite.Errors = strconv.Itoa(0)
+	xmlOutput.Testsuite.Errors = strconv.Itoa(erroredTests)
 
 	xmlOutput.Testsuite.Time = strconv.FormatFloat(endTime.Sub(startTime).Seconds(), 'f', 5, 64)
 	xmlOutput.Testsuite.Timestamp = time.Now().UTC().Format(DateTimeFormatDirective)
 
 	// <properties>
+	xmlOutput.Testsuite.Properties = getTestSuiteProperties(&c)
 
-	// <testcase>
-	// Loop through all of the sorted test IDs
-	for _, testID := range allTestIDs {
-		testCase := TestCase{}
-		testCase.Name = testID
-		testCase.Classname = TestSuiteName
-		testCase.Status = c.Results[testID].State
+	xmlOutput.Testsuite.Testcase = testCases
 
-		// Clean the time strings to remove the " m=" suffix
-		start, err := time.Parse(DateTimeFormatDirective, strings.Split(c.Results[testID].StartTime, " m=")[0])
-		if err != nil {
-			log.Error("Failed to parse start time: %v", err)
-		}
-		end, err := time.Parse(DateTimeFormatDirective, strings.Split(c.Results[testID].EndTime, " m=")[0])
-		if err != nil {
-			log.Error("Failed to parse end time: %v", err)
-		}
+	return xmlOutput
+}
 
-		// Calculate the duration of the test case
-		difference := end.Sub(start)
-		testCase.Time = strconv.FormatFloat(difference.Seconds(), 'f', 10, 64)
+// GetResultDuration returns the duration of a test case from its start and end times, which is more
+// accurate than its duration field, as that one is truncated to seconds.
+func GetResultDuration(result *claim.Result) (time.Duration, error) {
+	// Clean the time strings to remove the " m=" suffix
+	start, err := time.Parse(DateTimeFormatDirective, strings.Split(result.StartTime, " m=")[0])
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse start time: %v", err)
+	}
+	end, err := time.Parse(DateTimeFormatDirective, strings.Split(result.EndTime, " m=")[0])
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse end time: %v", err)
+	}
 
-		// Populate the skipped message if the test case was skipped
-		if testCase.Status == TestStateSkipped {
-			testCase.Skipped = &SkippedMessage{}
-			testCase.Skipped.Text = c.Results[testID].SkipReason
-		} else {
-			testCase.Skipped = nil
-		}
+	return end.Sub(start), nil
+}
 
-		// Populate the failure message if the test case failed
-		if testCase.Status == TestStateFailed {
-			testCase.Failure = &FailureMessage{}
-			testCase.Failure.Text = c.Results[testID].CheckDetails
-		} else {
-			testCase.Failure = nil
-		}
+// GetResultObjects returns the report objects from a test case's check details, which are empty for
+// the test cases that don't have details. The test cases that don't use report objects, like the ones
+// of the preflight suite, have details that can't be decoded.
+func GetResultObjects(checkDetails string) (testhelper.FailureReasonOut, error) {
+	resultObjects := testhelper.FailureReasonOut{}
+	if checkDetails == "" {
+		return resultObjects, nil
+	}
 
-		// Append the test case to the test suite
-		xmlOutput.Testsuite.Testcase = append(xmlOutput.Testsuite.Testcase, testCase)
+	if err := j.Unmarshal([]byte(checkDetails), &resultObjects); err != nil {
+		return testhelper.FailureReasonOut{}, fmt.Errorf("failed to decode check details %s: %v", checkDetails, err)
 	}
 
-	return xmlOutput
+	return resultObjects, nil
+}
+
+// Returns the report objects from a test case's check details, or none if they can't be decoded.
+func getResultObjects(checkDetails string) testhelper.FailureReasonOut {
+	resultObjects, err := GetResultObjects(checkDetails)
+	if err != nil {
+		log.Debug("%v", err)
+	}
+
+	return resultObjects
 }
 
-func (c *ClaimBuilder) ToJUnitXML(outputFile string, startTime, endTime time.Time) {
+func reportObjectsToString(title string, objects []*testhelper.ReportObject) string {
+	str := title
+	for _, obj := range objects {
+		fields := []string{}
+		for i := range obj.ObjectFieldsKeys {
+			if i < len(obj.ObjectFieldsValues) {
+				fields = append(fields, obj.ObjectFieldsKeys[i]+": "+obj.ObjectFieldsValues[i])
+			}
+		}
+		str += "- " + obj.ObjectType + ": " + strings.Join(fields, ", ") + "\n"
+	}
+
+	return str
+}
+
+func waivedObjectsToString(waivedObjects []*testhelper.ReportObject) string {
+	return reportObjectsToString("Waived objects:\n", waivedObjects)
+}
+
+// ToJUnitXML writes the JUnit XML file of the claim's results. In expanded mode, there is a test case
+// for each object reported by the checks, e.g. access-control-pod-host-network/ns1/pod1.
+func (c *ClaimBuilder) ToJUnitXML(outputFile string, startTime, endTime time.Time, expanded bool) {
 	// Create the JUnit XML file from the claim output.
-	xmlOutput := populateXMLFromClaim(*c.claimRoot.Claim, startTime, endTime)
+	xmlOutput := populateXMLFromClaim(*c.claimRoot.Claim, startTime, endTime, expanded)
 
 	// Write the JUnit XML file.
 	payload, err := xml.MarshalIndent(xmlOutput, "", "  ")
@@ -314,6 +572,25 @@ func ReadClaimFile(claimFileName string) (data []byte, err error) {
 	return data, nil
 }
 
+// GetResultsFromClaimFile retrieves the checks results from a claim file.
+func GetResultsFromClaimFile(claimFileName string) (map[string]claim.Result, error) {
+	data, err := os.ReadFile(claimFileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read claim file %s: %v", claimFileName, err)
+	}
+
+	var claimRoot claim.Root
+	if err := j.Unmarshal(data, &claimRoot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal claim file %s: %v", claimFileName, err)
+	}
+
+	if claimRoot.Claim == nil {
+		return nil, fmt.Errorf("claim file %s has no claim field", claimFileName)
+	}
+
+	return claimRoot.Claim.Results, nil
+}
+
 // GetConfigurationFromClaimFile retrieves configuration details from claim file
 func GetConfigurationFromClaimFile(claimFileName string) (env *provider.TestEnvironment, err error) {
 	data, err := ReadClaimFile(claimFileName)
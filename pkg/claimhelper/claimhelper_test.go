@@ -17,12 +17,14 @@
 package claimhelper
 
 import (
+	j "encoding/json"
 	"os"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -105,7 +107,7 @@ func TestPopulateXMLFromClaim(t *testing.T) {
 		endTime, err := time.Parse(DateTimeFormatDirective, "2023-12-20 14:51:34 -0600 MST")
 		assert.Nil(t, err)
 
-		xmlResult := populateXMLFromClaim(generateClaim(map[string]claim.Result{"test-case1": tc.testResult}), startTime, endTime)
+		xmlResult := populateXMLFromClaim(generateClaim(map[string]claim.Result{"test-case1": tc.testResult}), startTime, endTime, false)
 
 		// Compare the values in the XML
 		assert.Equal(t, tc.expectedXMLResult.Failures, xmlResult.Failures)
@@ -189,7 +191,7 @@ func TestToJUnitXML(t *testing.T) {
 		testClaimBuilder.claimRoot.Claim.Results = make(map[string]claim.Result)
 		testClaimBuilder.claimRoot.Claim.Results = tc.testResults
 
-		testClaimBuilder.ToJUnitXML("testfile.xml", startTime, endTime)
+		testClaimBuilder.ToJUnitXML("testfile.xml", startTime, endTime, false)
 
 		// read the file and compare the contents
 		outputFile, err := os.ReadFile("testfile.xml")
@@ -308,3 +310,206 @@ func TestReadClaimFile(t *testing.T) {
 	// Check if the output is a valid JSON
 	assert.Contains(t, string(output), "test-case1")
 }
+
+func TestCheckpointAndGetResultsFromClaimFile(t *testing.T) {
+	claimBuilder := &ClaimBuilder{claimRoot: CreateClaimRoot()}
+	claimBuilder.claimRoot.Claim.Versions = &claim.Versions{Tnf: "1.0.0"}
+
+	outputFile := "testfile_checkpoint.json"
+	defer os.Remove(outputFile)
+
+	results := map[string]claim.Result{
+		"test-case1": {
+			TestID: &claim.Identifier{
+				Id:    "test-case1",
+				Suite: "test-suite1",
+			},
+			State: "passed",
+		},
+	}
+	claimBuilder.Checkpoint(outputFile, results)
+
+	// No temporary file should be left behind.
+	_, err := os.Stat(outputFile + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+
+	readResults, err := GetResultsFromClaimFile(outputFile)
+	assert.Nil(t, err)
+	assert.Len(t, readResults, 1)
+	assert.Equal(t, "passed", readResults["test-case1"].State)
+
+	_, err = GetResultsFromClaimFile("non-existent-file.json")
+	assert.NotNil(t, err)
+}
+
+func TestPopulateXMLFromClaimWithWaivers(t *testing.T) {
+	c := claim.Claim{
+		Results: map[string]claim.Result{
+			"test-case1": {
+				TestID:       &claim.Identifier{Id: "test-case1", Suite: "test-suite1"},
+				State:        "passed",
+				StartTime:    "2023-12-20 14:51:33 -0600 MST",
+				EndTime:      "2023-12-20 14:51:34 -0600 MST",
+				CheckDetails: `{"WaivedObjectsOut":[{"ObjectType":"Pod","ObjectFieldsKeys":["Namespace","Pod Name"],"ObjectFieldsValues":["tnf","pod1"]}],"CompliantObjectsOut":null,"NonCompliantObjectsOut":null}`,
+			},
+			"test-case2": {
+				TestID:       &claim.Identifier{Id: "test-case2", Suite: "test-suite1"},
+				State:        "passed",
+				StartTime:    "2023-12-20 14:51:33 -0600 MST",
+				EndTime:      "2023-12-20 14:51:34 -0600 MST",
+				CheckDetails: `{"CompliantObjectsOut":[],"NonCompliantObjectsOut":[]}`,
+			},
+		},
+	}
+
+	xmlOutput := populateXMLFromClaim(c, time.Now(), time.Now(), false)
+	assert.Len(t, xmlOutput.Testsuite.Testcase, 2)
+
+	assert.Equal(t, TestStatePassedWithWaivers, xmlOutput.Testsuite.Testcase[0].Status)
+	assert.Equal(t, "Waived objects:\n- Pod: Namespace: tnf, Pod Name: pod1\n", xmlOutput.Testsuite.Testcase[0].SystemOut)
+	assert.Nil(t, xmlOutput.Testsuite.Testcase[0].Failure)
+
+	assert.Equal(t, TestStatePassed, xmlOutput.Testsuite.Testcase[1].Status)
+	assert.Empty(t, xmlOutput.Testsuite.Testcase[1].SystemOut)
+
+	assert.Equal(t, map[string]int{"test-case1": 1}, GetWaivedObjectsCounts(c.Results))
+}
+
+func TestPopulateXMLFromClaimWithErrors(t *testing.T) {
+	c := claim.Claim{
+		Versions: &claim.Versions{Tnf: "v5.2.0", ClaimFormat: "v0.4.0"},
+		Results: map[string]claim.Result{
+			"test-case1": {
+				TestID:     &claim.Identifier{Id: "test-case1", Suite: "test-suite1", Tags: "common,extended"},
+				State:      TestStateError,
+				SkipReason: "failed to get pods",
+				StartTime:  "2023-12-20 14:51:33 -0600 MST",
+				EndTime:    "2023-12-20 14:51:34 -0600 MST",
+			},
+			"test-case2": {
+				TestID:     &claim.Identifier{Id: "test-case2", Suite: "test-suite1"},
+				State:      TestStateAborted,
+				SkipReason: "check timed out after 1m0s",
+				StartTime:  "2023-12-20 14:51:33 -0600 MST",
+				EndTime:    "2023-12-20 14:51:34 -0600 MST",
+			},
+			"test-case3": {
+				TestID:       &claim.Identifier{Id: "test-case3", Suite: "test-suite2"},
+				State:        TestStateFailed,
+				StartTime:    "2023-12-20 14:51:33 -0600 MST",
+				EndTime:      "2023-12-20 14:51:34 -0600 MST",
+				CheckDetails: `{"CompliantObjectsOut":null,"NonCompliantObjectsOut":[{"ObjectType":"Pod","ObjectFieldsKeys":["Reason For Non Compliance","Namespace","Pod Name"],"ObjectFieldsValues":["Pod has host network","tnf","pod1"]}]}`,
+			},
+		},
+	}
+
+	xmlOutput := populateXMLFromClaim(c, time.Now(), time.Now(), false)
+	assert.Equal(t, "3", xmlOutput.Tests)
+	assert.Equal(t, "2", xmlOutput.Errors)
+	assert.Equal(t, "1", xmlOutput.Failures)
+	assert.Equal(t, "2", xmlOutput.Testsuite.Errors)
+	assert.Contains(t, xmlOutput.Testsuite.Properties.Property, Property{Name: "certsuite-version", Value: "v5.2.0"})
+	assert.Contains(t, xmlOutput.Testsuite.Properties.Property, Property{Name: "claim-format-version", Value: "v0.4.0"})
+
+	assert.Len(t, xmlOutput.Testsuite.Testcase, 3)
+	assert.Equal(t, &ErrorMessage{Message: "failed to get pods", Type: TestStateError, Text: "failed to get pods"}, xmlOutput.Testsuite.Testcase[0].Error)
+	assert.Nil(t, xmlOutput.Testsuite.Testcase[0].Failure)
+	assert.Equal(t, []Property{{Name: "suite", Value: "test-suite1"}, {Name: "labels", Value: "common,extended"}}, xmlOutput.Testsuite.Testcase[0].Properties.Property)
+	assert.Equal(t, TestStateAborted, xmlOutput.Testsuite.Testcase[1].Error.Type)
+
+	assert.Nil(t, xmlOutput.Testsuite.Testcase[2].Error)
+	assert.Equal(t, &FailureMessage{
+		Message: "Non-compliant objects: 1",
+		Text:    "Non-compliant objects:\n- Pod: Reason For Non Compliance: Pod has host network, Namespace: tnf, Pod Name: pod1\n",
+	}, xmlOutput.Testsuite.Testcase[2].Failure)
+}
+
+func TestPopulateXMLFromClaimExpanded(t *testing.T) {
+	c := claim.Claim{
+		Results: map[string]claim.Result{
+			"test-case1": {
+				TestID:    &claim.Identifier{Id: "test-case1", Suite: "test-suite1"},
+				State:     TestStateFailed,
+				StartTime: "2023-12-20 14:51:33 -0600 MST",
+				EndTime:   "2023-12-20 14:51:34 -0600 MST",
+				CheckDetails: `{"WaivedObjectsOut":[{"ObjectType":"Pod","ObjectFieldsKeys":["Reason For Non Compliance","Namespace","Pod Name"],"ObjectFieldsValues":["Pod has host network","tnf","pod3"]}],` +
+					`"CompliantObjectsOut":[{"ObjectType":"Pod","ObjectFieldsKeys":["Reason For Compliance","Namespace","Pod Name"],"ObjectFieldsValues":["Pod has no host network","tnf","pod1"]}],` +
+					`"NonCompliantObjectsOut":[{"ObjectType":"Pod","ObjectFieldsKeys":["Reason For Non Compliance","Namespace","Pod Name"],"ObjectFieldsValues":["Pod has host network","tnf","pod2"]}]}`,
+			},
+			"test-case2": {
+				TestID:     &claim.Identifier{Id: "test-case2", Suite: "test-suite1"},
+				State:      TestStateSkipped,
+				SkipReason: "no pods found",
+				StartTime:  "2023-12-20 14:51:33 -0600 MST",
+				EndTime:    "2023-12-20 14:51:34 -0600 MST",
+			},
+			"test-case3": {
+				TestID:       &claim.Identifier{Id: "test-case3", Suite: "test-suite1"},
+				State:        TestStatePassed,
+				StartTime:    "2023-12-20 14:51:33 -0600 MST",
+				EndTime:      "2023-12-20 14:51:34 -0600 MST",
+				CheckDetails: `{"CompliantObjectsOut":[{"ObjectType":"Node","ObjectFieldsKeys":["Reason For Compliance","Name"],"ObjectFieldsValues":["Node is ready","node1"]}],"NonCompliantObjectsOut":null}`,
+			},
+		},
+	}
+
+	xmlOutput := populateXMLFromClaim(c, time.Now(), time.Now(), true)
+	assert.Equal(t, "5", xmlOutput.Tests)
+	assert.Equal(t, "1", xmlOutput.Failures)
+	assert.Equal(t, "1", xmlOutput.Disabled)
+
+	testCases := xmlOutput.Testsuite.Testcase
+	assert.Len(t, testCases, 5)
+
+	assert.Equal(t, "test-case1/tnf/pod1", testCases[0].Name)
+	assert.Equal(t, TestStatePassed, testCases[0].Status)
+	assert.Nil(t, testCases[0].Failure)
+
+	assert.Equal(t, "test-case1/tnf/pod2", testCases[1].Name)
+	assert.Equal(t, TestStateFailed, testCases[1].Status)
+	assert.Equal(t, "Pod has host network", testCases[1].Failure.Message)
+
+	assert.Equal(t, "test-case1/tnf/pod3", testCases[2].Name)
+	assert.Equal(t, TestStatePassedWithWaivers, testCases[2].Status)
+	assert.Nil(t, testCases[2].Failure)
+
+	// Test cases without objects are kept as they are.
+	assert.Equal(t, "test-case2", testCases[3].Name)
+	assert.Equal(t, "no pods found", testCases[3].Skipped.Text)
+
+	assert.Equal(t, "test-case3/node1", testCases[4].Name)
+	assert.Equal(t, TestStatePassed, testCases[4].Status)
+}
+
+func TestGetResultDuration(t *testing.T) {
+	duration, err := GetResultDuration(&claim.Result{
+		StartTime: "2023-12-20 14:51:33.5 -0600 MST m=+10.000000001",
+		EndTime:   "2023-12-20 14:51:35 -0600 MST m=+11.500000001",
+		Duration:  1,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1500*time.Millisecond, duration)
+
+	_, err = GetResultDuration(&claim.Result{StartTime: "2023-12-20 14:51:33 -0600 MST"})
+	assert.EqualError(t, err, `failed to parse end time: parsing time "" as "2006-01-02 15:04:05 -0700 MST": cannot parse "" as "2006"`)
+}
+
+func TestGetResultObjects(t *testing.T) {
+	checkDetails, err := j.Marshal(testhelper.FailureReasonOut{
+		NonCompliantObjectsOut: []*testhelper.ReportObject{testhelper.NewPodReportObject("ns1", "pod1", "reason", false)},
+	})
+	assert.Nil(t, err)
+
+	resultObjects, err := GetResultObjects(string(checkDetails))
+	assert.Nil(t, err)
+	assert.Len(t, resultObjects.NonCompliantObjectsOut, 1)
+	assert.Empty(t, resultObjects.CompliantObjectsOut)
+
+	resultObjects, err = GetResultObjects("")
+	assert.Nil(t, err)
+	assert.Empty(t, resultObjects.NonCompliantObjectsOut)
+
+	_, err = GetResultObjects("preflight check failed")
+	assert.NotNil(t, err)
+	assert.Empty(t, getResultObjects("preflight check failed").NonCompliantObjectsOut)
+}
@@ -0,0 +1,472 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package claimhelper
+
+import (
+	"bytes"
+	j "encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+)
+
+const (
+	SarifVersion = "2.1.0"
+	SarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	sarifToolName           = "certsuite"
+	sarifToolInformationURI = "https://github.com/redhat-best-practices-for-k8s/certsuite"
+
+	sarifLevelError = "error"
+)
+
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+type SarifRule struct {
+	ID               string         `json:"id"`
+	ShortDescription SarifMessage   `json:"shortDescription"`
+	FullDescription  SarifMessage   `json:"fullDescription"`
+	Help             *SarifMessage  `json:"help,omitempty"`
+	HelpURI          string         `json:"helpUri,omitempty"`
+	Properties       map[string]any `json:"properties,omitempty"`
+}
+
+type SarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SarifRule `json:"rules"`
+}
+
+type SarifTool struct {
+	Driver SarifDriver `json:"driver"`
+}
+
+type SarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+type SarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type SarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type SarifPhysicalLocation struct {
+	ArtifactLocation SarifArtifactLocation `json:"artifactLocation"`
+	Region           SarifRegion           `json:"region"`
+}
+
+type SarifLocation struct {
+	PhysicalLocation *SarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []SarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type SarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	RuleIndex  int               `json:"ruleIndex"`
+	Level      string            `json:"level"`
+	Message    SarifMessage      `json:"message"`
+	Locations  []SarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type SarifNotification struct {
+	Level      string            `json:"level"`
+	Message    SarifMessage      `json:"message"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type SarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	StartTimeUtc               string              `json:"startTimeUtc,omitempty"`
+	EndTimeUtc                 string              `json:"endTimeUtc,omitempty"`
+	ToolExecutionNotifications []SarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type SarifRun struct {
+	Tool        SarifTool         `json:"tool"`
+	Invocations []SarifInvocation `json:"invocations,omitempty"`
+	Results     []SarifResult     `json:"results"`
+}
+
+type SarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+// Returns the category classification of a claim result as a map, the same way it is stored in the catalog.
+func categoryClassificationToMap(categoryClassification *claim.CategoryClassification) map[string]string {
+	if categoryClassification == nil {
+		return nil
+	}
+
+	return map[string]string{
+		identifiers.Extended: categoryClassification.Extended,
+		identifiers.FarEdge:  categoryClassification.FarEdge,
+		identifiers.NonTelco: categoryClassification.NonTelco,
+		identifiers.Telco:    categoryClassification.Telco,
+	}
+}
+
+func newSarifRule(id, suite, description, remediation, exception, reference, tags string, categoryClassification map[string]string) SarifRule {
+	rule := SarifRule{
+		ID:               id,
+		ShortDescription: SarifMessage{Text: id},
+		FullDescription:  SarifMessage{Text: description},
+		Properties: map[string]any{
+			"suite":                  suite,
+			"categoryClassification": categoryClassification,
+			"bestPracticeReference":  reference,
+		},
+	}
+
+	if remediation != "" {
+		rule.Help = &SarifMessage{Text: remediation}
+		rule.Properties["remediation"] = remediation
+	}
+
+	if exception != "" {
+		rule.Properties["exceptionProcess"] = exception
+	}
+
+	if tags != "" {
+		rule.Properties["tags"] = strings.Split(tags, ",")
+	}
+
+	// The best practice reference is not always a link.
+	if strings.HasPrefix(reference, "http://") || strings.HasPrefix(reference, "https://") {
+		rule.HelpURI = reference
+	}
+
+	return rule
+}
+
+// Returns a rule for each catalog entry plus a rule for each claim result whose test case is not in
+// the catalog anymore, sorted by id.
+func getSarifRules(claimData *claim.Claim) []SarifRule {
+	rules := []SarifRule{}
+	catalogIDs := map[string]bool{}
+	for aID := range identifiers.Catalog {
+		entry := identifiers.Catalog[aID]
+		rules = append(rules, newSarifRule(aID.Id, aID.Suite, entry.Description, entry.Remediation, entry.ExceptionProcess,
+			entry.BestPracticeReference, entry.Tags, entry.CategoryClassification))
+		catalogIDs[aID.Id] = true
+	}
+
+	for testID := range claimData.Results {
+		result := claimData.Results[testID]
+		if catalogIDs[testID] || result.TestID == nil {
+			continue
+		}
+
+		catalogInfo := result.CatalogInfo
+		if catalogInfo == nil {
+			catalogInfo = &claim.CatalogInfo{}
+		}
+		rules = append(rules, newSarifRule(testID, result.TestID.Suite, catalogInfo.Description, catalogInfo.Remediation, catalogInfo.ExceptionProcess,
+			catalogInfo.BestPracticeReference, result.TestID.Tags, categoryClassificationToMap(result.CategoryClassification)))
+	}
+
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules
+}
+
+// Reads the next token of the decoder, failing if it is not the given delimiter.
+func expectJSONDelim(dec *j.Decoder, delim j.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if d, ok := tok.(j.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %v, found %v", delim, tok)
+	}
+
+	return nil
+}
+
+// Moves the decoder into the value of the given key of the next JSON object.
+func seekJSONObjectKey(dec *j.Decoder, key string) error {
+	if err := expectJSONDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		if tok == key {
+			return nil
+		}
+
+		// Skip the value of any other key.
+		if err := dec.Decode(&j.RawMessage{}); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("key %q not found", key)
+}
+
+// Returns the line of the claim file where the result of each test case starts, so they can be used
+// as the physical location of the SARIF results.
+func getClaimResultsLines(claimFileContents []byte) (map[string]int, error) {
+	dec := j.NewDecoder(bytes.NewReader(claimFileContents))
+	for _, key := range []string{"claim", "results"} {
+		if err := seekJSONObjectKey(dec, key); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := expectJSONDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	lines := map[string]int{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		testID, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected token %v in the claim results", tok)
+		}
+		lines[testID] = bytes.Count(claimFileContents[:dec.InputOffset()], []byte("\n")) + 1
+
+		if err := dec.Decode(&j.RawMessage{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return lines, nil
+}
+
+// Returns the logical location of a report object: namespace/kind/name, or just kind/name for
+// cluster-wide objects.
+func getReportObjectLogicalLocation(obj *testhelper.ReportObject) SarifLogicalLocation {
+	name := obj.GetName()
+	fullyQualifiedName := obj.ObjectType + "/" + name
+	if namespace := obj.GetNamespace(); namespace != "" {
+		fullyQualifiedName = namespace + "/" + fullyQualifiedName
+	}
+
+	return SarifLogicalLocation{
+		Name:               name,
+		FullyQualifiedName: fullyQualifiedName,
+		Kind:               obj.ObjectType,
+	}
+}
+
+// Returns the reason and the rest of the fields of a report object.
+func getReportObjectReasonAndFields(obj *testhelper.ReportObject) (reason string, fields map[string]string) {
+	fields = map[string]string{}
+	for i := range obj.ObjectFieldsKeys {
+		if i >= len(obj.ObjectFieldsValues) {
+			break
+		}
+
+		key, value := obj.ObjectFieldsKeys[i], obj.ObjectFieldsValues[i]
+		if key == testhelper.ReasonForNonCompliance {
+			reason = value
+			continue
+		}
+		fields[key] = value
+	}
+
+	return reason, fields
+}
+
+// Returns a result for each non-compliant object of a failed test case. Test cases that failed
+// without reporting objects get a single result with only the physical location, which is the result
+// of the test case in the claim file.
+func getSarifResults(testID string, ruleIndex int, result *claim.Result, physicalLocation *SarifPhysicalLocation) []SarifResult {
+	resultObjects := getResultObjects(result.CheckDetails)
+
+	if len(resultObjects.NonCompliantObjectsOut) == 0 {
+		message := result.SkipReason
+		if message == "" {
+			message = result.CheckDetails
+		}
+		if message == "" {
+			message = "Test case " + testID + " failed"
+		}
+
+		return []SarifResult{{
+			RuleID:    testID,
+			RuleIndex: ruleIndex,
+			Level:     sarifLevelError,
+			Message:   SarifMessage{Text: message},
+			Locations: []SarifLocation{{PhysicalLocation: physicalLocation}},
+		}}
+	}
+
+	sarifResults := []SarifResult{}
+	for _, obj := range resultObjects.NonCompliantObjectsOut {
+		reason, fields := getReportObjectReasonAndFields(obj)
+		if reason == "" {
+			reason = "Non-compliant " + obj.ObjectType
+		}
+
+		sarifResults = append(sarifResults, SarifResult{
+			RuleID:    testID,
+			RuleIndex: ruleIndex,
+			Level:     sarifLevelError,
+			Message:   SarifMessage{Text: reason},
+			Locations: []SarifLocation{{
+				PhysicalLocation: physicalLocation,
+				LogicalLocations: []SarifLogicalLocation{getReportObjectLogicalLocation(obj)},
+			}},
+			Properties: fields,
+		})
+	}
+
+	return sarifResults
+}
+
+// SARIF times must be in ISO 8601 format. Claim times that can't be parsed are left out.
+func toSarifTime(claimTime string) string {
+	t, err := time.Parse(DateTimeFormatDirective, claimTime)
+	if err != nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// GenerateSARIF converts the claim into a SARIF log. Every catalog entry becomes a rule and every
+// non-compliant object of the failed test cases becomes a result. Test cases that could not be run
+// because of an error are reported as tool execution notifications. The physical location of the
+// results is the line of their test case in the claim file, whose URI and contents are given.
+func GenerateSARIF(claimData *claim.Claim, claimFileURI string, claimFileContents []byte) *SarifLog {
+	rules := getSarifRules(claimData)
+	ruleIndexes := map[string]int{}
+	for i := range rules {
+		ruleIndexes[rules[i].ID] = i
+	}
+
+	testIDs := make([]string, 0, len(claimData.Results))
+	for testID := range claimData.Results {
+		testIDs = append(testIDs, testID)
+	}
+	sort.Strings(testIDs)
+
+	resultsLines, err := getClaimResultsLines(claimFileContents)
+	if err != nil {
+		log.Debug("Failed to get the lines of the results in the claim file %s: %v", claimFileURI, err)
+	}
+
+	results := []SarifResult{}
+	notifications := []SarifNotification{}
+	for _, testID := range testIDs {
+		result := claimData.Results[testID]
+		switch result.State {
+		case TestStateFailed:
+			// The whole claim file is the location of the results whose line is unknown.
+			physicalLocation := &SarifPhysicalLocation{
+				ArtifactLocation: SarifArtifactLocation{URI: claimFileURI},
+				Region:           SarifRegion{StartLine: max(resultsLines[testID], 1)},
+			}
+			results = append(results, getSarifResults(testID, ruleIndexes[testID], &result, physicalLocation)...)
+		case TestStateError, TestStateAborted:
+			notifications = append(notifications, SarifNotification{
+				Level:      sarifLevelError,
+				Message:    SarifMessage{Text: fmt.Sprintf("Test case %s %s: %s", testID, result.State, result.SkipReason)},
+				Properties: map[string]string{"ruleId": testID},
+			})
+		}
+	}
+
+	version := ""
+	if claimData.Versions != nil {
+		version = claimData.Versions.Tnf
+	}
+
+	invocation := SarifInvocation{
+		ExecutionSuccessful:        true,
+		ToolExecutionNotifications: notifications,
+	}
+	if claimData.Metadata != nil {
+		invocation.StartTimeUtc = toSarifTime(claimData.Metadata.StartTime)
+		invocation.EndTimeUtc = toSarifTime(claimData.Metadata.EndTime)
+	}
+
+	return &SarifLog{
+		Schema:  SarifSchema,
+		Version: SarifVersion,
+		Runs: []SarifRun{{
+			Tool: SarifTool{Driver: SarifDriver{
+				Name:           sarifToolName,
+				Version:        version,
+				InformationURI: sarifToolInformationURI,
+				Rules:          rules,
+			}},
+			Invocations: []SarifInvocation{invocation},
+			Results:     results,
+		}},
+	}
+}
+
+// ClaimFileToSARIF writes the SARIF log of the claim file into the output file. The SARIF log is
+// generated from the claim file as written to disk, e.g. after being sanitized, so the results and
+// their lines match it. The claim file is referenced by the results relative to the output file.
+func ClaimFileToSARIF(claimFile, outputFile string) {
+	data, err := os.ReadFile(claimFile)
+	if err != nil {
+		log.Error("Failed to read the claim file %s, the SARIF file won't be created: %v", claimFile, err)
+		return
+	}
+
+	var claimRoot claim.Root
+	UnmarshalClaim(data, &claimRoot)
+
+	claimFileURI, err := filepath.Rel(filepath.Dir(outputFile), claimFile)
+	if err != nil {
+		claimFileURI = claimFile
+	}
+
+	sarifLog := GenerateSARIF(claimRoot.Claim, filepath.ToSlash(claimFileURI), data)
+	payload, err := j.MarshalIndent(sarifLog, "", "  ")
+	if err != nil {
+		log.Fatal("Failed to generate the SARIF log: %v", err)
+	}
+
+	log.Info("Writing SARIF file: %s", outputFile)
+	err = os.WriteFile(outputFile, payload, claimFilePermissions)
+	if err != nil {
+		log.Fatal("Failed to write the SARIF file: %v", err)
+	}
+}
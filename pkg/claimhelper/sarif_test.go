@@ -0,0 +1,222 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package claimhelper
+
+import (
+	j "encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReportObjectLogicalLocation(t *testing.T) {
+	testCases := []struct {
+		obj              *testhelper.ReportObject
+		expectedLocation SarifLogicalLocation
+	}{
+		{
+			obj:              testhelper.NewPodReportObject("ns1", "pod1", "reason", false),
+			expectedLocation: SarifLogicalLocation{Name: "pod1", FullyQualifiedName: "ns1/Pod/pod1", Kind: testhelper.PodType},
+		},
+		{
+			obj:              testhelper.NewContainerReportObject("ns1", "pod1", "cnt1", "reason", false),
+			expectedLocation: SarifLogicalLocation{Name: "pod1/cnt1", FullyQualifiedName: "ns1/Container/pod1/cnt1", Kind: testhelper.ContainerType},
+		},
+		{
+			obj:              testhelper.NewNodeReportObject("node1", "reason", false),
+			expectedLocation: SarifLogicalLocation{Name: "node1", FullyQualifiedName: "Node/node1", Kind: testhelper.NodeType},
+		},
+		{
+			obj:              testhelper.NewClusterVersionReportObject("4.16.0", "reason", false),
+			expectedLocation: SarifLogicalLocation{Name: "4.16.0", FullyQualifiedName: "OCP Cluster/4.16.0", Kind: testhelper.OCPClusterType},
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedLocation, getReportObjectLogicalLocation(tc.obj))
+	}
+}
+
+func generateSarifTestClaim(t *testing.T) *claim.Claim {
+	checkDetails, err := j.Marshal(testhelper.FailureReasonOut{
+		CompliantObjectsOut: []*testhelper.ReportObject{testhelper.NewPodReportObject("ns1", "pod1", "Pod is compliant", true)},
+		NonCompliantObjectsOut: []*testhelper.ReportObject{
+			testhelper.NewPodReportObject("ns1", "pod2", "Pod has no owner", false),
+			testhelper.NewPodReportObject("ns2", "pod3", "Pod has no owner", false),
+		},
+	})
+	assert.Nil(t, err)
+
+	return &claim.Claim{
+		Metadata: &claim.Metadata{StartTime: "2024-05-07 10:00:00 +0000 UTC", EndTime: "2024-05-07 10:10:00 +0000 UTC"},
+		Versions: &claim.Versions{Tnf: "v5.2.0"},
+		Results: map[string]claim.Result{
+			"lifecycle-pod-owner-type": {
+				TestID:       &claim.Identifier{Id: "lifecycle-pod-owner-type", Suite: "lifecycle"},
+				State:        TestStateFailed,
+				CheckDetails: string(checkDetails),
+			},
+			"lifecycle-pod-scheduling": {
+				TestID: &claim.Identifier{Id: "lifecycle-pod-scheduling", Suite: "lifecycle"},
+				State:  TestStatePassed,
+			},
+			"lifecycle-liveness-probe": {
+				TestID:     &claim.Identifier{Id: "lifecycle-liveness-probe", Suite: "lifecycle"},
+				State:      "error",
+				SkipReason: "failed to get pods",
+			},
+			"acme-custom-check": {
+				TestID:                 &claim.Identifier{Id: "acme-custom-check", Suite: "acme", Tags: "extended"},
+				State:                  TestStateFailed,
+				SkipReason:             "custom check failed",
+				CatalogInfo:            &claim.CatalogInfo{Description: "Custom check", Remediation: "Fix it", BestPracticeReference: "https://example.com/acme"},
+				CategoryClassification: &claim.CategoryClassification{Extended: identifiers.Mandatory},
+			},
+		},
+	}
+}
+
+// Returns the line of the claim file where the result of the test case starts.
+func findClaimResultLine(t *testing.T, claimFileContents []byte, testID string) int {
+	for i, line := range strings.Split(string(claimFileContents), "\n") {
+		if strings.HasPrefix(line, `      "`+testID+`":`) {
+			return i + 1
+		}
+	}
+
+	t.Fatalf("result of %s not found in the claim file", testID)
+	return 0
+}
+
+func TestGetClaimResultsLines(t *testing.T) {
+	claimData := generateSarifTestClaim(t)
+	// The test case ids are in other fields of the claim too.
+	claimData.Configurations = map[string]interface{}{WaivedObjectsField: map[string]int{"lifecycle-pod-owner-type": 1}}
+	claimFileContents := MarshalClaimOutput(&claim.Root{Claim: claimData})
+
+	lines, err := getClaimResultsLines(claimFileContents)
+	assert.Nil(t, err)
+	assert.Len(t, lines, len(claimData.Results))
+	for testID := range claimData.Results {
+		assert.Equal(t, findClaimResultLine(t, claimFileContents, testID), lines[testID])
+	}
+
+	// Compact claim files have every result in the first line.
+	compactContents, err := j.Marshal(&claim.Root{Claim: claimData})
+	assert.Nil(t, err)
+	lines, err = getClaimResultsLines(compactContents)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, lines["lifecycle-pod-owner-type"])
+
+	_, err = getClaimResultsLines([]byte(`{"claim": {"versions": {}}}`))
+	assert.NotNil(t, err)
+	_, err = getClaimResultsLines(nil)
+	assert.NotNil(t, err)
+}
+
+func TestGenerateSARIF(t *testing.T) {
+	claimData := generateSarifTestClaim(t)
+	claimFileContents := MarshalClaimOutput(&claim.Root{Claim: claimData})
+	sarifLog := GenerateSARIF(claimData, "claim.json", claimFileContents)
+
+	assert.Equal(t, SarifVersion, sarifLog.Version)
+	assert.Len(t, sarifLog.Runs, 1)
+	run := sarifLog.Runs[0]
+	assert.Equal(t, "v5.2.0", run.Tool.Driver.Version)
+
+	// All the catalog entries plus the one from the claim that is not in the catalog.
+	rules := run.Tool.Driver.Rules
+	assert.Len(t, rules, len(identifiers.Catalog)+1)
+
+	ruleIndexes := map[string]int{}
+	for i := range rules {
+		ruleIndexes[rules[i].ID] = i
+	}
+
+	customRule := rules[ruleIndexes["acme-custom-check"]]
+	assert.Equal(t, "Custom check", customRule.FullDescription.Text)
+	assert.Equal(t, "Fix it", customRule.Help.Text)
+	assert.Equal(t, "https://example.com/acme", customRule.HelpURI)
+	assert.Equal(t, "acme", customRule.Properties["suite"])
+	assert.Equal(t, identifiers.Mandatory, customRule.Properties["categoryClassification"].(map[string]string)[identifiers.Extended])
+
+	podOwnerRule := rules[ruleIndexes["lifecycle-pod-owner-type"]]
+	assert.Equal(t, identifiers.Catalog[identifiers.TestPodDeploymentBestPracticesIdentifier].Description, podOwnerRule.FullDescription.Text)
+	assert.Contains(t, podOwnerRule.Properties, "categoryClassification")
+
+	// A result for the custom check, without location, and one for each non-compliant pod.
+	assert.Len(t, run.Results, 3)
+	assert.Equal(t, "acme-custom-check", run.Results[0].RuleID)
+	assert.Equal(t, ruleIndexes["acme-custom-check"], run.Results[0].RuleIndex)
+	assert.Equal(t, "custom check failed", run.Results[0].Message.Text)
+	assert.Len(t, run.Results[0].Locations, 1)
+	assert.Empty(t, run.Results[0].Locations[0].LogicalLocations)
+	assert.Equal(t, &SarifPhysicalLocation{
+		ArtifactLocation: SarifArtifactLocation{URI: "claim.json"},
+		Region:           SarifRegion{StartLine: findClaimResultLine(t, claimFileContents, "acme-custom-check")},
+	}, run.Results[0].Locations[0].PhysicalLocation)
+
+	for i, expectedName := range []string{"ns1/Pod/pod2", "ns2/Pod/pod3"} {
+		result := run.Results[i+1]
+		assert.Equal(t, "lifecycle-pod-owner-type", result.RuleID)
+		assert.Equal(t, ruleIndexes["lifecycle-pod-owner-type"], result.RuleIndex)
+		assert.Equal(t, "error", result.Level)
+		assert.Equal(t, "Pod has no owner", result.Message.Text)
+		assert.Len(t, result.Locations, 1)
+		assert.Equal(t, expectedName, result.Locations[0].LogicalLocations[0].FullyQualifiedName)
+		assert.Equal(t, "claim.json", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, findClaimResultLine(t, claimFileContents, "lifecycle-pod-owner-type"), result.Locations[0].PhysicalLocation.Region.StartLine)
+	}
+
+	assert.Len(t, run.Invocations, 1)
+	assert.Equal(t, "2024-05-07T10:00:00Z", run.Invocations[0].StartTimeUtc)
+	assert.Len(t, run.Invocations[0].ToolExecutionNotifications, 1)
+	assert.Equal(t, "lifecycle-liveness-probe", run.Invocations[0].ToolExecutionNotifications[0].Properties["ruleId"])
+
+	// Without the claim file contents the results point to its first line.
+	sarifLog = GenerateSARIF(claimData, "claim.json", nil)
+	for _, result := range sarifLog.Runs[0].Results {
+		assert.Equal(t, 1, result.Locations[0].PhysicalLocation.Region.StartLine)
+	}
+}
+
+func TestClaimFileToSARIF(t *testing.T) {
+	outputDir := t.TempDir()
+	outputFile := filepath.Join(outputDir, "results.sarif")
+	claimFile := filepath.Join(outputDir, "claim.json")
+
+	WriteClaimOutput(claimFile, MarshalClaimOutput(&claim.Root{Claim: generateSarifTestClaim(t)}))
+	ClaimFileToSARIF(claimFile, outputFile)
+
+	contents, err := os.ReadFile(outputFile)
+	assert.Nil(t, err)
+
+	sarifLog := SarifLog{}
+	assert.Nil(t, j.Unmarshal(contents, &sarifLog))
+	assert.Equal(t, SarifSchema, sarifLog.Schema)
+	assert.Len(t, sarifLog.Runs, 1)
+	assert.Len(t, sarifLog.Runs[0].Results, 3)
+	for _, result := range sarifLog.Runs[0].Results {
+		assert.Equal(t, "claim.json", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	}
+}
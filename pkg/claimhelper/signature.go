@@ -0,0 +1,239 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package claimhelper
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	j "encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+const (
+	// SignatureFileExtension is appended to the claim file name to get the name of its detached signature.
+	SignatureFileExtension = ".sig"
+
+	signatureAlgorithm = "ed25519"
+	digestAlgorithm    = "sha256"
+)
+
+// ClaimSignature is the detached signature of a claim file. The digest is computed over the canonical
+// JSON of the claim, so it doesn't depend on the indentation or the order of the fields in the file.
+type ClaimSignature struct {
+	Algorithm       string `json:"algorithm"`
+	DigestAlgorithm string `json:"digestAlgorithm"`
+	Digest          string `json:"digest"`
+	// SHA256 fingerprint of the public key that verifies the signature.
+	KeyID     string `json:"keyId"`
+	Signature string `json:"signature"`
+}
+
+// GetClaimDigest returns the digest of the canonical JSON of the claim: no whitespaces and sorted keys.
+func GetClaimDigest(claimData []byte) ([]byte, error) {
+	var claimRoot interface{}
+	decoder := j.NewDecoder(bytes.NewReader(claimData))
+	// Keeps the numbers as they are in the file.
+	decoder.UseNumber()
+	if err := decoder.Decode(&claimRoot); err != nil {
+		return nil, fmt.Errorf("failed to decode claim: %v", err)
+	}
+
+	canonicalData, err := j.Marshal(claimRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode claim: %v", err)
+	}
+
+	digest := sha256.Sum256(canonicalData)
+	return digest[:], nil
+}
+
+func getKeyID(publicKey ed25519.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %v", err)
+	}
+
+	fingerprint := sha256.Sum256(der)
+	return hex.EncodeToString(fingerprint[:]), nil
+}
+
+func readPEMFile(fileName string) (*pem.Block, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %s: %v", fileName, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key file %s is not PEM encoded", fileName)
+	}
+
+	return block, nil
+}
+
+// ReadSigningKey reads an ed25519 private key from a PEM encoded PKCS #8 file, like the ones
+// created with "openssl genpkey -algorithm ed25519".
+func ReadSigningKey(fileName string) (ed25519.PrivateKey, error) {
+	block, err := readPEMFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key file %s: %v", fileName, err)
+	}
+
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key file %s is not an %s key", fileName, signatureAlgorithm)
+	}
+
+	return privateKey, nil
+}
+
+// ReadVerificationKey reads an ed25519 public key from a PEM encoded PKIX file, like the ones
+// created with "openssl pkey -pubout".
+func ReadVerificationKey(fileName string) (ed25519.PublicKey, error) {
+	block, err := readPEMFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key file %s: %v", fileName, err)
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key file %s is not an %s key", fileName, signatureAlgorithm)
+	}
+
+	return publicKey, nil
+}
+
+// SignClaimFile writes the detached signature of the claim file next to it, returning its file name.
+func SignClaimFile(claimFileName, privateKeyFileName string) (signatureFileName string, err error) {
+	privateKey, err := ReadSigningKey(privateKeyFileName)
+	if err != nil {
+		return "", err
+	}
+
+	claimData, err := os.ReadFile(claimFileName)
+	if err != nil {
+		return "", fmt.Errorf("failed to read claim file %s: %v", claimFileName, err)
+	}
+
+	digest, err := GetClaimDigest(claimData)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the digest of claim file %s: %v", claimFileName, err)
+	}
+
+	keyID, err := getKeyID(privateKey.Public().(ed25519.PublicKey))
+	if err != nil {
+		return "", err
+	}
+
+	signature := ClaimSignature{
+		Algorithm:       signatureAlgorithm,
+		DigestAlgorithm: digestAlgorithm,
+		Digest:          hex.EncodeToString(digest),
+		KeyID:           keyID,
+		Signature:       base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, digest)),
+	}
+
+	payload, err := j.MarshalIndent(signature, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode claim signature: %v", err)
+	}
+
+	signatureFileName = claimFileName + SignatureFileExtension
+	if err := os.WriteFile(signatureFileName, payload, claimFilePermissions); err != nil {
+		return "", fmt.Errorf("failed to write claim signature file %s: %v", signatureFileName, err)
+	}
+
+	return signatureFileName, nil
+}
+
+// VerifyClaimFile checks that the claim file was signed with the private key of the public key and that
+// it was not modified afterwards.
+func VerifyClaimFile(claimFileName, signatureFileName, publicKeyFileName string) error {
+	publicKey, err := ReadVerificationKey(publicKeyFileName)
+	if err != nil {
+		return err
+	}
+
+	signatureData, err := os.ReadFile(signatureFileName)
+	if err != nil {
+		return fmt.Errorf("failed to read claim signature file %s: %v", signatureFileName, err)
+	}
+
+	signature := ClaimSignature{}
+	if err := j.Unmarshal(signatureData, &signature); err != nil {
+		return fmt.Errorf("failed to parse claim signature file %s: %v", signatureFileName, err)
+	}
+
+	if signature.Algorithm != signatureAlgorithm || signature.DigestAlgorithm != digestAlgorithm {
+		return fmt.Errorf("unsupported signature algorithm %s/%s, only %s/%s is supported",
+			signature.Algorithm, signature.DigestAlgorithm, signatureAlgorithm, digestAlgorithm)
+	}
+
+	keyID, err := getKeyID(publicKey)
+	if err != nil {
+		return err
+	}
+	if signature.KeyID != keyID {
+		return fmt.Errorf("claim was signed with key %s, but the public key is %s", signature.KeyID, keyID)
+	}
+
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding in %s: %v", signatureFileName, err)
+	}
+
+	signedDigest, err := hex.DecodeString(signature.Digest)
+	if err != nil {
+		return fmt.Errorf("invalid digest encoding in %s: %v", signatureFileName, err)
+	}
+
+	if !ed25519.Verify(publicKey, signedDigest, signatureBytes) {
+		return fmt.Errorf("invalid signature in %s: the signature file was modified", signatureFileName)
+	}
+
+	claimData, err := os.ReadFile(claimFileName)
+	if err != nil {
+		return fmt.Errorf("failed to read claim file %s: %v", claimFileName, err)
+	}
+
+	digest, err := GetClaimDigest(claimData)
+	if err != nil {
+		return fmt.Errorf("failed to get the digest of claim file %s: %v", claimFileName, err)
+	}
+
+	if !bytes.Equal(digest, signedDigest) {
+		return fmt.Errorf("claim file %s was modified after it was signed", claimFileName)
+	}
+
+	return nil
+}
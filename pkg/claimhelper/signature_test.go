@@ -0,0 +1,128 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package claimhelper
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	j "encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testClaim = `{"claim": {"configurations": {"Config": {"targetNameSpaces": [{"name": "tnf"}]}}, "nodes": {},
+"metadata": {"startTime": "2024-01-10 10:00:00 +0000 UTC", "endTime": "2024-01-10 10:30:00 +0000 UTC"},
+"results": {"test-case1": {"duration": 12, "state": "failed", "testID": {"id": "test-case1", "suite": "test-suite1"}}},
+"versions": {"claimFormat": "v0.4.0", "tnf": "v5.0.0"}}}`
+
+// Writes a new ed25519 key pair in PEM files, returning the private and public key file names.
+func writeTestKeys(t *testing.T, dir, name string) (privateKeyFile, publicKeyFile string) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+
+	privateDer, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.Nil(t, err)
+	publicDer, err := x509.MarshalPKIXPublicKey(publicKey)
+	assert.Nil(t, err)
+
+	privateKeyFile = filepath.Join(dir, name+".pem")
+	publicKeyFile = filepath.Join(dir, name+".pub.pem")
+	assert.Nil(t, os.WriteFile(privateKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDer}), 0o600))
+	assert.Nil(t, os.WriteFile(publicKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer}), 0o600))
+
+	return privateKeyFile, publicKeyFile
+}
+
+func TestSignAndVerifyClaimFile(t *testing.T) {
+	dir := t.TempDir()
+	privateKeyFile, publicKeyFile := writeTestKeys(t, dir, "key")
+	_, otherPublicKeyFile := writeTestKeys(t, dir, "other-key")
+
+	claimFile := filepath.Join(dir, "claim.json")
+	assert.Nil(t, os.WriteFile(claimFile, []byte(testClaim), 0o600))
+
+	signatureFile, err := SignClaimFile(claimFile, privateKeyFile)
+	assert.Nil(t, err)
+	assert.Equal(t, claimFile+SignatureFileExtension, signatureFile)
+	assert.Nil(t, VerifyClaimFile(claimFile, signatureFile, publicKeyFile))
+
+	// Another indentation of the same claim has the same digest.
+	var claimRoot interface{}
+	assert.Nil(t, j.Unmarshal([]byte(testClaim), &claimRoot))
+	indentedClaim, err := j.MarshalIndent(claimRoot, "", "    ")
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(claimFile, indentedClaim, 0o600))
+	assert.Nil(t, VerifyClaimFile(claimFile, signatureFile, publicKeyFile))
+
+	// Verification with another key.
+	err = VerifyClaimFile(claimFile, signatureFile, otherPublicKeyFile)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "claim was signed with key")
+
+	// Modified results.
+	assert.Nil(t, os.WriteFile(claimFile, []byte(strings.Replace(testClaim, `"failed"`, `"passed"`, 1)), 0o600))
+	err = VerifyClaimFile(claimFile, signatureFile, publicKeyFile)
+	assert.NotNil(t, err)
+	assert.Equal(t, "claim file "+claimFile+" was modified after it was signed", err.Error())
+
+	// Modified digest in the signature file, to match the modified results.
+	digest, err := GetClaimDigest([]byte(strings.Replace(testClaim, `"failed"`, `"passed"`, 1)))
+	assert.Nil(t, err)
+	signature := ClaimSignature{}
+	signatureData, err := os.ReadFile(signatureFile)
+	assert.Nil(t, err)
+	assert.Nil(t, j.Unmarshal(signatureData, &signature))
+	signature.Digest = hex.EncodeToString(digest)
+	signatureData, err = j.Marshal(signature)
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(signatureFile, signatureData, 0o600))
+	err = VerifyClaimFile(claimFile, signatureFile, publicKeyFile)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "the signature file was modified")
+}
+
+func TestSignClaimFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	privateKeyFile, publicKeyFile := writeTestKeys(t, dir, "key")
+
+	claimFile := filepath.Join(dir, "claim.json")
+	assert.Nil(t, os.WriteFile(claimFile, []byte(`{"claim": `), 0o600))
+
+	_, err := SignClaimFile(claimFile, privateKeyFile)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "failed to get the digest of claim file")
+
+	// A public key is not a signing key.
+	_, err = SignClaimFile(claimFile, publicKeyFile)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "failed to parse private key file")
+
+	_, err = SignClaimFile(claimFile, claimFile)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "is not PEM encoded")
+
+	_, err = SignClaimFile(claimFile, filepath.Join(dir, "non-existent.pem"))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "failed to read key file")
+}
@@ -2,6 +2,7 @@ package configuration_test
 
 import (
 	"testing"
+	"time"
 
 	configuration "github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
 	"github.com/stretchr/testify/assert"
@@ -32,4 +33,7 @@ func TestLoadConfiguration(t *testing.T) {
 	assert.Contains(t, env.CrdFilters, crd1)
 	crd2 := configuration.CrdFilter{NameSuffix: crdSuffix2}
 	assert.Contains(t, env.CrdFilters, crd2)
+	// check if the checks timeouts are parsed properly
+	assert.Equal(t, 30*time.Minute, env.DefaultCheckTimeout)
+	assert.Equal(t, []configuration.CheckTimeoutInfo{{ID: "lifecycle-pod-recreation", Timeout: time.Hour}}, env.CheckTimeouts)
 }
@@ -59,7 +59,36 @@ type Namespace struct {
 type CrdFilter struct {
 	NameSuffix string `yaml:"nameSuffix" json:"nameSuffix"`
 	Scalable   bool   `yaml:"scalable" json:"scalable"`
+	// Kubernetes label and field selectors of the CRs under test, e.g. "app in (a,b)".
+	LabelSelector string `yaml:"labelSelector,omitempty" json:"labelSelector,omitempty"`
+	FieldSelector string `yaml:"fieldSelector,omitempty" json:"fieldSelector,omitempty"`
 }
+
+// CheckTimeoutInfo sets the maximum time a check is allowed to run before it is aborted.
+type CheckTimeoutInfo struct {
+	// ID of the check, e.g. lifecycle-pod-recreation
+	ID      string        `yaml:"id" json:"id"`
+	Timeout time.Duration `yaml:"timeout" json:"timeout"`
+}
+
+// SuiteScoreThreshold sets the minimum readiness score of a test suite.
+type SuiteScoreThreshold struct {
+	Suite    string  `yaml:"suite" json:"suite"`
+	MinScore float64 `yaml:"minScore" json:"minScore"`
+}
+
+// ScoringConfig sets how the readiness score is computed and the thresholds of its pass/fail gate.
+type ScoringConfig struct {
+	// Category classification used to weight the checks: Telco, NonTelco, FarEdge or Extended.
+	Profile string `yaml:"profile,omitempty" json:"profile,omitempty"`
+	// Weights of the Mandatory and Optional checks of the profile. Unset means the default weight.
+	MandatoryWeight *float64 `yaml:"mandatoryWeight,omitempty" json:"mandatoryWeight,omitempty"`
+	OptionalWeight  *float64 `yaml:"optionalWeight,omitempty" json:"optionalWeight,omitempty"`
+	// Minimum overall score, from 0 to 100. Zero means no threshold.
+	MinScore       float64               `yaml:"minScore,omitempty" json:"minScore,omitempty"`
+	MinSuiteScores []SuiteScoreThreshold `yaml:"minSuiteScores,omitempty" json:"minSuiteScores,omitempty"`
+}
+
 type ManagedDeploymentsStatefulsets struct {
 	Name string `yaml:"name" json:"name"`
 }
@@ -68,10 +97,19 @@ type ManagedDeploymentsStatefulsets struct {
 type TestConfiguration struct {
 	// targetNameSpaces to be used in
 	TargetNameSpaces []Namespace `yaml:"targetNameSpaces,omitempty" json:"targetNameSpaces,omitempty"`
-	// labels identifying pods under test
+	// selectors of namespaces to add to targetNameSpaces, resolved at discovery time
+	TargetNameSpaceSelectors []NamespaceSelector `yaml:"targetNameSpaceSelectors,omitempty" json:"targetNameSpaceSelectors,omitempty"`
+	// selectors of namespaces that are never under test, even if listed in targetNameSpaces
+	ExcludedNameSpaceSelectors []NamespaceSelector `yaml:"excludedNameSpaceSelectors,omitempty" json:"excludedNameSpaceSelectors,omitempty"`
+	// labels identifying pods under test, as "key: value" or label selectors
 	PodsUnderTestLabels []string `yaml:"podsUnderTestLabels,omitempty" json:"podsUnderTestLabels,omitempty"`
-	// labels identifying operators unde test
+	// field selector the pods under test must also match, e.g. "spec.nodeName=worker-0". It doesn't
+	// apply to the deployments, statefulsets and other pod sets, which are selected by labels only.
+	PodsUnderTestFieldSelector string `yaml:"podsUnderTestFieldSelector,omitempty" json:"podsUnderTestFieldSelector,omitempty"`
+	// labels identifying operators unde test, as "key: value" or label selectors
 	OperatorsUnderTestLabels []string `yaml:"operatorsUnderTestLabels,omitempty" json:"operatorsUnderTestLabels,omitempty"`
+	// field selector the CSVs of the operators under test must also match, on metadata.name or metadata.namespace
+	OperatorsUnderTestFieldSelector string `yaml:"operatorsUnderTestFieldSelector,omitempty" json:"operatorsUnderTestFieldSelector,omitempty"`
 	// CRDs section.
 	CrdFilters          []CrdFilter                      `yaml:"targetCrdFilters,omitempty" json:"targetCrdFilters,omitempty"`
 	ManagedDeployments  []ManagedDeploymentsStatefulsets `yaml:"managedDeployments,omitempty" json:"managedDeployments,omitempty"`
@@ -87,6 +125,12 @@ type TestConfiguration struct {
 	ValidProtocolNames          []string                          `yaml:"validProtocolNames,omitempty" json:"validProtocolNames,omitempty"`
 	ServicesIgnoreList          []string                          `yaml:"servicesignorelist,omitempty" json:"servicesignorelist,omitempty"`
 	DebugDaemonSetNamespace     string                            `yaml:"debugDaemonSetNamespace,omitempty" json:"debugDaemonSetNamespace,omitempty"`
+	// Timeout for the checks that do not set their own one. Zero means no timeout.
+	DefaultCheckTimeout time.Duration `yaml:"defaultCheckTimeout,omitempty" json:"defaultCheckTimeout,omitempty"`
+	// Per check timeouts, taking precedence over any other timeout.
+	CheckTimeouts []CheckTimeoutInfo `yaml:"checkTimeouts,omitempty" json:"checkTimeouts,omitempty"`
+	// Readiness score settings.
+	Scoring ScoringConfig `yaml:"scoring,omitempty" json:"scoring,omitempty"`
 	// Collector's parameters
 	ExecutedBy           string `yaml:"executedBy,omitempty" json:"executedBy,omitempty"`
 	PartnerName          string `yaml:"partnerName,omitempty" json:"partnerName,omitempty"`
@@ -107,6 +151,8 @@ type TestParameters struct {
 	DaemonsetMemReq               string
 	DaemonsetMemLim               string
 	SanitizeClaim                 bool
+	ClaimSigningKey               string
+	ExitCodePolicy                string
 	TnfImageRepo                  string
 	TnfDebugImage                 string
 	NonIntrusiveOnly              bool
@@ -115,6 +161,22 @@ type TestParameters struct {
 	OmitArtifactsZipFile          bool
 	EnableDataCollection          bool
 	EnableXMLCreation             bool
+	ExpandXMLTestCases            bool
+	EnableSARIFCreation           bool
+	EventsFile                    string
+	OTLPEndpoint                  string
+	TracesFile                    string
+	MetricsTextfile               string
+	MetricsPushgatewayURL         string
 	ServerMode                    bool
 	Timeout                       time.Duration
+	Parallelism                   int
+	ResumeFrom                    string
+	RerunFailed                   string
+	WaiversFile                   string
+	FailOnExpiredWaivers          bool
+	FromSnapshot                  string
+	RecordExecs                   bool
+	PluginsDir                    string
+	PoliciesFile                  string
 }
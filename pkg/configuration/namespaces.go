@@ -0,0 +1,94 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// NamespaceSelector selects namespaces by their labels and names. A namespace is selected when it
+// matches all the fields that are set.
+type NamespaceSelector struct {
+	// Kubernetes label selector, e.g. "tenant=acme,env in (prod,staging)".
+	LabelSelector string `yaml:"labelSelector,omitempty" json:"labelSelector,omitempty"`
+	// Glob pattern of the name, e.g. "cnf-*".
+	NamePattern string `yaml:"namePattern,omitempty" json:"namePattern,omitempty"`
+	// Regular expression the whole name must match, e.g. "cnf-[a-z]+-[0-9]+".
+	NameRegex string `yaml:"nameRegex,omitempty" json:"nameRegex,omitempty"`
+}
+
+// Validate returns an error if the selector has no fields set or any of them is invalid.
+func (s *NamespaceSelector) Validate() error {
+	if s.LabelSelector == "" && s.NamePattern == "" && s.NameRegex == "" {
+		return errors.New("namespace selector has no labelSelector, namePattern nor nameRegex")
+	}
+
+	if _, err := labels.Parse(s.LabelSelector); err != nil {
+		return fmt.Errorf("invalid namespace label selector %q: %v", s.LabelSelector, err)
+	}
+	if _, err := path.Match(s.NamePattern, ""); err != nil {
+		return fmt.Errorf("invalid namespace name pattern %q: %v", s.NamePattern, err)
+	}
+	if _, err := regexp.Compile(s.NameRegex); err != nil {
+		return fmt.Errorf("invalid namespace name regex %q: %v", s.NameRegex, err)
+	}
+
+	return nil
+}
+
+// Matches returns whether the namespace with the given name and labels is selected. The selector must
+// be valid.
+func (s *NamespaceSelector) Matches(name string, namespaceLabels map[string]string) bool {
+	if s.LabelSelector != "" {
+		selector, err := labels.Parse(s.LabelSelector)
+		if err != nil || !selector.Matches(labels.Set(namespaceLabels)) {
+			return false
+		}
+	}
+
+	if s.NamePattern != "" {
+		if matched, err := path.Match(s.NamePattern, name); err != nil || !matched {
+			return false
+		}
+	}
+
+	if s.NameRegex != "" {
+		re, err := regexp.Compile("^(?:" + s.NameRegex + ")$")
+		if err != nil || !re.MatchString(name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns an error if any of the namespace selectors or exclusions is invalid.
+func validateNamespaceSelectors(config *TestConfiguration) error {
+	for _, selectors := range [][]NamespaceSelector{config.TargetNameSpaceSelectors, config.ExcludedNameSpaceSelectors} {
+		for i := range selectors {
+			if err := selectors[i].Validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
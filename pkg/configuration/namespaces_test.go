@@ -0,0 +1,68 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceSelectorValidate(t *testing.T) {
+	testCases := []struct {
+		selector      NamespaceSelector
+		expectedError string
+	}{
+		{selector: NamespaceSelector{LabelSelector: "tenant in (a,b),env!=dev"}},
+		{selector: NamespaceSelector{NamePattern: "cnf-*", NameRegex: "cnf-[a-z]+-[0-9]+"}},
+		{selector: NamespaceSelector{}, expectedError: "namespace selector has no labelSelector, namePattern nor nameRegex"},
+		{selector: NamespaceSelector{LabelSelector: "tenant in a"}, expectedError: "invalid namespace label selector"},
+		{selector: NamespaceSelector{NamePattern: "cnf-["}, expectedError: "invalid namespace name pattern"},
+		{selector: NamespaceSelector{NameRegex: "cnf-("}, expectedError: "invalid namespace name regex"},
+	}
+
+	for _, tc := range testCases {
+		err := tc.selector.Validate()
+		if tc.expectedError == "" {
+			assert.Nil(t, err)
+		} else {
+			assert.ErrorContains(t, err, tc.expectedError)
+		}
+	}
+}
+
+func TestNamespaceSelectorMatches(t *testing.T) {
+	nsLabels := map[string]string{"tenant": "acme", "env": "prod"}
+
+	testCases := []struct {
+		selector NamespaceSelector
+		name     string
+		expected bool
+	}{
+		{selector: NamespaceSelector{LabelSelector: "tenant=acme"}, name: "cnf-acme-1", expected: true},
+		{selector: NamespaceSelector{LabelSelector: "env in (dev,staging)"}, name: "cnf-acme-1", expected: false},
+		{selector: NamespaceSelector{NamePattern: "cnf-*-?"}, name: "cnf-acme-1", expected: true},
+		{selector: NamespaceSelector{NamePattern: "cnf-*"}, name: "other-cnf-acme", expected: false},
+		{selector: NamespaceSelector{NameRegex: "cnf-[a-z]+-[0-9]+"}, name: "cnf-acme-12", expected: true},
+		// The regex must match the whole name.
+		{selector: NamespaceSelector{NameRegex: "cnf-[a-z]+"}, name: "cnf-acme-12", expected: false},
+		{selector: NamespaceSelector{LabelSelector: "tenant=acme", NamePattern: "cnf-*"}, name: "test-acme", expected: false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tc.selector.Matches(tc.name, nsLabels), "%+v %s", tc.selector, tc.name)
+	}
+}
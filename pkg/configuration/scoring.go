@@ -0,0 +1,112 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/common"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+)
+
+// MaxScore is the highest readiness score and score threshold.
+const MaxScore = 100.0
+
+// ScoringProfiles are the category classifications that can be used to weight the checks.
+var ScoringProfiles = []string{identifiers.Telco, identifiers.NonTelco, identifiers.FarEdge, identifiers.Extended}
+
+func isValidScoringProfile(profile string) bool {
+	for _, p := range ScoringProfiles {
+		if p == profile {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isValidScore(score float64) bool {
+	return score >= 0 && score <= MaxScore
+}
+
+// Validate checks the values of the scoring config. The suites of the thresholds are not checked, as the
+// suites of the custom checks are only known once they are loaded.
+func (c *ScoringConfig) Validate() error {
+	if c.Profile != "" && !isValidScoringProfile(c.Profile) {
+		return fmt.Errorf("invalid scoring profile %q, valid profiles: %v", c.Profile, ScoringProfiles)
+	}
+
+	if c.MandatoryWeight != nil && *c.MandatoryWeight < 0 {
+		return fmt.Errorf("invalid mandatory scoring weight %v, it can't be negative", *c.MandatoryWeight)
+	}
+	if c.OptionalWeight != nil && *c.OptionalWeight < 0 {
+		return fmt.Errorf("invalid optional scoring weight %v, it can't be negative", *c.OptionalWeight)
+	}
+	// The default weights are not zero.
+	if c.MandatoryWeight != nil && c.OptionalWeight != nil && *c.MandatoryWeight == 0 && *c.OptionalWeight == 0 {
+		return errors.New("invalid scoring weights, the mandatory and optional weights can't be both zero")
+	}
+
+	if !isValidScore(c.MinScore) {
+		return fmt.Errorf("invalid minimum score %v, it must be between 0 and %v", c.MinScore, MaxScore)
+	}
+
+	for _, threshold := range c.MinSuiteScores {
+		if !isValidScore(threshold.MinScore) {
+			return fmt.Errorf("invalid minimum score %v of suite %s, it must be between 0 and %v", threshold.MinScore, threshold.Suite, MaxScore)
+		}
+	}
+
+	return nil
+}
+
+// Returns the suites of the catalog, which has the custom checks already loaded, plus the preflight one,
+// whose checks are only added once the preflight lib has run.
+func getKnownSuites() map[string]bool {
+	suites := map[string]bool{common.PreflightTestKey: true}
+	for aID := range identifiers.Catalog {
+		suites[aID.Suite] = true
+	}
+
+	return suites
+}
+
+// Checks the scoring config, failing on thresholds of unknown suites too, as they would never be checked.
+func validateScoringConfig(cfg *TestConfiguration) error {
+	if err := cfg.Scoring.Validate(); err != nil {
+		return fmt.Errorf("invalid scoring config: %v", err)
+	}
+
+	knownSuites := getKnownSuites()
+	for _, threshold := range cfg.Scoring.MinSuiteScores {
+		if knownSuites[threshold.Suite] {
+			continue
+		}
+
+		suites := make([]string, 0, len(knownSuites))
+		for suite := range knownSuites {
+			suites = append(suites, suite)
+		}
+		sort.Strings(suites)
+
+		return fmt.Errorf("invalid scoring config: unknown suite %q in minSuiteScores, known suites: %v", threshold.Suite, suites)
+	}
+
+	return nil
+}
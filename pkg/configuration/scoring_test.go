@@ -0,0 +1,90 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package configuration
+
+import (
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+	"github.com/stretchr/testify/assert"
+)
+
+func weight(w float64) *float64 {
+	return &w
+}
+
+func TestScoringConfigValidate(t *testing.T) {
+	assert.Nil(t, (&ScoringConfig{}).Validate())
+	assert.Nil(t, (&ScoringConfig{Profile: "FarEdge", MandatoryWeight: weight(0), MinScore: 100}).Validate())
+
+	testCases := []struct {
+		config        ScoringConfig
+		expectedError string
+	}{
+		{
+			config:        ScoringConfig{Profile: "telco"},
+			expectedError: `invalid scoring profile "telco", valid profiles: [Telco NonTelco FarEdge Extended]`,
+		},
+		{
+			config:        ScoringConfig{MandatoryWeight: weight(-2)},
+			expectedError: "invalid mandatory scoring weight -2, it can't be negative",
+		},
+		{
+			config:        ScoringConfig{MandatoryWeight: weight(0), OptionalWeight: weight(0)},
+			expectedError: "invalid scoring weights, the mandatory and optional weights can't be both zero",
+		},
+		{
+			config:        ScoringConfig{MinScore: -1},
+			expectedError: "invalid minimum score -1, it must be between 0 and 100",
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.config.Validate()
+		assert.NotNil(t, err)
+		assert.Equal(t, tc.expectedError, err.Error())
+	}
+}
+
+func TestValidateScoringConfig(t *testing.T) {
+	cfg := TestConfiguration{Scoring: ScoringConfig{MinSuiteScores: []SuiteScoreThreshold{
+		{Suite: "access-control", MinScore: 80},
+		// Its checks are not in the catalog until the preflight lib runs.
+		{Suite: "preflight", MinScore: 50},
+	}}}
+	assert.Nil(t, validateScoringConfig(&cfg))
+
+	cfg.Scoring.MinSuiteScores = append(cfg.Scoring.MinSuiteScores, SuiteScoreThreshold{Suite: "acme", MinScore: 50})
+	err := validateScoringConfig(&cfg)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `invalid scoring config: unknown suite "acme" in minSuiteScores, known suites: [access-control`)
+
+	// The suites of the custom checks are known once they are loaded.
+	aIDs, err := identifiers.AddCustomCatalogEntries("acme", []identifiers.CustomCatalogEntry{{ID: "check"}})
+	assert.Nil(t, err)
+	defer func() {
+		for _, aID := range aIDs {
+			delete(identifiers.Catalog, aID)
+		}
+	}()
+	assert.Nil(t, validateScoringConfig(&cfg))
+
+	cfg.Scoring.MinScore = 120
+	err = validateScoringConfig(&cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid scoring config: invalid minimum score 120, it must be between 0 and 100", err.Error())
+}
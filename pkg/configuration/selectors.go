@@ -0,0 +1,112 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// Legacy "key: value" form of the labels under test. Label keys and values cannot contain a colon, so
+// it never conflicts with the label selector syntax.
+var legacyLabelRegex = regexp.MustCompile(`^\s*(\S*)\s*:\s*(\S*)\s*$`)
+
+// ParseLabelSelector parses an entry of podsUnderTestLabels or operatorsUnderTestLabels. It is either
+// in the legacy "key: value" form or in the Kubernetes label selector syntax, e.g.
+// "app in (a,b),tier!=debug", whose requirements must all match.
+func ParseLabelSelector(selector string) (labels.Selector, error) {
+	if strings.TrimSpace(selector) == "" {
+		return nil, errors.New("empty label selector")
+	}
+
+	if !strings.Contains(selector, ":") {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %v", selector, err)
+		}
+		return parsed, nil
+	}
+
+	values := legacyLabelRegex.FindStringSubmatch(selector)
+	if values == nil {
+		return nil, fmt.Errorf("invalid label %q, expected \"key: value\" or a label selector", selector)
+	}
+
+	requirement, err := labels.NewRequirement(values[1], selection.Equals, []string{values[2]})
+	if err != nil {
+		return nil, fmt.Errorf("invalid label %q: %v", selector, err)
+	}
+
+	return labels.NewSelector().Add(*requirement), nil
+}
+
+// Custom resources, like the CSVs of the operators, can only be listed with field selectors on these fields.
+var customResourceSelectableFields = []string{"metadata.name", "metadata.namespace"}
+
+// Parses the field selector of custom resources, failing on fields they can't be selected by.
+func validateCustomResourceFieldSelector(selector string) error {
+	parsed, err := fields.ParseSelector(selector)
+	if err != nil {
+		return fmt.Errorf("invalid field selector %q: %v", selector, err)
+	}
+
+	for _, requirement := range parsed.Requirements() {
+		if !slices.Contains(customResourceSelectableFields, requirement.Field) {
+			return fmt.Errorf("invalid field selector %q: field %q is not supported, custom resources can only be selected by %v",
+				selector, requirement.Field, customResourceSelectableFields)
+		}
+	}
+
+	return nil
+}
+
+// Returns an error if any of the label or field selectors of the objects under test is invalid.
+func validateUnderTestSelectors(config *TestConfiguration) error {
+	for _, labelsUnderTest := range [][]string{config.PodsUnderTestLabels, config.OperatorsUnderTestLabels} {
+		for _, selector := range labelsUnderTest {
+			if _, err := ParseLabelSelector(selector); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := fields.ParseSelector(config.PodsUnderTestFieldSelector); err != nil {
+		return fmt.Errorf("invalid field selector %q: %v", config.PodsUnderTestFieldSelector, err)
+	}
+
+	if err := validateCustomResourceFieldSelector(config.OperatorsUnderTestFieldSelector); err != nil {
+		return fmt.Errorf("operators under test: %v", err)
+	}
+
+	for _, crdFilter := range config.CrdFilters {
+		if _, err := labels.Parse(crdFilter.LabelSelector); err != nil {
+			return fmt.Errorf("invalid label selector %q of CRD filter %q: %v", crdFilter.LabelSelector, crdFilter.NameSuffix, err)
+		}
+		if err := validateCustomResourceFieldSelector(crdFilter.FieldSelector); err != nil {
+			return fmt.Errorf("CRD filter %q: %v", crdFilter.NameSuffix, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	testCases := []struct {
+		selector      string
+		expected      string
+		expectedError string
+	}{
+		// Legacy "key: value" form.
+		{selector: "redhat-best-practices-for-k8s.com/generic: target", expected: "redhat-best-practices-for-k8s.com/generic=target"},
+		{selector: "app:web", expected: "app=web"},
+		{selector: "cnf/testEmpty:", expected: "cnf/testEmpty="},
+		// Kubernetes label selector syntax.
+		{selector: "app in (a,b),tier!=debug", expected: "app in (a,b),tier!=debug"},
+		{selector: "app=web,!canary", expected: "app=web,!canary"},
+		{selector: "", expectedError: "empty label selector"},
+		{selector: "app in a", expectedError: "invalid label selector"},
+		{selector: "app: web: x", expectedError: "expected \"key: value\" or a label selector"},
+		{selector: "app: web!", expectedError: "invalid label"},
+	}
+
+	for _, tc := range testCases {
+		selector, err := ParseLabelSelector(tc.selector)
+		if tc.expectedError != "" {
+			assert.ErrorContains(t, err, tc.expectedError, tc.selector)
+			continue
+		}
+		assert.Nil(t, err, tc.selector)
+		assert.Equal(t, tc.expected, selector.String())
+	}
+
+	selector, err := ParseLabelSelector("app in (a,b),tier!=debug")
+	assert.Nil(t, err)
+	assert.True(t, selector.Matches(labels.Set{"app": "a", "tier": "backend"}))
+	assert.False(t, selector.Matches(labels.Set{"app": "a", "tier": "debug"}))
+	assert.False(t, selector.Matches(labels.Set{"app": "c"}))
+}
+
+func TestValidateUnderTestSelectors(t *testing.T) {
+	testCases := []struct {
+		config        TestConfiguration
+		expectedError string
+	}{
+		{config: TestConfiguration{
+			PodsUnderTestLabels:        []string{"app: web", "app in (a,b),tier!=debug"},
+			PodsUnderTestFieldSelector: "spec.nodeName=worker-0",
+			OperatorsUnderTestLabels:   []string{"operator=x"},
+			CrdFilters:                 []CrdFilter{{NameSuffix: "example.com", LabelSelector: "app=web", FieldSelector: "metadata.name=cr1"}},
+		}},
+		{config: TestConfiguration{OperatorsUnderTestLabels: []string{"app in a"}}, expectedError: "invalid label selector"},
+		{config: TestConfiguration{PodsUnderTestFieldSelector: "spec.nodeName"}, expectedError: "invalid field selector"},
+		{config: TestConfiguration{CrdFilters: []CrdFilter{{NameSuffix: "example.com", LabelSelector: "app in a"}}},
+			expectedError: "of CRD filter \"example.com\""},
+		{config: TestConfiguration{OperatorsUnderTestFieldSelector: "metadata.namespace=ns1,metadata.name!=op.v1"}},
+		// CSVs are custom resources.
+		{config: TestConfiguration{OperatorsUnderTestFieldSelector: "status.phase=Succeeded"},
+			expectedError: `operators under test: invalid field selector "status.phase=Succeeded": field "status.phase" is not supported`},
+		{config: TestConfiguration{CrdFilters: []CrdFilter{{NameSuffix: "example.com", FieldSelector: "spec.replicas=2"}}},
+			expectedError: `CRD filter "example.com": invalid field selector "spec.replicas=2": field "spec.replicas" is not supported`},
+	}
+
+	for _, tc := range testCases {
+		err := validateUnderTestSelectors(&tc.config)
+		if tc.expectedError == "" {
+			assert.Nil(t, err)
+		} else {
+			assert.ErrorContains(t, err, tc.expectedError)
+		}
+	}
+}
@@ -48,6 +48,18 @@ func LoadConfiguration(filePath string) (TestConfiguration, error) {
 		return configuration, err
 	}
 
+	if err := validateNamespaceSelectors(&configuration); err != nil {
+		return configuration, err
+	}
+
+	if err := validateUnderTestSelectors(&configuration); err != nil {
+		return configuration, err
+	}
+
+	if err := validateScoringConfig(&configuration); err != nil {
+		return configuration, err
+	}
+
 	// Set default namespace for the debug daemonset pods, in case it was not set.
 	if configuration.DebugDaemonSetNamespace == "" {
 		log.Warn("No namespace configured for the debug DaemonSet. Defaulting to namespace %q", defaultDebugDaemonSetNamespace)
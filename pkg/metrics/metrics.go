@@ -0,0 +1,187 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/claimhelper"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+)
+
+const (
+	metricsNamespace = "certsuite"
+
+	// PushgatewayJob is the job name of the metrics pushed to the Pushgateway.
+	PushgatewayJob = "certsuite"
+)
+
+var (
+	// The metrics of the last run. A dedicated registry is used so only the certsuite metrics are
+	// exported, without the go runtime ones.
+	registry = prometheus.NewRegistry()
+
+	checkResults = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "check_results",
+		Help:      "Number of checks by suite and result state.",
+	}, []string{"suite", "state"})
+
+	checkResultsByLabel = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "check_results_by_label",
+		Help:      "Number of checks by label and result state. Checks are counted once for each of their labels.",
+	}, []string{"label", "state"})
+
+	checkDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "check_duration_seconds",
+		Help:      "Time the check took to run.",
+	}, []string{"check", "suite", "state"})
+
+	discoveredObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "discovered_objects",
+		Help:      "Number of objects under test found by the autodiscovery, by kind.",
+	}, []string{"kind"})
+
+	workloadNonCompliantObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "workload_non_compliant_objects",
+		Help:      "Number of times each workload object was reported as non-compliant by the failed checks.",
+	}, []string{"namespace", "kind", "name"})
+
+	runDuration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "run_duration_seconds",
+		Help:      "Time the last run took to run all the checks.",
+	})
+
+	lastRunTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "last_run_timestamp_seconds",
+		Help:      "Unix time when the last run finished.",
+	})
+)
+
+func init() {
+	registry.MustRegister(checkResults, checkResultsByLabel, checkDuration, discoveredObjects,
+		workloadNonCompliantObjects, runDuration, lastRunTimestamp)
+}
+
+// Returns the check's duration from its start and end times, or its duration field, truncated to
+// seconds, if they can't be parsed.
+func getCheckDuration(result *claim.Result) float64 {
+	duration, err := claimhelper.GetResultDuration(result)
+	if err != nil {
+		return float64(result.Duration)
+	}
+
+	return duration.Seconds()
+}
+
+func getNonCompliantObjects(checkDetails string) []*testhelper.ReportObject {
+	resultObjects, err := claimhelper.GetResultObjects(checkDetails)
+	if err != nil {
+		return nil
+	}
+
+	return resultObjects.NonCompliantObjectsOut
+}
+
+func updateDiscoveredObjects(env *provider.TestEnvironment) {
+	discoveredObjects.Reset()
+	for kind, count := range env.GetDiscoveredObjectsCounts() {
+		discoveredObjects.WithLabelValues(kind).Set(float64(count))
+	}
+}
+
+// Update replaces the metrics with the ones of a run, from the checks' results and the objects
+// found by the autodiscovery.
+func Update(results map[string]claim.Result, env *provider.TestEnvironment, startTime, endTime time.Time) {
+	checkResults.Reset()
+	checkResultsByLabel.Reset()
+	checkDuration.Reset()
+	workloadNonCompliantObjects.Reset()
+
+	for checkID := range results {
+		result := results[checkID]
+		suite := ""
+		labels := []string{}
+		if result.TestID != nil {
+			suite = result.TestID.Suite
+			for _, label := range strings.Split(result.TestID.Tags, ",") {
+				if label = strings.TrimSpace(label); label != "" {
+					labels = append(labels, label)
+				}
+			}
+		}
+
+		checkResults.WithLabelValues(suite, result.State).Inc()
+		for _, label := range labels {
+			checkResultsByLabel.WithLabelValues(label, result.State).Inc()
+		}
+		checkDuration.WithLabelValues(checkID, suite, result.State).Set(getCheckDuration(&result))
+
+		if result.State != claimhelper.TestStateFailed {
+			continue
+		}
+
+		for _, obj := range getNonCompliantObjects(result.CheckDetails) {
+			workloadNonCompliantObjects.WithLabelValues(obj.GetNamespace(), obj.ObjectType, obj.GetName()).Inc()
+		}
+	}
+
+	if env != nil {
+		updateDiscoveredObjects(env)
+	}
+
+	runDuration.Set(endTime.Sub(startTime).Seconds())
+	lastRunTimestamp.Set(float64(endTime.Unix()))
+}
+
+// Handler returns the http handler of the /metrics endpoint.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// WriteTextfile writes the metrics in the Prometheus text format, so they can be read by the
+// textfile collector of the node exporter.
+func WriteTextfile(filePath string) error {
+	if err := prometheus.WriteToTextfile(filePath, registry); err != nil {
+		return fmt.Errorf("failed to write metrics file %s: %v", filePath, err)
+	}
+
+	return nil
+}
+
+// Push sends the metrics to a Pushgateway, replacing the ones from the previous runs.
+func Push(pushgatewayURL string) error {
+	if err := push.New(pushgatewayURL, PushgatewayJob).Gatherer(registry).Push(); err != nil {
+		return fmt.Errorf("failed to push metrics to %s: %v", pushgatewayURL, err)
+	}
+
+	return nil
+}
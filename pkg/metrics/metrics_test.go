@@ -0,0 +1,153 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package metrics
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestResults(t *testing.T) map[string]claim.Result {
+	checkDetails, err := json.Marshal(testhelper.FailureReasonOut{
+		NonCompliantObjectsOut: []*testhelper.ReportObject{
+			testhelper.NewContainerReportObject("ns1", "pod1", "c1", "reason", false),
+			testhelper.NewContainerReportObject("ns1", "pod1", "c2", "reason", false),
+			testhelper.NewContainerReportObject("ns1", "pod1", "c2", "reason", false),
+			testhelper.NewPodReportObject("ns2", "pod2", "reason", false),
+		},
+		CompliantObjectsOut: []*testhelper.ReportObject{
+			testhelper.NewPodReportObject("ns1", "pod3", "reason", true),
+		},
+	})
+	assert.Nil(t, err)
+
+	return map[string]claim.Result{
+		"access-control-check1": {
+			TestID:       &claim.Identifier{Id: "access-control-check1", Suite: "access-control", Tags: "common,telco"},
+			State:        "failed",
+			StartTime:    "2024-05-02 10:00:00.000000000 +0000 UTC m=+1.000000001",
+			EndTime:      "2024-05-02 10:00:02.500000000 +0000 UTC m=+3.500000001",
+			Duration:     2,
+			CheckDetails: string(checkDetails),
+		},
+		"access-control-check2": {
+			TestID:   &claim.Identifier{Id: "access-control-check2", Suite: "access-control", Tags: "common"},
+			State:    "passed",
+			Duration: 3,
+		},
+		"networking-check1": {
+			TestID: &claim.Identifier{Id: "networking-check1", Suite: "networking", Tags: "telco"},
+			State:  "skipped",
+		},
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	env := &provider.TestEnvironment{
+		Pods:       []*provider.Pod{{}, {}},
+		Containers: []*provider.Container{{}, {}, {}},
+	}
+	startTime := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+	Update(getTestResults(t), env, startTime, startTime.Add(time.Minute))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(checkResults.WithLabelValues("access-control", "failed")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(checkResults.WithLabelValues("access-control", "passed")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(checkResults.WithLabelValues("networking", "skipped")))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(checkResultsByLabel.WithLabelValues("common", "failed")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(checkResultsByLabel.WithLabelValues("common", "passed")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(checkResultsByLabel.WithLabelValues("telco", "failed")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(checkResultsByLabel.WithLabelValues("telco", "skipped")))
+
+	// The duration comes from the start and end times, or from the duration field if they're missing.
+	assert.Equal(t, 2.5, testutil.ToFloat64(checkDuration.WithLabelValues("access-control-check1", "access-control", "failed")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(checkDuration.WithLabelValues("access-control-check2", "access-control", "passed")))
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(discoveredObjects.WithLabelValues("pods")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(discoveredObjects.WithLabelValues("containers")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(discoveredObjects.WithLabelValues("deployments")))
+
+	assert.Equal(t, 3, testutil.CollectAndCount(workloadNonCompliantObjects))
+	assert.Equal(t, float64(1), testutil.ToFloat64(workloadNonCompliantObjects.WithLabelValues("ns1", testhelper.ContainerType, "pod1/c1")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(workloadNonCompliantObjects.WithLabelValues("ns1", testhelper.ContainerType, "pod1/c2")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(workloadNonCompliantObjects.WithLabelValues("ns2", testhelper.PodType, "pod2")))
+
+	assert.Equal(t, float64(60), testutil.ToFloat64(runDuration))
+	assert.Equal(t, float64(startTime.Add(time.Minute).Unix()), testutil.ToFloat64(lastRunTimestamp))
+
+	// The metrics of the previous run are removed.
+	Update(map[string]claim.Result{}, nil, startTime, startTime)
+	assert.Equal(t, 0, testutil.CollectAndCount(checkResults))
+	assert.Equal(t, 0, testutil.CollectAndCount(workloadNonCompliantObjects))
+}
+
+func TestHandler(t *testing.T) {
+	Update(getTestResults(t), &provider.TestEnvironment{}, time.Now(), time.Now())
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `certsuite_check_results{state="failed",suite="access-control"} 1`)
+	assert.NotContains(t, recorder.Body.String(), "go_goroutines")
+}
+
+func TestWriteTextfile(t *testing.T) {
+	Update(getTestResults(t), &provider.TestEnvironment{}, time.Now(), time.Now())
+
+	metricsFile := filepath.Join(t.TempDir(), "certsuite.prom")
+	assert.Nil(t, WriteTextfile(metricsFile))
+
+	contents, err := os.ReadFile(metricsFile)
+	assert.Nil(t, err)
+	assert.Contains(t, string(contents), `certsuite_check_results_by_label{label="telco",state="skipped"} 1`)
+
+	assert.NotNil(t, WriteTextfile(filepath.Join(t.TempDir(), "non-existent-dir", "certsuite.prom")))
+}
+
+func TestPush(t *testing.T) {
+	Update(getTestResults(t), &provider.TestEnvironment{}, time.Now(), time.Now())
+
+	// Pushgateway stand-in that saves the last pushed metrics.
+	var pushedPath, pushedMetrics string
+	pushgateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		pushedPath = r.URL.Path
+		pushedMetrics = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer pushgateway.Close()
+
+	assert.Nil(t, Push(pushgateway.URL))
+	assert.Equal(t, "/metrics/job/"+PushgatewayJob, pushedPath)
+	// The metrics are sent in the protobuf format.
+	assert.Contains(t, pushedMetrics, "certsuite_check_duration_seconds")
+
+	pushgateway.Close()
+	assert.NotNil(t, Push(pushgateway.URL))
+}
@@ -0,0 +1,180 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package permissions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Placeholders of the namespaces that are only known once the configuration is loaded.
+const (
+	TargetNamespaces = "<target namespaces>"
+	ProbeNamespace   = "<probe namespace>"
+)
+
+// Permission is an API verb on a resource that the test suite needs.
+type Permission struct {
+	Verb        string `json:"verb"`
+	Group       string `json:"group,omitempty"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	// Empty for cluster scoped resources and for the namespaced ones needed in all the namespaces.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Permissions needed by the autodiscovery, the probe daemonset and the checks.
+var (
+	ListNamespaces                  = Permission{Verb: "list", Resource: "namespaces"}
+	ListNodes                       = Permission{Verb: "list", Resource: "nodes"}
+	ListPods                        = Permission{Verb: "list", Resource: "pods", Namespace: TargetNamespaces}
+	ListProbePods                   = Permission{Verb: "list", Resource: "pods", Namespace: ProbeNamespace}
+	ListEvents                      = Permission{Verb: "list", Resource: "events", Namespace: TargetNamespaces}
+	ListServices                    = Permission{Verb: "list", Resource: "services", Namespace: TargetNamespaces}
+	ListHelmReleases                = Permission{Verb: "list", Resource: "secrets", Namespace: TargetNamespaces}
+	ListResourceQuotas              = Permission{Verb: "list", Resource: "resourcequotas"}
+	ListPersistentVolumes           = Permission{Verb: "list", Resource: "persistentvolumes"}
+	ListPersistentVolumeClaims      = Permission{Verb: "list", Resource: "persistentvolumeclaims"}
+	ListStorageClasses              = Permission{Verb: "list", Group: "storage.k8s.io", Resource: "storageclasses"}
+	ListDeployments                 = Permission{Verb: "list", Group: "apps", Resource: "deployments", Namespace: TargetNamespaces}
+	ListStatefulSets                = Permission{Verb: "list", Group: "apps", Resource: "statefulsets", Namespace: TargetNamespaces}
+	ListDaemonSets                  = Permission{Verb: "list", Group: "apps", Resource: "daemonsets", Namespace: TargetNamespaces}
+	ListReplicaSets                 = Permission{Verb: "list", Group: "apps", Resource: "replicasets", Namespace: TargetNamespaces}
+	ListJobs                        = Permission{Verb: "list", Group: "batch", Resource: "jobs", Namespace: TargetNamespaces}
+	ListCronJobs                    = Permission{Verb: "list", Group: "batch", Resource: "cronjobs", Namespace: TargetNamespaces}
+	ListHorizontalPodAutoscalers    = Permission{Verb: "list", Group: "autoscaling", Resource: "horizontalpodautoscalers", Namespace: TargetNamespaces}
+	ListPodDisruptionBudgets        = Permission{Verb: "list", Group: "policy", Resource: "poddisruptionbudgets", Namespace: TargetNamespaces}
+	ListNetworkPolicies             = Permission{Verb: "list", Group: "networking.k8s.io", Resource: "networkpolicies"}
+	ListRoles                       = Permission{Verb: "list", Group: "rbac.authorization.k8s.io", Resource: "roles"}
+	ListRoleBindings                = Permission{Verb: "list", Group: "rbac.authorization.k8s.io", Resource: "rolebindings"}
+	ListClusterRoleBindings         = Permission{Verb: "list", Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings"}
+	ListCrds                        = Permission{Verb: "list", Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}
+	ListClusterServiceVersions      = Permission{Verb: "list", Group: "operators.coreos.com", Resource: "clusterserviceversions", Namespace: TargetNamespaces}
+	ListSubscriptions               = Permission{Verb: "list", Group: "operators.coreos.com", Resource: "subscriptions", Namespace: TargetNamespaces}
+	ListAllClusterServiceVersions   = Permission{Verb: "list", Group: "operators.coreos.com", Resource: "clusterserviceversions"}
+	ListAllSubscriptions            = Permission{Verb: "list", Group: "operators.coreos.com", Resource: "subscriptions"}
+	ListAllInstallPlans             = Permission{Verb: "list", Group: "operators.coreos.com", Resource: "installplans"}
+	ListAllCatalogSources           = Permission{Verb: "list", Group: "operators.coreos.com", Resource: "catalogsources"}
+	GetClusterOperators             = Permission{Verb: "get", Group: "config.openshift.io", Resource: "clusteroperators"}
+	GetIstioDeployments             = Permission{Verb: "get", Group: "apps", Resource: "deployments", Namespace: "istio-system"}
+	GetProbeDaemonSets              = Permission{Verb: "get", Group: "apps", Resource: "daemonsets", Namespace: ProbeNamespace}
+	CreateProbeDaemonSets           = Permission{Verb: "create", Group: "apps", Resource: "daemonsets", Namespace: ProbeNamespace}
+	GetNamespaces                   = Permission{Verb: "get", Resource: "namespaces"}
+	CreateNamespaces                = Permission{Verb: "create", Resource: "namespaces"}
+	ExecPods                        = Permission{Verb: "create", Resource: "pods", Subresource: "exec", Namespace: TargetNamespaces}
+	ExecProbePods                   = Permission{Verb: "create", Resource: "pods", Subresource: "exec", Namespace: ProbeNamespace}
+	GetPodLogs                      = Permission{Verb: "get", Resource: "pods", Subresource: "log", Namespace: TargetNamespaces}
+	GetNetworkAttachmentDefinitions = Permission{Verb: "get", Group: "k8s.cni.cncf.io", Resource: "network-attachment-definitions", Namespace: TargetNamespaces}
+	GetServiceAccounts              = Permission{Verb: "get", Resource: "serviceaccounts", Namespace: TargetNamespaces}
+	GetClusterServiceVersions       = Permission{Verb: "get", Group: "operators.coreos.com", Resource: "clusterserviceversions", Namespace: TargetNamespaces}
+	ListAllPods                     = Permission{Verb: "list", Resource: "pods"}
+	GetNodes                        = Permission{Verb: "get", Resource: "nodes"}
+	UpdateNodes                     = Permission{Verb: "update", Resource: "nodes"}
+	WatchPods                       = Permission{Verb: "watch", Resource: "pods", Namespace: TargetNamespaces}
+	DeletePods                      = Permission{Verb: "delete", Resource: "pods", Namespace: TargetNamespaces}
+	GetDeployments                  = Permission{Verb: "get", Group: "apps", Resource: "deployments", Namespace: TargetNamespaces}
+	UpdateDeployments               = Permission{Verb: "update", Group: "apps", Resource: "deployments", Namespace: TargetNamespaces}
+	GetStatefulSets                 = Permission{Verb: "get", Group: "apps", Resource: "statefulsets", Namespace: TargetNamespaces}
+	UpdateStatefulSets              = Permission{Verb: "update", Group: "apps", Resource: "statefulsets", Namespace: TargetNamespaces}
+	GetHorizontalPodAutoscalers     = Permission{Verb: "get", Group: "autoscaling", Resource: "horizontalpodautoscalers", Namespace: TargetNamespaces}
+	UpdateHorizontalPodAutoscalers  = Permission{Verb: "update", Group: "autoscaling", Resource: "horizontalpodautoscalers", Namespace: TargetNamespaces}
+)
+
+// GetResource returns the resource in the "resource.group/subresource" format used by kubectl.
+func (p *Permission) GetResource() string {
+	resource := p.Resource
+	if p.Group != "" {
+		resource += "." + p.Group
+	}
+	if p.Subresource != "" {
+		resource += "/" + p.Subresource
+	}
+
+	return resource
+}
+
+func (p Permission) String() string {
+	if p.Namespace == "" {
+		return fmt.Sprintf("%s %s", p.Verb, p.GetResource())
+	}
+
+	return fmt.Sprintf("%s %s in namespace %s", p.Verb, p.GetResource(), p.Namespace)
+}
+
+func less(a, b *Permission) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	if a.Group != b.Group {
+		return a.Group < b.Group
+	}
+	if a.Resource != b.Resource {
+		return a.Resource < b.Resource
+	}
+	if a.Subresource != b.Subresource {
+		return a.Subresource < b.Subresource
+	}
+
+	return a.Verb < b.Verb
+}
+
+// Sort sorts the permissions by namespace, group, resource, subresource and verb.
+func Sort(perms []Permission) {
+	sort.Slice(perms, func(i, j int) bool { return less(&perms[i], &perms[j]) })
+}
+
+// Resolve returns the permissions with the namespace placeholders replaced by the namespaces in the
+// configuration, without duplicates and sorted.
+func Resolve(perms []Permission, targetNamespaces []string, probeNamespace string) []Permission {
+	resolved := []Permission{}
+	seen := map[Permission]bool{}
+	add := func(p Permission) {
+		if !seen[p] {
+			seen[p] = true
+			resolved = append(resolved, p)
+		}
+	}
+
+	for _, p := range perms {
+		switch p.Namespace {
+		case TargetNamespaces:
+			for _, ns := range targetNamespaces {
+				p.Namespace = ns
+				add(p)
+			}
+		case ProbeNamespace:
+			p.Namespace = probeNamespace
+			add(p)
+		default:
+			add(p)
+		}
+	}
+
+	Sort(resolved)
+	return resolved
+}
+
+// Join returns the permissions separated by commas.
+func Join(perms []Permission) string {
+	strs := []string{}
+	for _, p := range perms {
+		strs = append(strs, p.String())
+	}
+
+	return strings.Join(strs, ", ")
+}
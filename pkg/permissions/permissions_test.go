@@ -0,0 +1,48 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package permissions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionString(t *testing.T) {
+	assert.Equal(t, "list nodes", ListNodes.String())
+	assert.Equal(t, "list storageclasses.storage.k8s.io", ListStorageClasses.String())
+
+	p := ExecProbePods
+	p.Namespace = "certsuite"
+	assert.Equal(t, "create pods/exec in namespace certsuite", p.String())
+	assert.Equal(t, "pods/exec", p.GetResource())
+	assert.Equal(t, "list nodes, create pods/exec in namespace certsuite", Join([]Permission{ListNodes, p}))
+}
+
+func TestResolve(t *testing.T) {
+	perms := []Permission{ListPods, ListProbePods, ListNodes, ListPods, GetIstioDeployments}
+	resolved := Resolve(perms, []string{"tnf", "app"}, "certsuite")
+
+	assert.Equal(t, []Permission{
+		{Verb: "list", Resource: "nodes"},
+		{Verb: "list", Resource: "pods", Namespace: "app"},
+		{Verb: "list", Resource: "pods", Namespace: "certsuite"},
+		{Verb: "get", Group: "apps", Resource: "deployments", Namespace: "istio-system"},
+		{Verb: "list", Resource: "pods", Namespace: "tnf"},
+	}, resolved)
+
+	assert.Empty(t, Resolve([]Permission{ListPods}, nil, "certsuite"))
+}
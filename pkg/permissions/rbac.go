@@ -0,0 +1,118 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package permissions
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Returns one rule per resource, with the verbs the permissions need on it.
+func getRules(perms []Permission) []rbacv1.PolicyRule {
+	type resourceKey struct{ group, resource string }
+	verbs := map[resourceKey][]string{}
+	keys := []resourceKey{}
+	for _, p := range perms {
+		resource := p.Resource
+		if p.Subresource != "" {
+			resource += "/" + p.Subresource
+		}
+
+		key := resourceKey{group: p.Group, resource: resource}
+		if _, exists := verbs[key]; !exists {
+			keys = append(keys, key)
+		}
+		verbs[key] = append(verbs[key], p.Verb)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].group != keys[j].group {
+			return keys[i].group < keys[j].group
+		}
+		return keys[i].resource < keys[j].resource
+	})
+
+	rules := []rbacv1.PolicyRule{}
+	for _, key := range keys {
+		sort.Strings(verbs[key])
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{key.group},
+			Resources: []string{key.resource},
+			Verbs:     verbs[key],
+		})
+	}
+
+	return rules
+}
+
+// GenerateRBAC returns the YAML of the ClusterRole and Roles, all with the given name, that grant the
+// permissions, whose namespaces must be resolved: the cluster scoped permissions go to the ClusterRole
+// and the rest to a Role in their namespace.
+func GenerateRBAC(name string, perms []Permission) ([]byte, error) {
+	clusterPerms := []Permission{}
+	namespacePerms := map[string][]Permission{}
+	namespaces := []string{}
+	for _, p := range perms {
+		if p.Namespace == "" {
+			clusterPerms = append(clusterPerms, p)
+			continue
+		}
+
+		if _, exists := namespacePerms[p.Namespace]; !exists {
+			namespaces = append(namespaces, p.Namespace)
+		}
+		namespacePerms[p.Namespace] = append(namespacePerms[p.Namespace], p)
+	}
+	sort.Strings(namespaces)
+
+	objects := []interface{}{}
+	if len(clusterPerms) > 0 {
+		objects = append(objects, rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Rules:      getRules(clusterPerms),
+		})
+	}
+
+	for _, ns := range namespaces {
+		objects = append(objects, rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			Rules:      getRules(namespacePerms[ns]),
+		})
+	}
+
+	var out bytes.Buffer
+	for i, object := range objects {
+		objectYAML, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the RBAC objects: %v", err)
+		}
+
+		if i > 0 {
+			out.WriteString("---\n")
+		}
+		out.Write(objectYAML)
+	}
+
+	return out.Bytes(), nil
+}
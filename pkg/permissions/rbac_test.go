@@ -0,0 +1,41 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package permissions
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateRBAC(t *testing.T) {
+	perms := Resolve([]Permission{
+		ListNodes, GetNodes, ListStorageClasses, ListPods, WatchPods, DeletePods, ExecProbePods, ListProbePods,
+		GetDeployments, UpdateDeployments,
+	}, []string{"tnf"}, "certsuite")
+
+	rbac, err := GenerateRBAC("certsuite", perms)
+	assert.Nil(t, err)
+
+	expected, err := os.ReadFile("testdata/rbac.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(rbac))
+
+	rbac, err = GenerateRBAC("certsuite", nil)
+	assert.Nil(t, err)
+	assert.Empty(t, rbac)
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package permissions
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const wildcard = "*"
+
+// Reviewer finds out whether the user the test suite runs as has the permissions, caching the answers.
+// The rules of every namespace are fetched once with a SelfSubjectRulesReview, and a SelfSubjectAccessReview
+// is used for the cluster scoped permissions and for the ones the rules don't allow, as the rules
+// review may be incomplete.
+type Reviewer struct {
+	client authorizationv1client.AuthorizationV1Interface
+
+	mutex   sync.Mutex
+	rules   map[string][]authorizationv1.ResourceRule
+	allowed map[Permission]bool
+}
+
+// NewReviewer returns a reviewer that uses the given client.
+func NewReviewer(client authorizationv1client.AuthorizationV1Interface) *Reviewer {
+	return &Reviewer{
+		client:  client,
+		rules:   map[string][]authorizationv1.ResourceRule{},
+		allowed: map[Permission]bool{},
+	}
+}
+
+// IsAllowed returns whether the permission, whose namespace must be resolved, is granted.
+func (r *Reviewer) IsAllowed(p Permission) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if allowed, exists := r.allowed[p]; exists {
+		return allowed, nil
+	}
+
+	allowed := false
+	if p.Namespace != "" {
+		rules, err := r.getRules(p.Namespace)
+		if err != nil {
+			return false, err
+		}
+		allowed = rulesAllow(rules, &p)
+	}
+
+	if !allowed {
+		var err error
+		allowed, err = r.accessReview(&p)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	r.allowed[p] = allowed
+	return allowed, nil
+}
+
+// GetMissing returns the permissions, whose namespaces must be resolved, that are not granted.
+func (r *Reviewer) GetMissing(perms []Permission) ([]Permission, error) {
+	missing := []Permission{}
+	for _, p := range perms {
+		allowed, err := r.IsAllowed(p)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			missing = append(missing, p)
+		}
+	}
+
+	return missing, nil
+}
+
+func (r *Reviewer) getRules(namespace string) ([]authorizationv1.ResourceRule, error) {
+	if rules, exists := r.rules[namespace]; exists {
+		return rules, nil
+	}
+
+	review, err := r.client.SelfSubjectRulesReviews().Create(context.TODO(), &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to review the rules of namespace %s: %v", namespace, err)
+	}
+
+	r.rules[namespace] = review.Status.ResourceRules
+	return review.Status.ResourceRules, nil
+}
+
+func (r *Reviewer) accessReview(p *Permission) (bool, error) {
+	review, err := r.client.SelfSubjectAccessReviews().Create(context.TODO(), &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   p.Namespace,
+				Verb:        p.Verb,
+				Group:       p.Group,
+				Resource:    p.Resource,
+				Subresource: p.Subresource,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to review permission %q: %v", p, err)
+	}
+
+	return review.Status.Allowed, nil
+}
+
+func matches(values []string, value string) bool {
+	for _, v := range values {
+		if v == wildcard || v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns whether any of the rules grants the permission. The rules restricted to some resource
+// names are ignored.
+func rulesAllow(rules []authorizationv1.ResourceRule, p *Permission) bool {
+	resource := p.Resource
+	if p.Subresource != "" {
+		resource += "/" + p.Subresource
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if len(rule.ResourceNames) > 0 || !matches(rule.Verbs, p.Verb) || !matches(rule.APIGroups, p.Group) {
+			continue
+		}
+
+		for _, r := range rule.Resources {
+			if r == wildcard || r == resource || (p.Subresource != "" && r == wildcard+"/"+p.Subresource) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package permissions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRulesAllow(t *testing.T) {
+	rules := []authorizationv1.ResourceRule{
+		{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+		{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"*/exec"}},
+		{Verbs: []string{"*"}, APIGroups: []string{"apps"}, Resources: []string{"*"}},
+		{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"helm"}},
+	}
+
+	testCases := []struct {
+		permission Permission
+		expected   bool
+	}{
+		{permission: Permission{Verb: "list", Resource: "pods"}, expected: true},
+		{permission: Permission{Verb: "delete", Resource: "pods"}, expected: false},
+		{permission: Permission{Verb: "create", Resource: "pods", Subresource: "exec"}, expected: true},
+		{permission: Permission{Verb: "get", Resource: "pods", Subresource: "log"}, expected: false},
+		{permission: Permission{Verb: "update", Group: "apps", Resource: "statefulsets"}, expected: true},
+		{permission: Permission{Verb: "list", Group: "policy", Resource: "poddisruptionbudgets"}, expected: false},
+		{permission: Permission{Verb: "get", Resource: "secrets"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, rulesAllow(rules, &tc.permission), tc.permission.String())
+	}
+}
+
+func TestReviewerGetMissing(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+	rulesReviews, accessReviews := 0, 0
+	client.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		rulesReviews++
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectRulesReview)
+		if review.Spec.Namespace == "tnf" {
+			review.Status.ResourceRules = []authorizationv1.ResourceRule{
+				{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			}
+		}
+		return true, review, nil
+	})
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		accessReviews++
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Resource == "nodes"
+		return true, review, nil
+	})
+
+	reviewer := NewReviewer(client.AuthorizationV1())
+	perms := Resolve([]Permission{ListNodes, ListNamespaces, ListPods, ListEvents}, []string{"tnf"}, "certsuite")
+
+	missing, err := reviewer.GetMissing(perms)
+	assert.Nil(t, err)
+	assert.Equal(t, []Permission{
+		{Verb: "list", Resource: "namespaces"},
+		{Verb: "list", Resource: "events", Namespace: "tnf"},
+	}, missing)
+	assert.Equal(t, 1, rulesReviews)
+	assert.Equal(t, 3, accessReviews)
+
+	// The answers are cached.
+	_, err = reviewer.GetMissing(perms)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, rulesReviews)
+	assert.Equal(t, 3, accessReviews)
+}
+
+func TestReviewerError(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+
+	_, err := NewReviewer(client.AuthorizationV1()).GetMissing([]Permission{ListNodes})
+	assert.Equal(t, `failed to review permission "list nodes": forbidden`, err.Error())
+}
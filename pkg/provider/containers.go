@@ -18,7 +18,6 @@ package provider
 
 import (
 	"bytes"
-	"context"
 	"errors"
 	"fmt"
 	defaultLog "log"
@@ -27,6 +26,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/go-logr/stdr"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/tracing"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/redhat-openshift-ecosystem/openshift-preflight/artifacts"
@@ -104,12 +104,21 @@ func (c *Container) SetPreflightResults(preflightImageCache map[string]Preflight
 		opts = append(opts, plibContainer.WithInsecureConnection())
 	}
 
+	ctx, span := tracing.Start(tracing.CurrentContext(), "preflight.Container",
+		tracing.AttrImage.String(c.Image),
+		tracing.AttrNamespace.String(c.Namespace),
+		tracing.AttrPod.String(c.Podname),
+		tracing.AttrContainer.String(c.Name),
+		tracing.AttrNode.String(c.NodeName),
+	)
+	defer span.End()
+
 	// Create artifacts handler
 	artifactsWriter, err := artifacts.NewMapWriter()
 	if err != nil {
 		return err
 	}
-	ctx := artifacts.ContextWithWriter(context.TODO(), artifactsWriter)
+	ctx = artifacts.ContextWithWriter(ctx, artifactsWriter)
 
 	// Add logger output to the context
 	logbytes := bytes.NewBuffer([]byte{})
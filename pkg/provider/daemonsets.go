@@ -0,0 +1,58 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package provider
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+type DaemonSet struct {
+	*appsv1.DaemonSet
+}
+
+func (ds *DaemonSet) IsDaemonSetReady() bool {
+	desired := ds.Status.DesiredNumberScheduled
+	return ds.Status.NumberReady == desired &&
+		ds.Status.UpdatedNumberScheduled == desired &&
+		ds.Status.NumberUnavailable == 0
+}
+
+// GetMaxUnavailable returns the number of pods that can be unavailable during a rolling update,
+// scaled against the number of nodes that should run the daemonset pod, as done by the daemonset
+// controller. It must only be called for the RollingUpdate strategy.
+func (ds *DaemonSet) GetMaxUnavailable() (int, error) {
+	maxUnavailable := intstr.FromInt32(1)
+	if ds.Spec.UpdateStrategy.RollingUpdate != nil && ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable != nil {
+		maxUnavailable = *ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable
+	}
+
+	value, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, int(ds.Status.DesiredNumberScheduled), true)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maxUnavailable of %s: %v", ds.ToString(), err)
+	}
+
+	return value, nil
+}
+
+func (ds *DaemonSet) ToString() string {
+	return fmt.Sprintf("daemonset: %s ns: %s",
+		ds.Name,
+		ds.Namespace,
+	)
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDaemonSetToString(t *testing.T) {
+	ds := DaemonSet{
+		DaemonSet: &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test1",
+				Namespace: "testNS",
+			},
+		},
+	}
+
+	assert.Equal(t, "daemonset: test1 ns: testNS", ds.ToString())
+}
+
+func TestIsDaemonSetReady(t *testing.T) {
+	testCases := []struct {
+		status         appsv1.DaemonSetStatus
+		expectedOutput bool
+	}{
+		{
+			status:         appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 3},
+			expectedOutput: true,
+		},
+		{
+			status:         appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 2, UpdatedNumberScheduled: 3, NumberUnavailable: 1},
+			expectedOutput: false,
+		},
+		{
+			status:         appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 1},
+			expectedOutput: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		ds := DaemonSet{DaemonSet: &appsv1.DaemonSet{Status: tc.status}}
+		assert.Equal(t, tc.expectedOutput, ds.IsDaemonSetReady())
+	}
+}
+
+func TestGetMaxUnavailable(t *testing.T) {
+	generateDS := func(maxUnavailable *intstr.IntOrString, desired int32) *DaemonSet {
+		return &DaemonSet{
+			DaemonSet: &appsv1.DaemonSet{
+				Spec: appsv1.DaemonSetSpec{
+					UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+						Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+						RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: maxUnavailable},
+					},
+				},
+				Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: desired},
+			},
+		}
+	}
+
+	toIntOrStringPtr := func(value intstr.IntOrString) *intstr.IntOrString {
+		return &value
+	}
+
+	testCases := []struct {
+		testDS         *DaemonSet
+		expectedOutput int
+		expectedError  bool
+	}{
+		{testDS: generateDS(nil, 10), expectedOutput: 1},
+		{testDS: generateDS(toIntOrStringPtr(intstr.FromInt32(3)), 10), expectedOutput: 3},
+		{testDS: generateDS(toIntOrStringPtr(intstr.FromString("25%")), 10), expectedOutput: 3},
+		{testDS: generateDS(toIntOrStringPtr(intstr.FromString("100%")), 4), expectedOutput: 4},
+		{testDS: generateDS(toIntOrStringPtr(intstr.FromString("bad")), 4), expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		maxUnavailable, err := tc.testDS.GetMaxUnavailable()
+		assert.Equal(t, tc.expectedError, err != nil)
+		assert.Equal(t, tc.expectedOutput, maxUnavailable)
+	}
+}
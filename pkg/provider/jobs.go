@@ -0,0 +1,45 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package provider
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// Job is a job under test that is not managed by a cronjob.
+type Job struct {
+	*batchv1.Job
+}
+
+func (j *Job) ToString() string {
+	return fmt.Sprintf("job: %s ns: %s",
+		j.Name,
+		j.Namespace,
+	)
+}
+
+type CronJob struct {
+	*batchv1.CronJob
+}
+
+func (cj *CronJob) ToString() string {
+	return fmt.Sprintf("cronjob: %s ns: %s",
+		cj.Name,
+		cj.Namespace,
+	)
+}
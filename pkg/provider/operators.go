@@ -31,6 +31,7 @@ import (
 	olmv1Alpha "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/tracing"
 	"github.com/redhat-openshift-ecosystem/openshift-preflight/artifacts"
 	plibRuntime "github.com/redhat-openshift-ecosystem/openshift-preflight/certification"
 	plibOperator "github.com/redhat-openshift-ecosystem/openshift-preflight/operator"
@@ -78,12 +79,18 @@ func (op *Operator) SetPreflightResults(env *TestEnvironment) error {
 	indexImage := op.InstallPlans[0].IndexImage
 	oc := clientsholder.GetClientsHolder()
 
+	ctx, span := tracing.Start(tracing.CurrentContext(), "preflight.Operator",
+		tracing.AttrImage.String(bundleImage),
+		tracing.AttrNamespace.String(op.Namespace),
+	)
+	defer span.End()
+
 	// Create artifacts handler
 	artifactsWriter, err := artifacts.NewMapWriter()
 	if err != nil {
 		return err
 	}
-	ctx := artifacts.ContextWithWriter(context.TODO(), artifactsWriter)
+	ctx = artifacts.ContextWithWriter(ctx, artifactsWriter)
 	opts := []plibOperator.Option{}
 	opts = append(opts, plibOperator.WithDockerConfigJSONFromFile(env.GetDockerConfigFile()))
 	if env.IsPreflightInsecureAllowed() {
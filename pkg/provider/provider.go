@@ -19,6 +19,7 @@ package provider
 import (
 	"context"
 	"regexp"
+	"sync"
 	"time"
 
 	"fmt"
@@ -32,6 +33,7 @@ import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
 	k8sPrivilegedDs "github.com/redhat-best-practices-for-k8s/privileged-daemonset"
 	plibRuntime "github.com/redhat-openshift-ecosystem/openshift-preflight/certification"
 	"helm.sh/helm/v3/pkg/release"
@@ -67,6 +69,15 @@ var (
 	MasterLabels = []string{"node-role.kubernetes.io/master", "node-role.kubernetes.io/control-plane"}
 )
 
+// ProbePermissions are the permissions needed to deploy the probe daemonset and to find its pods.
+var ProbePermissions = []permissions.Permission{
+	permissions.GetNamespaces,
+	permissions.CreateNamespaces,
+	permissions.GetProbeDaemonSets,
+	permissions.CreateProbeDaemonSets,
+	permissions.ListProbePods,
+}
+
 type TestEnvironment struct { // rename this with testTarget
 	Namespaces     []string `json:"testNamespaces"`
 	AbnormalEvents []*Event
@@ -81,6 +92,11 @@ type TestEnvironment struct { // rename this with testTarget
 	Deployments []*Deployment `json:"testDeployments"`
 	// StatefulSet Groupings
 	StatefulSets []*StatefulSet `json:"testStatefulSets"`
+	// DaemonSet, bare ReplicaSet, Job and CronJob Groupings
+	DaemonSets  []*DaemonSet  `json:"testDaemonSets"`
+	ReplicaSets []*ReplicaSet `json:"testReplicaSets"`
+	Jobs        []*Job        `json:"testJobs"`
+	CronJobs    []*CronJob    `json:"testCronJobs"`
 
 	// Note: Containers is a filtered list of objects based on a block list of disallowed container names.
 	Containers             []*Container `json:"testContainers"`
@@ -120,6 +136,8 @@ type TestEnvironment struct { // rename this with testTarget
 	CollectorAppPassword   string
 	CollectorAppEndpoint   string
 	SkipPreflight          bool
+	// Outcome of the discovery collectors. The checks that need the objects of a failed one are skipped.
+	DiscoveryReport autodiscover.DiscoveryReport `json:"discoveryReport"`
 }
 
 type MachineConfig struct {
@@ -173,10 +191,52 @@ type PreflightResultsDB struct {
 var (
 	env    = TestEnvironment{}
 	loaded = false
+	// Checks running concurrently may need to refresh the test environment at the same time.
+	envMutex sync.Mutex
+
+	// Config and discovered data the test environment was built from.
+	discoveredConfig configuration.TestConfiguration
+	discoveredData   autodiscover.DiscoveredTestData
+	// Set when the test environment must be built from previously discovered data, e.g. from a
+	// cluster snapshot, instead of running the autodiscovery.
+	offlineMode bool
 )
 
+// SetOfflineTestData makes the test environment to be built from the given config and discovered
+// data instead of loading the config file and running the autodiscovery against the cluster.
+func SetOfflineTestData(config *configuration.TestConfiguration, data *autodiscover.DiscoveredTestData) {
+	envMutex.Lock()
+	defer envMutex.Unlock()
+
+	discoveredConfig = *config
+	discoveredData = *data
+	offlineMode = true
+	loaded = false
+}
+
+// IsOfflineMode returns true if the test environment is built from previously discovered data.
+func IsOfflineMode() bool {
+	return offlineMode
+}
+
+// GetDiscoveredTestData returns the config and the discovered data the test environment was built from.
+func GetDiscoveredTestData() (configuration.TestConfiguration, autodiscover.DiscoveredTestData) {
+	envMutex.Lock()
+	defer envMutex.Unlock()
+
+	return discoveredConfig, discoveredData
+}
+
 func deployDaemonSet(namespace string) error {
-	k8sPrivilegedDs.SetDaemonSetClient(clientsholder.GetClientsHolder().K8sClient)
+	client := clientsholder.GetClientsHolder().K8sClient
+	missing, err := permissions.NewReviewer(client.AuthorizationV1()).GetMissing(permissions.Resolve(ProbePermissions, nil, namespace))
+	if err != nil {
+		log.Warn("Could not review the permissions of the tnf daemonset: %v", err)
+	} else if len(missing) > 0 {
+		return fmt.Errorf("missing permissions: %s", permissions.Join(missing))
+	}
+
+	k8sPrivilegedDs.SetDaemonSetClient(client)
 
 	dsImage := env.params.TnfImageRepo + "/" + env.params.TnfDebugImage
 	if k8sPrivilegedDs.IsDaemonSetReady(DaemonSetName, namespace, dsImage) {
@@ -186,7 +246,7 @@ func deployDaemonSet(namespace string) error {
 	matchLabels := make(map[string]string)
 	matchLabels["name"] = DaemonSetName
 	matchLabels["redhat-best-practices-for-k8s.com/app"] = DaemonSetName
-	_, err := k8sPrivilegedDs.CreateDaemonSet(DaemonSetName, namespace, containerName, dsImage, matchLabels, debugPodsTimeout,
+	_, err = k8sPrivilegedDs.CreateDaemonSet(DaemonSetName, namespace, containerName, dsImage, matchLabels, debugPodsTimeout,
 		configuration.GetTestParameters().DaemonsetCPUReq,
 		configuration.GetTestParameters().DaemonsetCPULim,
 		configuration.GetTestParameters().DaemonsetMemReq,
@@ -208,21 +268,31 @@ func buildTestEnvironment() { //nolint:funlen
 	env = TestEnvironment{}
 
 	env.params = *configuration.GetTestParameters()
-	config, err := configuration.LoadConfiguration(env.params.ConfigFile)
-	if err != nil {
-		log.Fatal("Cannot load configuration file: %v", err)
-	}
-	log.Debug("CERTSUITE configuration: %+v", config)
+	config, data := discoveredConfig, discoveredData
+	if offlineMode {
+		log.Info("Building the test environment from previously discovered data")
+		// The debug pods are only available if they were running when the data was discovered.
+		env.DaemonsetFailedToSpawn = len(data.DebugPods) == 0
+	} else {
+		var err error
+		config, err = configuration.LoadConfiguration(env.params.ConfigFile)
+		if err != nil {
+			log.Fatal("Cannot load configuration file: %v", err)
+		}
+		log.Debug("CERTSUITE configuration: %+v", config)
+
+		// Wait for the debug pods to be ready before the autodiscovery starts.
+		if err := deployDaemonSet(config.DebugDaemonSetNamespace); err != nil {
+			log.Error("The TNF daemonset could not be deployed, err: %v", err)
+			// Because of this failure, we are only able to run a certain amount of tests that do not rely
+			// on the existence of the daemonset debug pods.
+			env.DaemonsetFailedToSpawn = true
+		}
 
-	// Wait for the debug pods to be ready before the autodiscovery starts.
-	if err := deployDaemonSet(config.DebugDaemonSetNamespace); err != nil {
-		log.Error("The TNF daemonset could not be deployed, err: %v", err)
-		// Because of this failure, we are only able to run a certain amount of tests that do not rely
-		// on the existence of the daemonset debug pods.
-		env.DaemonsetFailedToSpawn = true
+		data = autodiscover.DoAutoDiscover(&config)
+		discoveredConfig, discoveredData = config, data
 	}
 
-	data := autodiscover.DoAutoDiscover(&config)
 	// OpenshiftVersion needs to be set asap, as other helper functions will use it here.
 	env.OpenshiftVersion = data.OpenshiftVersion
 	env.Config = config
@@ -298,6 +368,18 @@ func buildTestEnvironment() { //nolint:funlen
 		}
 		env.StatefulSets = append(env.StatefulSets, aNewStatefulSet)
 	}
+	for i := range data.DaemonSets {
+		env.DaemonSets = append(env.DaemonSets, &DaemonSet{&data.DaemonSets[i]})
+	}
+	for i := range data.ReplicaSets {
+		env.ReplicaSets = append(env.ReplicaSets, &ReplicaSet{&data.ReplicaSets[i]})
+	}
+	for i := range data.Jobs {
+		env.Jobs = append(env.Jobs, &Job{&data.Jobs[i]})
+	}
+	for i := range data.CronJobs {
+		env.CronJobs = append(env.CronJobs, &CronJob{&data.CronJobs[i]})
+	}
 
 	env.ScaleCrUnderTest = updateCrUnderTest(data.ScaleCrUnderTest)
 	env.HorizontalScaler = data.Hpas
@@ -307,6 +389,10 @@ func buildTestEnvironment() { //nolint:funlen
 	env.PartnerName = data.PartnerName
 	env.CollectorAppPassword = data.CollectorAppPassword
 	env.CollectorAppEndpoint = data.CollectorAppEndpoint
+	env.DiscoveryReport = data.DiscoveryReport
+	for _, collector := range env.DiscoveryReport.FailedCollectors() {
+		log.Warn("Discovery of %s failed, the checks that need it will be skipped: %s", collector.Name, collector.Error)
+	}
 
 	operators := createOperators(data.Csvs, data.Subscriptions, data.AllInstallPlans, data.AllCatalogSources, false, true)
 	env.Operators = operators
@@ -397,6 +483,9 @@ func isSkipHelmChart(helmName string, skipHelmChartList []configuration.SkipHelm
 }
 
 func GetTestEnvironment() TestEnvironment {
+	envMutex.Lock()
+	defer envMutex.Unlock()
+
 	if !loaded {
 		buildTestEnvironment()
 		loaded = true
@@ -492,6 +581,9 @@ func GetPciPerPod(annotation string) (pciAddr []string, err error) {
 }
 
 func (env *TestEnvironment) SetNeedsRefresh() {
+	envMutex.Lock()
+	defer envMutex.Unlock()
+
 	loaded = false
 }
 
@@ -535,6 +627,29 @@ func (env *TestEnvironment) IsSNO() bool {
 	return len(env.Nodes) == 1
 }
 
+// GetDiscoveredObjectsCounts returns the number of objects under test found by the autodiscovery, by kind.
+func (env *TestEnvironment) GetDiscoveredObjectsCounts() map[string]int {
+	return map[string]int{
+		"namespaces":                 len(env.Namespaces),
+		"pods":                       len(env.Pods),
+		"containers":                 len(env.Containers),
+		"deployments":                len(env.Deployments),
+		"statefulsets":               len(env.StatefulSets),
+		"daemonsets":                 len(env.DaemonSets),
+		"replicasets":                len(env.ReplicaSets),
+		"jobs":                       len(env.Jobs),
+		"cronjobs":                   len(env.CronJobs),
+		"operators":                  len(env.Operators),
+		"helm_chart_releases":        len(env.HelmChartReleases),
+		"crds":                       len(env.Crds),
+		"services":                   len(env.Services),
+		"horizontal_pod_autoscalers": len(env.HorizontalScaler),
+		"persistent_volumes":         len(env.PersistentVolumes),
+		"persistent_volume_claims":   len(env.PersistentVolumeClaims),
+		"nodes":                      len(env.Nodes),
+	}
+}
+
 func getMachineConfig(mcName string, machineConfigs map[string]MachineConfig) (MachineConfig, error) {
 	client := clientsholder.GetClientsHolder()
 
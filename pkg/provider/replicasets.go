@@ -0,0 +1,90 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Kinds of the replicated pod sets.
+const (
+	DeploymentKind  = "Deployment"
+	StatefulSetKind = "StatefulSet"
+	ReplicaSetKind  = "ReplicaSet"
+)
+
+// ReplicaSet is a replicaset under test that is not managed by a deployment.
+type ReplicaSet struct {
+	*appsv1.ReplicaSet
+}
+
+func (rs *ReplicaSet) IsReplicaSetReady() bool {
+	var replicas int32
+	if rs.Spec.Replicas != nil {
+		replicas = *(rs.Spec.Replicas)
+	} else {
+		replicas = 1
+	}
+	return rs.Status.ReadyReplicas == replicas && rs.Status.AvailableReplicas == replicas
+}
+
+func (rs *ReplicaSet) ToString() string {
+	return fmt.Sprintf("replicaset: %s ns: %s",
+		rs.Name,
+		rs.Namespace,
+	)
+}
+
+// ReplicatedPodSet holds the fields shared by the deployments, statefulsets and bare replicasets under
+// test, whose pods are created from a template with a number of replicas.
+type ReplicatedPodSet struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Replicas  *int32
+	Template  *corev1.PodTemplateSpec
+}
+
+func (ps *ReplicatedPodSet) ToString() string {
+	return fmt.Sprintf("%s: %s ns: %s",
+		strings.ToLower(ps.Kind),
+		ps.Name,
+		ps.Namespace,
+	)
+}
+
+// GetReplicatedPodSets returns the deployments, statefulsets and bare replicasets under test, in that order.
+func (env *TestEnvironment) GetReplicatedPodSets() []*ReplicatedPodSet {
+	podSets := make([]*ReplicatedPodSet, 0, len(env.Deployments)+len(env.StatefulSets)+len(env.ReplicaSets))
+	for _, d := range env.Deployments {
+		podSets = append(podSets, &ReplicatedPodSet{Kind: DeploymentKind, Namespace: d.Namespace, Name: d.Name,
+			Replicas: d.Spec.Replicas, Template: &d.Spec.Template})
+	}
+	for _, s := range env.StatefulSets {
+		podSets = append(podSets, &ReplicatedPodSet{Kind: StatefulSetKind, Namespace: s.Namespace, Name: s.Name,
+			Replicas: s.Spec.Replicas, Template: &s.Spec.Template})
+	}
+	for _, rs := range env.ReplicaSets {
+		podSets = append(podSets, &ReplicatedPodSet{Kind: ReplicaSetKind, Namespace: rs.Namespace, Name: rs.Name,
+			Replicas: rs.Spec.Replicas, Template: &rs.Spec.Template})
+	}
+
+	return podSets
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReplicaSetToString(t *testing.T) {
+	rs := ReplicaSet{
+		ReplicaSet: &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test1",
+				Namespace: "testNS",
+			},
+		},
+	}
+
+	assert.Equal(t, "replicaset: test1 ns: testNS", rs.ToString())
+}
+
+func TestIsReplicaSetReady(t *testing.T) {
+	replicas := int32(2)
+	testCases := []struct {
+		specReplicas   *int32
+		status         appsv1.ReplicaSetStatus
+		expectedOutput bool
+	}{
+		{specReplicas: &replicas, status: appsv1.ReplicaSetStatus{ReadyReplicas: 2, AvailableReplicas: 2}, expectedOutput: true},
+		{specReplicas: &replicas, status: appsv1.ReplicaSetStatus{ReadyReplicas: 2, AvailableReplicas: 1}, expectedOutput: false},
+		{specReplicas: nil, status: appsv1.ReplicaSetStatus{ReadyReplicas: 1, AvailableReplicas: 1}, expectedOutput: true},
+	}
+
+	for _, tc := range testCases {
+		rs := ReplicaSet{ReplicaSet: &appsv1.ReplicaSet{Spec: appsv1.ReplicaSetSpec{Replicas: tc.specReplicas}, Status: tc.status}}
+		assert.Equal(t, tc.expectedOutput, rs.IsReplicaSetReady())
+	}
+}
+
+func TestGetReplicatedPodSets(t *testing.T) {
+	replicas := int32(3)
+	template := corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}}}
+	env := TestEnvironment{
+		Deployments: []*Deployment{{Deployment: &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "dp1", Namespace: "ns1"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas, Template: template},
+		}}},
+		StatefulSets: []*StatefulSet{{StatefulSet: &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "ss1", Namespace: "ns1"},
+			Spec:       appsv1.StatefulSetSpec{Template: template},
+		}}},
+		ReplicaSets: []*ReplicaSet{{ReplicaSet: &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "rs1", Namespace: "ns2"},
+			Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas, Template: template},
+		}}},
+	}
+
+	podSets := env.GetReplicatedPodSets()
+	assert.Equal(t, []*ReplicatedPodSet{
+		{Kind: DeploymentKind, Namespace: "ns1", Name: "dp1", Replicas: &replicas, Template: &env.Deployments[0].Spec.Template},
+		{Kind: StatefulSetKind, Namespace: "ns1", Name: "ss1", Template: &env.StatefulSets[0].Spec.Template},
+		{Kind: ReplicaSetKind, Namespace: "ns2", Name: "rs1", Replicas: &replicas, Template: &env.ReplicaSets[0].Spec.Template},
+	}, podSets)
+	assert.Equal(t, "statefulset: ss1 ns: ns1", podSets[1].ToString())
+
+	assert.Empty(t, (&TestEnvironment{}).GetReplicatedPodSets())
+}
@@ -17,6 +17,7 @@
 package scheduling
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -83,11 +84,11 @@ var schedulingRequirements = map[string]string{SharedCPUScheduling: "SHARED_CPU_
 	ExclusiveCPUScheduling: "EXCLUSIVE_CPU_SCHEDULING: scheduling priority < 10 and scheduling policy == SCHED_RR or SCHED_FIFO",
 	IsolatedCPUScheduling:  "ISOLATED_CPU_SCHEDULING: scheduling policy == SCHED_RR or SCHED_FIFO"}
 
-func ProcessPidsCPUScheduling(processes []*crclient.Process, testContainer *provider.Container, check string, logger *log.Logger) (compliantContainerPids, nonCompliantContainerPids []*testhelper.ReportObject) {
+func ProcessPidsCPUScheduling(ctx context.Context, processes []*crclient.Process, testContainer *provider.Container, check string, logger *log.Logger) (compliantContainerPids, nonCompliantContainerPids []*testhelper.ReportObject) {
 	hasCPUSchedulingConditionSuccess := false
 	for _, process := range processes {
 		logger.Debug("Testing process %q", process)
-		schedulePolicy, schedulePriority, err := GetProcessCPUSchedulingFn(process.Pid, testContainer)
+		schedulePolicy, schedulePriority, err := GetProcessCPUSchedulingFn(ctx, process.Pid, testContainer)
 		if err != nil {
 			logger.Error("Unable to get the scheduling policy and priority : %v", err)
 			return compliantContainerPids, nonCompliantContainerPids
@@ -117,22 +118,22 @@ func ProcessPidsCPUScheduling(processes []*crclient.Process, testContainer *prov
 	return compliantContainerPids, nonCompliantContainerPids
 }
 
-func GetProcessCPUScheduling(pid int, testContainer *provider.Container) (schedulePolicy string, schedulePriority int, err error) {
+func GetProcessCPUScheduling(ctx context.Context, pid int, testContainer *provider.Container) (schedulePolicy string, schedulePriority int, err error) {
 	log.Info("Checking the scheduling policy/priority in %v for pid=%d", testContainer, pid)
 
 	command := fmt.Sprintf("chrt -p %d", pid)
 	env := provider.GetTestEnvironment()
-	ctx, err := crclient.GetNodeDebugPodContext(testContainer.NodeName, &env)
+	debugPodCtx, err := crclient.GetNodeDebugPodContext(ctx, testContainer.NodeName, &env)
 	if err != nil {
 		return "", 0, fmt.Errorf("failed to get debug pod's context for container %s: %v", testContainer, err)
 	}
 
 	ch := clientsholder.GetClientsHolder()
 
-	stdout, stderr, err := ch.ExecCommandContainer(ctx, command)
+	stdout, stderr, err := ch.ExecCommandContainer(debugPodCtx, command)
 	if err != nil || stderr != "" {
 		return schedulePolicy, InvalidPriority, fmt.Errorf("command %q failed to run in debug pod %s (node %s): %v (stderr: %v)",
-			command, ctx.GetPodName(), testContainer.NodeName, err, stderr)
+			command, debugPodCtx.GetPodName(), testContainer.NodeName, err, stderr)
 	}
 
 	schedulePolicy, schedulePriority, err = parseSchedulingPolicyAndPriority(stdout)
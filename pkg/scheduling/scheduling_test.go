@@ -17,6 +17,7 @@
 package scheduling
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -39,12 +40,12 @@ func TestProcessPidsCPUScheduling(t *testing.T) {
 	testContainer.Container = &corev1.Container{}
 
 	testCases := []struct {
-		mockGetProcessCPUScheduling func(int, *provider.Container) (string, int, error)
+		mockGetProcessCPUScheduling func(context.Context, int, *provider.Container) (string, int, error)
 		check                       string
 		compliant, nonCompliant     []testhelper.ReportObject
 	}{
 		{
-			mockGetProcessCPUScheduling: func(pid int, container *provider.Container) (string, int, error) {
+			mockGetProcessCPUScheduling: func(_ context.Context, pid int, container *provider.Container) (string, int, error) {
 				return "SCHED_OTHER", 0, nil
 			},
 			check:     SharedCPUScheduling + "1",
@@ -96,7 +97,7 @@ func TestProcessPidsCPUScheduling(t *testing.T) {
 			},
 		},
 		{
-			mockGetProcessCPUScheduling: func(pid int, container *provider.Container) (string, int, error) {
+			mockGetProcessCPUScheduling: func(_ context.Context, pid int, container *provider.Container) (string, int, error) {
 				return "SCHED_RR", 90, nil
 			},
 			check:     SharedCPUScheduling + "2",
@@ -147,7 +148,7 @@ func TestProcessPidsCPUScheduling(t *testing.T) {
 				},
 			}},
 		{
-			mockGetProcessCPUScheduling: func(pid int, container *provider.Container) (string, int, error) {
+			mockGetProcessCPUScheduling: func(_ context.Context, pid int, container *provider.Container) (string, int, error) {
 				return "SCHED_FIFO", 9, nil
 			},
 			check:     ExclusiveCPUScheduling + "1",
@@ -198,7 +199,7 @@ func TestProcessPidsCPUScheduling(t *testing.T) {
 				},
 			}},
 		{
-			mockGetProcessCPUScheduling: func(pid int, container *provider.Container) (string, int, error) {
+			mockGetProcessCPUScheduling: func(_ context.Context, pid int, container *provider.Container) (string, int, error) {
 				return "SCHED_FIFO", 11, nil
 			},
 			check: ExclusiveCPUScheduling + "2",
@@ -249,7 +250,7 @@ func TestProcessPidsCPUScheduling(t *testing.T) {
 
 			compliant: []testhelper.ReportObject{}},
 		{
-			mockGetProcessCPUScheduling: func(pid int, container *provider.Container) (string, int, error) {
+			mockGetProcessCPUScheduling: func(_ context.Context, pid int, container *provider.Container) (string, int, error) {
 				return "SCHED_FIFO", 50, nil
 			},
 			check:     IsolatedCPUScheduling + "1",
@@ -300,7 +301,7 @@ func TestProcessPidsCPUScheduling(t *testing.T) {
 				},
 			}},
 		{
-			mockGetProcessCPUScheduling: func(pid int, container *provider.Container) (string, int, error) {
+			mockGetProcessCPUScheduling: func(_ context.Context, pid int, container *provider.Container) (string, int, error) {
 				return "SCHED_RR", 99, nil
 			},
 			check:     IsolatedCPUScheduling + "2",
@@ -351,7 +352,7 @@ func TestProcessPidsCPUScheduling(t *testing.T) {
 				},
 			}},
 		{
-			mockGetProcessCPUScheduling: func(pid int, container *provider.Container) (string, int, error) {
+			mockGetProcessCPUScheduling: func(_ context.Context, pid int, container *provider.Container) (string, int, error) {
 				return "SCHED_OTHER", 0, nil
 			},
 			check: IsolatedCPUScheduling + "3",
@@ -406,7 +407,7 @@ func TestProcessPidsCPUScheduling(t *testing.T) {
 	log.SetupLogger(&logArchive, "INFO")
 	for _, tc := range testCases {
 		GetProcessCPUSchedulingFn = tc.mockGetProcessCPUScheduling
-		compliant, nonCompliant := ProcessPidsCPUScheduling(testPids, testContainer, tc.check, log.GetLogger())
+		compliant, nonCompliant := ProcessPidsCPUScheduling(context.TODO(), testPids, testContainer, tc.check, log.GetLogger())
 
 		fmt.Printf(
 			"test=%s Actual compliant=%s,\n",
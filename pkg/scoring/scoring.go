@@ -0,0 +1,219 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+)
+
+const (
+	DefaultProfile         = identifiers.Telco
+	DefaultMandatoryWeight = 3.0
+	DefaultOptionalWeight  = 1.0
+
+	statePassed  = "passed"
+	stateSkipped = "skipped"
+)
+
+// Policy is the scoring configuration, with the default values set.
+type Policy struct {
+	Profile         string
+	MandatoryWeight float64
+	OptionalWeight  float64
+	MinScore        float64
+	MinSuiteScores  map[string]float64
+}
+
+// SuiteScore is the readiness score of a test suite.
+type SuiteScore struct {
+	Suite string `json:"suite"`
+	// Percentage of the weight of the evaluated checks that passed. Zero if there's no weight.
+	Score    float64 `json:"score"`
+	MinScore float64 `json:"minScore,omitempty"`
+	Passed   int     `json:"passed"`
+	// Failed, errored and aborted checks.
+	Failed          int `json:"failed"`
+	MandatoryFailed int `json:"mandatoryFailed"`
+	Skipped         int `json:"skipped"`
+	// Sum of the weights of the evaluated checks and of the ones that passed.
+	Weight       float64 `json:"weight"`
+	PassedWeight float64 `json:"passedWeight"`
+	GatePassed   bool    `json:"gatePassed"`
+}
+
+// Score is the weighted readiness score of a run, overall and by test suite.
+type Score struct {
+	Profile         string       `json:"profile"`
+	MandatoryWeight float64      `json:"mandatoryWeight"`
+	OptionalWeight  float64      `json:"optionalWeight"`
+	Score           float64      `json:"score"`
+	MinScore        float64      `json:"minScore,omitempty"`
+	Weight          float64      `json:"weight"`
+	Suites          []SuiteScore `json:"suites"`
+	GatePassed      bool         `json:"gatePassed"`
+	// Reasons why the gate did not pass.
+	GateFailures []string `json:"gateFailures,omitempty"`
+}
+
+// NewPolicy returns the scoring policy of the config, checking its values and setting the default ones.
+func NewPolicy(config *configuration.ScoringConfig) (*Policy, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	policy := Policy{
+		Profile:         config.Profile,
+		MandatoryWeight: DefaultMandatoryWeight,
+		OptionalWeight:  DefaultOptionalWeight,
+		MinScore:        config.MinScore,
+		MinSuiteScores:  map[string]float64{},
+	}
+
+	if policy.Profile == "" {
+		policy.Profile = DefaultProfile
+	}
+
+	if config.MandatoryWeight != nil {
+		policy.MandatoryWeight = *config.MandatoryWeight
+	}
+	if config.OptionalWeight != nil {
+		policy.OptionalWeight = *config.OptionalWeight
+	}
+
+	for _, threshold := range config.MinSuiteScores {
+		policy.MinSuiteScores[threshold.Suite] = threshold.MinScore
+	}
+
+	return &policy, nil
+}
+
+// Returns the classification of the check in the profile: Mandatory or Optional.
+func getClassification(result *claim.Result, profile string) string {
+	if result.CategoryClassification == nil {
+		return identifiers.Optional
+	}
+
+	classification := map[string]string{
+		identifiers.Telco:    result.CategoryClassification.Telco,
+		identifiers.NonTelco: result.CategoryClassification.NonTelco,
+		identifiers.FarEdge:  result.CategoryClassification.FarEdge,
+		identifiers.Extended: result.CategoryClassification.Extended,
+	}[profile]
+
+	if classification == identifiers.Mandatory {
+		return identifiers.Mandatory
+	}
+
+	return identifiers.Optional
+}
+
+func getPercentage(passedWeight, totalWeight float64) float64 {
+	if totalWeight == 0 {
+		return 0
+	}
+
+	// Rounded to one decimal.
+	const decimals = 10
+	return math.Round(passedWeight/totalWeight*configuration.MaxScore*decimals) / decimals
+}
+
+// Compute returns the readiness score of the checks results. Every check that was not skipped adds its weight,
+// that depends on its classification in the policy profile, and the score is the percentage of that weight that
+// passed. The checks that ended with an error or were aborted count as failed.
+func Compute(results map[string]claim.Result, policy *Policy) *Score {
+	suites := map[string]*SuiteScore{}
+	for testID := range results {
+		result := results[testID]
+		if result.TestID == nil {
+			continue
+		}
+
+		suite, exists := suites[result.TestID.Suite]
+		if !exists {
+			suite = &SuiteScore{Suite: result.TestID.Suite}
+			suites[result.TestID.Suite] = suite
+		}
+
+		if result.State == stateSkipped {
+			suite.Skipped++
+			continue
+		}
+
+		classification := getClassification(&result, policy.Profile)
+		weight := policy.OptionalWeight
+		if classification == identifiers.Mandatory {
+			weight = policy.MandatoryWeight
+		}
+
+		suite.Weight += weight
+		if result.State == statePassed {
+			suite.Passed++
+			suite.PassedWeight += weight
+			continue
+		}
+
+		suite.Failed++
+		if classification == identifiers.Mandatory {
+			suite.MandatoryFailed++
+		}
+	}
+
+	score := Score{
+		Profile:         policy.Profile,
+		MandatoryWeight: policy.MandatoryWeight,
+		OptionalWeight:  policy.OptionalWeight,
+		MinScore:        policy.MinScore,
+		Suites:          []SuiteScore{},
+		GatePassed:      true,
+	}
+
+	var passedWeight float64
+	for _, suite := range suites {
+		suite.Score = getPercentage(suite.PassedWeight, suite.Weight)
+		suite.MinScore = policy.MinSuiteScores[suite.Suite]
+		// The suites whose checks were all skipped have no score to check.
+		suite.GatePassed = suite.Weight == 0 || suite.Score >= suite.MinScore
+		if !suite.GatePassed {
+			score.GateFailures = append(score.GateFailures, fmt.Sprintf("suite %s score %.1f is below the minimum score %.1f",
+				suite.Suite, suite.Score, suite.MinScore))
+		}
+
+		passedWeight += suite.PassedWeight
+		score.Weight += suite.Weight
+		score.Suites = append(score.Suites, *suite)
+	}
+
+	sort.Slice(score.Suites, func(i, j int) bool { return score.Suites[i].Suite < score.Suites[j].Suite })
+	sort.Strings(score.GateFailures)
+
+	score.Score = getPercentage(passedWeight, score.Weight)
+	if score.Weight == 0 {
+		score.GateFailures = append(score.GateFailures, "no weighted checks were evaluated")
+	} else if score.Score < score.MinScore {
+		score.GateFailures = append(score.GateFailures, fmt.Sprintf("overall score %.1f is below the minimum score %.1f",
+			score.Score, score.MinScore))
+	}
+
+	score.GatePassed = len(score.GateFailures) == 0
+	return &score
+}
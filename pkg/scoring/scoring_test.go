@@ -0,0 +1,156 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package scoring
+
+import (
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+)
+
+func newResult(id, suite, state, telco, nonTelco string) claim.Result {
+	return claim.Result{
+		TestID: &claim.Identifier{Id: id, Suite: suite},
+		State:  state,
+		CategoryClassification: &claim.CategoryClassification{
+			Telco:    telco,
+			NonTelco: nonTelco,
+			FarEdge:  "Optional",
+			Extended: "Mandatory",
+		},
+	}
+}
+
+func getTestResults() map[string]claim.Result {
+	return map[string]claim.Result{
+		"access-control-1": newResult("access-control-1", "access-control", "passed", "Mandatory", "Optional"),
+		"access-control-2": newResult("access-control-2", "access-control", "failed", "Mandatory", "Mandatory"),
+		"access-control-3": newResult("access-control-3", "access-control", "passed", "Optional", "Optional"),
+		"networking-1":     newResult("networking-1", "networking", "passed", "Mandatory", "Mandatory"),
+		"networking-2":     newResult("networking-2", "networking", "error", "Optional", "Mandatory"),
+		"networking-3":     newResult("networking-3", "networking", "skipped", "Mandatory", "Mandatory"),
+		"platform-1":       newResult("platform-1", "platform", "skipped", "Mandatory", "Mandatory"),
+	}
+}
+
+func weight(w float64) *float64 {
+	return &w
+}
+
+func TestNewPolicy(t *testing.T) {
+	policy, err := NewPolicy(&configuration.ScoringConfig{})
+	assert.Nil(t, err)
+	assert.Equal(t, &Policy{Profile: "Telco", MandatoryWeight: 3, OptionalWeight: 1, MinSuiteScores: map[string]float64{}}, policy)
+
+	policy, err = NewPolicy(&configuration.ScoringConfig{
+		Profile:         "NonTelco",
+		OptionalWeight:  weight(0),
+		MinScore:        80,
+		MinSuiteScores:  []configuration.SuiteScoreThreshold{{Suite: "networking", MinScore: 90}},
+		MandatoryWeight: weight(1),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &Policy{Profile: "NonTelco", MandatoryWeight: 1, OptionalWeight: 0, MinScore: 80,
+		MinSuiteScores: map[string]float64{"networking": 90}}, policy)
+
+	testCases := []struct {
+		config        configuration.ScoringConfig
+		expectedError string
+	}{
+		{
+			config:        configuration.ScoringConfig{Profile: "telco"},
+			expectedError: `invalid scoring profile "telco", valid profiles: [Telco NonTelco FarEdge Extended]`,
+		},
+		{
+			config:        configuration.ScoringConfig{MandatoryWeight: weight(0), OptionalWeight: weight(0)},
+			expectedError: "invalid scoring weights, the mandatory and optional weights can't be both zero",
+		},
+		{
+			config:        configuration.ScoringConfig{OptionalWeight: weight(-1)},
+			expectedError: "invalid optional scoring weight -1, it can't be negative",
+		},
+		{
+			config:        configuration.ScoringConfig{MinScore: 101},
+			expectedError: "invalid minimum score 101, it must be between 0 and 100",
+		},
+		{
+			config:        configuration.ScoringConfig{MinSuiteScores: []configuration.SuiteScoreThreshold{{Suite: "networking", MinScore: -5}}},
+			expectedError: "invalid minimum score -5 of suite networking, it must be between 0 and 100",
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := NewPolicy(&tc.config)
+		assert.NotNil(t, err)
+		assert.Equal(t, tc.expectedError, err.Error())
+	}
+}
+
+func TestCompute(t *testing.T) {
+	policy, err := NewPolicy(&configuration.ScoringConfig{})
+	assert.Nil(t, err)
+
+	score := Compute(getTestResults(), policy)
+	assert.Equal(t, &Score{
+		Profile:         "Telco",
+		MandatoryWeight: 3,
+		OptionalWeight:  1,
+		// (3+1+3) / (3+3+1+3+1)
+		Score:  63.6,
+		Weight: 11,
+		Suites: []SuiteScore{
+			{Suite: "access-control", Score: 57.1, Passed: 2, Failed: 1, MandatoryFailed: 1, Weight: 7, PassedWeight: 4, GatePassed: true},
+			{Suite: "networking", Score: 75, Passed: 1, Failed: 1, Skipped: 1, Weight: 4, PassedWeight: 3, GatePassed: true},
+			{Suite: "platform", Skipped: 1, GatePassed: true},
+		},
+		GatePassed: true,
+	}, score)
+
+	// Another profile, with thresholds.
+	policy, err = NewPolicy(&configuration.ScoringConfig{
+		Profile:  "NonTelco",
+		MinScore: 60,
+		MinSuiteScores: []configuration.SuiteScoreThreshold{
+			{Suite: "access-control", MinScore: 30},
+			{Suite: "networking", MinScore: 60},
+			{Suite: "platform", MinScore: 100},
+		},
+	})
+	assert.Nil(t, err)
+
+	score = Compute(getTestResults(), policy)
+	// (1+1+3) / (1+3+1+3+3)
+	assert.Equal(t, 45.5, score.Score)
+	assert.Equal(t, 40.0, score.Suites[0].Score)
+	assert.Equal(t, 50.0, score.Suites[1].Score)
+	assert.Equal(t, 1, score.Suites[1].MandatoryFailed)
+	assert.False(t, score.GatePassed)
+	assert.Equal(t, []string{
+		"suite networking score 50.0 is below the minimum score 60.0",
+		"overall score 45.5 is below the minimum score 60.0",
+	}, score.GateFailures)
+	// The suites without evaluated checks pass the gate.
+	assert.True(t, score.Suites[2].GatePassed)
+
+	// No checks were evaluated.
+	score = Compute(map[string]claim.Result{"platform-1": newResult("platform-1", "platform", "skipped", "Mandatory", "Mandatory")}, policy)
+	assert.Equal(t, 0.0, score.Score)
+	assert.False(t, score.GatePassed)
+	assert.Equal(t, []string{"no weighted checks were evaluated"}, score.GateFailures)
+}
@@ -0,0 +1,218 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package snapshot
+
+import (
+	"context"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/versions"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const machineConfigAnnotation = "machineconfiguration.openshift.io/currentConfig"
+
+// New creates a snapshot with the given config and discovered data, along with the rest of the
+// objects the checks get from the cluster on their own. Objects that cannot be retrieved are
+// left out of the snapshot, so the checks using them will report the same errors offline.
+func New(config *configuration.TestConfiguration, data *autodiscover.DiscoveredTestData, execRecords []clientsholder.ExecRecord) *Snapshot {
+	snapshot := &Snapshot{
+		CertsuiteVersion: versions.GitVersion(),
+		CreatedAt:        time.Now(),
+		Config:           *config,
+		Data:             *data,
+		ExecRecords:      execRecords,
+	}
+
+	// Don't keep any credentials in the snapshot.
+	snapshot.Config.CollectorAppPassword = ""
+	snapshot.Data.CollectorAppPassword = ""
+
+	client := clientsholder.GetClientsHolder()
+	snapshot.GroupResources = client.GroupResources
+	snapshot.collectMachineConfigs(client)
+	snapshot.collectNetworkAttachmentDefinitions(client)
+	snapshot.collectOperatorGroups(client)
+	snapshot.collectServiceAccounts(client)
+	snapshot.collectCSIDrivers(client)
+	snapshot.collectPodOwners(client)
+	snapshot.collectCustomResources(client)
+
+	return snapshot
+}
+
+func (s *Snapshot) collectMachineConfigs(client *clientsholder.ClientsHolder) {
+	if s.Data.OpenshiftVersion == autodiscover.NonOpenshiftClusterVersion || s.Data.Nodes == nil {
+		return
+	}
+
+	collected := map[string]bool{}
+	for i := range s.Data.Nodes.Items {
+		mcName, exists := s.Data.Nodes.Items[i].Annotations[machineConfigAnnotation]
+		if !exists || collected[mcName] {
+			continue
+		}
+
+		mc, err := client.MachineCfg.MachineconfigurationV1().MachineConfigs().Get(context.TODO(), mcName, metav1.GetOptions{})
+		if err != nil {
+			log.Warn("Failed to get machineConfig %q, err: %v", mcName, err)
+			continue
+		}
+
+		s.MachineConfigs = append(s.MachineConfigs, *mc)
+		collected[mcName] = true
+	}
+}
+
+func (s *Snapshot) collectNetworkAttachmentDefinitions(client *clientsholder.ClientsHolder) {
+	for _, namespace := range s.Data.Namespaces {
+		nads, err := client.CNCFNetworkingClient.NetworkAttachmentDefinitions(namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			log.Warn("Failed to get the network attachment definitions in namespace %q, err: %v", namespace, err)
+			continue
+		}
+
+		s.NetworkAttachmentDefinitions = append(s.NetworkAttachmentDefinitions, nads.Items...)
+	}
+}
+
+func (s *Snapshot) collectOperatorGroups(client *clientsholder.ClientsHolder) {
+	operatorGroups, err := client.OlmClient.OperatorsV1().OperatorGroups("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		log.Warn("Failed to get the operator groups, err: %v", err)
+		return
+	}
+
+	s.OperatorGroups = operatorGroups.Items
+}
+
+func (s *Snapshot) collectServiceAccounts(client *clientsholder.ClientsHolder) {
+	collected := map[string]bool{}
+	for i := range s.Data.Pods {
+		pod := &s.Data.Pods[i]
+		key := pod.Namespace + "/" + pod.Spec.ServiceAccountName
+		if pod.Spec.ServiceAccountName == "" || collected[key] {
+			continue
+		}
+
+		sa, err := client.K8sClient.CoreV1().ServiceAccounts(pod.Namespace).Get(context.TODO(), pod.Spec.ServiceAccountName, metav1.GetOptions{})
+		if err != nil {
+			log.Warn("Failed to get service account %q, err: %v", key, err)
+			continue
+		}
+
+		s.ServiceAccounts = append(s.ServiceAccounts, *sa)
+		collected[key] = true
+	}
+}
+
+func (s *Snapshot) collectCSIDrivers(client *clientsholder.ClientsHolder) {
+	csiDrivers, err := client.K8sClient.StorageV1().CSIDrivers().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		log.Warn("Failed to get the CSI drivers, err: %v", err)
+		return
+	}
+
+	s.CSIDrivers = csiDrivers.Items
+}
+
+// Returns the GVR of the resource of the given kind in the given API version.
+func getResourceSchema(resourceList []*metav1.APIResourceList, apiVersion, kind string) (schema.GroupVersionResource, bool) {
+	for _, gr := range resourceList {
+		if gr.GroupVersion != apiVersion {
+			continue
+		}
+
+		for i := range gr.APIResources {
+			if gr.APIResources[i].Kind == kind {
+				gv, err := schema.ParseGroupVersion(gr.GroupVersion)
+				if err != nil {
+					return schema.GroupVersionResource{}, false
+				}
+				return gv.WithResource(gr.APIResources[i].Name), true
+			}
+		}
+	}
+
+	return schema.GroupVersionResource{}, false
+}
+
+// Collects the whole ownership tree of the pods, so their top owners can be found offline.
+func (s *Snapshot) collectPodOwners(client *clientsholder.ClientsHolder) {
+	pods := []*corev1.Pod{}
+	for i := range s.Data.AllPods {
+		pods = append(pods, &s.Data.AllPods[i])
+	}
+	for i := range s.Data.Pods {
+		pods = append(pods, &s.Data.Pods[i])
+	}
+	for _, csvPods := range s.Data.CSVToPodListMap {
+		pods = append(pods, csvPods...)
+	}
+
+	collected := map[string]bool{}
+	var followOwners func(namespace string, ownerRefs []metav1.OwnerReference)
+	followOwners = func(namespace string, ownerRefs []metav1.OwnerReference) {
+		for _, ownerRef := range ownerRefs {
+			key := ownerRef.APIVersion + "/" + ownerRef.Kind + "/" + namespace + "/" + ownerRef.Name
+			if collected[key] {
+				continue
+			}
+			collected[key] = true
+
+			gvr, found := getResourceSchema(s.GroupResources, ownerRef.APIVersion, ownerRef.Kind)
+			if !found {
+				log.Warn("Failed to find the resource of owner %q", key)
+				continue
+			}
+
+			owner, err := client.DynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), ownerRef.Name, metav1.GetOptions{})
+			if err != nil {
+				log.Warn("Failed to get owner %q, err: %v", key, err)
+				continue
+			}
+
+			s.UnstructuredObjects = append(s.UnstructuredObjects, *owner)
+			followOwners(namespace, owner.GetOwnerReferences())
+		}
+	}
+
+	for _, pod := range pods {
+		followOwners(pod.Namespace, pod.OwnerReferences)
+	}
+}
+
+func (s *Snapshot) collectCustomResources(client *clientsholder.ClientsHolder) {
+	for _, crd := range s.Data.Crds {
+		for _, version := range crd.Spec.Versions {
+			gvr := schema.GroupVersionResource{Group: crd.Spec.Group, Version: version.Name, Resource: crd.Spec.Names.Plural}
+			crs, err := client.DynamicClient.Resource(gvr).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				log.Warn("Failed to get the CRs of CRD %q version %q, err: %v", crd.Name, version.Name, err)
+				continue
+			}
+
+			s.UnstructuredObjects = append(s.UnstructuredObjects, crs.Items...)
+		}
+	}
+}
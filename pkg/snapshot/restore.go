@@ -0,0 +1,215 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package snapshot
+
+import (
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Restore sets up the clients holder with fake clients serving the snapshot's objects, makes the
+// commands run in the containers to be served from the snapshot's recorded outputs and makes the
+// test environment to be built from the snapshot's discovered data. Returns the replayer serving
+// the recorded outputs.
+func (s *Snapshot) Restore() *clientsholder.CommandReplayer {
+	clientsholder.SetOfflineClientsHolder(s.getOfflineObjects())
+
+	replayer := clientsholder.NewCommandReplayer(s.ExecRecords)
+	clientsholder.SetCommandExecutor(replayer)
+
+	provider.SetOfflineTestData(&s.Config, &s.Data)
+
+	return replayer
+}
+
+// The fake clients fail to be created if the same object is added twice, and some of them, e.g.
+// pods, may appear in more than one of the discovered data lists.
+type objectsSet struct {
+	objects []runtime.Object
+	keys    map[string]bool
+}
+
+func (set *objectsSet) add(kind string, meta metav1.Object, obj runtime.Object) {
+	if set.keys == nil {
+		set.keys = map[string]bool{}
+	}
+
+	key := kind + "/" + meta.GetNamespace() + "/" + meta.GetName()
+	if set.keys[key] {
+		return
+	}
+
+	set.keys[key] = true
+	set.objects = append(set.objects, obj)
+}
+
+//nolint:funlen
+func (s *Snapshot) getOfflineObjects() *clientsholder.OfflineObjects {
+	data := &s.Data
+
+	k8sObjects := objectsSet{}
+	for _, pods := range [][]corev1.Pod{data.AllPods, data.Pods, data.DebugPods} {
+		for i := range pods {
+			k8sObjects.add("Pod", &pods[i], &pods[i])
+		}
+	}
+	for _, csvPods := range data.CSVToPodListMap {
+		for _, pod := range csvPods {
+			k8sObjects.add("Pod", pod, pod)
+		}
+	}
+	for _, namespace := range data.AllNamespaces {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+		k8sObjects.add("Namespace", ns, ns)
+	}
+	if data.Nodes != nil {
+		for i := range data.Nodes.Items {
+			k8sObjects.add("Node", &data.Nodes.Items[i], &data.Nodes.Items[i])
+		}
+	}
+	for i := range data.Deployments {
+		k8sObjects.add("Deployment", &data.Deployments[i], &data.Deployments[i])
+	}
+	for i := range data.StatefulSet {
+		k8sObjects.add("StatefulSet", &data.StatefulSet[i], &data.StatefulSet[i])
+	}
+	for i := range data.DaemonSets {
+		k8sObjects.add("DaemonSet", &data.DaemonSets[i], &data.DaemonSets[i])
+	}
+	for i := range data.ReplicaSets {
+		k8sObjects.add("ReplicaSet", &data.ReplicaSets[i], &data.ReplicaSets[i])
+	}
+	for i := range data.Jobs {
+		k8sObjects.add("Job", &data.Jobs[i], &data.Jobs[i])
+	}
+	for i := range data.CronJobs {
+		k8sObjects.add("CronJob", &data.CronJobs[i], &data.CronJobs[i])
+	}
+	for i := range data.ResourceQuotaItems {
+		k8sObjects.add("ResourceQuota", &data.ResourceQuotaItems[i], &data.ResourceQuotaItems[i])
+	}
+	for i := range data.PodDisruptionBudgets {
+		k8sObjects.add("PodDisruptionBudget", &data.PodDisruptionBudgets[i], &data.PodDisruptionBudgets[i])
+	}
+	for i := range data.NetworkPolicies {
+		k8sObjects.add("NetworkPolicy", &data.NetworkPolicies[i], &data.NetworkPolicies[i])
+	}
+	for i := range data.PersistentVolumes {
+		k8sObjects.add("PersistentVolume", &data.PersistentVolumes[i], &data.PersistentVolumes[i])
+	}
+	for i := range data.PersistentVolumeClaims {
+		k8sObjects.add("PersistentVolumeClaim", &data.PersistentVolumeClaims[i], &data.PersistentVolumeClaims[i])
+	}
+	for i := range data.ClusterRoleBindings {
+		k8sObjects.add("ClusterRoleBinding", &data.ClusterRoleBindings[i], &data.ClusterRoleBindings[i])
+	}
+	for i := range data.RoleBindings {
+		k8sObjects.add("RoleBinding", &data.RoleBindings[i], &data.RoleBindings[i])
+	}
+	for i := range data.Roles {
+		k8sObjects.add("Role", &data.Roles[i], &data.Roles[i])
+	}
+	for _, service := range data.Services {
+		k8sObjects.add("Service", service, service)
+	}
+	for _, hpa := range data.Hpas {
+		k8sObjects.add("HorizontalPodAutoscaler", hpa, hpa)
+	}
+	for i := range data.StorageClasses {
+		k8sObjects.add("StorageClass", &data.StorageClasses[i], &data.StorageClasses[i])
+	}
+	for i := range s.ServiceAccounts {
+		k8sObjects.add("ServiceAccount", &s.ServiceAccounts[i], &s.ServiceAccounts[i])
+	}
+	for i := range s.CSIDrivers {
+		k8sObjects.add("CSIDriver", &s.CSIDrivers[i], &s.CSIDrivers[i])
+	}
+
+	apiExtObjects := objectsSet{}
+	for _, crd := range data.AllCrds {
+		apiExtObjects.add("CustomResourceDefinition", crd, crd)
+	}
+	for _, crd := range data.Crds {
+		apiExtObjects.add("CustomResourceDefinition", crd, crd)
+	}
+
+	olmObjects := objectsSet{}
+	for _, csv := range data.AllCsvs {
+		olmObjects.add("ClusterServiceVersion", csv, csv)
+	}
+	for _, csv := range data.Csvs {
+		olmObjects.add("ClusterServiceVersion", csv, csv)
+	}
+	for i := range data.AllSubscriptions {
+		olmObjects.add("Subscription", &data.AllSubscriptions[i], &data.AllSubscriptions[i])
+	}
+	for i := range data.Subscriptions {
+		olmObjects.add("Subscription", &data.Subscriptions[i], &data.Subscriptions[i])
+	}
+	for _, installPlan := range data.AllInstallPlans {
+		olmObjects.add("InstallPlan", installPlan, installPlan)
+	}
+	for _, catalogSource := range data.AllCatalogSources {
+		olmObjects.add("CatalogSource", catalogSource, catalogSource)
+	}
+	for i := range s.OperatorGroups {
+		olmObjects.add("OperatorGroup", &s.OperatorGroups[i], &s.OperatorGroups[i])
+	}
+
+	machineConfigObjects := objectsSet{}
+	for i := range s.MachineConfigs {
+		machineConfigObjects.add("MachineConfig", &s.MachineConfigs[i], &s.MachineConfigs[i])
+	}
+
+	cncfNetworkingObjects := objectsSet{}
+	for i := range s.NetworkAttachmentDefinitions {
+		cncfNetworkingObjects.add("NetworkAttachmentDefinition", &s.NetworkAttachmentDefinitions[i], &s.NetworkAttachmentDefinitions[i])
+	}
+
+	dynamicObjects := objectsSet{}
+	for i := range s.UnstructuredObjects {
+		obj := &s.UnstructuredObjects[i]
+		dynamicObjects.add(obj.GetAPIVersion()+"/"+obj.GetKind(), obj, obj)
+	}
+
+	// Resources listed by the checks must have their list kind registered in the dynamic client.
+	dynamicListKinds := map[schema.GroupVersionResource]string{}
+	for _, crd := range data.Crds {
+		listKind := crd.Spec.Names.ListKind
+		if listKind == "" {
+			listKind = crd.Spec.Names.Kind + "List"
+		}
+		for _, version := range crd.Spec.Versions {
+			dynamicListKinds[schema.GroupVersionResource{Group: crd.Spec.Group, Version: version.Name, Resource: crd.Spec.Names.Plural}] = listKind
+		}
+	}
+
+	return &clientsholder.OfflineObjects{
+		K8sObjects:            k8sObjects.objects,
+		APIExtObjects:         apiExtObjects.objects,
+		OlmObjects:            olmObjects.objects,
+		MachineConfigObjects:  machineConfigObjects.objects,
+		CNCFNetworkingObjects: cncfNetworkingObjects.objects,
+		DynamicObjects:        dynamicObjects.objects,
+		DynamicListKinds:      dynamicListKinds,
+		GroupResources:        s.GroupResources,
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	nadv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	mcv1 "github.com/openshift/api/machineconfiguration/v1"
+	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// Files inside the snapshot archive.
+	dataFileName        = "snapshot.json"
+	execRecordsFileName = "exec-records.jsonl"
+
+	archiveFilePerm = 0o644
+)
+
+// Snapshot holds everything the checks need to run without access to the cluster: the config and
+// the data collected by the autodiscovery, the objects the checks get from the cluster on their own
+// and the outputs of the commands they run in the debug pods.
+type Snapshot struct {
+	CertsuiteVersion string                          `json:"certsuiteVersion"`
+	CreatedAt        time.Time                       `json:"createdAt"`
+	Config           configuration.TestConfiguration `json:"config"`
+	Data             autodiscover.DiscoveredTestData `json:"data"`
+
+	GroupResources               []*metav1.APIResourceList           `json:"groupResources"`
+	MachineConfigs               []mcv1.MachineConfig                `json:"machineConfigs"`
+	NetworkAttachmentDefinitions []nadv1.NetworkAttachmentDefinition `json:"networkAttachmentDefinitions"`
+	OperatorGroups               []olmv1.OperatorGroup               `json:"operatorGroups"`
+	ServiceAccounts              []corev1.ServiceAccount             `json:"serviceAccounts"`
+	CSIDrivers                   []storagev1.CSIDriver               `json:"csiDrivers"`
+	// Owners of the pods and custom resources of the CRDs under test, served by the dynamic client.
+	UnstructuredObjects []unstructured.Unstructured `json:"unstructuredObjects"`
+
+	// Saved in a separate file of the archive.
+	ExecRecords []clientsholder.ExecRecord `json:"-"`
+}
+
+// Save writes the snapshot to a tar.gz archive.
+func (s *Snapshot) Save(archivePath string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %v", err)
+	}
+
+	var execRecords bytes.Buffer
+	if err := clientsholder.WriteExecRecords(&execRecords, s.ExecRecords); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, file := range []struct {
+		name     string
+		contents []byte
+	}{{dataFileName, data}, {execRecordsFileName, execRecords.Bytes()}} {
+		header := &tar.Header{
+			Name:    file.name,
+			Mode:    archiveFilePerm,
+			Size:    int64(len(file.contents)),
+			ModTime: s.CreatedAt,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write tar header for %s: %v", file.name, err)
+		}
+		if _, err := tarWriter.Write(file.contents); err != nil {
+			return fmt.Errorf("failed to write %s to the archive: %v", file.name, err)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to close tar writer: %v", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to close gzip writer: %v", err)
+	}
+
+	if err := os.WriteFile(archivePath, buf.Bytes(), archiveFilePerm); err != nil {
+		return fmt.Errorf("failed to write snapshot archive %s: %v", archivePath, err)
+	}
+
+	return nil
+}
+
+// Load reads a snapshot from a tar.gz archive created with Save.
+func Load(archivePath string) (*Snapshot, error) {
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot archive %s: %v", archivePath, err)
+	}
+	defer archive.Close()
+
+	gzipReader, err := gzip.NewReader(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot archive %s: %v", archivePath, err)
+	}
+	defer gzipReader.Close()
+
+	snapshot := Snapshot{}
+	foundFiles := map[string]bool{}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot archive %s: %v", archivePath, err)
+		}
+
+		switch header.Name {
+		case dataFileName:
+			err = json.NewDecoder(tarReader).Decode(&snapshot)
+		case execRecordsFileName:
+			snapshot.ExecRecords, err = clientsholder.ReadExecRecords(tarReader)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s from snapshot archive %s: %v", header.Name, archivePath, err)
+		}
+		foundFiles[header.Name] = true
+	}
+
+	for _, fileName := range []string{dataFileName, execRecordsFileName} {
+		if !foundFiles[fileName] {
+			return nil, fmt.Errorf("invalid snapshot archive %s: %s not found", archivePath, fileName)
+		}
+	}
+
+	return &snapshot, nil
+}
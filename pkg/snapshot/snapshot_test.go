@@ -0,0 +1,156 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package snapshot
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/podhelper"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func getTestSnapshot() *Snapshot {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod1",
+			Namespace: "ns1",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "deployment1-abc"},
+			},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "container1"}}},
+	}
+
+	crd := &apiextv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "mycrs.example.com"},
+		Spec: apiextv1.CustomResourceDefinitionSpec{
+			Group:    "example.com",
+			Names:    apiextv1.CustomResourceDefinitionNames{Plural: "mycrs", Kind: "MyCr"},
+			Versions: []apiextv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+		},
+	}
+
+	replicaSet := unstructured.Unstructured{}
+	replicaSet.SetAPIVersion("apps/v1")
+	replicaSet.SetKind("ReplicaSet")
+	replicaSet.SetNamespace("ns1")
+	replicaSet.SetName("deployment1-abc")
+	replicaSet.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "deployment1"}})
+
+	deployment := unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+	deployment.SetNamespace("ns1")
+	deployment.SetName("deployment1")
+
+	cr := unstructured.Unstructured{}
+	cr.SetAPIVersion("example.com/v1")
+	cr.SetKind("MyCr")
+	cr.SetNamespace("ns1")
+	cr.SetName("cr1")
+
+	return &Snapshot{
+		CertsuiteVersion: "v0.0.0",
+		CreatedAt:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Data: autodiscover.DiscoveredTestData{
+			Namespaces:       []string{"ns1"},
+			AllNamespaces:    []string{"ns1", "ns2"},
+			Pods:             []corev1.Pod{pod},
+			AllPods:          []corev1.Pod{pod},
+			Crds:             []*apiextv1.CustomResourceDefinition{crd},
+			AllCrds:          []*apiextv1.CustomResourceDefinition{crd},
+			Nodes:            &corev1.NodeList{},
+			OpenshiftVersion: autodiscover.NonOpenshiftClusterVersion,
+		},
+		GroupResources: []*metav1.APIResourceList{{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{{Name: "replicasets", Kind: "ReplicaSet"}, {Name: "deployments", Kind: "Deployment"}},
+		}},
+		UnstructuredObjects: []unstructured.Unstructured{replicaSet, deployment, cr},
+		ExecRecords: []clientsholder.ExecRecord{
+			{Namespace: "ns1", Pod: "pod1", Container: "container1", Command: "uname -r", Stdout: "5.14.0"},
+		},
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	snapshot := getTestSnapshot()
+	archivePath := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+
+	assert.Nil(t, snapshot.Save(archivePath))
+
+	loadedSnapshot, err := Load(archivePath)
+	assert.Nil(t, err)
+	assert.Equal(t, snapshot.CertsuiteVersion, loadedSnapshot.CertsuiteVersion)
+	assert.True(t, snapshot.CreatedAt.Equal(loadedSnapshot.CreatedAt))
+	assert.Equal(t, snapshot.Data.Pods, loadedSnapshot.Data.Pods)
+	assert.Equal(t, snapshot.Data.Crds, loadedSnapshot.Data.Crds)
+	assert.Equal(t, snapshot.UnstructuredObjects, loadedSnapshot.UnstructuredObjects)
+	assert.Equal(t, snapshot.ExecRecords, loadedSnapshot.ExecRecords)
+
+	_, err = Load(filepath.Join(t.TempDir(), "nonexistent.tar.gz"))
+	assert.NotNil(t, err)
+}
+
+func TestRestore(t *testing.T) {
+	defer clientsholder.SetCommandExecutor(nil)
+
+	snapshot := getTestSnapshot()
+	replayer := snapshot.Restore()
+	client := clientsholder.GetClientsHolder()
+
+	// The same pod appears in several lists, but it's only served once.
+	pods, err := client.K8sClient.CoreV1().Pods("ns1").List(context.TODO(), metav1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, pods.Items, 1)
+
+	namespaces, err := client.K8sClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, namespaces.Items, 2)
+
+	// The pod's top owner is found following the owners served by the dynamic client.
+	topOwners, err := podhelper.GetPodTopOwner("ns1", snapshot.Data.Pods[0].OwnerReferences)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]podhelper.TopOwner{"deployment1": {Kind: "Deployment", Name: "deployment1", Namespace: "ns1"}}, topOwners)
+
+	crs, err := client.DynamicClient.Resource(schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "mycrs"}).
+		List(context.TODO(), metav1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, crs.Items, 1)
+
+	// Commands are served from the recorded outputs.
+	stdout, _, err := client.ExecCommandContainer(clientsholder.NewContext("ns1", "pod1", "container1"), "uname -r")
+	assert.Nil(t, err)
+	assert.Equal(t, "5.14.0", stdout)
+	assert.Equal(t, 0, replayer.GetMissingCommands())
+
+	assert.True(t, provider.IsOfflineMode())
+	config, data := provider.GetDiscoveredTestData()
+	assert.Equal(t, snapshot.Config, config)
+	assert.Equal(t, snapshot.Data.Pods, data.Pods)
+}
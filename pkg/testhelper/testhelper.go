@@ -20,7 +20,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 )
 
@@ -37,6 +39,8 @@ type ReportObject struct {
 }
 
 type FailureReasonOut struct {
+	// Must go first, as some parsers expect the non-compliant objects to be the last field.
+	WaivedObjectsOut       []*ReportObject `json:",omitempty"`
 	CompliantObjectsOut    []*ReportObject
 	NonCompliantObjectsOut []*ReportObject
 }
@@ -122,6 +126,10 @@ const (
 	ServiceIPVersion                = "Service IP Version"
 	DeploymentName                  = "Deployment Name"
 	StatefulSetName                 = "StatefulSet Name"
+	DaemonSetName                   = "DaemonSet Name"
+	ReplicaSetName                  = "ReplicaSet Name"
+	JobName                         = "Job Name"
+	CronJobName                     = "CronJob Name"
 	PodDisruptionBudgetReference    = "Pod Disruption Budget Reference"
 	CustomResourceDefinitionName    = "Custom Resource Definition Name"
 	CustomResourceDefinitionVersion = "Custom Resource Definition Version"
@@ -198,6 +206,10 @@ const (
 	ServiceType                  = "Service"
 	DeploymentType               = "Deployment"
 	StatefulSetType              = "StatefulSet"
+	DaemonSetType                = "DaemonSet"
+	ReplicaSetType               = "ReplicaSet"
+	JobType                      = "Job"
+	CronJobType                  = "CronJob"
 	ICMPResultType               = "ICMP result"
 	NetworkType                  = "Network"
 	CustomResourceDefinitionType = "Custom Resource Definition"
@@ -329,6 +341,51 @@ func NewStatefulSetReportObject(aNamespace, aStatefulSetName, aReason string, is
 	return out
 }
 
+// NewDaemonSetReportObject creates a new ReportObject for a DaemonSet.
+func NewDaemonSetReportObject(aNamespace, aDaemonSetName, aReason string, isCompliant bool) (out *ReportObject) {
+	out = NewReportObject(aReason, DaemonSetType, isCompliant)
+	out.AddField(Namespace, aNamespace)
+	out.AddField(DaemonSetName, aDaemonSetName)
+	return out
+}
+
+// NewReplicaSetReportObject creates a new ReportObject for a ReplicaSet.
+func NewReplicaSetReportObject(aNamespace, aReplicaSetName, aReason string, isCompliant bool) (out *ReportObject) {
+	out = NewReportObject(aReason, ReplicaSetType, isCompliant)
+	out.AddField(Namespace, aNamespace)
+	out.AddField(ReplicaSetName, aReplicaSetName)
+	return out
+}
+
+// NewReplicatedPodSetReportObject creates a new ReportObject for a deployment, statefulset or replicaset,
+// depending on the kind of the pod set.
+func NewReplicatedPodSetReportObject(podSet *provider.ReplicatedPodSet, aReason string, isCompliant bool) (out *ReportObject) {
+	switch podSet.Kind {
+	case provider.StatefulSetKind:
+		return NewStatefulSetReportObject(podSet.Namespace, podSet.Name, aReason, isCompliant)
+	case provider.ReplicaSetKind:
+		return NewReplicaSetReportObject(podSet.Namespace, podSet.Name, aReason, isCompliant)
+	default:
+		return NewDeploymentReportObject(podSet.Namespace, podSet.Name, aReason, isCompliant)
+	}
+}
+
+// NewJobReportObject creates a new ReportObject for a Job.
+func NewJobReportObject(aNamespace, aJobName, aReason string, isCompliant bool) (out *ReportObject) {
+	out = NewReportObject(aReason, JobType, isCompliant)
+	out.AddField(Namespace, aNamespace)
+	out.AddField(JobName, aJobName)
+	return out
+}
+
+// NewCronJobReportObject creates a new ReportObject for a CronJob.
+func NewCronJobReportObject(aNamespace, aCronJobName, aReason string, isCompliant bool) (out *ReportObject) {
+	out = NewReportObject(aReason, CronJobType, isCompliant)
+	out.AddField(Namespace, aNamespace)
+	out.AddField(CronJobName, aCronJobName)
+	return out
+}
+
 // NewCrdReportObject creates a new ReportObject for a custom resource definition (CRD).
 // It takes the name, version, reason, and compliance status as parameters and returns the created ReportObject.
 func NewCrdReportObject(aName, aVersion, aReason string, isCompliant bool) (out *ReportObject) {
@@ -383,6 +440,46 @@ func (obj *ReportObject) SetType(aType string) (out *ReportObject) {
 	return obj
 }
 
+// GetNamespace returns the value of the Namespace field, or an empty string for cluster-wide objects.
+func (obj *ReportObject) GetNamespace() string {
+	for i := range obj.ObjectFieldsKeys {
+		if obj.ObjectFieldsKeys[i] == Namespace && i < len(obj.ObjectFieldsValues) {
+			return obj.ObjectFieldsValues[i]
+		}
+	}
+
+	return ""
+}
+
+// GetName returns the name of the object, made of the values of all its name fields joined by "/",
+// e.g. "pod1/container1" for a container. Objects without name fields, like the taints or the
+// cluster version, are named after their first field that is not the reason or the namespace.
+func (obj *ReportObject) GetName() string {
+	names := []string{}
+	otherValues := []string{}
+	for i := range obj.ObjectFieldsKeys {
+		if i >= len(obj.ObjectFieldsValues) {
+			break
+		}
+
+		key, value := obj.ObjectFieldsKeys[i], obj.ObjectFieldsValues[i]
+		switch {
+		case key == ReasonForNonCompliance, key == ReasonForCompliance, key == Namespace:
+			// Not part of the name.
+		case key == Name || strings.HasSuffix(key, " "+Name):
+			names = append(names, value)
+		default:
+			otherValues = append(otherValues, value)
+		}
+	}
+
+	if len(names) == 0 && len(otherValues) > 0 {
+		names = otherValues[:1]
+	}
+
+	return strings.Join(names, "/")
+}
+
 // ResultToString converts an integer result code into a corresponding string representation.
 // It takes an integer result as input and returns the corresponding string representation.
 // The possible result codes are SUCCESS, FAILURE, and ERROR.
@@ -399,8 +496,31 @@ func ResultToString(result int) (str string) {
 	return ""
 }
 
+// Returns whether any of the discovery collectors failed, with the reason to skip the checks that need its objects.
+func isDiscoveryFailed(env *provider.TestEnvironment, collectors ...string) (failed bool, reason string) {
+	for _, collector := range collectors {
+		if err := env.DiscoveryReport.GetError(collector); err != nil {
+			return true, fmt.Sprintf("discovery of %s failed: %v", collector, err)
+		}
+	}
+
+	return false, ""
+}
+
+// GetDiscoveryFailedSkipFn skips the check if any of the discovery collectors it depends on failed.
+func GetDiscoveryFailedSkipFn(env *provider.TestEnvironment, collectors ...string) func() (bool, string) {
+	return func() (bool, string) {
+		return isDiscoveryFailed(env, collectors...)
+	}
+}
+
 func GetNonOCPClusterSkipFn() func() (bool, string) {
 	return func() (bool, string) {
+		env := provider.GetTestEnvironment()
+		if failed, reason := isDiscoveryFailed(&env, autodiscover.CollectorOpenshiftVersion); failed {
+			return true, reason
+		}
+
 		if !provider.IsOCPCluster() {
 			return true, "non-OCP cluster detected"
 		}
@@ -410,6 +530,10 @@ func GetNonOCPClusterSkipFn() func() (bool, string) {
 
 func GetNoServicesUnderTestSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorServices); failed {
+			return true, reason
+		}
+
 		if len(env.Services) == 0 {
 			return true, "no services to check found"
 		}
@@ -420,6 +544,10 @@ func GetNoServicesUnderTestSkipFn(env *provider.TestEnvironment) func() (bool, s
 
 func GetDaemonSetFailedToSpawnSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorDebugPods); failed {
+			return true, reason
+		}
+
 		if env.DaemonsetFailedToSpawn {
 			return true, "no daemonSets to check found"
 		}
@@ -430,6 +558,10 @@ func GetDaemonSetFailedToSpawnSkipFn(env *provider.TestEnvironment) func() (bool
 
 func GetNoCPUPinningPodsSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorPods); failed {
+			return true, reason
+		}
+
 		if len(env.GetCPUPinningPodsWithDpdk()) == 0 {
 			return true, "no CPU pinning pods to check found"
 		}
@@ -440,6 +572,10 @@ func GetNoCPUPinningPodsSkipFn(env *provider.TestEnvironment) func() (bool, stri
 
 func GetNoSRIOVPodsSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorPods); failed {
+			return true, reason
+		}
+
 		pods, err := env.GetPodsUsingSRIOV()
 		if err != nil {
 			return true, fmt.Sprintf("failed to get SRIOV pods: %v", err)
@@ -455,6 +591,10 @@ func GetNoSRIOVPodsSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 
 func GetNoContainersUnderTestSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorPods); failed {
+			return true, reason
+		}
+
 		if len(env.Containers) == 0 {
 			return true, "no containers to check found"
 		}
@@ -465,6 +605,10 @@ func GetNoContainersUnderTestSkipFn(env *provider.TestEnvironment) func() (bool,
 
 func GetNoPodsUnderTestSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorPods); failed {
+			return true, reason
+		}
+
 		if len(env.Pods) == 0 {
 			return true, "no pods to check found"
 		}
@@ -475,6 +619,10 @@ func GetNoPodsUnderTestSkipFn(env *provider.TestEnvironment) func() (bool, strin
 
 func GetNoDeploymentsUnderTestSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorDeployments); failed {
+			return true, reason
+		}
+
 		if len(env.Deployments) == 0 {
 			return true, "no deployments to check found"
 		}
@@ -485,6 +633,10 @@ func GetNoDeploymentsUnderTestSkipFn(env *provider.TestEnvironment) func() (bool
 
 func GetNoStatefulSetsUnderTestSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorStatefulSets); failed {
+			return true, reason
+		}
+
 		if len(env.StatefulSets) == 0 {
 			return true, "no statefulSets to check found"
 		}
@@ -493,8 +645,55 @@ func GetNoStatefulSetsUnderTestSkipFn(env *provider.TestEnvironment) func() (boo
 	}
 }
 
+func GetNoDaemonSetsUnderTestSkipFn(env *provider.TestEnvironment) func() (bool, string) {
+	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorDaemonSets); failed {
+			return true, reason
+		}
+
+		if len(env.DaemonSets) == 0 {
+			return true, "no daemonSets to check found"
+		}
+
+		return false, ""
+	}
+}
+
+func GetNoReplicaSetsUnderTestSkipFn(env *provider.TestEnvironment) func() (bool, string) {
+	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorReplicaSets); failed {
+			return true, reason
+		}
+
+		if len(env.ReplicaSets) == 0 {
+			return true, "no replicaSets to check found"
+		}
+
+		return false, ""
+	}
+}
+
+// GetNoJobsUnderTestSkipFn skips the checks when there are neither jobs nor cronjobs under test.
+func GetNoJobsUnderTestSkipFn(env *provider.TestEnvironment) func() (bool, string) {
+	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorJobs, autodiscover.CollectorCronJobs); failed {
+			return true, reason
+		}
+
+		if len(env.Jobs) == 0 && len(env.CronJobs) == 0 {
+			return true, "no jobs or cronJobs to check found"
+		}
+
+		return false, ""
+	}
+}
+
 func GetNoCrdsUnderTestSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorCrds); failed {
+			return true, reason
+		}
+
 		if len(env.Crds) == 0 {
 			return true, "no roles to check"
 		}
@@ -515,6 +714,10 @@ func GetNoNamespacesSkipFn(env *provider.TestEnvironment) func() (bool, string)
 
 func GetNoRolesSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorRoles); failed {
+			return true, reason
+		}
+
 		if len(env.Roles) == 0 {
 			return true, "There are no roles to check. Please check config."
 		}
@@ -525,6 +728,10 @@ func GetNoRolesSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 
 func GetSharedProcessNamespacePodsSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorPods); failed {
+			return true, reason
+		}
+
 		if len(env.GetShareProcessNamespacePods()) == 0 {
 			return true, "Shared process namespace pods found."
 		}
@@ -545,6 +752,10 @@ func GetNotIntrusiveSkipFn(env *provider.TestEnvironment) func() (bool, string)
 
 func GetNoPersistentVolumesSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorPersistentVolumes); failed {
+			return true, reason
+		}
+
 		if len(env.PersistentVolumes) == 0 {
 			return true, "no persistent volumes to check found"
 		}
@@ -555,6 +766,10 @@ func GetNoPersistentVolumesSkipFn(env *provider.TestEnvironment) func() (bool, s
 
 func GetNotEnoughWorkersSkipFn(env *provider.TestEnvironment, minWorkerNodes int) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorNodes); failed {
+			return true, reason
+		}
+
 		if env.GetWorkerCount() < minWorkerNodes {
 			return true, "not enough nodes to check found"
 		}
@@ -565,6 +780,10 @@ func GetNotEnoughWorkersSkipFn(env *provider.TestEnvironment, minWorkerNodes int
 
 func GetPodsWithoutAffinityRequiredLabelSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorPods); failed {
+			return true, reason
+		}
+
 		if len(env.GetPodsWithoutAffinityRequiredLabel()) == 0 {
 			return true, "no pods with required affinity label found"
 		}
@@ -575,6 +794,10 @@ func GetPodsWithoutAffinityRequiredLabelSkipFn(env *provider.TestEnvironment) fu
 
 func GetNoGuaranteedPodsWithExclusiveCPUsSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorPods); failed {
+			return true, reason
+		}
+
 		if len(env.GetGuaranteedPodsWithExclusiveCPUs()) == 0 {
 			return true, "no pods with exclusive CPUs found"
 		}
@@ -585,6 +808,10 @@ func GetNoGuaranteedPodsWithExclusiveCPUsSkipFn(env *provider.TestEnvironment) f
 
 func GetNoAffinityRequiredPodsSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorPods); failed {
+			return true, reason
+		}
+
 		if len(env.GetAffinityRequiredPods()) == 0 {
 			return true, "no pods with required affinity found"
 		}
@@ -595,6 +822,10 @@ func GetNoAffinityRequiredPodsSkipFn(env *provider.TestEnvironment) func() (bool
 
 func GetNoStorageClassesSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorStorageClasses); failed {
+			return true, reason
+		}
+
 		if len(env.StorageClassList) == 0 {
 			return true, "no storage classes found"
 		}
@@ -604,6 +835,10 @@ func GetNoStorageClassesSkipFn(env *provider.TestEnvironment) func() (bool, stri
 
 func GetNoPersistentVolumeClaimsSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorPersistentVolumeClaims); failed {
+			return true, reason
+		}
+
 		if len(env.PersistentVolumeClaims) == 0 {
 			return true, "no persistent volume claims found"
 		}
@@ -613,6 +848,10 @@ func GetNoPersistentVolumeClaimsSkipFn(env *provider.TestEnvironment) func() (bo
 
 func GetNoBareMetalNodesSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorNodes); failed {
+			return true, reason
+		}
+
 		if len(env.GetBaremetalNodes()) == 0 {
 			return true, "no baremetal nodes found"
 		}
@@ -622,6 +861,10 @@ func GetNoBareMetalNodesSkipFn(env *provider.TestEnvironment) func() (bool, stri
 
 func GetNoIstioSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorIstio); failed {
+			return true, reason
+		}
+
 		if !env.IstioServiceMeshFound {
 			return true, "no istio service mesh found"
 		}
@@ -631,6 +874,10 @@ func GetNoIstioSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 
 func GetNoHugepagesPodsSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorPods); failed {
+			return true, reason
+		}
+
 		if len(env.GetHugepagesPods()) == 0 {
 			return true, "no pods requesting hugepages found"
 		}
@@ -640,6 +887,10 @@ func GetNoHugepagesPodsSkipFn(env *provider.TestEnvironment) func() (bool, strin
 
 func GetNoOperatorsSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorOperatorsUnderTest, autodiscover.CollectorOperators); failed {
+			return true, reason
+		}
+
 		if len(env.Operators) == 0 {
 			return true, "no operators found"
 		}
@@ -649,6 +900,10 @@ func GetNoOperatorsSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 
 func GetNoOperatorCrdsSkipFn(env *provider.TestEnvironment) func() (bool, string) {
 	return func() (bool, string) {
+		if failed, reason := isDiscoveryFailed(env, autodiscover.CollectorCrds); failed {
+			return true, reason
+		}
+
 		if len(env.Crds) == 0 {
 			return true, "no operator crds found"
 		}
@@ -657,7 +912,14 @@ func GetNoOperatorCrdsSkipFn(env *provider.TestEnvironment) func() (bool, string
 }
 
 func ResultObjectsToString(compliantObject, nonCompliantObject []*ReportObject) (string, error) {
+	return ResultObjectsWithWaivedToString(compliantObject, nonCompliantObject, nil)
+}
+
+// ResultObjectsWithWaivedToString is like ResultObjectsToString, but also includes the
+// non-compliant objects that were waived.
+func ResultObjectsWithWaivedToString(compliantObject, nonCompliantObject, waivedObject []*ReportObject) (string, error) {
 	reason := FailureReasonOut{
+		WaivedObjectsOut:       waivedObject,
 		CompliantObjectsOut:    compliantObject,
 		NonCompliantObjectsOut: nonCompliantObject,
 	}
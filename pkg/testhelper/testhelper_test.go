@@ -19,9 +19,11 @@ package testhelper
 import (
 	"testing"
 
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -471,6 +473,25 @@ func TestSetContainerProcessValues(t *testing.T) {
 	assert.Equal(t, ContainerProcessType, reportObj.ObjectType)
 }
 
+func TestReportObjectGetNamespaceAndName(t *testing.T) {
+	testCases := []struct {
+		obj               *ReportObject
+		expectedNamespace string
+		expectedName      string
+	}{
+		{obj: NewPodReportObject("ns1", "pod1", "reason", false), expectedNamespace: "ns1", expectedName: "pod1"},
+		{obj: NewContainerReportObject("ns1", "pod1", "cnt1", "reason", true), expectedNamespace: "ns1", expectedName: "pod1/cnt1"},
+		{obj: NewNodeReportObject("node1", "reason", false), expectedNamespace: "", expectedName: "node1"},
+		{obj: NewTaintReportObject("12", "node1", "reason", false), expectedNamespace: "", expectedName: "node1"},
+		{obj: NewReportObject("reason", UndefinedType, false), expectedNamespace: "", expectedName: ""},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedNamespace, tc.obj.GetNamespace())
+		assert.Equal(t, tc.expectedName, tc.obj.GetName())
+	}
+}
+
 func TestResultToString(t *testing.T) {
 	testCases := []struct {
 		input          int
@@ -1104,3 +1125,131 @@ func TestGetNoOperatorsSkipFn(t *testing.T) {
 		assert.Equal(t, testCase.expectedResult, result)
 	}
 }
+
+func TestGetDiscoveryFailedSkipFn(t *testing.T) {
+	env := &provider.TestEnvironment{
+		Pods: []*provider.Pod{{Pod: &corev1.Pod{}}},
+		DiscoveryReport: autodiscover.DiscoveryReport{Collectors: []autodiscover.CollectorReport{
+			{Name: autodiscover.CollectorPods},
+			{Name: autodiscover.CollectorRoles, Error: "roles are forbidden"},
+		}},
+	}
+
+	skip, reason := GetDiscoveryFailedSkipFn(env, autodiscover.CollectorPods)()
+	assert.False(t, skip)
+	assert.Equal(t, "", reason)
+
+	skip, reason = GetDiscoveryFailedSkipFn(env, autodiscover.CollectorPods, autodiscover.CollectorRoles)()
+	assert.True(t, skip)
+	assert.Equal(t, "discovery of roles failed: roles are forbidden", reason)
+
+	// The skip functions of the discovered objects check their collector first.
+	skip, reason = GetNoRolesSkipFn(env)()
+	assert.True(t, skip)
+	assert.Equal(t, "discovery of roles failed: roles are forbidden", reason)
+
+	skip, _ = GetNoPodsUnderTestSkipFn(env)()
+	assert.False(t, skip)
+}
+
+func TestNewWorkloadReportObjects(t *testing.T) {
+	testCases := []struct {
+		reportObj        *ReportObject
+		expectedType     string
+		expectedNameType string
+	}{
+		{reportObj: NewDaemonSetReportObject("testNamespace", "testName", "testReason", true), expectedType: DaemonSetType, expectedNameType: DaemonSetName},
+		{reportObj: NewReplicaSetReportObject("testNamespace", "testName", "testReason", true), expectedType: ReplicaSetType, expectedNameType: ReplicaSetName},
+		{reportObj: NewJobReportObject("testNamespace", "testName", "testReason", true), expectedType: JobType, expectedNameType: JobName},
+		{reportObj: NewCronJobReportObject("testNamespace", "testName", "testReason", true), expectedType: CronJobType, expectedNameType: CronJobName},
+		{reportObj: NewReplicatedPodSetReportObject(&provider.ReplicatedPodSet{Kind: provider.DeploymentKind, Namespace: "testNamespace", Name: "testName"}, "testReason", true),
+			expectedType: DeploymentType, expectedNameType: DeploymentName},
+		{reportObj: NewReplicatedPodSetReportObject(&provider.ReplicatedPodSet{Kind: provider.StatefulSetKind, Namespace: "testNamespace", Name: "testName"}, "testReason", true),
+			expectedType: StatefulSetType, expectedNameType: StatefulSetName},
+		{reportObj: NewReplicatedPodSetReportObject(&provider.ReplicatedPodSet{Kind: provider.ReplicaSetKind, Namespace: "testNamespace", Name: "testName"}, "testReason", true),
+			expectedType: ReplicaSetType, expectedNameType: ReplicaSetName},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedType, testCase.reportObj.ObjectType)
+		assert.Equal(t, []string{ReasonForCompliance, Namespace, testCase.expectedNameType}, testCase.reportObj.ObjectFieldsKeys)
+		assert.Equal(t, []string{"testReason", "testNamespace", "testName"}, testCase.reportObj.ObjectFieldsValues)
+	}
+}
+
+func TestGetNoDaemonSetsUnderTestSkipFn(t *testing.T) {
+	testCases := []struct {
+		testEnv        *provider.TestEnvironment
+		expectedResult bool
+	}{
+		{
+			testEnv:        &provider.TestEnvironment{DaemonSets: nil},
+			expectedResult: true,
+		},
+		{
+			testEnv:        &provider.TestEnvironment{DaemonSets: []*provider.DaemonSet{{DaemonSet: &appsv1.DaemonSet{}}}},
+			expectedResult: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testFunc := GetNoDaemonSetsUnderTestSkipFn(testCase.testEnv)
+		result, _ := testFunc()
+		assert.Equal(t, testCase.expectedResult, result)
+	}
+}
+
+func TestGetNoReplicaSetsUnderTestSkipFn(t *testing.T) {
+	testCases := []struct {
+		testEnv        *provider.TestEnvironment
+		expectedResult bool
+	}{
+		{
+			testEnv:        &provider.TestEnvironment{ReplicaSets: nil},
+			expectedResult: true,
+		},
+		{
+			testEnv:        &provider.TestEnvironment{ReplicaSets: []*provider.ReplicaSet{{ReplicaSet: &appsv1.ReplicaSet{}}}},
+			expectedResult: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testFunc := GetNoReplicaSetsUnderTestSkipFn(testCase.testEnv)
+		result, _ := testFunc()
+		assert.Equal(t, testCase.expectedResult, result)
+	}
+}
+
+func TestGetNoJobsUnderTestSkipFn(t *testing.T) {
+	testCases := []struct {
+		testEnv        *provider.TestEnvironment
+		expectedResult bool
+	}{
+		{
+			testEnv:        &provider.TestEnvironment{},
+			expectedResult: true,
+		},
+		{
+			testEnv:        &provider.TestEnvironment{Jobs: []*provider.Job{{Job: &batchv1.Job{}}}},
+			expectedResult: false,
+		},
+		{
+			testEnv:        &provider.TestEnvironment{CronJobs: []*provider.CronJob{{CronJob: &batchv1.CronJob{}}}},
+			expectedResult: false,
+		},
+		{
+			testEnv: &provider.TestEnvironment{
+				CronJobs:        []*provider.CronJob{{CronJob: &batchv1.CronJob{}}},
+				DiscoveryReport: autodiscover.DiscoveryReport{Collectors: []autodiscover.CollectorReport{{Name: autodiscover.CollectorJobs, Error: "forbidden"}}},
+			},
+			expectedResult: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testFunc := GetNoJobsUnderTestSkipFn(testCase.testEnv)
+		result, _ := testFunc()
+		assert.Equal(t, testCase.expectedResult, result)
+	}
+}
@@ -0,0 +1,202 @@
+package waivers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+	"gopkg.in/yaml.v3"
+)
+
+// Fields added to the report objects that matched a waiver.
+const (
+	WaiverReasonField     = "Waiver Reason"
+	WaiverApprovedByField = "Waiver Approved By"
+	WaiverExpiresField    = "Waiver Expires"
+	ExpiredWaiverField    = "Expired Waiver"
+)
+
+// Waiver accepts a known non-compliance of some objects in a check until the waiver expires.
+// The namespace, kind and name are optional and support shell patterns, e.g. "tnf-*". An
+// empty value matches any object.
+type Waiver struct {
+	TestID     string    `yaml:"testID" json:"testID"`
+	Namespace  string    `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	Kind       string    `yaml:"kind,omitempty" json:"kind,omitempty"`
+	Name       string    `yaml:"name,omitempty" json:"name,omitempty"`
+	Reason     string    `yaml:"reason" json:"reason"`
+	ApprovedBy string    `yaml:"approvedBy" json:"approvedBy"`
+	Expires    time.Time `yaml:"expires" json:"expires"`
+}
+
+type waiversFile struct {
+	Waivers []Waiver `yaml:"waivers"`
+}
+
+// LoadWaivers reads the waivers from a YAML file. All the waivers must have a test ID, a reason,
+// an approver and an expiration date.
+func LoadWaivers(filePath string) ([]Waiver, error) {
+	contents, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read waivers file %s: %v", filePath, err)
+	}
+
+	file := waiversFile{}
+	if err := yaml.Unmarshal(contents, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse waivers file %s: %v", filePath, err)
+	}
+
+	var errs []error
+	for i := range file.Waivers {
+		if err := file.Waivers[i].validate(); err != nil {
+			errs = append(errs, fmt.Errorf("waiver %d: %v", i, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid waivers file %s: %v", filePath, errors.Join(errs...))
+	}
+
+	return file.Waivers, nil
+}
+
+func (w *Waiver) validate() error {
+	missingFields := []string{}
+	if w.TestID == "" {
+		missingFields = append(missingFields, "testID")
+	}
+	if w.Reason == "" {
+		missingFields = append(missingFields, "reason")
+	}
+	if w.ApprovedBy == "" {
+		missingFields = append(missingFields, "approvedBy")
+	}
+	if w.Expires.IsZero() {
+		missingFields = append(missingFields, "expires")
+	}
+
+	if len(missingFields) > 0 {
+		return fmt.Errorf("missing fields: %s", strings.Join(missingFields, ", "))
+	}
+
+	for _, pattern := range []string{w.Namespace, w.Kind, w.Name} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// IsExpired returns true if the waiver's expiration date is before the given time.
+func (w *Waiver) IsExpired(now time.Time) bool {
+	return !now.Before(w.Expires)
+}
+
+func (w *Waiver) String() string {
+	return fmt.Sprintf("testID=%s namespace=%s kind=%s name=%s (approved by %s, expires %s)",
+		w.TestID, w.Namespace, w.Kind, w.Name, w.ApprovedBy, w.Expires.Format(time.RFC3339))
+}
+
+func matchesPattern(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	matched, _ := path.Match(pattern, value)
+	return matched
+}
+
+// Returns the value of the object's field whose key matches the given one, case-insensitive.
+func getFieldValue(obj *testhelper.ReportObject, key string) (string, bool) {
+	for i, fieldKey := range obj.ObjectFieldsKeys {
+		if strings.EqualFold(fieldKey, key) && i < len(obj.ObjectFieldsValues) {
+			return obj.ObjectFieldsValues[i], true
+		}
+	}
+
+	return "", false
+}
+
+// Matches returns true if the waiver applies to the given report object of the given check. The
+// kind is matched against the object type and the name against the object's "Name" field or the
+// field named after its type, e.g. "Pod Name" for pods.
+func (w *Waiver) Matches(testID string, obj *testhelper.ReportObject) bool {
+	if w.TestID != testID {
+		return false
+	}
+
+	if w.Kind != "" && !strings.EqualFold(w.Kind, obj.ObjectType) && !matchesPattern(w.Kind, obj.ObjectType) {
+		return false
+	}
+
+	if w.Namespace != "" {
+		namespace, found := getFieldValue(obj, testhelper.Namespace)
+		if !found || !matchesPattern(w.Namespace, namespace) {
+			return false
+		}
+	}
+
+	if w.Name != "" {
+		name, found := getFieldValue(obj, testhelper.Name)
+		if !found {
+			name, found = getFieldValue(obj, obj.ObjectType+" Name")
+		}
+		if !found || !matchesPattern(w.Name, name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Apply splits the non-compliant objects of a check in the ones that are still non-compliant and
+// the ones that are waived by any of the waivers. Objects matching only expired waivers remain
+// non-compliant, and the expired waiver is added to them so it can be easily spotted.
+func Apply(testID string, waivers []Waiver, nonCompliantObjects []*testhelper.ReportObject,
+	now time.Time) (remainingObjects, waivedObjects []*testhelper.ReportObject, expiredWaivers []*Waiver) {
+	for _, obj := range nonCompliantObjects {
+		var validWaiver, expiredWaiver *Waiver
+		for i := range waivers {
+			if !waivers[i].Matches(testID, obj) {
+				continue
+			}
+
+			if waivers[i].IsExpired(now) {
+				expiredWaiver = &waivers[i]
+				continue
+			}
+
+			validWaiver = &waivers[i]
+			break
+		}
+
+		switch {
+		case validWaiver != nil:
+			waivedObj := copyReportObject(obj).
+				AddField(WaiverReasonField, validWaiver.Reason).
+				AddField(WaiverApprovedByField, validWaiver.ApprovedBy).
+				AddField(WaiverExpiresField, validWaiver.Expires.Format(time.RFC3339))
+			waivedObjects = append(waivedObjects, waivedObj)
+		case expiredWaiver != nil:
+			remainingObjects = append(remainingObjects, copyReportObject(obj).AddField(ExpiredWaiverField, expiredWaiver.String()))
+			expiredWaivers = append(expiredWaivers, expiredWaiver)
+		default:
+			remainingObjects = append(remainingObjects, obj)
+		}
+	}
+
+	return remainingObjects, waivedObjects, expiredWaivers
+}
+
+func copyReportObject(obj *testhelper.ReportObject) *testhelper.ReportObject {
+	return &testhelper.ReportObject{
+		ObjectType:         obj.ObjectType,
+		ObjectFieldsKeys:   append([]string{}, obj.ObjectFieldsKeys...),
+		ObjectFieldsValues: append([]string{}, obj.ObjectFieldsValues...),
+	}
+}
@@ -0,0 +1,89 @@
+package waivers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadWaivers(t *testing.T) {
+	waivers, err := LoadWaivers("testdata/waivers.yml")
+	assert.Nil(t, err)
+	assert.Len(t, waivers, 2)
+
+	assert.Equal(t, "access-control-sys-admin-capability-check", waivers[0].TestID)
+	assert.Equal(t, "tnf", waivers[0].Namespace)
+	assert.Equal(t, "Container", waivers[0].Kind)
+	assert.Equal(t, "test", waivers[0].Name)
+	assert.Equal(t, "jane.doe@example.com", waivers[0].ApprovedBy)
+	assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), waivers[0].Expires)
+	assert.Equal(t, time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC), waivers[1].Expires)
+
+	_, err = LoadWaivers("testdata/non-existent.yml")
+	assert.NotNil(t, err)
+
+	// Waivers must have a reason, an approver and an expiration date.
+	invalidFile := filepath.Join(t.TempDir(), "invalid.yml")
+	assert.Nil(t, os.WriteFile(invalidFile, []byte("waivers:\n  - testID: lifecycle-pod-owner-type\n"), 0o600))
+	_, err = LoadWaivers(invalidFile)
+	assert.ErrorContains(t, err, "missing fields: reason, approvedBy, expires")
+}
+
+func TestMatches(t *testing.T) {
+	container := testhelper.NewContainerReportObject("tnf", "test-pod", "test", "SYS_ADMIN found", false)
+	pod := testhelper.NewPodReportObject("tnf-1", "test-pod", "pod has no owner", false)
+
+	testCases := []struct {
+		waiver        Waiver
+		testID        string
+		obj           *testhelper.ReportObject
+		expectedMatch bool
+	}{
+		{Waiver{TestID: "check1"}, "check1", container, true},
+		{Waiver{TestID: "check1"}, "check2", container, false},
+		{Waiver{TestID: "check1", Namespace: "tnf", Kind: "container", Name: "test"}, "check1", container, true},
+		{Waiver{TestID: "check1", Namespace: "other"}, "check1", container, false},
+		{Waiver{TestID: "check1", Kind: "Pod"}, "check1", container, false},
+		{Waiver{TestID: "check1", Name: "test-pod"}, "check1", container, false},
+		{Waiver{TestID: "check1", Namespace: "tnf-*", Name: "test-*"}, "check1", pod, true},
+		{Waiver{TestID: "check1", Namespace: "tnf-*"}, "check1", container, false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedMatch, tc.waiver.Matches(tc.testID, tc.obj), "waiver %s", tc.waiver.String())
+	}
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	waivers := []Waiver{
+		{TestID: "check1", Name: "pod1", Reason: "reason1", ApprovedBy: "user1", Expires: now.Add(time.Hour)},
+		{TestID: "check1", Name: "pod2", Reason: "reason2", ApprovedBy: "user2", Expires: now.Add(-time.Hour)},
+	}
+
+	nonCompliantObjects := []*testhelper.ReportObject{
+		testhelper.NewPodReportObject("tnf", "pod1", "reason", false),
+		testhelper.NewPodReportObject("tnf", "pod2", "reason", false),
+		testhelper.NewPodReportObject("tnf", "pod3", "reason", false),
+	}
+
+	remaining, waived, expired := Apply("check1", waivers, nonCompliantObjects, now)
+	assert.Len(t, waived, 1)
+	assert.Contains(t, waived[0].ObjectFieldsValues, "pod1")
+	assert.Contains(t, waived[0].ObjectFieldsKeys, WaiverReasonField)
+	assert.Contains(t, waived[0].ObjectFieldsValues, "user1")
+
+	assert.Len(t, remaining, 2)
+	assert.Contains(t, remaining[0].ObjectFieldsKeys, ExpiredWaiverField)
+	assert.NotContains(t, remaining[1].ObjectFieldsKeys, ExpiredWaiverField)
+
+	assert.Len(t, expired, 1)
+	assert.Equal(t, "reason2", expired[0].Reason)
+
+	// The original objects must not be modified.
+	assert.NotContains(t, nonCompliantObjects[1].ObjectFieldsKeys, ExpiredWaiverField)
+}
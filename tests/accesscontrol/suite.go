@@ -25,7 +25,9 @@ import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/crclient"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/podhelper"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/stringhelper"
@@ -165,6 +167,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestNamespaceBestPracticesIdentifier)).
+		WithPermissions(permissions.ListCrds).
 		WithSkipCheckFn(testhelper.GetNoNamespacesSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testNamespace(c, &env)
@@ -179,20 +182,23 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestPodRoleBindingsBestPracticesIdentifier)).
-		WithSkipCheckFn(testhelper.GetNoPodsUnderTestSkipFn(&env)).
+		WithPermissions(permissions.ListRoleBindings).
+		WithSkipCheckFn(testhelper.GetNoPodsUnderTestSkipFn(&env), testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorRoleBindings)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testPodRoleBindings(c, &env)
 			return nil
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestPodClusterRoleBindingsBestPracticesIdentifier)).
-		WithSkipCheckFn(testhelper.GetNoPodsUnderTestSkipFn(&env)).
+		WithPermissions(permissions.ListClusterRoleBindings).
+		WithSkipCheckFn(testhelper.GetNoPodsUnderTestSkipFn(&env), testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorClusterRoleBindings)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testPodClusterRoleBindings(c, &env)
 			return nil
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestPodAutomountServiceAccountIdentifier)).
+		WithPermissions(permissions.GetServiceAccounts).
 		WithSkipCheckFn(testhelper.GetNoPodsUnderTestSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testAutomountServiceToken(c, &env)
@@ -200,6 +206,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOneProcessPerContainerIdentifier)).
+		WithPermissions(permissions.ExecProbePods).
 		WithSkipCheckFn(testhelper.GetNoContainersUnderTestSkipFn(&env), testhelper.GetDaemonSetFailedToSpawnSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOneProcessPerContainer(c, &env)
@@ -221,13 +228,15 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestNamespaceResourceQuotaIdentifier)).
-		WithSkipCheckFn(testhelper.GetNoPodsUnderTestSkipFn(&env)).
+		WithPermissions(permissions.ListResourceQuotas).
+		WithSkipCheckFn(testhelper.GetNoPodsUnderTestSkipFn(&env), testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorResourceQuotas)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testNamespaceResourceQuota(c, &env)
 			return nil
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestNoSSHDaemonsAllowedIdentifier)).
+		WithPermissions(permissions.ExecProbePods).
 		WithSkipCheckFn(testhelper.GetDaemonSetFailedToSpawnSkipFn(&env), testhelper.GetNoContainersUnderTestSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testNoSSHDaemonsAllowed(c, &env)
@@ -249,6 +258,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestServicesDoNotUseNodeportsIdentifier)).
+		WithPermissions(permissions.ListServices).
 		WithSkipCheckFn(testhelper.GetNoServicesUnderTestSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testNodePort(c, &env)
@@ -256,6 +266,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestCrdRoleIdentifier)).
+		WithPermissions(permissions.ListCrds, permissions.ListRoles).
 		WithSkipCheckFn(testhelper.GetNoCrdsUnderTestSkipFn(&env), testhelper.GetNoNamespacesSkipFn(&env), testhelper.GetNoRolesSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testCrdRoles(c, &env)
@@ -741,7 +752,7 @@ func testOneProcessPerContainer(check *checksdb.Check, env *provider.TestEnviron
 			check.LogError("Debug pod not found for node %q", cut.NodeName)
 			return
 		}
-		ocpContext := clientsholder.NewContext(debugPod.Namespace, debugPod.Name, debugPod.Spec.Containers[0].Name)
+		ocpContext := clientsholder.NewContext(debugPod.Namespace, debugPod.Name, debugPod.Spec.Containers[0].Name).WithContext(check.Context())
 		pid, err := crclient.GetPidFromContainer(cut, ocpContext)
 		if err != nil {
 			check.LogError("Could not get PID for Container %q, error: %v", cut, err)
@@ -874,7 +885,7 @@ func testNoSSHDaemonsAllowed(check *checksdb.Check, env *provider.TestEnvironmen
 		cut := put.Containers[0]
 
 		// 1. Find SSH port
-		port, err := netutil.GetSSHDaemonPort(cut)
+		port, err := netutil.GetSSHDaemonPort(check.Context(), cut)
 		if err != nil {
 			check.LogError("Could not get ssh daemon port on %q, err: %v", cut, err)
 			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewPodReportObject(put.Namespace, put.Name, "Failed to get the ssh port for pod", false))
@@ -896,7 +907,7 @@ func testNoSSHDaemonsAllowed(check *checksdb.Check, env *provider.TestEnvironmen
 
 		// 2. Check if SSH port is listening
 		sshPortInfo := netutil.PortInfo{PortNumber: int32(sshServicePortNumber), Protocol: sshServicePortProtocol}
-		listeningPorts, err := netutil.GetListeningPorts(cut)
+		listeningPorts, err := netutil.GetListeningPorts(check.Context(), cut)
 		if err != nil {
 			check.LogError("Failed to get the listening ports for Pod %q, err: %v", put, err)
 			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewPodReportObject(put.Namespace, put.Name, "Failed to get the listening ports for pod", false))
@@ -28,7 +28,9 @@ import (
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
 	"github.com/redhat-best-practices-for-k8s/oct/pkg/certdb"
@@ -57,6 +59,10 @@ var (
 	}
 
 	skipIfNoOperatorsFn = func() (bool, string) {
+		if failed, reason := testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorOperatorsUnderTest, autodiscover.CollectorOperators)(); failed {
+			return true, reason
+		}
+
 		if len(env.Operators) == 0 {
 			return true, "There are no operators to check. Please check under test labels."
 		}
@@ -65,6 +71,10 @@ var (
 	}
 
 	skipIfNoHelmChartReleasesFn = func() (bool, string) {
+		if failed, reason := testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorHelmCharts)(); failed {
+			return true, reason
+		}
+
 		if len(env.HelmChartReleases) == 0 {
 			return true, "There are no helm chart releases to check."
 		}
@@ -80,6 +90,7 @@ func LoadChecks() {
 		WithBeforeEachFn(beforeEachFn)
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestHelmVersionIdentifier)).
+		WithPermissions(permissions.ListHelmReleases, permissions.ListAllPods).
 		WithSkipCheckFn(skipIfNoHelmChartReleasesFn).
 		WithCheckFn(func(check *checksdb.Check) error {
 			testHelmVersion(check)
@@ -87,6 +98,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOperatorIsCertifiedIdentifier)).
+		WithPermissions(permissions.ListClusterServiceVersions, permissions.ListSubscriptions).
 		WithSkipCheckFn(skipIfNoOperatorsFn).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testAllOperatorCertified(c, &env, validator)
@@ -94,7 +106,8 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestHelmIsCertifiedIdentifier)).
-		WithSkipCheckFn(skipIfNoHelmChartReleasesFn).
+		WithPermissions(permissions.ListHelmReleases).
+		WithSkipCheckFn(skipIfNoHelmChartReleasesFn, testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorK8sVersion)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testHelmCertified(c, &env, validator)
 			return nil
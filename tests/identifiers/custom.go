@@ -0,0 +1,77 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package identifiers
+
+import (
+	"fmt"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+)
+
+// CustomCatalogEntry describes a check that is not part of the test suite, like the ones of the
+// plugins and the policies.
+type CustomCatalogEntry struct {
+	ID          string
+	Description string
+	Remediation string
+	Exception   string
+	Reference   string
+	// Classification of the check by scenario. The scenarios not set are optional.
+	CategoryClassification map[string]string
+	Tags                   []string
+}
+
+// Custom checks are optional in all the scenarios unless they say otherwise.
+func getCustomCategoryClassification(classification map[string]string) map[string]string {
+	categoryClassification := map[string]string{
+		FarEdge:  Optional,
+		Telco:    Optional,
+		NonTelco: Optional,
+		Extended: Optional,
+	}
+
+	for scenario, value := range classification {
+		categoryClassification[scenario] = value
+	}
+
+	return categoryClassification
+}
+
+// AddCustomCatalogEntries adds the custom checks of a suite to the catalog, failing in case the
+// suite or any of the checks' ids, prefixed with the suite name, already exist in the catalog.
+func AddCustomCatalogEntries(suite string, entries []CustomCatalogEntry) ([]claim.Identifier, error) {
+	for aID := range Catalog {
+		if aID.Suite == suite {
+			return nil, fmt.Errorf("suite %q already exists", suite)
+		}
+		for i := range entries {
+			if aID.Id == suite+"-"+entries[i].ID {
+				return nil, fmt.Errorf("check %q already exists", aID.Id)
+			}
+		}
+	}
+
+	aIDs := []claim.Identifier{}
+	for i := range entries {
+		entry := &entries[i]
+		aID := AddCatalogEntry(entry.ID, suite, entry.Description, entry.Remediation, entry.Exception, entry.Reference,
+			false, getCustomCategoryClassification(entry.CategoryClassification), entry.Tags...)
+		aIDs = append(aIDs, aID)
+	}
+
+	return aIDs, nil
+}
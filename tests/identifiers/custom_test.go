@@ -0,0 +1,61 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package identifiers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddCustomCatalogEntries(t *testing.T) {
+	entries := []CustomCatalogEntry{
+		{ID: "check1", Description: "First check", CategoryClassification: map[string]string{Telco: Mandatory}},
+		{ID: "image-registry", Description: "Second check", Tags: []string{TagExtended}},
+	}
+
+	aIDs, err := AddCustomCatalogEntries("custom", entries)
+	assert.Nil(t, err)
+	defer func() {
+		for _, aID := range aIDs {
+			delete(Catalog, aID)
+			delete(Classification, aID.Id)
+		}
+	}()
+	assert.Len(t, aIDs, 2)
+
+	entry := Catalog[aIDs[0]]
+	assert.Equal(t, "custom-check1", entry.Identifier.Id)
+	assert.Equal(t, "custom", entry.Identifier.Suite)
+	assert.Equal(t, TagCommon, entry.Tags)
+	assert.Equal(t, NoDocumentedProcess, entry.ExceptionProcess)
+	assert.Equal(t, map[string]string{
+		FarEdge:  Optional,
+		Telco:    Mandatory,
+		NonTelco: Optional,
+		Extended: Optional,
+	}, entry.CategoryClassification)
+	assert.Equal(t, TagExtended, Catalog[aIDs[1]].Tags)
+
+	// The same suite or check ids cannot be added twice.
+	_, err = AddCustomCatalogEntries("custom", entries)
+	assert.EqualError(t, err, `suite "custom" already exists`)
+
+	// Check ids are prefixed with the suite name.
+	_, err = AddCustomCatalogEntries("custom-image", []CustomCatalogEntry{{ID: "registry"}})
+	assert.EqualError(t, err, `check "custom-image-registry" already exists`)
+}
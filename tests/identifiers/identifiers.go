@@ -95,6 +95,8 @@ var (
 	TestIpcLockIdentifier                             claim.Identifier
 	TestBpfIdentifier                                 claim.Identifier
 	TestStorageProvisioner                            claim.Identifier
+	TestDaemonSetUpdateStrategyIdentifier             claim.Identifier
+	TestJobLimitsIdentifier                           claim.Identifier
 	TestExclusiveCPUPoolIdentifier                    claim.Identifier
 	TestSharedCPUPoolSchedulingPolicy                 claim.Identifier
 	TestExclusiveCPUPoolSchedulingPolicy              claim.Identifier
@@ -354,6 +356,38 @@ func InitCatalog() map[claim.Identifier]claim.TestCaseDescription {
 		},
 		TagCommon)
 
+	TestDaemonSetUpdateStrategyIdentifier = AddCatalogEntry(
+		"daemonset-update-strategy",
+		common.LifecycleTestKey,
+		`Checks that the workload DaemonSets use the RollingUpdate update strategy with a maxUnavailable lower than the number of nodes running the DaemonSet pods, so that an upgrade does not take the DaemonSet down on all nodes at once.`,
+		DaemonSetUpdateStrategyRemediation,
+		NoDocumentedProcess,
+		TestDaemonSetUpdateStrategyIdentifierDocLink,
+		true,
+		map[string]string{
+			FarEdge:  Optional,
+			Telco:    Optional,
+			NonTelco: Optional,
+			Extended: Mandatory,
+		},
+		TagCommon)
+
+	TestJobLimitsIdentifier = AddCatalogEntry(
+		"job-limits",
+		common.LifecycleTestKey,
+		`Checks that the workload Jobs, and the job templates of the workload CronJobs, set backoffLimit, activeDeadlineSeconds and ttlSecondsAfterFinished, so that failing or stuck jobs are bounded and finished jobs are cleaned up.`,
+		JobLimitsRemediation,
+		NoDocumentedProcess,
+		TestJobLimitsIdentifierDocLink,
+		true,
+		map[string]string{
+			FarEdge:  Optional,
+			Telco:    Optional,
+			NonTelco: Optional,
+			Extended: Mandatory,
+		},
+		TagCommon)
+
 	TestContainerPostStartIdentifier = AddCatalogEntry(
 		"container-poststart",
 		common.LifecycleTestKey,
@@ -1065,7 +1099,7 @@ that Node's kernel may not have the same hacks.'`,
 	TestPodHighAvailabilityBestPractices = AddCatalogEntry(
 		"pod-high-availability",
 		common.LifecycleTestKey,
-		`Ensures that workloads Pods specify podAntiAffinity rules and replica value is set to more than 1.`,
+		`Ensures that workloads Deployments, StatefulSets and ReplicaSets not managed by a Deployment specify podAntiAffinity rules and replica value is set to more than 1.`,
 		PodHighAvailabilityBestPracticesRemediation,
 		NoDocumentedProcess+NotApplicableSNO,
 		TestPodHighAvailabilityBestPracticesDocLink,
@@ -1577,7 +1611,7 @@ that Node's kernel may not have the same hacks.'`,
 	TestPodDisruptionBudgetIdentifier = AddCatalogEntry(
 		"pod-disruption-budget",
 		common.ObservabilityTestKey,
-		`Checks to see if pod disruption budgets have allowed values for minAvailable and maxUnavailable`,
+		`Checks to see if pod disruption budgets have allowed values for minAvailable and maxUnavailable for the workload Deployments, StatefulSets and ReplicaSets not managed by a Deployment`,
 		PodDisruptionBudgetRemediation,
 		NoExceptions,
 		TestPodDisruptionBudgetIdentifierDocLink,
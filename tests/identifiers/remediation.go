@@ -176,6 +176,10 @@ const (
 
 	DpdkCPUPinningExecProbeRemediation = "If the workload is doing CPU pinning and running a DPDK process do not use exec probes (executing a command within the container) as it may pile up and block the node eventually."
 
+	DaemonSetUpdateStrategyRemediation = `Set the DaemonSet updateStrategy type to RollingUpdate, with a maxUnavailable lower than the number of nodes running the DaemonSet pods.`
+
+	JobLimitsRemediation = `Set backoffLimit, activeDeadlineSeconds and ttlSecondsAfterFinished in the spec of the Jobs and in the jobTemplate of the CronJobs.`
+
 	CheckStorageProvisionerRemediation = `Use a non-local storage (e.g. no kubernetes.io/no-provisioner and no topolvm.io provisioners) in multinode clusters. Local storage are recommended for single node clusters only, but a single local provisioner should be installed.`
 
 	ExclusiveCPUPoolRemediation = `Ensure that if one container in a Pod selects an exclusive CPU pool the rest also select this type of CPU pool`
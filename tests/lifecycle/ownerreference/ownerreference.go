@@ -44,6 +44,10 @@ func NewOwnerReference(put *corev1.Pod) *OwnerReference {
 
 // func (o *OwnerReference)  run the tests and store results in
 // o.result
+// Pods owned by ReplicaSets, which covers the ones of the deployments and of the bare replicasets under
+// test, or by StatefulSets pass. The pods of DaemonSets and Jobs, including the ones created by CronJobs,
+// fail on purpose: they should not run the workload, and their own settings are checked by
+// lifecycle-daemonset-update-strategy and lifecycle-job-limits.
 func (o *OwnerReference) RunTest(logger *log.Logger) {
 	for _, k := range o.put.OwnerReferences {
 		if k.Kind == statefulSet || k.Kind == replicaSet {
@@ -45,6 +45,14 @@ func TestRunTest(t *testing.T) {
 			podKind:        "NotARealKind",
 			expectedResult: testhelper.FAILURE,
 		},
+		{
+			podKind:        "DaemonSet",
+			expectedResult: testhelper.FAILURE,
+		},
+		{
+			podKind:        "Job",
+			expectedResult: testhelper.FAILURE,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -17,11 +17,15 @@
 package lifecycle
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/postmortem"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
@@ -33,6 +37,7 @@ import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/lifecycle/scaling"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/lifecycle/tolerations"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/lifecycle/volumes"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/lifecycle/workloads"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -61,6 +66,14 @@ var (
 		}
 		return false, ""
 	}
+
+	// replicated podset = deployment, statefulset or bare replicaset
+	skipIfNoReplicatedPodSetsUnderTest = func() (bool, string) {
+		if len(env.Deployments) == 0 && len(env.StatefulSets) == 0 && len(env.ReplicaSets) == 0 {
+			return true, "no deployments, statefulsets nor replicasets to check found"
+		}
+		return false, ""
+	}
 )
 
 //nolint:funlen
@@ -80,9 +93,15 @@ func LoadChecks() {
 
 	// Scale CRD test
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestCrdScalingIdentifier)).
+		WithPermissions(permissions.ListCrds, permissions.ListHorizontalPodAutoscalers, permissions.GetHorizontalPodAutoscalers, permissions.UpdateHorizontalPodAutoscalers).
+		WithEnvPermissionsFn(func(env *provider.TestEnvironment) []permissions.Permission {
+			return autodiscover.GetCrScalePermissions(env.Crds)
+		}).
+		WithIntrusive().
 		WithSkipCheckFn(
 			testhelper.GetNoCrdsUnderTestSkipFn(&env),
-			testhelper.GetNotIntrusiveSkipFn(&env)).
+			testhelper.GetNotIntrusiveSkipFn(&env),
+			testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorScaleCrs, autodiscover.CollectorHorizontalPodAutoscalers)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			// Note: We skip this test because 'testHighAvailability' in the lifecycle suite is already
 			// testing the replicas and antiaffinity rules that should already be in place for crd.
@@ -140,8 +159,9 @@ func LoadChecks() {
 
 	// High availability test
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestPodHighAvailabilityBestPractices)).
+		WithPermissions(permissions.ListNodes, permissions.ListDeployments, permissions.ListStatefulSets, permissions.ListReplicaSets).
 		WithSkipCheckFn(testhelper.GetNotEnoughWorkersSkipFn(&env, minWorkerNodesForLifecycle)).
-		WithSkipCheckFn(skipIfNoPodSetsetsUnderTest).
+		WithSkipCheckFn(skipIfNoReplicatedPodSetsUnderTest).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testHighAvailability(c, &env)
 			return nil
@@ -149,6 +169,7 @@ func LoadChecks() {
 
 	// Selector and affinity best practices test
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestPodNodeSelectorAndAffinityBestPractices)).
+		WithPermissions(permissions.ListNodes).
 		WithSkipCheckFn(
 			testhelper.GetNotEnoughWorkersSkipFn(&env, minWorkerNodesForLifecycle),
 			testhelper.GetPodsWithoutAffinityRequiredLabelSkipFn(&env)).
@@ -159,6 +180,8 @@ func LoadChecks() {
 
 	// Pod recreation test
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestPodRecreationIdentifier)).
+		WithPermissions(permissions.ListNodes, permissions.ListDeployments, permissions.ListStatefulSets, permissions.GetNodes, permissions.UpdateNodes, permissions.WatchPods, permissions.DeletePods, permissions.GetDeployments, permissions.GetStatefulSets).
+		WithIntrusive().
 		WithSkipCheckFn(
 			testhelper.GetNotEnoughWorkersSkipFn(&env, minWorkerNodesForLifecycle),
 			testhelper.GetNotIntrusiveSkipFn(&env)).
@@ -170,9 +193,12 @@ func LoadChecks() {
 
 	// Deployment scaling test
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestDeploymentScalingIdentifier)).
+		WithPermissions(permissions.ListNodes, permissions.ListDeployments, permissions.ListHorizontalPodAutoscalers, permissions.GetDeployments, permissions.UpdateDeployments, permissions.GetHorizontalPodAutoscalers, permissions.UpdateHorizontalPodAutoscalers).
+		WithIntrusive().
 		WithSkipCheckFn(
 			testhelper.GetNotIntrusiveSkipFn(&env),
-			testhelper.GetNotEnoughWorkersSkipFn(&env, minWorkerNodesForLifecycle)).
+			testhelper.GetNotEnoughWorkersSkipFn(&env, minWorkerNodesForLifecycle),
+			testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorHorizontalPodAutoscalers)).
 		WithSkipCheckFn(skipIfNoPodSetsetsUnderTest).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testDeploymentScaling(&env, timeout, c)
@@ -181,9 +207,12 @@ func LoadChecks() {
 
 	// Statefulset scaling test
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestStateFulSetScalingIdentifier)).
+		WithPermissions(permissions.ListNodes, permissions.ListStatefulSets, permissions.ListHorizontalPodAutoscalers, permissions.GetStatefulSets, permissions.UpdateStatefulSets, permissions.GetHorizontalPodAutoscalers, permissions.UpdateHorizontalPodAutoscalers).
+		WithIntrusive().
 		WithSkipCheckFn(
 			testhelper.GetNotIntrusiveSkipFn(&env),
-			testhelper.GetNotEnoughWorkersSkipFn(&env, minWorkerNodesForLifecycle)).
+			testhelper.GetNotEnoughWorkersSkipFn(&env, minWorkerNodesForLifecycle),
+			testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorHorizontalPodAutoscalers)).
 		WithSkipCheckFn(skipIfNoPodSetsetsUnderTest).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testStatefulSetScaling(&env, timeout, c)
@@ -192,6 +221,7 @@ func LoadChecks() {
 
 	// Persistent volume reclaim policy test
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestPersistentVolumeReclaimPolicyIdentifier)).
+		WithPermissions(permissions.ListPersistentVolumes).
 		WithSkipCheckFn(
 			testhelper.GetNoPersistentVolumesSkipFn(&env),
 			testhelper.GetNoPodsUnderTestSkipFn(&env)).
@@ -226,6 +256,7 @@ func LoadChecks() {
 
 	// Storage provisioner test
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestStorageProvisioner)).
+		WithPermissions(permissions.ListStorageClasses, permissions.ListPersistentVolumeClaims).
 		WithSkipCheckFn(
 			testhelper.GetNoPodsUnderTestSkipFn(&env),
 			testhelper.GetNoStorageClassesSkipFn(&env),
@@ -234,6 +265,24 @@ func LoadChecks() {
 			testStorageProvisioner(c, &env)
 			return nil
 		}))
+
+	// DaemonSet update strategy test
+	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestDaemonSetUpdateStrategyIdentifier)).
+		WithPermissions(permissions.ListDaemonSets).
+		WithSkipCheckFn(testhelper.GetNoDaemonSetsUnderTestSkipFn(&env)).
+		WithCheckFn(func(c *checksdb.Check) error {
+			testDaemonSetUpdateStrategy(c, &env)
+			return nil
+		}))
+
+	// Job limits test
+	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestJobLimitsIdentifier)).
+		WithPermissions(permissions.ListJobs, permissions.ListCronJobs).
+		WithSkipCheckFn(testhelper.GetNoJobsUnderTestSkipFn(&env)).
+		WithCheckFn(func(c *checksdb.Check) error {
+			testJobLimits(c, &env)
+			return nil
+		}))
 }
 
 func testContainersPreStop(check *checksdb.Check, env *provider.TestEnvironment) {
@@ -521,54 +570,41 @@ func testStatefulSetScaling(env *provider.TestEnvironment, timeout time.Duration
 }
 
 // testHighAvailability
-func testHighAvailability(check *checksdb.Check, env *provider.TestEnvironment) {
-	var compliantObjects []*testhelper.ReportObject
-	var nonCompliantObjects []*testhelper.ReportObject
-	for _, dp := range env.Deployments {
-		check.LogInfo("Testing Deployment %q", dp.ToString())
-		if dp.Spec.Replicas == nil || *(dp.Spec.Replicas) <= 1 {
-			check.LogError("Deployment %q found without valid high availability (number of replicas must be greater than 1)", dp.ToString())
-			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewDeploymentReportObject(dp.Namespace, dp.Name, "Deployment found without valid high availability", false))
-			continue
-		}
+// Returns the report objects of the high availability of a pod set, none if its pods require affinity.
+func getPodSetHighAvailabilityReportObjects(check *checksdb.Check, podSet *provider.ReplicatedPodSet) (compliantObjects, nonCompliantObjects []*testhelper.ReportObject) {
+	check.LogInfo("Testing %s %q", podSet.Kind, podSet.ToString())
+	if podSet.Replicas == nil || *(podSet.Replicas) <= 1 {
+		check.LogError("%s %q found without valid high availability (number of replicas must be greater than 1)", podSet.Kind, podSet.ToString())
+		nonCompliantObjects = append(nonCompliantObjects, testhelper.NewReplicatedPodSetReportObject(podSet, podSet.Kind+" found without valid high availability", false))
+		return compliantObjects, nonCompliantObjects
+	}
 
-		// Skip any AffinityRequired pods
-		//nolint:goconst
-		if dp.Spec.Template.Labels["AffinityRequired"] == "true" {
-			check.LogInfo("Skipping Deployment %q with affinity required", dp.ToString())
-			continue
-		}
+	// Skip any AffinityRequired pods
+	//nolint:goconst
+	if podSet.Template.Labels["AffinityRequired"] == "true" {
+		check.LogInfo("Skipping %s %q with affinity required", podSet.Kind, podSet.ToString())
+		return compliantObjects, nonCompliantObjects
+	}
 
-		if dp.Spec.Template.Spec.Affinity == nil ||
-			dp.Spec.Template.Spec.Affinity.PodAntiAffinity == nil {
-			check.LogError("Deployment %q found without valid high availability (PodAntiAffinity must be defined)", dp.ToString())
-			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewDeploymentReportObject(dp.Namespace, dp.Name, "Deployment found without valid high availability", false))
-		} else {
-			check.LogInfo("Deployment %q has valid high availability", dp.ToString())
-			compliantObjects = append(compliantObjects, testhelper.NewDeploymentReportObject(dp.Namespace, dp.Name, "Deployment has valid high availability", true))
-		}
+	if podSet.Template.Spec.Affinity == nil ||
+		podSet.Template.Spec.Affinity.PodAntiAffinity == nil {
+		check.LogError("%s %q found without valid high availability (PodAntiAffinity must be defined)", podSet.Kind, podSet.ToString())
+		nonCompliantObjects = append(nonCompliantObjects, testhelper.NewReplicatedPodSetReportObject(podSet, podSet.Kind+" found without valid high availability", false))
+	} else {
+		check.LogInfo("%s %q has valid high availability", podSet.Kind, podSet.ToString())
+		compliantObjects = append(compliantObjects, testhelper.NewReplicatedPodSetReportObject(podSet, podSet.Kind+" has valid high availability", true))
 	}
-	for _, st := range env.StatefulSets {
-		if st.Spec.Replicas == nil || *(st.Spec.Replicas) <= 1 {
-			check.LogError("StatefulSet %q found without valid high availability (number of replicas must be greater than 1)", st.ToString())
-			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewStatefulSetReportObject(st.Namespace, st.Name, "StatefulSet found without valid high availability", false))
-			continue
-		}
 
-		// Skip any AffinityRequired pods
-		if st.Spec.Template.Labels["AffinityRequired"] == "true" {
-			check.LogInfo("Skipping StatefulSet %q with affinity required", st.ToString())
-			continue
-		}
+	return compliantObjects, nonCompliantObjects
+}
 
-		if st.Spec.Template.Spec.Affinity == nil ||
-			st.Spec.Template.Spec.Affinity.PodAntiAffinity == nil {
-			check.LogError("StatefulSet %q found without valid high availability (PodAntiAffinity must be defined)", st.ToString())
-			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewStatefulSetReportObject(st.Namespace, st.Name, "StatefulSet found without valid high availability", false))
-		} else {
-			check.LogInfo("StatefulSet %q has valid high availability", st.ToString())
-			compliantObjects = append(compliantObjects, testhelper.NewStatefulSetReportObject(st.Namespace, st.Name, "StatefulSet has valid high availability", true))
-		}
+func testHighAvailability(check *checksdb.Check, env *provider.TestEnvironment) {
+	var compliantObjects []*testhelper.ReportObject
+	var nonCompliantObjects []*testhelper.ReportObject
+	for _, podSet := range env.GetReplicatedPodSets() {
+		podSetCompliantObjects, podSetNonCompliantObjects := getPodSetHighAvailabilityReportObjects(check, podSet)
+		compliantObjects = append(compliantObjects, podSetCompliantObjects...)
+		nonCompliantObjects = append(nonCompliantObjects, podSetNonCompliantObjects...)
 	}
 
 	check.SetResult(compliantObjects, nonCompliantObjects)
@@ -883,3 +919,54 @@ func testStorageProvisioner(check *checksdb.Check, env *provider.TestEnvironment
 	}
 	check.SetResult(compliantObjects, nonCompliantObjects)
 }
+
+func testDaemonSetUpdateStrategy(check *checksdb.Check, env *provider.TestEnvironment) {
+	var compliantObjects []*testhelper.ReportObject
+	var nonCompliantObjects []*testhelper.ReportObject
+	for _, ds := range env.DaemonSets {
+		check.LogInfo("Testing DaemonSet %q", ds.ToString())
+		issue, err := workloads.GetDaemonSetUpdateStrategyIssue(ds)
+		if err != nil {
+			check.LogError("Failed to check the update strategy of DaemonSet %q, err: %v", ds.ToString(), err)
+			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewDaemonSetReportObject(ds.Namespace, ds.Name, fmt.Sprintf("Invalid update strategy: %v", err), false))
+			continue
+		}
+
+		if issue != "" {
+			check.LogError("DaemonSet %q has a non compliant update strategy: %s", ds.ToString(), issue)
+			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewDaemonSetReportObject(ds.Namespace, ds.Name, issue, false))
+		} else {
+			check.LogInfo("DaemonSet %q has a compliant update strategy", ds.ToString())
+			compliantObjects = append(compliantObjects, testhelper.NewDaemonSetReportObject(ds.Namespace, ds.Name, "DaemonSet has a compliant update strategy", true))
+		}
+	}
+
+	check.SetResult(compliantObjects, nonCompliantObjects)
+}
+
+func testJobLimits(check *checksdb.Check, env *provider.TestEnvironment) {
+	var compliantObjects []*testhelper.ReportObject
+	var nonCompliantObjects []*testhelper.ReportObject
+	for _, job := range env.Jobs {
+		check.LogInfo("Testing Job %q", job.ToString())
+		if missing := workloads.GetMissingJobLimits(&job.Spec); len(missing) > 0 {
+			check.LogError("Job %q does not set %s", job.ToString(), strings.Join(missing, ", "))
+			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewJobReportObject(job.Namespace, job.Name, "Job does not set "+strings.Join(missing, ", "), false))
+		} else {
+			check.LogInfo("Job %q sets all its limits", job.ToString())
+			compliantObjects = append(compliantObjects, testhelper.NewJobReportObject(job.Namespace, job.Name, "Job sets all its limits", true))
+		}
+	}
+	for _, cronJob := range env.CronJobs {
+		check.LogInfo("Testing CronJob %q", cronJob.ToString())
+		if missing := workloads.GetMissingJobLimits(&cronJob.Spec.JobTemplate.Spec); len(missing) > 0 {
+			check.LogError("CronJob %q job template does not set %s", cronJob.ToString(), strings.Join(missing, ", "))
+			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewCronJobReportObject(cronJob.Namespace, cronJob.Name, "CronJob job template does not set "+strings.Join(missing, ", "), false))
+		} else {
+			check.LogInfo("CronJob %q job template sets all its limits", cronJob.ToString())
+			compliantObjects = append(compliantObjects, testhelper.NewCronJobReportObject(cronJob.Namespace, cronJob.Name, "CronJob job template sets all its limits", true))
+		}
+	}
+
+	check.SetResult(compliantObjects, nonCompliantObjects)
+}
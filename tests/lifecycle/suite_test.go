@@ -19,8 +19,12 @@ package lifecycle
 import (
 	"testing"
 
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestNameInDeploymentSkipList(t *testing.T) {
@@ -94,3 +98,41 @@ func TestNameInStatefulSetSkipList(t *testing.T) {
 		assert.Equal(t, tc.expectedOutput, nameInStatefulSetSkipList(tc.testName, tc.testNamespace, tc.testList))
 	}
 }
+
+func TestGetPodSetHighAvailabilityReportObjects(t *testing.T) {
+	one, two := int32(1), int32(2)
+	antiAffinityTemplate := corev1.PodTemplateSpec{Spec: corev1.PodSpec{Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}}}
+	affinityRequiredTemplate := corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"AffinityRequired": "true"}}}
+
+	testCases := []struct {
+		podSet               *provider.ReplicatedPodSet
+		expectedCompliant    int
+		expectedNonCompliant int
+	}{
+		{
+			podSet:            &provider.ReplicatedPodSet{Kind: provider.DeploymentKind, Name: "dp1", Replicas: &two, Template: &antiAffinityTemplate},
+			expectedCompliant: 1,
+		},
+		{
+			podSet:               &provider.ReplicatedPodSet{Kind: provider.StatefulSetKind, Name: "ss1", Replicas: &one, Template: &antiAffinityTemplate},
+			expectedNonCompliant: 1,
+		},
+		{
+			podSet:               &provider.ReplicatedPodSet{Kind: provider.ReplicaSetKind, Name: "rs1", Replicas: &two, Template: &corev1.PodTemplateSpec{}},
+			expectedNonCompliant: 1,
+		},
+		{
+			podSet: &provider.ReplicatedPodSet{Kind: provider.ReplicaSetKind, Name: "rs2", Replicas: &two, Template: &affinityRequiredTemplate},
+		},
+	}
+
+	for _, tc := range testCases {
+		compliantObjects, nonCompliantObjects := getPodSetHighAvailabilityReportObjects(checksdb.NewCheck("test", nil), tc.podSet)
+		assert.Len(t, compliantObjects, tc.expectedCompliant)
+		assert.Len(t, nonCompliantObjects, tc.expectedNonCompliant)
+		for _, obj := range append(compliantObjects, nonCompliantObjects...) {
+			assert.Equal(t, tc.podSet.Kind, obj.ObjectType)
+			assert.Equal(t, tc.podSet.Name, obj.GetName())
+		}
+	}
+}
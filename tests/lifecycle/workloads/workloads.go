@@ -0,0 +1,62 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package workloads
+
+import (
+	"fmt"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// GetDaemonSetUpdateStrategyIssue returns why the update strategy of the daemonset is not compliant,
+// or an empty string when it is.
+func GetDaemonSetUpdateStrategyIssue(ds *provider.DaemonSet) (string, error) {
+	if ds.Spec.UpdateStrategy.Type != appsv1.RollingUpdateDaemonSetStrategyType {
+		return fmt.Sprintf("DaemonSet uses the %s update strategy instead of %s", ds.Spec.UpdateStrategy.Type, appsv1.RollingUpdateDaemonSetStrategyType), nil
+	}
+
+	maxUnavailable, err := ds.GetMaxUnavailable()
+	if err != nil {
+		return "", err
+	}
+
+	// With a single node, the only pod is always unavailable during the update.
+	desired := int(ds.Status.DesiredNumberScheduled)
+	if desired > 1 && maxUnavailable >= desired {
+		return fmt.Sprintf("DaemonSet maxUnavailable (%d) allows all of its %d pods to be unavailable during an update", maxUnavailable, desired), nil
+	}
+
+	return "", nil
+}
+
+// GetMissingJobLimits returns the limits that are not set in the job spec. Note that the API server
+// defaults the backoffLimit of the Jobs, but not the one of the job templates of the CronJobs.
+func GetMissingJobLimits(spec *batchv1.JobSpec) []string {
+	missing := []string{}
+	if spec.BackoffLimit == nil {
+		missing = append(missing, "backoffLimit")
+	}
+	if spec.ActiveDeadlineSeconds == nil {
+		missing = append(missing, "activeDeadlineSeconds")
+	}
+	if spec.TTLSecondsAfterFinished == nil {
+		missing = append(missing, "ttlSecondsAfterFinished")
+	}
+
+	return missing
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package workloads
+
+import (
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestGetDaemonSetUpdateStrategyIssue(t *testing.T) {
+	generateDS := func(strategyType appsv1.DaemonSetUpdateStrategyType, maxUnavailable string, desired int32) *provider.DaemonSet {
+		ds := &provider.DaemonSet{
+			DaemonSet: &appsv1.DaemonSet{
+				Spec:   appsv1.DaemonSetSpec{UpdateStrategy: appsv1.DaemonSetUpdateStrategy{Type: strategyType}},
+				Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: desired},
+			},
+		}
+		if maxUnavailable != "" {
+			value := intstr.Parse(maxUnavailable)
+			ds.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &value}
+		}
+		return ds
+	}
+
+	testCases := []struct {
+		testDS        *provider.DaemonSet
+		expectedIssue string
+		expectedError bool
+	}{
+		{testDS: generateDS(appsv1.RollingUpdateDaemonSetStrategyType, "", 3)},
+		{testDS: generateDS(appsv1.RollingUpdateDaemonSetStrategyType, "50%", 3)},
+		{testDS: generateDS(appsv1.RollingUpdateDaemonSetStrategyType, "1", 1)},
+		{
+			testDS:        generateDS(appsv1.OnDeleteDaemonSetStrategyType, "", 3),
+			expectedIssue: "DaemonSet uses the OnDelete update strategy instead of RollingUpdate",
+		},
+		{
+			testDS:        generateDS(appsv1.RollingUpdateDaemonSetStrategyType, "100%", 3),
+			expectedIssue: "DaemonSet maxUnavailable (3) allows all of its 3 pods to be unavailable during an update",
+		},
+		{
+			testDS:        generateDS(appsv1.RollingUpdateDaemonSetStrategyType, "5", 3),
+			expectedIssue: "DaemonSet maxUnavailable (5) allows all of its 3 pods to be unavailable during an update",
+		},
+		{testDS: generateDS(appsv1.RollingUpdateDaemonSetStrategyType, "bad", 3), expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		issue, err := GetDaemonSetUpdateStrategyIssue(tc.testDS)
+		assert.Equal(t, tc.expectedError, err != nil)
+		assert.Equal(t, tc.expectedIssue, issue)
+	}
+}
+
+func TestGetMissingJobLimits(t *testing.T) {
+	limit := int32(3)
+	seconds := int64(600)
+	ttl := int32(0)
+
+	assert.Equal(t, []string{"backoffLimit", "activeDeadlineSeconds", "ttlSecondsAfterFinished"}, GetMissingJobLimits(&batchv1.JobSpec{}))
+	assert.Equal(t, []string{"activeDeadlineSeconds"}, GetMissingJobLimits(&batchv1.JobSpec{BackoffLimit: &limit, TTLSecondsAfterFinished: &ttl}))
+	assert.Empty(t, GetMissingJobLimits(&batchv1.JobSpec{BackoffLimit: &limit, ActiveDeadlineSeconds: &seconds, TTLSecondsAfterFinished: &ttl}))
+}
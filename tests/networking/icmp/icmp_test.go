@@ -17,6 +17,7 @@
 package icmp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -968,6 +969,7 @@ func TestRunNetworkingTests(t *testing.T) {
 				TestPing = TestPingFailure
 			}
 			gotReport, _ := RunNetworkingTests(
+				context.TODO(),
 				tt.args.netsUnderTest,
 				tt.args.count,
 				tt.args.aIPVersion,
@@ -984,11 +986,11 @@ func TestRunNetworkingTests(t *testing.T) {
 	}
 }
 
-var TestPingSuccess = func(sourceContainerID *provider.Container, targetContainerIP netcommons.ContainerIP, count int) (results PingResults, err error) {
+var TestPingSuccess = func(_ context.Context, sourceContainerID *provider.Container, targetContainerIP netcommons.ContainerIP, count int) (results PingResults, err error) {
 	return PingResults{outcome: testhelper.SUCCESS, transmitted: 10, received: 10, errors: 0}, nil
 }
 
-var TestPingFailure = func(sourceContainerID *provider.Container, targetContainerIP netcommons.ContainerIP, count int) (results PingResults, err error) {
+var TestPingFailure = func(_ context.Context, sourceContainerID *provider.Container, targetContainerIP netcommons.ContainerIP, count int) (results PingResults, err error) {
 	return PingResults{
 			outcome:     testhelper.FAILURE,
 			transmitted: 10,
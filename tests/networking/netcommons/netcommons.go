@@ -17,6 +17,7 @@
 package netcommons
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"strconv"
@@ -191,7 +192,7 @@ var ReservedIstioPorts = map[int32]bool{
 	15000: true, // Envoy admin port (commands/diagnostics)
 }
 
-func findRoguePodsListeningToPorts(pods []*provider.Pod, portsToTest map[int32]bool, portsOrigin string, logger *log.Logger) (compliantObjects, nonCompliantObjects []*testhelper.ReportObject) {
+func findRoguePodsListeningToPorts(ctx context.Context, pods []*provider.Pod, portsToTest map[int32]bool, portsOrigin string, logger *log.Logger) (compliantObjects, nonCompliantObjects []*testhelper.ReportObject) {
 	for _, put := range pods {
 		logger.Info("Testing Pod %q", put)
 		compliantObjectsEntries, nonCompliantObjectsEntries := findRogueContainersDeclaringPorts(put.Containers, portsToTest, portsOrigin, logger)
@@ -199,7 +200,7 @@ func findRoguePodsListeningToPorts(pods []*provider.Pod, portsToTest map[int32]b
 		compliantObjects = append(compliantObjects, compliantObjectsEntries...)
 		nonCompliantObjects = append(nonCompliantObjects, nonCompliantObjectsEntries...)
 		cut := put.Containers[0]
-		listeningPorts, err := netutil.GetListeningPorts(cut)
+		listeningPorts, err := netutil.GetListeningPorts(ctx, cut)
 		if err != nil {
 			logger.Error("Failed to get the listening ports on %q, err: %v", cut, err)
 			nonCompliantObjects = append(nonCompliantObjects,
@@ -247,8 +248,8 @@ func findRoguePodsListeningToPorts(pods []*provider.Pod, portsToTest map[int32]b
 	return compliantObjects, nonCompliantObjects
 }
 
-func TestReservedPortsUsage(env *provider.TestEnvironment, reservedPorts map[int32]bool, portsOrigin string, logger *log.Logger) (compliantObjects, nonCompliantObjects []*testhelper.ReportObject) {
-	compliantObjectsEntries, nonCompliantObjectsEntries := findRoguePodsListeningToPorts(env.Pods, reservedPorts, portsOrigin, logger)
+func TestReservedPortsUsage(ctx context.Context, env *provider.TestEnvironment, reservedPorts map[int32]bool, portsOrigin string, logger *log.Logger) (compliantObjects, nonCompliantObjects []*testhelper.ReportObject) {
+	compliantObjectsEntries, nonCompliantObjectsEntries := findRoguePodsListeningToPorts(ctx, env.Pods, reservedPorts, portsOrigin, logger)
 	compliantObjects = append(compliantObjects, compliantObjectsEntries...)
 	nonCompliantObjects = append(nonCompliantObjects, nonCompliantObjectsEntries...)
 
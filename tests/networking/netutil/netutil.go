@@ -17,6 +17,7 @@
 package netutil
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -69,8 +70,8 @@ func parseListeningPorts(cmdOut string) (map[PortInfo]bool, error) {
 	return portSet, nil
 }
 
-func GetListeningPorts(cut *provider.Container) (map[PortInfo]bool, error) {
-	outStr, errStr, err := crclient.ExecCommandContainerNSEnter(getListeningPortsCmd, cut)
+func GetListeningPorts(ctx context.Context, cut *provider.Container) (map[PortInfo]bool, error) {
+	outStr, errStr, err := crclient.ExecCommandContainerNSEnter(ctx, getListeningPortsCmd, cut)
 	if err != nil || errStr != "" {
 		return nil, fmt.Errorf("failed to execute command %s on %s, err: %v", getListeningPortsCmd, cut, err)
 	}
@@ -78,9 +79,9 @@ func GetListeningPorts(cut *provider.Container) (map[PortInfo]bool, error) {
 	return parseListeningPorts(outStr)
 }
 
-func GetSSHDaemonPort(cut *provider.Container) (string, error) {
+func GetSSHDaemonPort(ctx context.Context, cut *provider.Container) (string, error) {
 	const findSSHDaemonPort = "ss -tpln | grep sshd | head -1 | awk '{ print $4 }' | awk -F : '{ print $2 }'"
-	outStr, errStr, err := crclient.ExecCommandContainerNSEnter(findSSHDaemonPort, cut)
+	outStr, errStr, err := crclient.ExecCommandContainerNSEnter(ctx, findSSHDaemonPort, cut)
 	if err != nil || errStr != "" {
 		return "", fmt.Errorf("failed to execute command %s on %s, err: %v", findSSHDaemonPort, cut, err)
 	}
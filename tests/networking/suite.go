@@ -21,7 +21,9 @@ import (
 	"strconv"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/common"
@@ -63,6 +65,7 @@ func LoadChecks() {
 
 	// Default interface ICMP IPv4 test case
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestICMPv4ConnectivityIdentifier)).
+		WithPermissions(permissions.ExecProbePods).
 		WithSkipCheckFn(testhelper.GetNoContainersUnderTestSkipFn(&env), testhelper.GetDaemonSetFailedToSpawnSkipFn(&env), testhelper.GetNoPodsUnderTestSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testNetworkConnectivity(&env, netcommons.IPv4, netcommons.DEFAULT, c)
@@ -71,6 +74,7 @@ func LoadChecks() {
 
 	// Multus interfaces ICMP IPv4 test case
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestICMPv4ConnectivityMultusIdentifier)).
+		WithPermissions(permissions.ExecProbePods).
 		WithSkipCheckFn(testhelper.GetNoContainersUnderTestSkipFn(&env), testhelper.GetDaemonSetFailedToSpawnSkipFn(&env), testhelper.GetNoPodsUnderTestSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testNetworkConnectivity(&env, netcommons.IPv4, netcommons.MULTUS, c)
@@ -79,6 +83,7 @@ func LoadChecks() {
 
 	// Default interface ICMP IPv6 test case
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestICMPv6ConnectivityIdentifier)).
+		WithPermissions(permissions.ExecProbePods).
 		WithSkipCheckFn(testhelper.GetNoContainersUnderTestSkipFn(&env), testhelper.GetDaemonSetFailedToSpawnSkipFn(&env), testhelper.GetNoPodsUnderTestSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testNetworkConnectivity(&env, netcommons.IPv6, netcommons.DEFAULT, c)
@@ -87,6 +92,7 @@ func LoadChecks() {
 
 	// Multus interfaces ICMP IPv6 test case
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestICMPv6ConnectivityMultusIdentifier)).
+		WithPermissions(permissions.ExecProbePods).
 		WithSkipCheckFn(testhelper.GetNoContainersUnderTestSkipFn(&env), testhelper.GetDaemonSetFailedToSpawnSkipFn(&env), testhelper.GetNoPodsUnderTestSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testNetworkConnectivity(&env, netcommons.IPv6, netcommons.MULTUS, c)
@@ -95,6 +101,7 @@ func LoadChecks() {
 
 	// Undeclared container ports usage test case
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestUndeclaredContainerPortsUsage)).
+		WithPermissions(permissions.ExecProbePods).
 		WithSkipCheckFn(testhelper.GetNoContainersUnderTestSkipFn(&env), testhelper.GetDaemonSetFailedToSpawnSkipFn(&env), testhelper.GetNoPodsUnderTestSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testUndeclaredContainerPortsUsage(c, &env)
@@ -103,6 +110,7 @@ func LoadChecks() {
 
 	// OCP reserved ports usage test case
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOCPReservedPortsUsage)).
+		WithPermissions(permissions.ExecProbePods).
 		WithSkipCheckFn(testhelper.GetNoContainersUnderTestSkipFn(&env), testhelper.GetDaemonSetFailedToSpawnSkipFn(&env), testhelper.GetNoPodsUnderTestSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOCPReservedPortsUsage(c, &env)
@@ -111,6 +119,7 @@ func LoadChecks() {
 
 	// Dual stack services test case
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestServiceDualStackIdentifier)).
+		WithPermissions(permissions.ListServices).
 		WithSkipCheckFn(testhelper.GetNoServicesUnderTestSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testDualStackServices(c, &env)
@@ -119,7 +128,8 @@ func LoadChecks() {
 
 	// Network policy deny all test case
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestNetworkPolicyDenyAllIdentifier)).
-		WithSkipCheckFn(testhelper.GetNoPodsUnderTestSkipFn(&env)).
+		WithPermissions(permissions.ListNetworkPolicies).
+		WithSkipCheckFn(testhelper.GetNoPodsUnderTestSkipFn(&env), testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorNetworkPolicies)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testNetworkPolicyDenyAll(c, &env)
 			return nil
@@ -127,6 +137,7 @@ func LoadChecks() {
 
 	// Extended partner ports test case
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestReservedExtendedPartnerPorts)).
+		WithPermissions(permissions.ExecProbePods).
 		WithSkipCheckFn(testhelper.GetNoPodsUnderTestSkipFn(&env), testhelper.GetDaemonSetFailedToSpawnSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testPartnerSpecificTCPPorts(c, &env)
@@ -135,6 +146,7 @@ func LoadChecks() {
 
 	// DPDK CPU pinning exec probe test case
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestDpdkCPUPinningExecProbe)).
+		WithPermissions(permissions.ExecPods).
 		WithSkipCheckFn(testhelper.GetNoCPUPinningPodsSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			dpdkPods := env.GetCPUPinningPodsWithDpdk()
@@ -144,6 +156,7 @@ func LoadChecks() {
 
 	// Restart on reboot label test case
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestRestartOnRebootLabelOnPodsUsingSRIOV)).
+		WithPermissions(permissions.GetNetworkAttachmentDefinitions).
 		WithSkipCheckFn(testhelper.GetNoSRIOVPodsSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			sriovPods, err := env.GetPodsUsingSRIOV()
@@ -197,7 +210,7 @@ func testUndeclaredContainerPortsUsage(check *checksdb.Check, env *provider.Test
 
 		// Then check the actual ports that the containers are listening on
 		firstPodContainer := put.Containers[0]
-		listeningPorts, err := netutil.GetListeningPorts(firstPodContainer)
+		listeningPorts, err := netutil.GetListeningPorts(check.Context(), firstPodContainer)
 		if err != nil {
 			check.LogError("Failed to get container %q listening ports, err: %v", firstPodContainer, err)
 			nonCompliantObjects = append(nonCompliantObjects,
@@ -252,7 +265,7 @@ func testUndeclaredContainerPortsUsage(check *checksdb.Check, env *provider.Test
 // testDefaultNetworkConnectivity test the connectivity between the default interfaces of containers under test
 func testNetworkConnectivity(env *provider.TestEnvironment, aIPVersion netcommons.IPVersion, aType netcommons.IFType, check *checksdb.Check) {
 	netsUnderTest := icmp.BuildNetTestContext(env.Pods, aIPVersion, aType, check.GetLogger())
-	report, skip := icmp.RunNetworkingTests(netsUnderTest, defaultNumPings, aIPVersion, check.GetLogger())
+	report, skip := icmp.RunNetworkingTests(check.Context(), netsUnderTest, defaultNumPings, aIPVersion, check.GetLogger())
 	if skip {
 		check.LogInfo("There are no %q networks to test with at least 2 pods, skipping test", aIPVersion)
 	}
@@ -264,7 +277,7 @@ func testOCPReservedPortsUsage(check *checksdb.Check, env *provider.TestEnvironm
 	OCPReservedPorts := map[int32]bool{
 		22623: true,
 		22624: true}
-	compliantObjects, nonCompliantObjects := netcommons.TestReservedPortsUsage(env, OCPReservedPorts, "OCP", check.GetLogger())
+	compliantObjects, nonCompliantObjects := netcommons.TestReservedPortsUsage(check.Context(), env, OCPReservedPorts, "OCP", check.GetLogger())
 	check.SetResult(compliantObjects, nonCompliantObjects)
 }
 
@@ -281,7 +294,7 @@ func testPartnerSpecificTCPPorts(check *checksdb.Check, env *provider.TestEnviro
 		15001: true,
 		15000: true,
 	}
-	compliantObjects, nonCompliantObjects := netcommons.TestReservedPortsUsage(env, ReservedPorts, "Partner", check.GetLogger())
+	compliantObjects, nonCompliantObjects := netcommons.TestReservedPortsUsage(check.Context(), env, ReservedPorts, "Partner", check.GetLogger())
 	check.SetResult(compliantObjects, nonCompliantObjects)
 }
 
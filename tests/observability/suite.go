@@ -28,10 +28,13 @@ import (
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -43,6 +46,18 @@ var (
 		env = provider.GetTestEnvironment()
 		return nil
 	}
+
+	// Skips the check only if there are neither deployments nor statefulsets.
+	skipIfNoPodSetsFn = func() (bool, string) {
+		skipDeployments, deploymentsReason := testhelper.GetNoDeploymentsUnderTestSkipFn(&env)()
+		skipStatefulSets, statefulSetsReason := testhelper.GetNoStatefulSetsUnderTestSkipFn(&env)()
+		skipReplicaSets, replicaSetsReason := testhelper.GetNoReplicaSetsUnderTestSkipFn(&env)()
+		if skipDeployments && skipStatefulSets && skipReplicaSets {
+			return true, deploymentsReason + ", " + statefulSetsReason + ", " + replicaSetsReason
+		}
+
+		return false, ""
+	}
 )
 
 func LoadChecks() {
@@ -52,6 +67,7 @@ func LoadChecks() {
 		WithBeforeEachFn(beforeEachFn)
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestLoggingIdentifier)).
+		WithPermissions(permissions.GetPodLogs).
 		WithSkipCheckFn(testhelper.GetNoContainersUnderTestSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testContainersLogging(c, &env)
@@ -59,6 +75,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestCrdsStatusSubresourceIdentifier)).
+		WithPermissions(permissions.ListCrds).
 		WithSkipCheckFn(testhelper.GetNoCrdsUnderTestSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testCrds(c, &env)
@@ -73,8 +90,8 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestPodDisruptionBudgetIdentifier)).
-		WithSkipCheckFn(testhelper.GetNoDeploymentsUnderTestSkipFn(&env), testhelper.GetNoStatefulSetsUnderTestSkipFn(&env)).
-		WithSkipModeAll().
+		WithPermissions(permissions.ListPodDisruptionBudgets, permissions.ListDeployments, permissions.ListStatefulSets, permissions.ListReplicaSets).
+		WithSkipCheckFn(testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorPodDisruptionBudgets), skipIfNoPodSetsFn).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testPodDisruptionBudgets(c, &env)
 			return nil
@@ -185,88 +202,53 @@ func testTerminationMessagePolicy(check *checksdb.Check, env *provider.TestEnvir
 	check.SetResult(compliantObjects, nonCompliantObjects)
 }
 
-//nolint:funlen
-func testPodDisruptionBudgets(check *checksdb.Check, env *provider.TestEnvironment) {
-	var compliantObjects []*testhelper.ReportObject
-	var nonCompliantObjects []*testhelper.ReportObject
-
-	// Loop through all of the of Deployments and StatefulSets and check if the PDBs are valid
-	for _, d := range env.Deployments {
-		check.LogInfo("Testing Deployment %q", d.ToString())
-		deploymentSelector := labels.Set(d.Spec.Template.Labels)
-		pdbFound := false
-		for pdbIndex := range env.PodDisruptionBudgets {
-			pdb := &env.PodDisruptionBudgets[pdbIndex]
-			if pdb.Namespace != d.Namespace {
-				continue
-			}
-			pdbSelector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
-			if err != nil {
-				check.LogError("Could not convert the PDB %q label selector to selector, err: %v", pdbSelector, err)
-				continue
-			}
-			if pdbSelector.Matches(deploymentSelector) {
-				pdbFound = true
-				if ok, err := pdbv1.CheckPDBIsValid(pdb, d.Spec.Replicas); !ok {
-					check.LogError("PDB %q is not valid for Deployment %q, err: %v", pdb.Name, d.Name, err)
-					nonCompliantObjects = append(nonCompliantObjects, testhelper.NewReportObject(fmt.Sprintf("Invalid PodDisruptionBudget config: %v", err), testhelper.DeploymentType, false).
-						AddField(testhelper.DeploymentName, d.Name).
-						AddField(testhelper.Namespace, d.Namespace).
-						AddField(testhelper.PodDisruptionBudgetReference, pdb.Name))
-				} else {
-					check.LogInfo("PDB %q is valid for Deployment: %q", pdb.Name, d.Name)
-					compliantObjects = append(compliantObjects, testhelper.NewReportObject("Deployment: references PodDisruptionBudget", testhelper.DeploymentType, true).
-						AddField(testhelper.DeploymentName, d.Name).
-						AddField(testhelper.Namespace, d.Namespace).
-						AddField(testhelper.PodDisruptionBudgetReference, pdb.Name))
-				}
-			}
+// Returns the report objects of the PDBs of a pod set, which must have a valid PDB in its namespace
+// whose selector matches the labels of its pods.
+func getPodSetPodDisruptionBudgetsReportObjects(check *checksdb.Check, podSet *provider.ReplicatedPodSet,
+	pdbs []policyv1.PodDisruptionBudget) (compliantObjects, nonCompliantObjects []*testhelper.ReportObject) {
+	check.LogInfo("Testing %s %q", podSet.Kind, podSet.ToString())
+	podSetSelector := labels.Set(podSet.Template.Labels)
+	pdbFound := false
+	for pdbIndex := range pdbs {
+		pdb := &pdbs[pdbIndex]
+		if pdb.Namespace != podSet.Namespace {
+			continue
 		}
-		if !pdbFound {
-			check.LogError("Deployment %q is missing a corresponding PodDisruptionBudget", d.ToString())
-			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewReportObject("Deployment is missing a corresponding PodDisruptionBudget", testhelper.DeploymentType, false).
-				AddField(testhelper.DeploymentName, d.Name).
-				AddField(testhelper.Namespace, d.Namespace))
+		pdbSelector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			check.LogError("Could not convert the PDB %q label selector to selector, err: %v", pdbSelector, err)
+			continue
 		}
-	}
-
-	for _, s := range env.StatefulSets {
-		check.LogInfo("Testing StatefulSet %q", s.ToString())
-		statefulSetSelector := labels.Set(s.Spec.Template.Labels)
-		pdbFound := false
-		for pdbIndex := range env.PodDisruptionBudgets {
-			pdb := &env.PodDisruptionBudgets[pdbIndex]
-			if pdb.Namespace != s.Namespace {
-				continue
-			}
-			pdbSelector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
-			if err != nil {
-				check.LogError("Could not convert the PDB %q label selector to selector, err: %v", pdbSelector, err)
-				continue
-			}
-			if pdbSelector.Matches(statefulSetSelector) {
-				pdbFound = true
-				if ok, err := pdbv1.CheckPDBIsValid(pdb, s.Spec.Replicas); !ok {
-					check.LogError("PDB %q is not valid for StatefulSet %q, err: %v", pdb.Name, s.Name, err)
-					nonCompliantObjects = append(nonCompliantObjects, testhelper.NewReportObject(fmt.Sprintf("Invalid PodDisruptionBudget config: %v", err), testhelper.StatefulSetType, false).
-						AddField(testhelper.StatefulSetName, s.Name).
-						AddField(testhelper.Namespace, s.Namespace).
-						AddField(testhelper.PodDisruptionBudgetReference, pdb.Name))
-				} else {
-					check.LogInfo("PDB %q is valid for StatefulSet: %q", pdb.Name, s.Name)
-					compliantObjects = append(compliantObjects, testhelper.NewReportObject("StatefulSet: references PodDisruptionBudget", testhelper.StatefulSetType, true).
-						AddField(testhelper.StatefulSetName, s.Name).
-						AddField(testhelper.Namespace, s.Namespace).
-						AddField(testhelper.PodDisruptionBudgetReference, pdb.Name))
-				}
+		if pdbSelector.Matches(podSetSelector) {
+			pdbFound = true
+			if ok, err := pdbv1.CheckPDBIsValid(pdb, podSet.Replicas); !ok {
+				check.LogError("PDB %q is not valid for %s %q, err: %v", pdb.Name, podSet.Kind, podSet.Name, err)
+				nonCompliantObjects = append(nonCompliantObjects, testhelper.NewReplicatedPodSetReportObject(podSet, fmt.Sprintf("Invalid PodDisruptionBudget config: %v", err), false).
+					AddField(testhelper.PodDisruptionBudgetReference, pdb.Name))
+			} else {
+				check.LogInfo("PDB %q is valid for %s: %q", pdb.Name, podSet.Kind, podSet.Name)
+				compliantObjects = append(compliantObjects, testhelper.NewReplicatedPodSetReportObject(podSet, podSet.Kind+": references PodDisruptionBudget", true).
+					AddField(testhelper.PodDisruptionBudgetReference, pdb.Name))
 			}
 		}
-		if !pdbFound {
-			check.LogError("StatefulSet %q is missing a corresponding PodDisruptionBudget", s.ToString())
-			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewReportObject("StatefulSet is missing a corresponding PodDisruptionBudget", testhelper.StatefulSetType, false).
-				AddField(testhelper.StatefulSetName, s.Name).
-				AddField(testhelper.Namespace, s.Namespace))
-		}
+	}
+	if !pdbFound {
+		check.LogError("%s %q is missing a corresponding PodDisruptionBudget", podSet.Kind, podSet.ToString())
+		nonCompliantObjects = append(nonCompliantObjects, testhelper.NewReplicatedPodSetReportObject(podSet, podSet.Kind+" is missing a corresponding PodDisruptionBudget", false))
+	}
+
+	return compliantObjects, nonCompliantObjects
+}
+
+func testPodDisruptionBudgets(check *checksdb.Check, env *provider.TestEnvironment) {
+	var compliantObjects []*testhelper.ReportObject
+	var nonCompliantObjects []*testhelper.ReportObject
+
+	// Loop through all of the of Deployments, StatefulSets and bare ReplicaSets and check if the PDBs are valid
+	for _, podSet := range env.GetReplicatedPodSets() {
+		podSetCompliantObjects, podSetNonCompliantObjects := getPodSetPodDisruptionBudgetsReportObjects(check, podSet, env.PodDisruptionBudgets)
+		compliantObjects = append(compliantObjects, podSetCompliantObjects...)
+		nonCompliantObjects = append(nonCompliantObjects, podSetNonCompliantObjects...)
 	}
 
 	check.SetResult(compliantObjects, nonCompliantObjects)
@@ -15,3 +15,70 @@
 // 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
 
 package observability
+
+import (
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestGetPodSetPodDisruptionBudgetsReportObjects(t *testing.T) {
+	replicas := int32(2)
+	template := corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}}}
+	newPDB := func(name, namespace string, minAvailable int) policyv1.PodDisruptionBudget {
+		value := intstr.FromInt(minAvailable)
+		return policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+				MinAvailable: &value,
+			},
+		}
+	}
+
+	testCases := []struct {
+		podSet                *provider.ReplicatedPodSet
+		pdbs                  []policyv1.PodDisruptionBudget
+		expectedCompliant     int
+		expectedNonCompliant  int
+		expectedReportObjType string
+	}{
+		{
+			podSet:                &provider.ReplicatedPodSet{Kind: provider.DeploymentKind, Namespace: "ns1", Name: "dp1", Replicas: &replicas, Template: &template},
+			pdbs:                  []policyv1.PodDisruptionBudget{newPDB("pdb1", "ns1", 1)},
+			expectedCompliant:     1,
+			expectedReportObjType: testhelper.DeploymentType,
+		},
+		{
+			// The PDB doesn't allow any disruption.
+			podSet:                &provider.ReplicatedPodSet{Kind: provider.StatefulSetKind, Namespace: "ns1", Name: "ss1", Replicas: &replicas, Template: &template},
+			pdbs:                  []policyv1.PodDisruptionBudget{newPDB("pdb1", "ns1", 3)},
+			expectedNonCompliant:  1,
+			expectedReportObjType: testhelper.StatefulSetType,
+		},
+		{
+			// The PDB is in another namespace.
+			podSet:                &provider.ReplicatedPodSet{Kind: provider.ReplicaSetKind, Namespace: "ns2", Name: "rs1", Replicas: &replicas, Template: &template},
+			pdbs:                  []policyv1.PodDisruptionBudget{newPDB("pdb1", "ns1", 1)},
+			expectedNonCompliant:  1,
+			expectedReportObjType: testhelper.ReplicaSetType,
+		},
+	}
+
+	for _, tc := range testCases {
+		compliantObjects, nonCompliantObjects := getPodSetPodDisruptionBudgetsReportObjects(checksdb.NewCheck("test", nil), tc.podSet, tc.pdbs)
+		assert.Len(t, compliantObjects, tc.expectedCompliant)
+		assert.Len(t, nonCompliantObjects, tc.expectedNonCompliant)
+		for _, obj := range append(compliantObjects, nonCompliantObjects...) {
+			assert.Equal(t, tc.expectedReportObjType, obj.ObjectType)
+			assert.Equal(t, tc.podSet.Name, obj.GetName())
+		}
+	}
+}
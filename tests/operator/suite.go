@@ -26,7 +26,9 @@ import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/operator/phasecheck"
 
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/autodiscover"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/versions"
@@ -49,6 +51,7 @@ func LoadChecks() {
 		WithBeforeEachFn(beforeEachFn)
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOperatorInstallStatusSucceededIdentifier)).
+		WithPermissions(permissions.ListClusterServiceVersions, permissions.ListSubscriptions, permissions.GetClusterServiceVersions).
 		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOperatorInstallationPhaseSucceeded(c, &env)
@@ -56,6 +59,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOperatorNoSCCAccess)).
+		WithPermissions(permissions.ListClusterServiceVersions, permissions.ListSubscriptions).
 		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOperatorInstallationAccessToSCC(c, &env)
@@ -63,6 +67,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOperatorIsInstalledViaOLMIdentifier)).
+		WithPermissions(permissions.ListClusterServiceVersions, permissions.ListSubscriptions).
 		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOperatorOlmSubscription(c, &env)
@@ -70,6 +75,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOperatorHasSemanticVersioningIdentifier)).
+		WithPermissions(permissions.ListClusterServiceVersions, permissions.ListSubscriptions).
 		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOperatorSemanticVersioning(c, &env)
@@ -77,6 +83,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOperatorCrdVersioningIdentifier)).
+		WithPermissions(permissions.ListClusterServiceVersions, permissions.ListSubscriptions, permissions.ListCrds).
 		WithSkipCheckFn(testhelper.GetNoOperatorCrdsSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOperatorCrdVersioning(c, &env)
@@ -84,6 +91,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOperatorCrdSchemaIdentifier)).
+		WithPermissions(permissions.ListClusterServiceVersions, permissions.ListSubscriptions, permissions.ListCrds).
 		WithSkipCheckFn(testhelper.GetNoOperatorCrdsSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOperatorCrdOpenAPISpec(c, &env)
@@ -91,6 +99,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOperatorSingleCrdOwnerIdentifier)).
+		WithPermissions(permissions.ListClusterServiceVersions, permissions.ListSubscriptions).
 		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOperatorSingleCrdOwner(c, &env)
@@ -98,27 +107,31 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOperatorRunAsUserID)).
-		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env)).
+		WithPermissions(permissions.ListClusterServiceVersions, permissions.ListSubscriptions).
+		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env), testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorOperatorPods)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOperatorPodsRunAsUserID(c, &env)
 			return nil
 		}))
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOperatorRunAsNonRoot)).
-		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env)).
+		WithPermissions(permissions.ListClusterServiceVersions, permissions.ListSubscriptions).
+		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env), testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorOperatorPods)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOperatorPodsRunAsNonRoot(c, &env)
 			return nil
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOperatorAutomountTokens)).
-		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env)).
+		WithPermissions(permissions.ListClusterServiceVersions, permissions.ListSubscriptions, permissions.GetServiceAccounts).
+		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env), testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorOperatorPods)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOperatorPodsAutomountTokens(c, &env)
 			return nil
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestOperatorReadOnlyFilesystem)).
-		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env)).
+		WithPermissions(permissions.ListClusterServiceVersions, permissions.ListSubscriptions).
+		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env), testhelper.GetDiscoveryFailedSkipFn(&env, autodiscover.CollectorOperatorPods)).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testOperatorContainersReadOnlyFilesystem(c, &env)
 			return nil
@@ -25,6 +25,7 @@ import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/crclient"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/scheduling"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
@@ -100,6 +101,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestSharedCPUPoolSchedulingPolicy)).
+		WithPermissions(permissions.ExecProbePods).
 		WithSkipCheckFn(skipIfNoNonGuaranteedPodContainersWithoutHostPID).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testSchedulingPolicyInCPUPool(c, &env, env.GetNonGuaranteedPodContainersWithoutHostPID(), scheduling.SharedCPUScheduling)
@@ -107,6 +109,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestExclusiveCPUPoolSchedulingPolicy)).
+		WithPermissions(permissions.ExecProbePods).
 		WithSkipCheckFn(skipIfNoGuaranteedPodContainersWithExclusiveCPUsWithoutHostPID).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testSchedulingPolicyInCPUPool(c, &env, env.GetGuaranteedPodContainersWithExclusiveCPUsWithoutHostPID(), scheduling.ExclusiveCPUScheduling)
@@ -114,6 +117,7 @@ func LoadChecks() {
 		}))
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(identifiers.TestIsolatedCPUPoolSchedulingPolicy)).
+		WithPermissions(permissions.ExecProbePods).
 		WithSkipCheckFn(skipIfNoGuaranteedPodContainersWithIsolatedCPUsWithoutHostPID).
 		WithCheckFn(func(c *checksdb.Check) error {
 			testSchedulingPolicyInCPUPool(c, &env, env.GetGuaranteedPodContainersWithIsolatedCPUsWithoutHostPID(), scheduling.ExclusiveCPUScheduling)
@@ -249,7 +253,7 @@ func testSchedulingPolicyInCPUPool(check *checksdb.Check, env *provider.TestEnvi
 		check.LogInfo("Testing Container %q", cut)
 
 		// Get the pid namespace
-		pidNamespace, err := crclient.GetContainerPidNamespace(cut, env)
+		pidNamespace, err := crclient.GetContainerPidNamespace(check.Context(), cut, env)
 		if err != nil {
 			check.LogError("Unable to get pid namespace for Container %q, err: %v", cut, err)
 			nonCompliantContainersPids = append(nonCompliantContainersPids,
@@ -259,14 +263,14 @@ func testSchedulingPolicyInCPUPool(check *checksdb.Check, env *provider.TestEnvi
 		check.LogDebug("PID namespace for Container %q is %q", cut, pidNamespace)
 
 		// Get the list of process ids running in the pid namespace
-		processes, err := crclient.GetPidsFromPidNamespace(pidNamespace, cut)
+		processes, err := crclient.GetPidsFromPidNamespace(check.Context(), pidNamespace, cut)
 		if err != nil {
 			check.LogError("Unable to get PIDs from PID namespace %q for Container %q, err: %v", pidNamespace, cut, err)
 			nonCompliantContainersPids = append(nonCompliantContainersPids,
 				testhelper.NewContainerReportObject(cut.Namespace, cut.Podname, cut.Name, fmt.Sprintf("Internal error, err=%s", err), false))
 		}
 
-		compliantPids, nonCompliantPids := scheduling.ProcessPidsCPUScheduling(processes, cut, schedulingType, check.GetLogger())
+		compliantPids, nonCompliantPids := scheduling.ProcessPidsCPUScheduling(check.Context(), processes, cut, schedulingType, check.GetLogger())
 		// Check for the specified priority for each processes running in that pid namespace
 
 		compliantContainersPids = append(compliantContainersPids, compliantPids...)
@@ -314,7 +318,7 @@ func testRtAppsNoExecProbes(check *checksdb.Check, env *provider.TestEnvironment
 			continue
 		}
 
-		processes, err := crclient.GetContainerProcesses(cut, env)
+		processes, err := crclient.GetContainerProcesses(check.Context(), cut, env)
 		if err != nil {
 			check.LogError("Could not determine the processes pids for container %q, err: %v", cut, err)
 			nonCompliantObjects = append(nonCompliantObjects, testhelper.NewContainerReportObject(cut.Namespace, cut.Podname, cut.Name, "Could not determine the processes pids for container", false))
@@ -326,7 +330,7 @@ func testRtAppsNoExecProbes(check *checksdb.Check, env *provider.TestEnvironment
 		allProcessesCompliant := true
 		for _, p := range notExecProbeProcesses {
 			check.LogInfo("Testing process %q", p)
-			schedPolicy, _, err := scheduling.GetProcessCPUScheduling(p.Pid, cut)
+			schedPolicy, _, err := scheduling.GetProcessCPUScheduling(check.Context(), p.Pid, cut)
 			if err != nil {
 				// If the process does not exist anymore it means that it has finished since the time the process list
 				// was retrieved. In this case, just ignore the error and continue processing the rest of the processes.
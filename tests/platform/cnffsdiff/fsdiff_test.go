@@ -411,3 +411,53 @@ func Test_shouldUseCustomPodman(t *testing.T) {
 		})
 	}
 }
+
+// Replays "podman diff" outputs. The records are synthetic: they were written by hand in the format
+// of the --record-execs flag, not recorded in a cluster.
+func TestRunTestRecordedExecs(t *testing.T) {
+	replayer, err := clientsholder.NewCommandReplayerFromFile("testdata/exec-records.jsonl")
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		debugPod               string
+		containerUID           string
+		expectedResult         int
+		expectedChangedFolders []string
+		expectedDeletedFolders []string
+	}{
+		{
+			debugPod:       "certsuite-debug-worker-0",
+			containerUID:   "3f1c0a2b9d8e",
+			expectedResult: testhelper.SUCCESS,
+		},
+		{
+			debugPod:               "certsuite-debug-worker-0",
+			containerUID:           "9be47d51a3c0",
+			expectedResult:         testhelper.FAILURE,
+			expectedChangedFolders: []string{"/usr/bin"},
+		},
+		{
+			debugPod:               "certsuite-debug-worker-1",
+			containerUID:           "c7d20f6e4a11",
+			expectedResult:         testhelper.FAILURE,
+			expectedChangedFolders: []string{"/usr/lib"},
+			expectedDeletedFolders: []string{"/usr/lib"},
+		},
+		{
+			debugPod:       "certsuite-debug-worker-1",
+			containerUID:   "0d8a5e23f9b7",
+			expectedResult: testhelper.ERROR,
+		},
+	}
+
+	for _, tc := range testCases {
+		ctx := clientsholder.NewContext("certsuite", tc.debugPod, "container-00")
+		fsdiff := NewFsDiffTester(&checksdb.Check{}, replayer, ctx, "4.14.0")
+		fsdiff.RunTest(tc.containerUID)
+		assert.Equal(t, tc.expectedResult, fsdiff.GetResults())
+		assert.Equal(t, tc.expectedChangedFolders, fsdiff.ChangedFolders)
+		assert.Equal(t, tc.expectedDeletedFolders, fsdiff.DeletedFolders)
+	}
+
+	assert.Equal(t, 0, replayer.GetMissingCommands())
+}
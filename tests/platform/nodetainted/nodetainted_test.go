@@ -525,3 +525,63 @@ func TestGetOtherTaintedBits(t *testing.T) {
 		assert.Equal(t, tc.expectedBits, bits)
 	}
 }
+
+// Replays the outputs of the commands run in the debug pods. The records are synthetic: they were
+// written by hand in the format of the --record-execs flag, not recorded in a cluster.
+func TestNodeTaintedRecordedExecs(t *testing.T) {
+	replayer, err := clientsholder.NewCommandReplayerFromFile("testdata/exec-records.jsonl")
+	assert.Nil(t, err)
+
+	_ = clientsholder.GetTestClientsHolder(nil)
+	clientsholder.SetCommandExecutor(replayer)
+	defer func() {
+		clientsholder.SetCommandExecutor(nil)
+		clientsholder.ClearTestClientsHolder()
+	}()
+
+	testCases := []struct {
+		debugPod           string
+		expectedTaintsMask uint64
+		expectedTainters   map[string]string
+		expectedTaintBits  map[int]bool
+		expectedErrorMsg   string
+	}{
+		{
+			// Proprietary (P), out-of-tree (O) and unsigned (E) modules.
+			debugPod:           "certsuite-debug-worker-0",
+			expectedTaintsMask: 12289,
+			expectedTainters:   map[string]string{"nvidia": "POE", "nvidia_modeset": "POE"},
+			expectedTaintBits:  map[int]bool{0: true, 12: true, 13: true},
+		},
+		{
+			debugPod:           "certsuite-debug-worker-1",
+			expectedTaintsMask: 0,
+			expectedTainters:   map[string]string{},
+			expectedTaintBits:  map[int]bool{},
+		},
+		{
+			debugPod:         "certsuite-debug-worker-2",
+			expectedErrorMsg: "command terminated with exit code 137",
+		},
+	}
+
+	for _, tc := range testCases {
+		ctx := clientsholder.NewContext("certsuite", tc.debugPod, "container-00")
+		nt := NewNodeTaintedTester(&ctx, "fake-node-name")
+
+		taintsMask, err := nt.GetKernelTaintsMask()
+		if tc.expectedErrorMsg != "" {
+			assert.EqualError(t, err, tc.expectedErrorMsg)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expectedTaintsMask, taintsMask)
+
+		tainters, taintBits, err := nt.GetTainterModules(map[string]bool{"zfs": true})
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expectedTainters, tainters)
+		assert.Equal(t, tc.expectedTaintBits, taintBits)
+	}
+
+	assert.Equal(t, 0, replayer.GetMissingCommands())
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+)
+
+// A plugin is an executable that provides custom checks. It must support two commands:
+//   - "<plugin> describe": prints a PluginDescription in JSON format.
+//   - "<plugin> run <check-id>": reads the test environment in JSON format from stdin, the same
+//     one that is saved in the claim file's configurations, and prints a CheckOutput in JSON
+//     format. Anything written to stderr is added to the check's log.
+//
+// The checks' ids in the catalog and the claim file are prefixed with the plugin's suite name, like
+// the ones of the built-in checks, but the plugin's run command gets the ids without that prefix.
+// A non-zero exit code sets the check's result to error.
+const (
+	describeCmd = "describe"
+	runCmd      = "run"
+
+	describeTimeout = 1 * time.Minute
+	// Time to wait for the output of a plugin's child processes after the plugin has been killed.
+	execWaitDelay = 5 * time.Second
+)
+
+// CheckDescription holds the catalog entry of a plugin's check.
+type CheckDescription struct {
+	// Id without the suite name prefix.
+	ID                     string            `json:"id"`
+	Description            string            `json:"description"`
+	Remediation            string            `json:"remediation"`
+	Exception              string            `json:"exception,omitempty"`
+	Reference              string            `json:"reference,omitempty"`
+	Tags                   []string          `json:"tags,omitempty"`
+	CategoryClassification map[string]string `json:"categoryClassification,omitempty"`
+	// Intrusive checks never run at the same time as any other check.
+	Intrusive bool `json:"intrusive,omitempty"`
+}
+
+// PluginDescription is the output of the plugin's describe command.
+type PluginDescription struct {
+	Suite  string             `json:"suite"`
+	Checks []CheckDescription `json:"checks"`
+}
+
+// CheckOutput is the output of the plugin's run command. The check is skipped when SkipReason is
+// set or when both lists of objects are empty.
+type CheckOutput struct {
+	CompliantObjects    []*testhelper.ReportObject `json:"compliantObjects"`
+	NonCompliantObjects []*testhelper.ReportObject `json:"nonCompliantObjects"`
+	SkipReason          string                     `json:"skipReason,omitempty"`
+}
+
+type Plugin struct {
+	Path string
+	PluginDescription
+}
+
+func (p *Plugin) String() string {
+	return filepath.Base(p.Path)
+}
+
+func (p *Plugin) exec(ctx context.Context, stdin []byte, args ...string) (stdout, stderr string, err error) {
+	var outBuf, errBuf bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Path, args...)
+	cmd.WaitDelay = execWaitDelay
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+
+	err = cmd.Run()
+	return outBuf.String(), errBuf.String(), err
+}
+
+// LoadPlugin runs the plugin's describe command and checks its output.
+func LoadPlugin(path string) (*Plugin, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), describeTimeout)
+	defer cancel()
+
+	plugin := &Plugin{Path: path}
+	stdout, stderr, err := plugin.exec(ctx, nil, describeCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run plugin %s %s: %v (stderr: %s)", path, describeCmd, err, strings.TrimSpace(stderr))
+	}
+
+	if err := json.Unmarshal([]byte(stdout), &plugin.PluginDescription); err != nil {
+		return nil, fmt.Errorf("failed to parse the description of plugin %s: %v", path, err)
+	}
+
+	if plugin.Suite == "" {
+		return nil, fmt.Errorf("plugin %s has no suite name", path)
+	}
+
+	if len(plugin.Checks) == 0 {
+		return nil, fmt.Errorf("plugin %s has no checks", path)
+	}
+
+	checkIDs := map[string]bool{}
+	for i := range plugin.Checks {
+		checkID := plugin.Checks[i].ID
+		if checkID == "" {
+			return nil, fmt.Errorf("plugin %s has a check without id", path)
+		}
+		if checkIDs[checkID] {
+			return nil, fmt.Errorf("plugin %s has more than one check with id %q", path, checkID)
+		}
+		checkIDs[checkID] = true
+	}
+
+	return plugin, nil
+}
+
+// LoadPluginsDir loads all the executable files in the folder as plugins, in alphabetical order.
+func LoadPluginsDir(dir string) ([]*Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugins folder %s: %v", dir, err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	plugins := []*Plugin{}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get info of file %s: %v", entry.Name(), err)
+		}
+
+		const anyExecBit = 0o111
+		if !info.Mode().IsRegular() || info.Mode().Perm()&anyExecBit == 0 {
+			continue
+		}
+
+		plugin, err := LoadPlugin(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		plugins = append(plugins, plugin)
+	}
+
+	return plugins, nil
+}
+
+// RunCheck runs the plugin's run command for the check, passing the test environment in stdin.
+// Returns the check output and the plugin's stderr.
+func (p *Plugin) RunCheck(ctx context.Context, checkID string, env []byte) (output *CheckOutput, stderr string, err error) {
+	stdout, stderr, err := p.exec(ctx, env, runCmd, checkID)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, stderr, fmt.Errorf("plugin %s was stopped while running check %s: %v", p, checkID, ctxErr)
+	}
+	if err != nil {
+		return nil, stderr, fmt.Errorf("plugin %s failed to run check %s: %v", p, checkID, err)
+	}
+
+	output = &CheckOutput{}
+	if err := json.Unmarshal([]byte(stdout), output); err != nil {
+		return nil, stderr, fmt.Errorf("failed to parse the output of check %s from plugin %s: %v", checkID, p, err)
+	}
+
+	return output, stderr, nil
+}
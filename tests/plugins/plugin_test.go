@@ -0,0 +1,127 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package plugins
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPluginsDir = "testdata/plugins"
+
+func TestLoadPluginsDir(t *testing.T) {
+	plugins, err := LoadPluginsDir(testPluginsDir)
+	assert.Nil(t, err)
+	assert.Len(t, plugins, 1)
+
+	plugin := plugins[0]
+	assert.Equal(t, "acme-checks", plugin.String())
+	assert.Equal(t, "acme", plugin.Suite)
+	assert.Len(t, plugin.Checks, 4)
+	assert.Equal(t, CheckDescription{
+		ID:                     "image-registry",
+		Description:            "Containers must use images from the ACME registry.",
+		Remediation:            "Push the images to registry.acme.com.",
+		Tags:                   []string{"common"},
+		CategoryClassification: map[string]string{"Telco": "Mandatory"},
+	}, plugin.Checks[0])
+	assert.True(t, plugin.Checks[1].Intrusive)
+
+	_, err = LoadPluginsDir("testdata/not-found")
+	assert.NotNil(t, err)
+}
+
+func TestLoadPlugin(t *testing.T) {
+	testCases := []struct {
+		describeOutput   string
+		expectedErrorMsg string
+	}{
+		{
+			describeOutput:   `{"checks": [{"id": "check1"}]}`,
+			expectedErrorMsg: "has no suite name",
+		},
+		{
+			describeOutput:   `{"suite": "suite1", "checks": []}`,
+			expectedErrorMsg: "has no checks",
+		},
+		{
+			describeOutput:   `{"suite": "suite1", "checks": [{"description": "desc"}]}`,
+			expectedErrorMsg: "has a check without id",
+		},
+		{
+			describeOutput:   `{"suite": "suite1", "checks": [{"id": "check1"}, {"id": "check1"}]}`,
+			expectedErrorMsg: `has more than one check with id "check1"`,
+		},
+		{
+			describeOutput:   `not json`,
+			expectedErrorMsg: "failed to parse the description of plugin",
+		},
+		{
+			describeOutput: `{"suite": "suite1", "checks": [{"id": "check1"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		pluginPath := filepath.Join(t.TempDir(), "plugin")
+		script := "#!/bin/sh\ncat <<'EOF'\n" + tc.describeOutput + "\nEOF\n"
+		assert.Nil(t, os.WriteFile(pluginPath, []byte(script), 0o755)) //nolint:gosec
+
+		_, err := LoadPlugin(pluginPath)
+		if tc.expectedErrorMsg == "" {
+			assert.Nil(t, err)
+		} else {
+			assert.ErrorContains(t, err, tc.expectedErrorMsg)
+		}
+	}
+
+	_, err := LoadPlugin("testdata/plugins/README.md")
+	assert.ErrorContains(t, err, "failed to run plugin")
+}
+
+func TestRunCheck(t *testing.T) {
+	plugin, err := LoadPlugin(filepath.Join(testPluginsDir, "acme-checks"))
+	assert.Nil(t, err)
+
+	output, stderr, err := plugin.RunCheck(context.TODO(), "image-registry", []byte(`{"testNamespaces":["acme-ns"]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "checking images of namespaces in the test environment\n", stderr)
+	assert.Len(t, output.CompliantObjects, 1)
+	assert.Len(t, output.NonCompliantObjects, 1)
+	assert.Equal(t, "Container", output.NonCompliantObjects[0].ObjectType)
+	assert.Equal(t, []string{"Image is from docker.io", "acme-ns", "pod2", "c1"}, output.NonCompliantObjects[0].ObjectFieldsValues)
+
+	output, _, err = plugin.RunCheck(context.TODO(), "not-applicable", []byte(`{}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "not an ACME edge cluster", output.SkipReason)
+
+	_, stderr, err = plugin.RunCheck(context.TODO(), "broken", []byte(`{}`))
+	assert.EqualError(t, err, "plugin acme-checks failed to run check broken: exit status 2")
+	assert.Equal(t, "unknown check broken\n", stderr)
+
+	// The plugin is killed when the context expires.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	startTime := time.Now()
+	_, _, err = plugin.RunCheck(ctx, "hanging", []byte(`{}`))
+	assert.EqualError(t, err, "plugin acme-checks was stopped while running check hanging: context deadline exceeded")
+	assert.Less(t, time.Since(startTime), execWaitDelay)
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+)
+
+// AddCatalogEntries adds the plugin's checks to the catalog, failing in case any of the checks'
+// ids or the plugin's suite already exist in the catalog.
+func (p *Plugin) AddCatalogEntries() ([]claim.Identifier, error) {
+	entries := []identifiers.CustomCatalogEntry{}
+	for i := range p.Checks {
+		entries = append(entries, identifiers.CustomCatalogEntry{
+			ID:                     p.Checks[i].ID,
+			Description:            p.Checks[i].Description,
+			Remediation:            p.Checks[i].Remediation,
+			Exception:              p.Checks[i].Exception,
+			Reference:              p.Checks[i].Reference,
+			CategoryClassification: p.Checks[i].CategoryClassification,
+			Tags:                   p.Checks[i].Tags,
+		})
+	}
+
+	aIDs, err := identifiers.AddCustomCatalogEntries(p.Suite, entries)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %v", p, err)
+	}
+
+	return aIDs, nil
+}
+
+// AddCatalogEntries loads the plugins of the folder and adds their checks to the catalog.
+func AddCatalogEntries(pluginsDir string) error {
+	plugins, err := LoadPluginsDir(pluginsDir)
+	if err != nil {
+		return err
+	}
+
+	for _, plugin := range plugins {
+		if _, err := plugin.AddCatalogEntries(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadChecks creates a checks group for each plugin of the folder with its checks.
+func LoadChecks(pluginsDir string) error {
+	plugins, err := LoadPluginsDir(pluginsDir)
+	if err != nil {
+		return err
+	}
+
+	for _, plugin := range plugins {
+		log.Debug("Loading checks of plugin %s (suite %s)", plugin, plugin.Suite)
+		if err := loadPluginChecks(plugin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func loadPluginChecks(plugin *Plugin) error {
+	aIDs, err := plugin.AddCatalogEntries()
+	if err != nil {
+		return err
+	}
+
+	checksGroup := checksdb.NewChecksGroup(plugin.Suite)
+
+	for i, aID := range aIDs {
+		pluginCheckID := plugin.Checks[i].ID
+		check := checksdb.NewCheck(identifiers.GetTestIDAndLabels(aID)).
+			WithCheckFn(func(check *checksdb.Check) error {
+				env := provider.GetTestEnvironment()
+				testPluginCheck(check, plugin, pluginCheckID, &env)
+				return nil
+			})
+
+		if plugin.Checks[i].Intrusive {
+			check.WithIntrusive()
+		}
+
+		checksGroup.Add(check)
+	}
+
+	return nil
+}
+
+func testPluginCheck(check *checksdb.Check, plugin *Plugin, pluginCheckID string, env *provider.TestEnvironment) {
+	// Plugins don't need the credentials of the collector.
+	envCopy := *env
+	envCopy.CollectorAppPassword = ""
+	envJSON, err := json.Marshal(&envCopy)
+	if err != nil {
+		check.LogError("Failed to marshal the test environment: %v", err)
+		check.SetResultError(fmt.Sprintf("failed to marshal the test environment: %v", err))
+		return
+	}
+
+	check.LogInfo("Running check %s of plugin %s", check.ID, plugin)
+	output, stderr, err := plugin.RunCheck(check.Context(), pluginCheckID, envJSON)
+	for _, line := range strings.Split(strings.TrimSpace(stderr), "\n") {
+		if line != "" {
+			check.LogInfo("%s: %s", plugin, line)
+		}
+	}
+
+	if err != nil {
+		check.LogError("%v", err)
+		check.SetResultError(err.Error())
+		return
+	}
+
+	if output.SkipReason != "" {
+		check.LogInfo("Check skipped by plugin %s: %s", plugin, output.SkipReason)
+		check.SetResultSkipped(output.SkipReason)
+		return
+	}
+
+	check.SetResult(output.CompliantObjects, output.NonCompliantObjects)
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package plugins
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+	"github.com/stretchr/testify/assert"
+)
+
+func removeCatalogEntries(aIDs []claim.Identifier) {
+	for _, aID := range aIDs {
+		delete(identifiers.Catalog, aID)
+		delete(identifiers.Classification, aID.Id)
+	}
+}
+
+func TestAddCatalogEntries(t *testing.T) {
+	plugin, err := LoadPlugin(filepath.Join(testPluginsDir, "acme-checks"))
+	assert.Nil(t, err)
+
+	aIDs, err := plugin.AddCatalogEntries()
+	assert.Nil(t, err)
+	defer removeCatalogEntries(aIDs)
+	assert.Len(t, aIDs, 4)
+
+	entry := identifiers.Catalog[aIDs[0]]
+	assert.Equal(t, "acme-image-registry", entry.Identifier.Id)
+	assert.Equal(t, "acme", entry.Identifier.Suite)
+	assert.Equal(t, "common", entry.Tags)
+	assert.Equal(t, "Containers must use images from the ACME registry.", entry.Description)
+	assert.Equal(t, identifiers.NoDocumentedProcess, entry.ExceptionProcess)
+	assert.Equal(t, map[string]string{
+		identifiers.FarEdge:  identifiers.Optional,
+		identifiers.Telco:    identifiers.Mandatory,
+		identifiers.NonTelco: identifiers.Optional,
+		identifiers.Extended: identifiers.Optional,
+	}, entry.CategoryClassification)
+
+	// The same suite or check ids cannot be added twice.
+	_, err = plugin.AddCatalogEntries()
+	assert.EqualError(t, err, `plugin acme-checks: suite "acme" already exists`)
+
+	// Check ids are prefixed with the suite name.
+	plugin.Suite = "acme-image"
+	plugin.Checks = []CheckDescription{{ID: "registry"}}
+	_, err = plugin.AddCatalogEntries()
+	assert.EqualError(t, err, `plugin acme-checks: check "acme-image-registry" already exists`)
+}
+
+func TestTestPluginCheck(t *testing.T) {
+	plugin, err := LoadPlugin(filepath.Join(testPluginsDir, "acme-checks"))
+	assert.Nil(t, err)
+
+	testEnv := &provider.TestEnvironment{Namespaces: []string{"acme-ns"}, CollectorAppPassword: "secret"}
+
+	testCases := []struct {
+		pluginCheckID  string
+		expectedResult checksdb.CheckResult
+	}{
+		{pluginCheckID: "image-registry", expectedResult: checksdb.CheckResultFailed},
+		{pluginCheckID: "cost-center-label", expectedResult: checksdb.CheckResultPassed},
+		{pluginCheckID: "not-applicable", expectedResult: checksdb.CheckResultSkipped},
+		{pluginCheckID: "broken", expectedResult: checksdb.CheckResultError},
+	}
+
+	for _, tc := range testCases {
+		check := checksdb.NewCheck("acme-"+tc.pluginCheckID, nil)
+		testPluginCheck(check, plugin, tc.pluginCheckID, testEnv)
+		assert.Equal(t, tc.expectedResult, check.Result, tc.pluginCheckID)
+	}
+
+	// The original environment is not modified.
+	assert.Equal(t, "secret", testEnv.CollectorAppPassword)
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package policies
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of objects the policies can be evaluated against.
+const (
+	TargetPod         = "pod"
+	TargetContainer   = "container"
+	TargetDeployment  = "deployment"
+	TargetStatefulSet = "statefulset"
+	TargetDaemonSet   = "daemonset"
+	TargetReplicaSet  = "replicaset"
+	TargetJob         = "job"
+	TargetCronJob     = "cronjob"
+	TargetOperator    = "operator"
+	TargetNode        = "node"
+)
+
+// Variables available in the policies' expressions.
+const (
+	// The object under evaluation.
+	objectVar = "object"
+	// The pod of the container under evaluation. Only available for container policies.
+	podVar = "pod"
+)
+
+// Policy is a check defined as a CEL expression that is evaluated for every object of the target
+// kind. The object is compliant when the expression is true.
+type Policy struct {
+	// Id without the suite name prefix.
+	ID                     string            `yaml:"id"`
+	Description            string            `yaml:"description"`
+	Remediation            string            `yaml:"remediation"`
+	Exception              string            `yaml:"exception"`
+	Reference              string            `yaml:"reference"`
+	Tags                   []string          `yaml:"tags"`
+	CategoryClassification map[string]string `yaml:"categoryClassification"`
+	Target                 string            `yaml:"target"`
+	Expression             string            `yaml:"expression"`
+	// Reason shown for the non-compliant objects.
+	Message string `yaml:"message"`
+
+	program cel.Program
+}
+
+// PoliciesFile holds the policies of a suite.
+type PoliciesFile struct {
+	Suite    string   `yaml:"suite"`
+	Policies []Policy `yaml:"policies"`
+}
+
+func newCelEnv(target string) (*cel.Env, error) {
+	opts := []cel.EnvOption{
+		cel.Variable(objectVar, cel.DynType),
+		cel.CrossTypeNumericComparisons(true),
+		ext.Strings(),
+	}
+
+	if target == TargetContainer {
+		opts = append(opts, cel.Variable(podVar, cel.DynType))
+	}
+
+	return cel.NewEnv(opts...)
+}
+
+// Compiles the policy's expression, which must return a bool.
+func (p *Policy) compile() error {
+	switch p.Target {
+	case TargetPod, TargetContainer, TargetDeployment, TargetStatefulSet, TargetDaemonSet, TargetReplicaSet, TargetJob, TargetCronJob, TargetOperator, TargetNode:
+	default:
+		return fmt.Errorf("policy %s has an invalid target %q", p.ID, p.Target)
+	}
+
+	env, err := newCelEnv(p.Target)
+	if err != nil {
+		return fmt.Errorf("failed to create the CEL environment for policy %s: %v", p.ID, err)
+	}
+
+	ast, issues := env.Compile(p.Expression)
+	if issues != nil && issues.Err() != nil {
+		return fmt.Errorf("failed to compile the expression of policy %s: %v", p.ID, issues.Err())
+	}
+
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return fmt.Errorf("the expression of policy %s must return a bool, not %v", p.ID, ast.OutputType())
+	}
+
+	p.program, err = env.Program(ast)
+	if err != nil {
+		return fmt.Errorf("failed to create the program of policy %s: %v", p.ID, err)
+	}
+
+	return nil
+}
+
+// Returns the object as a generic map, the same way it would be seen in JSON format.
+func toCelValue(obj any) (map[string]any, error) {
+	bytes, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	value := map[string]any{}
+	if err := json.Unmarshal(bytes, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// Evaluate returns whether the object complies with the policy. The pod is only used by the
+// container policies.
+func (p *Policy) Evaluate(obj, pod any) (bool, error) {
+	objectValue, err := toCelValue(obj)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert object: %v", err)
+	}
+
+	vars := map[string]any{objectVar: objectValue}
+	if p.Target == TargetContainer {
+		podValue, err := toCelValue(pod)
+		if err != nil {
+			return false, fmt.Errorf("failed to convert pod: %v", err)
+		}
+		vars[podVar] = podValue
+	}
+
+	out, _, err := p.program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+
+	compliant, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression returned %v instead of a bool", out.Value())
+	}
+
+	return compliant, nil
+}
+
+// LoadPoliciesFile reads the policies from a YAML file and compiles their expressions.
+func LoadPoliciesFile(filePath string) (*PoliciesFile, error) {
+	contents, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policies file %s: %v", filePath, err)
+	}
+
+	policiesFile := PoliciesFile{}
+	if err := yaml.Unmarshal(contents, &policiesFile); err != nil {
+		return nil, fmt.Errorf("failed to parse policies file %s: %v", filePath, err)
+	}
+
+	if policiesFile.Suite == "" {
+		return nil, fmt.Errorf("policies file %s has no suite name", filePath)
+	}
+
+	policyIDs := map[string]bool{}
+	for i := range policiesFile.Policies {
+		policy := &policiesFile.Policies[i]
+		if policy.ID == "" {
+			return nil, fmt.Errorf("policies file %s has a policy without id", filePath)
+		}
+		if policyIDs[policy.ID] {
+			return nil, fmt.Errorf("policies file %s has more than one policy with id %q", filePath, policy.ID)
+		}
+		policyIDs[policy.ID] = true
+
+		if err := policy.compile(); err != nil {
+			return nil, fmt.Errorf("invalid policies file %s: %v", filePath, err)
+		}
+	}
+
+	return &policiesFile, nil
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package policies
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestLoadPoliciesFile(t *testing.T) {
+	policiesFile, err := LoadPoliciesFile("testdata/policies.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "acme", policiesFile.Suite)
+	assert.Len(t, policiesFile.Policies, 5)
+	assert.Equal(t, "image-tag-not-latest", policiesFile.Policies[0].ID)
+	assert.Equal(t, TargetContainer, policiesFile.Policies[0].Target)
+	assert.Equal(t, map[string]string{"Telco": "Mandatory"}, policiesFile.Policies[0].CategoryClassification)
+
+	_, err = LoadPoliciesFile("testdata/not-found.yaml")
+	assert.NotNil(t, err)
+}
+
+func TestLoadPoliciesFileErrors(t *testing.T) {
+	testCases := []struct {
+		contents         string
+		expectedErrorMsg string
+	}{
+		{
+			contents:         "policies:\n  - id: p1\n    target: pod\n    expression: 'true'\n",
+			expectedErrorMsg: "has no suite name",
+		},
+		{
+			contents:         "suite: s1\npolicies:\n  - target: pod\n    expression: 'true'\n",
+			expectedErrorMsg: "has a policy without id",
+		},
+		{
+			contents:         "suite: s1\npolicies:\n  - id: p1\n    target: pod\n    expression: 'true'\n  - id: p1\n    target: pod\n    expression: 'true'\n",
+			expectedErrorMsg: "has more than one policy with id \"p1\"",
+		},
+		{
+			contents:         "suite: s1\npolicies:\n  - id: p1\n    target: service\n    expression: 'true'\n",
+			expectedErrorMsg: "policy p1 has an invalid target \"service\"",
+		},
+		{
+			contents:         "suite: s1\npolicies:\n  - id: p1\n    target: pod\n    expression: 'object.spec.'\n",
+			expectedErrorMsg: "failed to compile the expression of policy p1",
+		},
+		{
+			contents:         "suite: s1\npolicies:\n  - id: p1\n    target: pod\n    expression: '1 + 2'\n",
+			expectedErrorMsg: "the expression of policy p1 must return a bool",
+		},
+		{
+			// The pod variable is only available for container policies.
+			contents:         "suite: s1\npolicies:\n  - id: p1\n    target: pod\n    expression: 'has(pod.spec)'\n",
+			expectedErrorMsg: "undeclared reference to 'pod'",
+		},
+	}
+
+	for _, tc := range testCases {
+		filePath := filepath.Join(t.TempDir(), "policies.yaml")
+		assert.Nil(t, os.WriteFile(filePath, []byte(tc.contents), 0o600))
+
+		_, err := LoadPoliciesFile(filePath)
+		assert.ErrorContains(t, err, tc.expectedErrorMsg)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	policiesFile, err := LoadPoliciesFile("testdata/policies.yaml")
+	assert.Nil(t, err)
+
+	imageTagPolicy := &policiesFile.Policies[0]
+	for image, expected := range map[string]bool{
+		"quay.io/acme/app:1.0.2":    true,
+		"quay.io/acme/app:latest":   false,
+		"quay.io/acme/app":          false,
+		"quay.io/acme/app@sha256:1": true,
+	} {
+		compliant, err := imageTagPolicy.Evaluate(&corev1.Container{Image: image}, &corev1.Pod{})
+		assert.Nil(t, err)
+		assert.Equal(t, expected, compliant, image)
+	}
+
+	// Missing fields make the evaluation to fail.
+	labelPolicy := &policiesFile.Policies[3]
+	_, err = labelPolicy.Evaluate(&corev1.Container{}, &corev1.Pod{})
+	assert.ErrorContains(t, err, "no such key: labels")
+}
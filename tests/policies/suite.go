@@ -0,0 +1,248 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+
+package policies
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+)
+
+var (
+	env provider.TestEnvironment
+
+	beforeEachFn = func(check *checksdb.Check) error {
+		env = provider.GetTestEnvironment()
+		return nil
+	}
+)
+
+// AddCatalogEntries adds the policies to the catalog, failing in case any of the policies' ids
+// or the suite already exist in the catalog.
+func (f *PoliciesFile) AddCatalogEntries() ([]claim.Identifier, error) {
+	entries := []identifiers.CustomCatalogEntry{}
+	for i := range f.Policies {
+		entries = append(entries, identifiers.CustomCatalogEntry{
+			ID:                     f.Policies[i].ID,
+			Description:            f.Policies[i].Description,
+			Remediation:            f.Policies[i].Remediation,
+			Exception:              f.Policies[i].Exception,
+			Reference:              f.Policies[i].Reference,
+			CategoryClassification: f.Policies[i].CategoryClassification,
+			Tags:                   f.Policies[i].Tags,
+		})
+	}
+
+	aIDs, err := identifiers.AddCustomCatalogEntries(f.Suite, entries)
+	if err != nil {
+		return nil, fmt.Errorf("policies file: %v", err)
+	}
+
+	return aIDs, nil
+}
+
+// AddCatalogEntries loads the policies file and adds its policies to the catalog.
+func AddCatalogEntries(policiesFilePath string) error {
+	policiesFile, err := LoadPoliciesFile(policiesFilePath)
+	if err != nil {
+		return err
+	}
+
+	_, err = policiesFile.AddCatalogEntries()
+	return err
+}
+
+// LoadChecks creates a checks group for the suite of the policies file with a check for each policy.
+func LoadChecks(policiesFilePath string) error {
+	policiesFile, err := LoadPoliciesFile(policiesFilePath)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("Loading %d policies of suite %s", len(policiesFile.Policies), policiesFile.Suite)
+
+	aIDs, err := policiesFile.AddCatalogEntries()
+	if err != nil {
+		return err
+	}
+
+	checksGroup := checksdb.NewChecksGroup(policiesFile.Suite).
+		WithBeforeEachFn(beforeEachFn)
+
+	for i, aID := range aIDs {
+		policy := &policiesFile.Policies[i]
+		checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(aID)).
+			WithSkipCheckFn(getTargetSkipFn(policy.Target, &env)).
+			WithCheckFn(func(check *checksdb.Check) error {
+				testPolicy(check, policy, &env)
+				return nil
+			}))
+	}
+
+	return nil
+}
+
+func getTargetSkipFn(target string, env *provider.TestEnvironment) func() (bool, string) {
+	switch target {
+	case TargetPod:
+		return testhelper.GetNoPodsUnderTestSkipFn(env)
+	case TargetContainer:
+		return testhelper.GetNoContainersUnderTestSkipFn(env)
+	case TargetDeployment:
+		return testhelper.GetNoDeploymentsUnderTestSkipFn(env)
+	case TargetStatefulSet:
+		return testhelper.GetNoStatefulSetsUnderTestSkipFn(env)
+	case TargetDaemonSet:
+		return testhelper.GetNoDaemonSetsUnderTestSkipFn(env)
+	case TargetReplicaSet:
+		return testhelper.GetNoReplicaSetsUnderTestSkipFn(env)
+	case TargetJob, TargetCronJob:
+		// The check is skipped later on when only the other kind is found, as it has no targets.
+		return testhelper.GetNoJobsUnderTestSkipFn(env)
+	case TargetOperator:
+		return testhelper.GetNoOperatorsSkipFn(env)
+	default:
+		return func() (bool, string) {
+			if len(env.Nodes) == 0 {
+				return true, "no nodes found"
+			}
+			return false, ""
+		}
+	}
+}
+
+// Object under evaluation along with its report object.
+type policyTarget struct {
+	obj, pod      any
+	newReportFunc func(reason string, isCompliant bool) *testhelper.ReportObject
+}
+
+//nolint:funlen
+func getPolicyTargets(target string, env *provider.TestEnvironment) []policyTarget {
+	targets := []policyTarget{}
+	switch target {
+	case TargetPod:
+		for _, put := range env.Pods {
+			targets = append(targets, policyTarget{obj: put.Pod, newReportFunc: func(reason string, isCompliant bool) *testhelper.ReportObject {
+				return testhelper.NewPodReportObject(put.Namespace, put.Name, reason, isCompliant)
+			}})
+		}
+	case TargetContainer:
+		for _, put := range env.Pods {
+			for _, cut := range put.Containers {
+				targets = append(targets, policyTarget{obj: cut.Container, pod: put.Pod, newReportFunc: func(reason string, isCompliant bool) *testhelper.ReportObject {
+					return testhelper.NewContainerReportObject(cut.Namespace, cut.Podname, cut.Name, reason, isCompliant)
+				}})
+			}
+		}
+	case TargetDeployment:
+		for _, dut := range env.Deployments {
+			targets = append(targets, policyTarget{obj: dut.Deployment, newReportFunc: func(reason string, isCompliant bool) *testhelper.ReportObject {
+				return testhelper.NewDeploymentReportObject(dut.Namespace, dut.Name, reason, isCompliant)
+			}})
+		}
+	case TargetStatefulSet:
+		for _, sut := range env.StatefulSets {
+			targets = append(targets, policyTarget{obj: sut.StatefulSet, newReportFunc: func(reason string, isCompliant bool) *testhelper.ReportObject {
+				return testhelper.NewStatefulSetReportObject(sut.Namespace, sut.Name, reason, isCompliant)
+			}})
+		}
+	case TargetDaemonSet:
+		for _, dsut := range env.DaemonSets {
+			targets = append(targets, policyTarget{obj: dsut.DaemonSet, newReportFunc: func(reason string, isCompliant bool) *testhelper.ReportObject {
+				return testhelper.NewDaemonSetReportObject(dsut.Namespace, dsut.Name, reason, isCompliant)
+			}})
+		}
+	case TargetReplicaSet:
+		for _, rsut := range env.ReplicaSets {
+			targets = append(targets, policyTarget{obj: rsut.ReplicaSet, newReportFunc: func(reason string, isCompliant bool) *testhelper.ReportObject {
+				return testhelper.NewReplicaSetReportObject(rsut.Namespace, rsut.Name, reason, isCompliant)
+			}})
+		}
+	case TargetJob:
+		for _, jut := range env.Jobs {
+			targets = append(targets, policyTarget{obj: jut.Job, newReportFunc: func(reason string, isCompliant bool) *testhelper.ReportObject {
+				return testhelper.NewJobReportObject(jut.Namespace, jut.Name, reason, isCompliant)
+			}})
+		}
+	case TargetCronJob:
+		for _, cjut := range env.CronJobs {
+			targets = append(targets, policyTarget{obj: cjut.CronJob, newReportFunc: func(reason string, isCompliant bool) *testhelper.ReportObject {
+				return testhelper.NewCronJobReportObject(cjut.Namespace, cjut.Name, reason, isCompliant)
+			}})
+		}
+	case TargetOperator:
+		for _, op := range env.Operators {
+			targets = append(targets, policyTarget{obj: op, newReportFunc: func(reason string, isCompliant bool) *testhelper.ReportObject {
+				return testhelper.NewOperatorReportObject(op.Namespace, op.Name, reason, isCompliant)
+			}})
+		}
+	case TargetNode:
+		nodeNames := make([]string, 0, len(env.Nodes))
+		for nodeName := range env.Nodes {
+			nodeNames = append(nodeNames, nodeName)
+		}
+		sort.Strings(nodeNames)
+
+		for _, nodeName := range nodeNames {
+			node := env.Nodes[nodeName]
+			targets = append(targets, policyTarget{obj: node.Data, newReportFunc: func(reason string, isCompliant bool) *testhelper.ReportObject {
+				return testhelper.NewNodeReportObject(node.Data.Name, reason, isCompliant)
+			}})
+		}
+	}
+
+	return targets
+}
+
+func testPolicy(check *checksdb.Check, policy *Policy, env *provider.TestEnvironment) {
+	nonCompliantReason := policy.Message
+	if nonCompliantReason == "" {
+		nonCompliantReason = "Policy " + policy.ID + " is not satisfied"
+	}
+
+	var compliantObjects []*testhelper.ReportObject
+	var nonCompliantObjects []*testhelper.ReportObject
+	evalErrors := 0
+	for _, target := range getPolicyTargets(policy.Target, env) {
+		compliant, err := policy.Evaluate(target.obj, target.pod)
+		if err != nil {
+			check.LogError("Failed to evaluate policy %s: %v", policy.ID, err)
+			nonCompliantObjects = append(nonCompliantObjects, target.newReportFunc(fmt.Sprintf("Failed to evaluate policy %s: %v", policy.ID, err), false))
+			evalErrors++
+			continue
+		}
+
+		if compliant {
+			compliantObjects = append(compliantObjects, target.newReportFunc("Policy "+policy.ID+" is satisfied", true))
+		} else {
+			nonCompliantObjects = append(nonCompliantObjects, target.newReportFunc(nonCompliantReason, false))
+		}
+	}
+
+	if evalErrors > 0 {
+		check.SetResultError(fmt.Sprintf("failed to evaluate policy %s for %d objects", policy.ID, evalErrors))
+	}
+
+	check.SetResult(compliantObjects, nonCompliantObjects)
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2020-2024 Red Hat, Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program; if not, write to the Free Software Foundation, Inc.,
+// 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+package policies
+
+import (
+	"testing"
+
+	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
+	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func removeCatalogEntries(aIDs []claim.Identifier) {
+	for _, aID := range aIDs {
+		delete(identifiers.Catalog, aID)
+		delete(identifiers.Classification, aID.Id)
+	}
+}
+
+func TestAddCatalogEntries(t *testing.T) {
+	policiesFile, err := LoadPoliciesFile("testdata/policies.yaml")
+	assert.Nil(t, err)
+
+	aIDs, err := policiesFile.AddCatalogEntries()
+	assert.Nil(t, err)
+	defer removeCatalogEntries(aIDs)
+	assert.Len(t, aIDs, 5)
+
+	entry := identifiers.Catalog[aIDs[0]]
+	assert.Equal(t, "acme-image-tag-not-latest", entry.Identifier.Id)
+	assert.Equal(t, "acme", entry.Identifier.Suite)
+	assert.Equal(t, "common", entry.Tags)
+	assert.Equal(t, identifiers.Mandatory, entry.CategoryClassification[identifiers.Telco])
+	assert.Equal(t, identifiers.Optional, entry.CategoryClassification[identifiers.NonTelco])
+
+	_, err = policiesFile.AddCatalogEntries()
+	assert.EqualError(t, err, `policies file: suite "acme" already exists`)
+}
+
+func newTestPod(name string, labels map[string]string, hostNetwork bool, containers ...corev1.Container) *provider.Pod {
+	pod := provider.NewPod(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1", Labels: labels},
+		Spec:       corev1.PodSpec{HostNetwork: hostNetwork, Containers: containers},
+	})
+	return &pod
+}
+
+func TestTestPolicy(t *testing.T) {
+	policiesFile, err := LoadPoliciesFile("testdata/policies.yaml")
+	assert.Nil(t, err)
+
+	replicas := int32(1)
+	testEnv := &provider.TestEnvironment{
+		Pods: []*provider.Pod{
+			newTestPod("pod1", map[string]string{"acme.com/cost-center": "1234"}, false,
+				corev1.Container{Name: "c1", Image: "quay.io/acme/app:1.0", TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError}),
+			newTestPod("pod2", nil, true,
+				corev1.Container{Name: "c1", Image: "quay.io/acme/app:latest", TerminationMessagePolicy: corev1.TerminationMessageReadFile},
+				corev1.Container{Name: "c2", Image: "quay.io/acme/sidecar:2.1", TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError}),
+		},
+		Deployments: []*provider.Deployment{
+			{Deployment: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "dep1", Namespace: "ns1"},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			}},
+		},
+	}
+
+	testCases := []struct {
+		policyIdx                int
+		expectedResult           checksdb.CheckResult
+		expectedCompliantObjs    int
+		expectedNonCompliantObjs int
+	}{
+		{policyIdx: 0, expectedResult: checksdb.CheckResultFailed, expectedCompliantObjs: 2, expectedNonCompliantObjs: 1},
+		{policyIdx: 1, expectedResult: checksdb.CheckResultFailed, expectedCompliantObjs: 2, expectedNonCompliantObjs: 1},
+		{policyIdx: 2, expectedResult: checksdb.CheckResultFailed, expectedCompliantObjs: 1, expectedNonCompliantObjs: 1},
+		// pod2 has no labels at all, so the expression cannot be evaluated for its containers.
+		{policyIdx: 3, expectedResult: checksdb.CheckResultError, expectedCompliantObjs: 1, expectedNonCompliantObjs: 2},
+		{policyIdx: 4, expectedResult: checksdb.CheckResultFailed, expectedCompliantObjs: 0, expectedNonCompliantObjs: 1},
+	}
+
+	for _, tc := range testCases {
+		policy := &policiesFile.Policies[tc.policyIdx]
+		check := checksdb.NewCheck("acme-"+policy.ID, nil)
+		testPolicy(check, policy, testEnv)
+		assert.Equal(t, tc.expectedResult, check.Result, policy.ID)
+
+		compliantObjs, nonCompliantObjs := 0, 0
+		for _, target := range getPolicyTargets(policy.Target, testEnv) {
+			compliant, err := policy.Evaluate(target.obj, target.pod)
+			if err == nil && compliant {
+				compliantObjs++
+			} else {
+				nonCompliantObjs++
+			}
+		}
+		assert.Equal(t, tc.expectedCompliantObjs, compliantObjs, policy.ID)
+		assert.Equal(t, tc.expectedNonCompliantObjs, nonCompliantObjs, policy.ID)
+	}
+}
+
+func TestGetPolicyTargets(t *testing.T) {
+	testEnv := &provider.TestEnvironment{
+		Pods: []*provider.Pod{newTestPod("pod1", nil, false, corev1.Container{Name: "c1"}, corev1.Container{Name: "c2"})},
+		Nodes: map[string]provider.Node{
+			"worker-1": {Data: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}}},
+			"worker-0": {Data: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-0"}}},
+		},
+	}
+
+	containerTargets := getPolicyTargets(TargetContainer, testEnv)
+	assert.Len(t, containerTargets, 2)
+	assert.Equal(t, testhelper.NewContainerReportObject("ns1", "pod1", "c2", "reason", false), containerTargets[1].newReportFunc("reason", false))
+	assert.Equal(t, testEnv.Pods[0].Pod, containerTargets[1].pod)
+
+	nodeTargets := getPolicyTargets(TargetNode, testEnv)
+	assert.Len(t, nodeTargets, 2)
+	assert.Equal(t, testhelper.NewNodeReportObject("worker-0", "reason", true), nodeTargets[0].newReportFunc("reason", true))
+
+	assert.Empty(t, getPolicyTargets(TargetOperator, testEnv))
+
+	testEnv.CronJobs = []*provider.CronJob{{CronJob: &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "cronjob1", Namespace: "ns1"}}}}
+	cronJobTargets := getPolicyTargets(TargetCronJob, testEnv)
+	assert.Len(t, cronJobTargets, 1)
+	assert.Equal(t, testhelper.NewCronJobReportObject("ns1", "cronjob1", "reason", true), cronJobTargets[0].newReportFunc("reason", true))
+	assert.Empty(t, getPolicyTargets(TargetJob, testEnv))
+}
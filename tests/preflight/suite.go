@@ -23,6 +23,7 @@ import (
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/checksdb"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/permissions"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/testhelper"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/common"
@@ -36,6 +37,8 @@ var (
 		env = provider.GetTestEnvironment()
 		return nil
 	}
+
+	operatorsPermissions = []permissions.Permission{permissions.ListClusterServiceVersions, permissions.ListSubscriptions}
 )
 
 func labelsAllowTestRun(labelFilter string, allowedLabels []string) bool {
@@ -47,6 +50,17 @@ func labelsAllowTestRun(labelFilter string, allowedLabels []string) bool {
 	return false
 }
 
+// IsSelected returns true if the labels expr selects the preflight checks, which are only known once
+// the preflight lib has run.
+func IsSelected(labelsExpr string) bool {
+	return labelsAllowTestRun(labelsExpr, []string{common.PreflightTestKey, identifiers.TagPreflight})
+}
+
+// GetPermissions returns the permissions needed by the preflight checks.
+func GetPermissions() []permissions.Permission {
+	return append([]permissions.Permission{}, operatorsPermissions...)
+}
+
 // Returns true if the preflight checks should run.
 // Conditions: (1) the labels expr should contain any of the preflight tags/labels & (2) the
 // preflight dockerconfig file must exist.
@@ -56,9 +70,8 @@ func labelsAllowTestRun(labelFilter string, allowedLabels []string) bool {
 // check that has run. The CheckFn will simply store the result.
 func ShouldRun(labelsExpr string) bool {
 	env = provider.GetTestEnvironment()
-	preflightAllowedLabels := []string{common.PreflightTestKey, identifiers.TagPreflight}
 
-	if !labelsAllowTestRun(labelsExpr, preflightAllowedLabels) {
+	if !IsSelected(labelsExpr) {
 		return false
 	}
 
@@ -190,6 +203,7 @@ func generatePreflightOperatorCnfCertTest(checksGroup *checksdb.ChecksGroup, tes
 	}, identifiers.TagPreflight)
 
 	checksGroup.Add(checksdb.NewCheck(identifiers.GetTestIDAndLabels(aID)).
+		WithPermissions(operatorsPermissions...).
 		WithSkipCheckFn(testhelper.GetNoOperatorsSkipFn(&env)).
 		WithCheckFn(func(check *checksdb.Check) error {
 			var compliantObjects []*testhelper.ReportObject
@@ -19,10 +19,12 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/redhat-best-practices-for-k8s/certsuite-claim/pkg/claim"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/clientsholder"
+	"github.com/redhat-best-practices-for-k8s/certsuite/internal/events"
 	"github.com/redhat-best-practices-for-k8s/certsuite/internal/log"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/arrayhelper"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/certsuite"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/configuration"
+	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/metrics"
 	"github.com/redhat-best-practices-for-k8s/certsuite/pkg/provider"
 	"github.com/redhat-best-practices-for-k8s/certsuite/tests/identifiers"
 	"github.com/robert-nix/ansihtml"
@@ -36,6 +38,8 @@ const (
 	logTimeout = 1000
 
 	readTimeoutSeconds = 10
+
+	eventsStreamFormat = "events"
 )
 
 var (
@@ -72,6 +76,12 @@ func logStreamHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer conn.Close()
+
+	if r.URL.Query().Get("format") == eventsStreamFormat {
+		streamEvents(conn)
+		return
+	}
+
 	// Create a scanner to read the log file line by line
 	for {
 		scanner := bufio.NewScanner(buf)
@@ -94,6 +104,29 @@ func logStreamHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Sends the run progress events as JSON messages, instead of the log lines.
+func streamEvents(conn *websocket.Conn) {
+	eventsChan, unsubscribe := events.Subscribe()
+	defer unsubscribe()
+
+	// The client doesn't send anything, so reading only fails once it has disconnected.
+	go func() {
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				unsubscribe()
+				return
+			}
+		}
+	}()
+
+	for event := range eventsChan {
+		if err := conn.WriteMessage(websocket.TextMessage, event); err != nil {
+			log.Info("Failed to send event: %v", err)
+			return
+		}
+	}
+}
+
 type RequestedData struct {
 	SelectedOptions                      []string `json:"selectedOptions"`
 	TargetNameSpaces                     []string `json:"targetNameSpaces"`
@@ -208,6 +241,7 @@ func StartServer(outputFolder string) {
 	installReqHandlers()
 
 	http.HandleFunc("/runFunction", runHandler)
+	http.Handle("/metrics", metrics.Handler())
 
 	log.Info("Server is running on :8084...")
 	if err := server.ListenAndServe(); err != nil {